@@ -0,0 +1,176 @@
+// Package caldavtest provides an in-process CalDAV server for tests. It answers free-busy-query REPORTs and stores
+// the events written to its single calendar.
+package caldavtest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/ical"
+)
+
+const (
+	// CalendarPath is the path of the calendar collection served
+	CalendarPath = "/calendars/test/"
+	timeLayout   = "20060102T150405Z"
+)
+
+type freeBusyQuery struct {
+	XMLName   xml.Name `xml:"urn:ietf:params:xml:ns:caldav free-busy-query"`
+	TimeRange struct {
+		Start string `xml:"start,attr"`
+		End   string `xml:"end,attr"`
+	} `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+}
+
+// Server is a fake CalDAV server. Its busy time is made of the periods added with AddBusy and of the events written to
+// it.
+type Server struct {
+	*httptest.Server
+	// Username and Password, when set, are required from clients through basic authentication
+	Username string
+	Password string
+
+	mu     sync.Mutex
+	busy   []ical.Period
+	events map[string]ical.Event
+}
+
+// NewServer starts a server, which should be closed when done.
+func NewServer() *Server {
+	server := &Server{events: make(map[string]ical.Event)}
+	server.Server = httptest.NewServer(http.HandlerFunc(server.handle))
+	return server
+}
+
+// CalendarURL returns the URL of the calendar collection.
+func (server *Server) CalendarURL() string {
+	return server.URL + CalendarPath
+}
+
+// AddBusy adds busy periods which are not backed by an event.
+func (server *Server) AddBusy(periods ...ical.Period) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.busy = append(server.busy, periods...)
+}
+
+// Event returns the event stored with the given UID.
+func (server *Server) Event(uid string) (ical.Event, bool) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	event, ok := server.events[uid]
+	return event, ok
+}
+
+// EventCount returns the number of events stored.
+func (server *Server) EventCount() int {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return len(server.events)
+}
+
+func (server *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if server.Username != "" || server.Password != "" {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != server.Username || password != server.Password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="caldavtest"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+	if !strings.HasPrefix(r.URL.Path, CalendarPath) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	resource := strings.TrimPrefix(r.URL.Path, CalendarPath)
+
+	switch {
+	case r.Method == "REPORT" && resource == "":
+		server.freeBusy(w, r)
+	case r.Method == http.MethodPut && strings.HasSuffix(resource, ".ics"):
+		server.put(w, r, strings.TrimSuffix(resource, ".ics"))
+	case r.Method == http.MethodDelete && strings.HasSuffix(resource, ".ics"):
+		server.delete(w, strings.TrimSuffix(resource, ".ics"))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (server *Server) freeBusy(w http.ResponseWriter, r *http.Request) {
+	query := freeBusyQuery{}
+	if err := xml.NewDecoder(r.Body).Decode(&query); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	start, startErr := time.Parse(timeLayout, query.TimeRange.Start)
+	end, endErr := time.Parse(timeLayout, query.TimeRange.End)
+	if startErr != nil || endErr != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	server.mu.Lock()
+	cal := ical.Calendar{BusyPeriods: server.busy}
+	for _, event := range server.events {
+		cal.Events = append(cal.Events, event)
+	}
+	server.mu.Unlock()
+
+	periods := make([]string, 0)
+	for _, period := range cal.Busy(start, end) {
+		periods = append(periods, period.Start.UTC().Format(timeLayout)+"/"+period.End.UTC().Format(timeLayout))
+	}
+
+	var body bytes.Buffer
+	fmt.Fprint(&body, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//caldavtest//EN\r\nBEGIN:VFREEBUSY\r\n")
+	fmt.Fprintf(&body, "DTSTART:%s\r\nDTEND:%s\r\n", query.TimeRange.Start, query.TimeRange.End)
+	if len(periods) > 0 {
+		fmt.Fprintf(&body, "FREEBUSY;FBTYPE=BUSY:%s\r\n", strings.Join(periods, ","))
+	}
+	fmt.Fprint(&body, "END:VFREEBUSY\r\nEND:VCALENDAR\r\n")
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Write(body.Bytes())
+}
+
+func (server *Server) put(w http.ResponseWriter, r *http.Request, uid string) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	cal, err := ical.Parse(bytes.NewReader(data), time.UTC)
+	if err != nil || len(cal.Events) != 1 || cal.Events[0].UID != uid {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	_, exists := server.events[uid]
+	server.events[uid] = cal.Events[0]
+	if exists {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (server *Server) delete(w http.ResponseWriter, uid string) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	if _, exists := server.events[uid]; !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	delete(server.events, uid)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,144 @@
+// Package caldav talks to CalDAV servers, as defined by RFC 4791, to read the free/busy time of a calendar and to
+// write events to it.
+package caldav
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/ical"
+)
+
+// maxResponseBytes bounds the free/busy responses read from servers
+const maxResponseBytes = 5 << 20
+
+const timeRangeLayout = "20060102T150405Z"
+
+const freeBusyQuery = `<?xml version="1.0" encoding="utf-8"?>
+<C:free-busy-query xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <C:time-range start="%s" end="%s"/>
+</C:free-busy-query>`
+
+// StatusError is returned when the server answers a request with an unexpected status.
+type StatusError struct {
+	Method     string
+	StatusCode int
+}
+
+func (err StatusError) Error() string {
+	return fmt.Sprintf("caldav: %s returned status %d", err.Method, err.StatusCode)
+}
+
+// Client accesses a single calendar collection.
+type Client struct {
+	httpClient  *http.Client
+	calendarURL string
+	username    string
+	password    string
+}
+
+// FreeBusy returns the busy periods of the calendar between from and to, using a free-busy-query REPORT.
+func (client Client) FreeBusy(ctx context.Context, from, to time.Time) ([]ical.Period, error) {
+	body := fmt.Sprintf(freeBusyQuery, from.UTC().Format(timeRangeLayout), to.UTC().Format(timeRangeLayout))
+	req, err := client.newRequest(ctx, "REPORT", client.calendarURL, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+	req.Header.Set("Depth", "1")
+
+	resp, err := client.do(req, http.StatusOK)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	cal, err := ical.Parse(io.LimitReader(resp.Body, maxResponseBytes), time.UTC)
+	if err != nil {
+		return nil, fmt.Errorf("caldav: invalid free/busy response: %w", err)
+	}
+	return cal.Busy(from, to), nil
+}
+
+// PutEvent creates or replaces the event of cal, which should hold a single event, in the calendar. The event is
+// stored under its UID so that writing it again replaces it.
+func (client Client) PutEvent(ctx context.Context, cal ical.Calendar) error {
+	if len(cal.Events) != 1 {
+		return fmt.Errorf("caldav: a calendar resource holds a single event, got %d", len(cal.Events))
+	}
+	data, err := cal.Bytes()
+	if err != nil {
+		return err
+	}
+
+	req, err := client.newRequest(ctx, http.MethodPut, client.eventURL(cal.Events[0].UID), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/calendar; charset=utf-8")
+
+	resp, err := client.do(req, http.StatusCreated, http.StatusNoContent, http.StatusOK)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// DeleteEvent removes the event with the given UID from the calendar. Events which do not exist are ignored.
+func (client Client) DeleteEvent(ctx context.Context, uid string) error {
+	req, err := client.newRequest(ctx, http.MethodDelete, client.eventURL(uid), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.do(req, http.StatusNoContent, http.StatusOK, http.StatusNotFound, http.StatusGone)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (client Client) eventURL(uid string) string {
+	return client.calendarURL + url.PathEscape(uid) + ".ics"
+}
+
+func (client Client) newRequest(ctx context.Context, method, target string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if client.username != "" || client.password != "" {
+		req.SetBasicAuth(client.username, client.password)
+	}
+	return req, nil
+}
+
+// do sends the request and fails with a StatusError unless the response has one of the expected statuses.
+func (client Client) do(req *http.Request, expected ...int) (*http.Response, error) {
+	resp, err := client.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	for _, status := range expected {
+		if resp.StatusCode == status {
+			return resp, nil
+		}
+	}
+	resp.Body.Close()
+	return nil, StatusError{Method: req.Method, StatusCode: resp.StatusCode}
+}
+
+// NewClient returns a client for the calendar collection at calendarURL. Requests are sent with basic authentication
+// when a username or password is given.
+func NewClient(httpClient *http.Client, calendarURL, username, password string) Client {
+	if !strings.HasSuffix(calendarURL, "/") {
+		calendarURL += "/"
+	}
+	return Client{httpClient: httpClient, calendarURL: calendarURL, username: username, password: password}
+}
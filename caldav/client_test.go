@@ -0,0 +1,96 @@
+package caldav
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/caldav/caldavtest"
+	"github.com/harbor-xyz/coding-project/ical"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type ClientTestSuite struct {
+	suite.Suite
+	server *caldavtest.Server
+	client Client
+	ctx    context.Context
+	day    time.Time
+}
+
+func (suite *ClientTestSuite) SetupTest() {
+	suite.server = caldavtest.NewServer()
+	suite.server.Username, suite.server.Password = "host", "secret"
+	suite.client = NewClient(http.DefaultClient, suite.server.CalendarURL(), "host", "secret")
+	suite.ctx = context.Background()
+	suite.day = time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+}
+
+func (suite *ClientTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *ClientTestSuite) event(uid string, start time.Time) ical.Calendar {
+	return ical.Calendar{ProdID: "-//test//EN", Events: []ical.Event{{
+		UID:     uid,
+		Start:   start,
+		End:     start.Add(30 * time.Minute),
+		Summary: "Intro call with test",
+		Status:  ical.StatusConfirmed,
+	}}}
+}
+
+func (suite *ClientTestSuite) TestFreeBusyReturnsBusyPeriodsInRange() {
+	suite.server.AddBusy(
+		ical.Period{Start: suite.day.Add(9 * time.Hour), End: suite.day.Add(10 * time.Hour)},
+		ical.Period{Start: suite.day.AddDate(0, 0, 2), End: suite.day.AddDate(0, 0, 2).Add(time.Hour)},
+	)
+	suite.NoError(suite.client.PutEvent(suite.ctx, suite.event("event-1@test", suite.day.Add(14*time.Hour))))
+
+	periods, err := suite.client.FreeBusy(suite.ctx, suite.day, suite.day.AddDate(0, 0, 1))
+	suite.NoError(err)
+	suite.Equal([]ical.Period{
+		{Start: suite.day.Add(9 * time.Hour), End: suite.day.Add(10 * time.Hour)},
+		{Start: suite.day.Add(14 * time.Hour), End: suite.day.Add(14*time.Hour + 30*time.Minute)},
+	}, periods)
+}
+
+func (suite *ClientTestSuite) TestPutEventReplacesEventWithSameUID() {
+	suite.NoError(suite.client.PutEvent(suite.ctx, suite.event("event-1@test", suite.day.Add(9*time.Hour))))
+	suite.NoError(suite.client.PutEvent(suite.ctx, suite.event("event-1@test", suite.day.Add(11*time.Hour))))
+
+	suite.Equal(1, suite.server.EventCount())
+	event, ok := suite.server.Event("event-1@test")
+	suite.True(ok)
+	suite.Equal(suite.day.Add(11*time.Hour), event.Start)
+}
+
+func (suite *ClientTestSuite) TestDeleteEventIgnoresMissingEvents() {
+	suite.NoError(suite.client.PutEvent(suite.ctx, suite.event("event-1@test", suite.day.Add(9*time.Hour))))
+
+	suite.NoError(suite.client.DeleteEvent(suite.ctx, "event-1@test"))
+	suite.NoError(suite.client.DeleteEvent(suite.ctx, "event-1@test"))
+	suite.Equal(0, suite.server.EventCount())
+}
+
+func (suite *ClientTestSuite) TestRequestsFailWithWrongCredentials() {
+	client := NewClient(http.DefaultClient, suite.server.CalendarURL(), "host", "wrong")
+
+	_, err := client.FreeBusy(suite.ctx, suite.day, suite.day.AddDate(0, 0, 1))
+	statusErr := StatusError{}
+	suite.True(errors.As(err, &statusErr))
+	suite.Equal(http.StatusUnauthorized, statusErr.StatusCode)
+	suite.EqualError(err, "caldav: REPORT returned status 401")
+}
+
+func (suite *ClientTestSuite) TestPutEventRequiresASingleEvent() {
+	err := suite.client.PutEvent(suite.ctx, ical.Calendar{})
+	suite.EqualError(err, "caldav: a calendar resource holds a single event, got 0")
+}
+
+func TestClientTestSuite(t *testing.T) {
+	suite.Run(t, new(ClientTestSuite))
+}
@@ -0,0 +1,42 @@
+package contract
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// maxAPIKeyNameLength keeps the names of API keys short enough to be listed
+const maxAPIKeyNameLength = 100
+
+// APIKey creates a new API key, named so that the user can tell what it is used for.
+type APIKey struct {
+	Name string `json:"name"`
+}
+
+func (apiKey *APIKey) Bind(r *http.Request) error {
+	if apiKey.Name == "" {
+		return model.Validation("name", "name is required")
+	}
+
+	if len(apiKey.Name) > maxAPIKeyNameLength {
+		return model.Validation("name", "name should be at most 100 characters long")
+	}
+
+	return nil
+}
+
+// APIKeyResponse is an API key. The key itself is only returned when it is created, after that only its prefix is.
+type APIKeyResponse struct {
+	ID         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Key        string     `json:"key,omitempty"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type APIKeyList struct {
+	APIKeys []APIKeyResponse `json:"api_keys"`
+}
@@ -1,8 +1,10 @@
 package contract
 
 import (
-	"errors"
+	"fmt"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/harbor-xyz/coding-project/model"
 )
@@ -10,20 +12,105 @@ import (
 type UserAvailability struct {
 	Availability        []model.DayAvailability `json:"availability"`
 	MeetingDurationMins int                     `json:"meeting_duration_mins"`
+	// SlotIncrementMins is how often slots start, every meeting_duration_mins when not set
+	SlotIncrementMins int                   `json:"slot_increment_mins,omitempty"`
+	TimeZone          string                `json:"time_zone"`
+	SchedulingRules   model.SchedulingRules `json:"scheduling_rules"`
 }
 
 func (availability *UserAvailability) Bind(r *http.Request) error {
 	if len(availability.Availability) == 0 {
-		return errors.New("at least one day's availability is required")
+		return model.Validation("availability", "at least one day's availability is required")
+	}
+
+	if err := validateAvailability(availability.Availability); err != nil {
+		return err
 	}
 
 	if availability.MeetingDurationMins < 15 {
-		return errors.New("meeting_duration should be at least 15")
+		return model.Validation("meeting_duration_mins", "meeting_duration should be at least 15")
+	}
+
+	if err := validateSlotIncrement(availability.SlotIncrementMins); err != nil {
+		return err
+	}
+
+	if _, err := ParseTimeZone(availability.TimeZone); err != nil {
+		return model.Validation("time_zone", "invalid time_zone")
+	}
+
+	return validateSchedulingRules(availability.SchedulingRules)
+}
+
+// validateSlotIncrement makes sure that slots start at least every 5 minutes, unless the increment is not set.
+func validateSlotIncrement(increment int) error {
+	if increment != 0 && increment < 5 {
+		return model.Validation("slot_increment_mins", "slot_increment_mins should be at least 5")
+	}
+	return nil
+}
+
+// validateSchedulingRules makes sure none of the scheduling rules is negative.
+func validateSchedulingRules(rules model.SchedulingRules) error {
+	values := []struct {
+		field string
+		value int
+	}{
+		{"before_buffer_mins", rules.BeforeBufferMins},
+		{"after_buffer_mins", rules.AfterBufferMins},
+		{"min_notice_mins", rules.MinNoticeMins},
+		{"max_horizon_days", rules.MaxHorizonDays},
+		{"max_per_day", rules.MaxPerDay},
+		{"max_per_week", rules.MaxPerWeek},
+	}
+	for _, v := range values {
+		if v.value < 0 {
+			return model.Validation("scheduling_rules."+v.field, v.field+" should not be negative")
+		}
+	}
+	return nil
+}
+
+// validateAvailability makes sure every window is on a known day, ends after it starts
+// and does not overlap with any other window of the same day.
+func validateAvailability(availability []model.DayAvailability) error {
+	windows := make(map[model.Day][]model.DayAvailability)
+	for _, a := range availability {
+		if !a.Day.IsValid() {
+			return model.Validation("availability", fmt.Sprintf("invalid day: %s", a.Day))
+		}
+		if a.EndTime <= a.StartTime {
+			return model.Validation("availability", fmt.Sprintf("end_time should be after start_time for %s", a.Day))
+		}
+		windows[a.Day] = append(windows[a.Day], a)
+	}
+
+	for day, w := range windows {
+		sort.Slice(w, func(i, j int) bool { return w[i].StartTime < w[j].StartTime })
+		for i := 1; i < len(w); i++ {
+			if w[i].StartTime < w[i-1].EndTime {
+				return model.Validation("availability", fmt.Sprintf("availability windows overlap for %s", day))
+			}
+		}
 	}
 
 	return nil
 }
 
 type UserAvailabilityOverlap struct {
-	Overlap []model.DayAvailability `json:"overlap"`
+	Overlap   []model.DayAvailability `json:"overlap"`
+	Intervals []Interval              `json:"intervals,omitempty"`
+}
+
+type Interval struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+type AvailabilityOverlap struct {
+	UserIDs      []int      `json:"user_ids"`
+	From         time.Time  `json:"from"`
+	To           time.Time  `json:"to"`
+	DurationMins int        `json:"duration_mins"`
+	Intervals    []Interval `json:"intervals"`
 }
@@ -0,0 +1,73 @@
+package contract
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+const DateLayout = "2006-01-02"
+
+type AvailabilityOverride struct {
+	StartDate    string               `json:"start_date"`
+	EndDate      string               `json:"end_date"`
+	Unavailable  bool                 `json:"unavailable"`
+	Availability []model.Availability `json:"availability"`
+}
+
+func (override *AvailabilityOverride) Bind(r *http.Request) error {
+	if override.StartDate == "" {
+		return model.Validation("start_date", "start_date is required")
+	}
+	startDate, err := time.Parse(DateLayout, override.StartDate)
+	if err != nil {
+		return model.Validation("start_date", "invalid start_date")
+	}
+
+	// A single date override only needs the start date
+	if override.EndDate == "" {
+		override.EndDate = override.StartDate
+	}
+	endDate, err := time.Parse(DateLayout, override.EndDate)
+	if err != nil {
+		return model.Validation("end_date", "invalid end_date")
+	}
+	if endDate.Before(startDate) {
+		return model.Validation("end_date", "end_date should not be before start_date")
+	}
+
+	if override.Unavailable && len(override.Availability) > 0 {
+		return model.Validation("availability", "availability should be empty when unavailable is set")
+	}
+	if !override.Unavailable && len(override.Availability) == 0 {
+		return model.Validation("availability", "either availability or unavailable is required")
+	}
+
+	windows := make([]model.Availability, len(override.Availability))
+	copy(windows, override.Availability)
+	sort.Slice(windows, func(i, j int) bool { return windows[i].StartTime < windows[j].StartTime })
+	for i, w := range windows {
+		if w.EndTime <= w.StartTime {
+			return model.Validation("availability", "end_time should be after start_time")
+		}
+		if i > 0 && w.StartTime < windows[i-1].EndTime {
+			return model.Validation("availability", "availability windows overlap")
+		}
+	}
+
+	return nil
+}
+
+type AvailabilityOverrideResponse struct {
+	ID           uint                 `json:"id"`
+	StartDate    string               `json:"start_date"`
+	EndDate      string               `json:"end_date"`
+	Unavailable  bool                 `json:"unavailable"`
+	Availability []model.Availability `json:"availability"`
+}
+
+type AvailabilityOverrideList struct {
+	Overrides []AvailabilityOverrideResponse `json:"overrides"`
+}
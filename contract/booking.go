@@ -0,0 +1,19 @@
+package contract
+
+import "time"
+
+// Booking is an invitee's view of their event. It only shows what the invitee needs to know about the host.
+type Booking struct {
+	HostName      string    `json:"host_name"`
+	EventTypeName string    `json:"event_type_name,omitempty"`
+	Location      string    `json:"location,omitempty"`
+	InviteeEmail  string    `json:"invitee_email"`
+	InviteeName   string    `json:"invitee_name"`
+	InviteeNotes  string    `json:"invitee_notes"`
+	StartTime     time.Time `json:"start_time"`
+	EndTime       time.Time `json:"end_time"`
+	Status        string    `json:"status"`
+	CancelReason  string    `json:"cancel_reason,omitempty"`
+	// ManagementToken is the token to use for the booking from now on. It changes when the booking is rescheduled.
+	ManagementToken string `json:"management_token"`
+}
@@ -0,0 +1,7 @@
+package contract
+
+// CalendarFeed is the secret URL calendar apps can subscribe to for a user's events. Only the latest feed URL of a
+// user works.
+type CalendarFeed struct {
+	URL string `json:"url"`
+}
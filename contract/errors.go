@@ -1,21 +1,27 @@
 package contract
 
 import (
+	"database/sql"
+	"errors"
 	"net/http"
 
 	"github.com/go-chi/render"
+
+	"github.com/harbor-xyz/coding-project/model"
 )
 
 type ErrorResponse struct {
-	Err        error  `json:"-"`
-	StatusCode int    `json:"-"`
-	StatusText string `json:"status_text"`
-	Message    string `json:"message"`
+	Err        error             `json:"-"`
+	StatusCode int               `json:"-"`
+	StatusText string            `json:"status_text"`
+	Code       string            `json:"code"`
+	Message    string            `json:"message"`
+	Fields     map[string]string `json:"fields,omitempty"`
 }
 
 var (
-	ErrNotFound   = &ErrorResponse{StatusCode: 404, Message: "not found"}
-	ErrBadRequest = &ErrorResponse{StatusCode: 400, Message: "bad request"}
+	ErrNotFound   = &ErrorResponse{StatusCode: 404, Code: "not_found", Message: "not found"}
+	ErrBadRequest = &ErrorResponse{StatusCode: 400, Code: "bad_request", Message: "bad request"}
 )
 
 func (e *ErrorResponse) Render(w http.ResponseWriter, r *http.Request) error {
@@ -23,11 +29,13 @@ func (e *ErrorResponse) Render(w http.ResponseWriter, r *http.Request) error {
 	return nil
 }
 
+// ErrorRenderer renders requests which could not be read at all, such as malformed JSON.
 func ErrorRenderer(err error) *ErrorResponse {
 	return &ErrorResponse{
 		Err:        err,
 		StatusCode: 400,
 		StatusText: "bad request",
+		Code:       "bad_request",
 		Message:    err.Error(),
 	}
 }
@@ -37,15 +45,99 @@ func NotFoundErrorRenderer(err error) *ErrorResponse {
 		Err:        err,
 		StatusCode: 404,
 		StatusText: "not found",
+		Code:       "not_found",
 		Message:    err.Error(),
 	}
 }
 
+// ServerErrorRenderer renders unexpected errors. Their message is not reported, since it may come from the database or
+// other services.
 func ServerErrorRenderer(err error) *ErrorResponse {
 	return &ErrorResponse{
 		Err:        err,
 		StatusCode: 500,
 		StatusText: "internal server error",
+		Code:       "internal_error",
+		Message:    "internal server error",
+	}
+}
+
+func ConflictErrorRenderer(err error) *ErrorResponse {
+	return &ErrorResponse{
+		Err:        err,
+		StatusCode: 409,
+		StatusText: "conflict",
+		Code:       "conflict",
+		Message:    err.Error(),
+	}
+}
+
+func UnauthorizedErrorRenderer(err error) *ErrorResponse {
+	return &ErrorResponse{
+		Err:        err,
+		StatusCode: 401,
+		StatusText: "unauthorized",
+		Code:       "unauthorized",
+		Message:    err.Error(),
+	}
+}
+
+func ForbiddenErrorRenderer(err error) *ErrorResponse {
+	return &ErrorResponse{
+		Err:        err,
+		StatusCode: 403,
+		StatusText: "forbidden",
+		Code:       "forbidden",
 		Message:    err.Error(),
 	}
 }
+
+func UnprocessableErrorRenderer(err error) *ErrorResponse {
+	return &ErrorResponse{
+		Err:        err,
+		StatusCode: 422,
+		StatusText: "unprocessable entity",
+		Code:       "validation_failed",
+		Message:    err.Error(),
+	}
+}
+
+// DomainErrorRenderer renders an error of the model with the status of its kind. Only its own message is reported,
+// not the one of its cause.
+func DomainErrorRenderer(err *model.Error) *ErrorResponse {
+	var resp *ErrorResponse
+	switch err.Kind {
+	case model.KindNotFound:
+		resp = NotFoundErrorRenderer(err)
+	case model.KindConflict:
+		resp = ConflictErrorRenderer(err)
+	case model.KindValidation:
+		resp = UnprocessableErrorRenderer(err)
+	case model.KindForbidden:
+		resp = ForbiddenErrorRenderer(err)
+	case model.KindUnauthorized:
+		resp = UnauthorizedErrorRenderer(err)
+	default:
+		return ServerErrorRenderer(err)
+	}
+	resp.Code = err.Code
+	resp.Message = err.Message
+	resp.Fields = err.Fields
+	return resp
+}
+
+// ErrorRendererFor renders any error returned while serving a request: errors of the model with the status of their
+// kind, records which were not found with 404 and anything else as an internal error.
+func ErrorRendererFor(err error) *ErrorResponse {
+	var domainErr *model.Error
+	switch {
+	case errors.As(err, &domainErr):
+		return DomainErrorRenderer(domainErr)
+	case errors.Is(err, sql.ErrNoRows):
+		resp := NotFoundErrorRenderer(err)
+		resp.Message = "not found"
+		return resp
+	default:
+		return ServerErrorRenderer(err)
+	}
+}
@@ -1,46 +1,120 @@
 package contract
 
 import (
-	"errors"
+	"fmt"
 	"net/http"
 	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
 )
 
+// Event books either an existing slot by its ID or the free slot starting at StartTime.
 type Event struct {
-	SlotID       int    `json:"slot_id"`
-	InviteeEmail string `json:"invitee_email"`
-	InviteeName  string `json:"invitee_name"`
-	InviteeNotes string `json:"invitee_notes"`
+	SlotID       int       `json:"slot_id"`
+	StartTime    time.Time `json:"start_time"`
+	EventTypeID  int       `json:"event_type_id"`
+	InviteeEmail string    `json:"invitee_email"`
+	InviteeName  string    `json:"invitee_name"`
+	InviteeNotes string    `json:"invitee_notes"`
 }
 
 func (event *Event) Bind(r *http.Request) error {
-	if event.SlotID == 0 {
-		return errors.New("slot_id is required")
+	if event.SlotID == 0 && event.StartTime.IsZero() {
+		return model.Validation("slot_id", "either slot_id or start_time is required")
+	}
+
+	if event.SlotID != 0 && !event.StartTime.IsZero() {
+		return model.Validation("slot_id", "only one of slot_id and start_time should be given")
 	}
 
 	if event.InviteeEmail == "" {
-		return errors.New("invitee_email is required")
+		return model.Validation("invitee_email", "invitee_email is required")
 	}
 
 	if event.InviteeName == "" {
-		return errors.New("invitee_name is required")
+		return model.Validation("invitee_name", "invitee_name is required")
+	}
+
+	return nil
+}
+
+// CancelEvent cancels an event, optionally giving a reason.
+type CancelEvent struct {
+	Reason string `json:"reason"`
+}
+
+func (cancel *CancelEvent) Bind(r *http.Request) error {
+	if len(cancel.Reason) > maxReasonLength {
+		return model.Validation("reason", fmt.Sprintf("reason should be at most %d characters", maxReasonLength))
+	}
+
+	return nil
+}
+
+// RescheduleEvent moves an event to either an existing slot by its ID or the free slot starting at StartTime.
+type RescheduleEvent struct {
+	SlotID    int       `json:"slot_id"`
+	StartTime time.Time `json:"start_time"`
+	Reason    string    `json:"reason"`
+}
+
+func (reschedule *RescheduleEvent) Bind(r *http.Request) error {
+	if reschedule.SlotID == 0 && reschedule.StartTime.IsZero() {
+		return model.Validation("slot_id", "either slot_id or start_time is required")
+	}
+
+	if reschedule.SlotID != 0 && !reschedule.StartTime.IsZero() {
+		return model.Validation("slot_id", "only one of slot_id and start_time should be given")
+	}
+
+	if len(reschedule.Reason) > maxReasonLength {
+		return model.Validation("reason", fmt.Sprintf("reason should be at most %d characters", maxReasonLength))
 	}
 
 	return nil
 }
 
+const maxReasonLength = 500
+
 type EventResponse struct {
-	ID           int       `json:"id"`
-	UserID       int       `json:"user_id"`
-	SlotID       int       `json:"slot_id"`
+	ID          int `json:"id"`
+	UserID      int `json:"user_id"`
+	SlotID      int `json:"slot_id"`
+	EventTypeID int `json:"event_type_id"`
+	// TeamEventTypeID is set for bookings made through an event type of a team
+	TeamEventTypeID int `json:"team_event_type_id,omitempty"`
+	// SeriesID is set for the occurrences of a recurring booking
+	SeriesID     int       `json:"series_id,omitempty"`
 	InviteeEmail string    `json:"invitee_email"`
 	InviteeName  string    `json:"invitee_name"`
 	InviteeNotes string    `json:"invitee_notes"`
 	StartTime    time.Time `json:"start_time"`
 	EndTime      time.Time `json:"end_time"`
+	Status       string    `json:"status"`
+	CancelReason string    `json:"cancel_reason,omitempty"`
 	CreatedAt    time.Time `json:"created_at"`
+	// ManagementToken lets the invitee view, cancel and reschedule the booking through /bookings/{token} until the
+	// event is rescheduled or ends
+	ManagementToken string `json:"management_token,omitempty"`
 }
 
 type EventListResponse struct {
 	Events []EventResponse `json:"events"`
 }
+
+// EventChange is a cancellation or a reschedule of an event. StartTime and EndTime are only set for reschedules.
+type EventChange struct {
+	Action            string     `json:"action"`
+	ChangedBy         string     `json:"changed_by"`
+	Reason            string     `json:"reason,omitempty"`
+	PreviousSlotID    int        `json:"previous_slot_id"`
+	PreviousStartTime time.Time  `json:"previous_start_time"`
+	PreviousEndTime   time.Time  `json:"previous_end_time"`
+	StartTime         *time.Time `json:"start_time,omitempty"`
+	EndTime           *time.Time `json:"end_time,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+type EventChangeList struct {
+	Changes []EventChange `json:"changes"`
+}
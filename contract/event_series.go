@@ -0,0 +1,102 @@
+package contract
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// EventSeries books the free slots starting at StartTime and repeating at the given frequency, either Count times or
+// for as long as they start no later than Until. Every occurrence is booked, or none of them is.
+type EventSeries struct {
+	EventTypeID int       `json:"event_type_id"`
+	StartTime   time.Time `json:"start_time"`
+	// Frequency is one of weekly, biweekly or monthly. Monthly occurrences fall on the day of the month of StartTime,
+	// skipping months without that day.
+	Frequency    string    `json:"frequency"`
+	Count        int       `json:"count,omitempty"`
+	Until        time.Time `json:"until"`
+	InviteeEmail string    `json:"invitee_email"`
+	InviteeName  string    `json:"invitee_name"`
+	InviteeNotes string    `json:"invitee_notes"`
+}
+
+func (series *EventSeries) Bind(r *http.Request) error {
+	if series.StartTime.IsZero() {
+		return model.Validation("start_time", "start_time is required")
+	}
+
+	if err := validateFrequency(series.Frequency); err != nil {
+		return err
+	}
+
+	if series.Count == 0 && series.Until.IsZero() {
+		return model.Validation("count", "either count or until is required")
+	}
+
+	if series.Count != 0 && !series.Until.IsZero() {
+		return model.Validation("count", "only one of count and until should be given")
+	}
+
+	if series.Count != 0 && (series.Count < 2 || series.Count > model.MaxOccurrences) {
+		return model.Validation("count", fmt.Sprintf("count should be between 2 and %d", model.MaxOccurrences))
+	}
+
+	if !series.Until.IsZero() && !series.Until.After(series.StartTime) {
+		return model.Validation("until", "until should be after start_time")
+	}
+
+	if series.InviteeEmail == "" {
+		return model.Validation("invitee_email", "invitee_email is required")
+	}
+
+	if series.InviteeName == "" {
+		return model.Validation("invitee_name", "invitee_name is required")
+	}
+
+	return nil
+}
+
+func validateFrequency(frequency string) error {
+	switch frequency {
+	case model.FrequencyWeekly, model.FrequencyBiweekly, model.FrequencyMonthly:
+		return nil
+	}
+	return model.Validation("frequency", "frequency should be one of weekly, biweekly or monthly")
+}
+
+// RescheduleSeries moves the upcoming occurrences of a series to the free slots starting at StartTime and repeating
+// at the frequency of the series.
+type RescheduleSeries struct {
+	StartTime time.Time `json:"start_time"`
+	Reason    string    `json:"reason"`
+}
+
+func (reschedule *RescheduleSeries) Bind(r *http.Request) error {
+	if reschedule.StartTime.IsZero() {
+		return model.Validation("start_time", "start_time is required")
+	}
+
+	if len(reschedule.Reason) > maxReasonLength {
+		return model.Validation("reason", fmt.Sprintf("reason should be at most %d characters", maxReasonLength))
+	}
+
+	return nil
+}
+
+type EventSeriesResponse struct {
+	ID           int        `json:"id"`
+	UserID       int        `json:"user_id"`
+	EventTypeID  int        `json:"event_type_id"`
+	Frequency    string     `json:"frequency"`
+	Count        int        `json:"count,omitempty"`
+	Until        *time.Time `json:"until,omitempty"`
+	InviteeEmail string     `json:"invitee_email"`
+	InviteeName  string     `json:"invitee_name"`
+	InviteeNotes string     `json:"invitee_notes"`
+	CreatedAt    time.Time  `json:"created_at"`
+	// Events are the occurrences of the series, earliest first, including the cancelled ones
+	Events []EventResponse `json:"events"`
+}
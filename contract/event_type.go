@@ -0,0 +1,75 @@
+package contract
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+type EventType struct {
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	DurationMins int    `json:"duration_mins"`
+	// SlotIncrementMins replaces the user's slot increment for this event type when given
+	SlotIncrementMins int `json:"slot_increment_mins,omitempty"`
+	// Capacity is how many invitees can book each slot, one when not given
+	Capacity     int                     `json:"capacity,omitempty"`
+	Description  string                  `json:"description"`
+	Location     string                  `json:"location"`
+	Availability []model.DayAvailability `json:"availability"`
+	// SchedulingRules replace the user's scheduling rules for this event type when given
+	SchedulingRules *model.SchedulingRules `json:"scheduling_rules,omitempty"`
+}
+
+func (eventType *EventType) Bind(r *http.Request) error {
+	if eventType.Name == "" {
+		return model.Validation("name", "name is required")
+	}
+
+	if !slugPattern.MatchString(eventType.Slug) {
+		return model.Validation("slug", "slug should only contain lowercase letters, digits and hyphens")
+	}
+
+	if eventType.DurationMins < 15 {
+		return model.Validation("duration_mins", "duration_mins should be at least 15")
+	}
+
+	if err := validateSlotIncrement(eventType.SlotIncrementMins); err != nil {
+		return err
+	}
+
+	if eventType.Capacity < 0 {
+		return model.Validation("capacity", "capacity should not be negative")
+	}
+
+	if eventType.SchedulingRules != nil {
+		if err := validateSchedulingRules(*eventType.SchedulingRules); err != nil {
+			return err
+		}
+	}
+
+	return validateAvailability(eventType.Availability)
+}
+
+type EventTypeResponse struct {
+	ID                uint                    `json:"id"`
+	UserID            uint                    `json:"user_id"`
+	Name              string                  `json:"name"`
+	Slug              string                  `json:"slug"`
+	DurationMins      int                     `json:"duration_mins"`
+	SlotIncrementMins int                     `json:"slot_increment_mins,omitempty"`
+	Capacity          int                     `json:"capacity,omitempty"`
+	Description       string                  `json:"description"`
+	Location          string                  `json:"location"`
+	Availability      []model.DayAvailability `json:"availability"`
+	SchedulingRules   *model.SchedulingRules  `json:"scheduling_rules,omitempty"`
+	CreatedAt         time.Time               `json:"created_at"`
+}
+
+type EventTypeList struct {
+	EventTypes []EventTypeResponse `json:"event_types"`
+}
@@ -0,0 +1,79 @@
+package contract
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// ExternalCalendar is a calendar kept outside of the app whose events block the user's time. ICS calendars with a URL
+// are fetched from there, the others are imported by uploading ICS files. CalDAV calendars are read through
+// free/busy queries to the calendar collection at the URL, and can have the user's bookings written back to them.
+type ExternalCalendar struct {
+	Name      string `json:"name"`
+	Provider  string `json:"provider"`
+	URL       string `json:"url"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	WriteBack bool   `json:"write_back"`
+}
+
+func (calendar *ExternalCalendar) Bind(r *http.Request) error {
+	if calendar.Name == "" {
+		return model.Validation("name", "name is required")
+	}
+
+	switch calendar.Provider {
+	case "":
+		calendar.Provider = model.ProviderICS
+	case model.ProviderICS, model.ProviderCalDAV:
+	default:
+		return model.Validation("provider", "provider should be one of ics and caldav")
+	}
+
+	if calendar.Provider == model.ProviderCalDAV && calendar.URL == "" {
+		return model.Validation("url", "url is required for caldav calendars")
+	}
+	if calendar.WriteBack && calendar.Provider != model.ProviderCalDAV {
+		return model.Validation("write_back", "write_back is only supported by caldav calendars")
+	}
+
+	if calendar.URL != "" {
+		parsed, err := url.Parse(calendar.URL)
+		if err != nil || parsed.Host == "" {
+			return model.Validation("url", "url should be an absolute http, https or webcal URL")
+		}
+		switch strings.ToLower(parsed.Scheme) {
+		case "http", "https":
+		case "webcal":
+			if calendar.Provider == model.ProviderCalDAV {
+				return model.Validation("url", "url of caldav calendars should be an http or https URL")
+			}
+		default:
+			return model.Validation("url", "url should be an absolute http, https or webcal URL")
+		}
+	}
+
+	return nil
+}
+
+// ExternalCalendarResponse is an external calendar, whose password is never returned.
+type ExternalCalendarResponse struct {
+	ID        uint       `json:"id"`
+	UserID    uint       `json:"user_id"`
+	Name      string     `json:"name"`
+	Provider  string     `json:"provider"`
+	URL       string     `json:"url,omitempty"`
+	Username  string     `json:"username,omitempty"`
+	WriteBack bool       `json:"write_back"`
+	SyncedAt  *time.Time `json:"synced_at,omitempty"`
+	// BusyBlocks is the number of busy blocks imported by the last sync, only returned by imports and syncs
+	BusyBlocks *int `json:"busy_blocks,omitempty"`
+}
+
+type ExternalCalendarList struct {
+	ExternalCalendars []ExternalCalendarResponse `json:"external_calendars"`
+}
@@ -0,0 +1,24 @@
+package contract
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// JobResponse is a background job along with the outcome of its last run.
+type JobResponse struct {
+	ID          uint            `json:"id"`
+	Kind        string          `json:"kind"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       *time.Time      `json:"run_at,omitempty"`
+	LastError   string          `json:"last_error,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	Payload     json.RawMessage `json:"payload" swaggertype:"object"`
+}
+
+type JobList struct {
+	Jobs []JobResponse `json:"jobs"`
+}
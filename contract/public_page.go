@@ -0,0 +1,62 @@
+package contract
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// PublicPage is a user's public booking page. It only shows what invitees need to pick a time.
+type PublicPage struct {
+	HostName   string            `json:"host_name"`
+	TimeZone   string            `json:"time_zone"`
+	EventTypes []PublicEventType `json:"event_types"`
+}
+
+type PublicEventType struct {
+	Name         string `json:"name"`
+	Slug         string `json:"slug"`
+	DurationMins int    `json:"duration_mins"`
+	Description  string `json:"description,omitempty"`
+	Location     string `json:"location,omitempty"`
+}
+
+// PublicSlot is a time which can be booked through the public booking page.
+type PublicSlot struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+type PublicSlotList struct {
+	Slots []PublicSlot `json:"slots"`
+}
+
+// PublicBooking books the free slot of an event type starting at StartTime through the public booking page.
+type PublicBooking struct {
+	EventTypeSlug string    `json:"event_type_slug"`
+	StartTime     time.Time `json:"start_time"`
+	InviteeEmail  string    `json:"invitee_email"`
+	InviteeName   string    `json:"invitee_name"`
+	InviteeNotes  string    `json:"invitee_notes"`
+}
+
+func (booking *PublicBooking) Bind(r *http.Request) error {
+	if booking.EventTypeSlug == "" {
+		return model.Validation("event_type_slug", "event_type_slug is required")
+	}
+
+	if booking.StartTime.IsZero() {
+		return model.Validation("start_time", "start_time is required")
+	}
+
+	if booking.InviteeEmail == "" {
+		return model.Validation("invitee_email", "invitee_email is required")
+	}
+
+	if booking.InviteeName == "" {
+		return model.Validation("invitee_name", "invitee_name is required")
+	}
+
+	return nil
+}
@@ -2,12 +2,17 @@ package contract
 
 import "time"
 
+// Slot is a bookable time. Slots computed on demand have no ID and are booked through their start time. Slots of group
+// event types stay bookable, by their ID or their start time, until every seat is booked.
 type Slot struct {
-	ID        int       `json:"id"`
-	UserID    uint      `json:"user_id"`
-	StartTime time.Time `json:"start_time"`
-	EndTime   time.Time `json:"end_time"`
-	Status    string    `json:"status"`
+	ID          int       `json:"id,omitempty"`
+	UserID      uint      `json:"user_id"`
+	EventTypeID uint      `json:"event_type_id"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	Status      string    `json:"status"`
+	// RemainingSeats is how many more invitees can book the slot
+	RemainingSeats int `json:"remaining_seats"`
 }
 
 type SlotList struct {
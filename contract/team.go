@@ -0,0 +1,179 @@
+package contract
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// Team creates a team, optionally along with its first members.
+type Team struct {
+	Name    string       `json:"name"`
+	Members []TeamMember `json:"members"`
+}
+
+func (team *Team) Bind(r *http.Request) error {
+	if team.Name == "" {
+		return model.Validation("name", "name is required")
+	}
+
+	seen := make(map[int]bool)
+	for i := range team.Members {
+		if err := team.Members[i].Bind(r); err != nil {
+			return err
+		}
+		if seen[team.Members[i].UserID] {
+			return model.Validation("members", fmt.Sprintf("user %d is given more than once", team.Members[i].UserID))
+		}
+		seen[team.Members[i].UserID] = true
+	}
+
+	return nil
+}
+
+// TeamMember adds a user to a team. Round robin bookings go to the free members of the highest priority first.
+type TeamMember struct {
+	UserID   int `json:"user_id"`
+	Priority int `json:"priority"`
+}
+
+func (member *TeamMember) Bind(r *http.Request) error {
+	if member.UserID <= 0 {
+		return model.Validation("user_id", "user_id is required")
+	}
+
+	if member.Priority < 0 {
+		return model.Validation("priority", "priority should not be negative")
+	}
+
+	return nil
+}
+
+type TeamResponse struct {
+	ID        uint                 `json:"id"`
+	OwnerID   uint                 `json:"owner_id"`
+	Name      string               `json:"name"`
+	Members   []TeamMemberResponse `json:"members"`
+	CreatedAt time.Time            `json:"created_at"`
+}
+
+// TeamMemberResponse tells how many bookings of the team a member got, and when they were booked last. Members are
+// only booked through the team once they have accepted.
+type TeamMemberResponse struct {
+	UserID       uint       `json:"user_id"`
+	Priority     int        `json:"priority"`
+	Accepted     bool       `json:"accepted"`
+	Bookings     int        `json:"bookings"`
+	LastBookedAt *time.Time `json:"last_booked_at,omitempty"`
+}
+
+type TeamList struct {
+	Teams []TeamResponse `json:"teams"`
+}
+
+// TeamMembership is a team a user has been added to, which only books them once they have accepted.
+type TeamMembership struct {
+	TeamID   uint   `json:"team_id"`
+	TeamName string `json:"team_name"`
+	OwnerID  uint   `json:"owner_id"`
+	Priority int    `json:"priority"`
+	Accepted bool   `json:"accepted"`
+}
+
+type TeamMembershipList struct {
+	Memberships []TeamMembership `json:"memberships"`
+}
+
+// TeamEventType is a kind of meeting a team offers, either with all of its members or with one of them in turns.
+type TeamEventType struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+	// SchedulingType is either collective, offering the times when every member is free, or round_robin, offering
+	// the times when any member is free
+	SchedulingType string `json:"scheduling_type"`
+	DurationMins   int    `json:"duration_mins"`
+	// SlotIncrementMins replaces the members' slot increments for this event type when given
+	SlotIncrementMins int    `json:"slot_increment_mins,omitempty"`
+	Description       string `json:"description"`
+	Location          string `json:"location"`
+}
+
+func (eventType *TeamEventType) Bind(r *http.Request) error {
+	if eventType.Name == "" {
+		return model.Validation("name", "name is required")
+	}
+
+	if !slugPattern.MatchString(eventType.Slug) {
+		return model.Validation("slug", "slug should only contain lowercase letters, digits and hyphens")
+	}
+
+	if eventType.SchedulingType != model.SchedulingCollective && eventType.SchedulingType != model.SchedulingRoundRobin {
+		return model.Validation("scheduling_type", "scheduling_type should be either collective or round_robin")
+	}
+
+	if eventType.DurationMins < 15 {
+		return model.Validation("duration_mins", "duration_mins should be at least 15")
+	}
+
+	return validateSlotIncrement(eventType.SlotIncrementMins)
+}
+
+type TeamEventTypeResponse struct {
+	ID                uint      `json:"id"`
+	TeamID            uint      `json:"team_id"`
+	Name              string    `json:"name"`
+	Slug              string    `json:"slug"`
+	SchedulingType    string    `json:"scheduling_type"`
+	DurationMins      int       `json:"duration_mins"`
+	SlotIncrementMins int       `json:"slot_increment_mins,omitempty"`
+	Description       string    `json:"description"`
+	Location          string    `json:"location"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type TeamEventTypeList struct {
+	EventTypes []TeamEventTypeResponse `json:"event_types"`
+}
+
+// TeamSlot is a bookable time of a team event type, along with the members who are free then: every member for
+// collective event types, and the ones a round robin booking can go to otherwise.
+type TeamSlot struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	UserIDs   []uint    `json:"user_ids"`
+}
+
+type TeamSlotList struct {
+	Slots []TeamSlot `json:"slots"`
+}
+
+// TeamEvent books the free time of a team event type starting at StartTime.
+type TeamEvent struct {
+	TeamEventTypeID int       `json:"team_event_type_id"`
+	StartTime       time.Time `json:"start_time"`
+	InviteeEmail    string    `json:"invitee_email"`
+	InviteeName     string    `json:"invitee_name"`
+	InviteeNotes    string    `json:"invitee_notes"`
+}
+
+func (event *TeamEvent) Bind(r *http.Request) error {
+	if event.TeamEventTypeID <= 0 {
+		return model.Validation("team_event_type_id", "team_event_type_id is required")
+	}
+
+	if event.StartTime.IsZero() {
+		return model.Validation("start_time", "start_time is required")
+	}
+
+	if event.InviteeEmail == "" {
+		return model.Validation("invitee_email", "invitee_email is required")
+	}
+
+	if event.InviteeName == "" {
+		return model.Validation("invitee_name", "invitee_name is required")
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package contract
+
+import (
+	"errors"
+	"time"
+)
+
+// ParseTimeZone loads an IANA time zone by name. An empty name resolves to UTC.
+// The server's local zone is rejected since it is meaningless to API consumers.
+func ParseTimeZone(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, errors.New("invalid time zone")
+	}
+	return time.LoadLocation(name)
+}
@@ -1,27 +1,96 @@
 package contract
 
 import (
-	"errors"
 	"net/http"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
 )
 
 type User struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	Name     string `json:"name"`
+	Email    string `json:"email"`
+	TimeZone string `json:"time_zone"`
+	// Slug is the path of the user's public booking page. One is derived from the name when it is not given.
+	Slug string `json:"slug"`
 }
 
 func (user *User) Bind(r *http.Request) error {
 	if user.Name == "" {
-		return errors.New("name is required")
+		return model.Validation("name", "name is required")
 	}
 
 	if user.Email == "" {
-		return errors.New("email is required")
+		return model.Validation("email", "email is required")
+	}
+
+	if _, err := ParseTimeZone(user.TimeZone); err != nil {
+		return model.Validation("time_zone", "invalid time_zone")
+	}
+
+	if user.Slug != "" && !slugPattern.MatchString(user.Slug) {
+		return model.Validation("slug", "slug should only contain lowercase letters, digits and hyphens")
 	}
 
 	return nil
 }
 
+// UserResponse is a user. APIKey authenticates the requests of the user, and is only returned when the user is created.
 type UserResponse struct {
-	ID uint `json:"id"`
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	TimeZone  string    `json:"time_zone"`
+	Slug      string    `json:"slug"`
+	APIKey    string    `json:"api_key,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UserUpdate changes the given fields of a user, leaving out the others.
+type UserUpdate struct {
+	Name     *string `json:"name"`
+	Email    *string `json:"email"`
+	TimeZone *string `json:"time_zone"`
+	Slug     *string `json:"slug"`
+}
+
+func (update *UserUpdate) Bind(r *http.Request) error {
+	if update.Name != nil && *update.Name == "" {
+		return model.Validation("name", "name should not be empty")
+	}
+
+	if update.Email != nil && *update.Email == "" {
+		return model.Validation("email", "email should not be empty")
+	}
+
+	if update.TimeZone != nil {
+		if _, err := ParseTimeZone(*update.TimeZone); err != nil || *update.TimeZone == "" {
+			return model.Validation("time_zone", "invalid time_zone")
+		}
+	}
+
+	if update.Slug != nil && !slugPattern.MatchString(*update.Slug) {
+		return model.Validation("slug", "slug should only contain lowercase letters, digits and hyphens")
+	}
+
+	return nil
+}
+
+// UserList is a page of users, along with how many users there are across all pages.
+type UserList struct {
+	Users []UserResponse `json:"users"`
+	Total int64          `json:"total"`
+}
+
+// UserSlug changes the path of a user's public booking page.
+type UserSlug struct {
+	Slug string `json:"slug"`
+}
+
+func (userSlug *UserSlug) Bind(r *http.Request) error {
+	if !slugPattern.MatchString(userSlug.Slug) {
+		return model.Validation("slug", "slug should only contain lowercase letters, digits and hyphens")
+	}
+
+	return nil
 }
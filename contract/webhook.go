@@ -0,0 +1,98 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// minWebhookSecretLength keeps secrets given by users long enough to not be guessed
+const minWebhookSecretLength = 16
+
+// Webhook subscribes a URL to events of the user. Without a secret a random one is generated.
+type Webhook struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+func (webhook *Webhook) Bind(r *http.Request) error {
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || parsed.Host == "" {
+		return model.Validation("url", "url should be an absolute http or https URL")
+	}
+	if scheme := strings.ToLower(parsed.Scheme); scheme != "http" && scheme != "https" {
+		return model.Validation("url", "url should be an absolute http or https URL")
+	}
+
+	if webhook.Secret != "" && len(webhook.Secret) < minWebhookSecretLength {
+		return model.Validation("secret", fmt.Sprintf("secret should be at least %d characters long", minWebhookSecretLength))
+	}
+
+	if len(webhook.Events) == 0 {
+		return model.Validation("events", "at least one event is required")
+	}
+	for _, event := range webhook.Events {
+		if !isWebhookEvent(event) {
+			return model.Validation("events", fmt.Sprintf("event should be one of %s", strings.Join(model.WebhookEvents, ", ")))
+		}
+	}
+
+	return nil
+}
+
+func isWebhookEvent(event string) bool {
+	for _, e := range model.WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookResponse is a webhook. Its secret is only returned when the webhook is created.
+type WebhookResponse struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"user_id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookList struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+// WebhookPayload is the body POSTed to webhooks. Data is an EventResponse for booking events and a
+// UserAvailability for availability.updated.
+type WebhookPayload struct {
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// WebhookDelivery is a payload delivered, or still to be delivered, to a webhook along with the outcome of its last
+// attempt.
+type WebhookDelivery struct {
+	ID             uint            `json:"id"`
+	WebhookID      uint            `json:"webhook_id"`
+	Event          string          `json:"event"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	ResponseStatus int             `json:"response_status,omitempty"`
+	Error          string          `json:"error,omitempty"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	Payload        json.RawMessage `json:"payload" swaggertype:"object"`
+}
+
+type WebhookDeliveryList struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
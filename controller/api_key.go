@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/harbor-xyz/coding-project/contract"
+)
+
+type APIKey struct {
+	apiKeyService APIKeyService
+}
+
+// Create - Creates an API key
+// @Summary This API creates a new API key for the user. The key is only returned here, only its prefix is returned afterwards.
+// @Tags api_key
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param api_key body contract.APIKey true "Add API key"
+// @Param user_id path int true "user id"
+// @Success 201 {object} contract.APIKeyResponse
+// @Router /users/{user_id}/api_keys [post]
+func (apiKey APIKey) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := contract.APIKey{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := apiKey.apiKeyService.Create(ctx, userID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+// GetAll - Gets a user's API keys
+// @Summary This API returns all API keys of a user, without the keys themselves
+// @Tags api_key
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Success 200 {object} contract.APIKeyList
+// @Router /users/{user_id}/api_keys [get]
+func (apiKey APIKey) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := apiKey.apiKeyService.GetAll(ctx, userID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Revoke - Revokes an API key
+// @Summary This API revokes an API key of a user, which stops authenticating requests straight away
+// @Tags api_key
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param api_key_id path int true "API key id"
+// @Router /users/{user_id}/api_keys/{api_key_id} [delete]
+func (apiKey APIKey) Revoke(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	apiKeyID, err := idFromURL(r, "apiKeyID", "API key ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	err = apiKey.apiKeyService.Revoke(ctx, userID, apiKeyID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func NewAPIKey(apiKeyService APIKeyService) APIKey {
+	return APIKey{apiKeyService: apiKeyService}
+}
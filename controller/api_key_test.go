@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+
+	"github.com/go-chi/chi"
+	"github.com/harbor-xyz/coding-project/model"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyTestSuite struct {
+	suite.Suite
+	controller        APIKey
+	mockAPIKeyService *MockAPIKeyService
+}
+
+func (suite *APIKeyTestSuite) SetupTest() {
+	suite.mockAPIKeyService = &MockAPIKeyService{}
+	suite.controller = NewAPIKey(suite.mockAPIKeyService)
+}
+
+func (suite *APIKeyTestSuite) request(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("apiKeyID", "2")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	return req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+}
+
+func (suite *APIKeyTestSuite) readBody(res *http.Response) string {
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	return string(body)
+}
+
+func (suite *APIKeyTestSuite) TestCreateHappyFlow() {
+	createdAt := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/users/1/api_keys", strings.NewReader(`{"name":"ci"}`))
+	req.Header.Add("Content-Type", "application/json")
+	suite.mockAPIKeyService.On("Create", req.Context(), 1, contract.APIKey{Name: "ci"}).
+		Return(contract.APIKeyResponse{ID: 2, Name: "ci", Prefix: "cal_abcdefgh", Key: "cal_abcdefghijkl", CreatedAt: createdAt}, nil)
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusCreated, res.StatusCode)
+	suite.Equal(`{"id":2,"name":"ci","prefix":"cal_abcdefgh","key":"cal_abcdefghijkl","created_at":"2023-06-01T00:00:00Z"}
+`, suite.readBody(res))
+}
+
+func (suite *APIKeyTestSuite) TestCreateReturnsUnprocessableEntityWithoutName() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/users/1/api_keys", strings.NewReader(`{}`))
+	req.Header.Add("Content-Type", "application/json")
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"name is required","fields":{"name":"name is required"}}
+`, suite.readBody(res))
+	suite.mockAPIKeyService.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *APIKeyTestSuite) TestRevokeHappyFlow() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodDelete, "/users/1/api_keys/2", nil)
+	suite.mockAPIKeyService.On("Revoke", req.Context(), 1, 2).Return(nil)
+
+	suite.controller.Revoke(w, req)
+
+	suite.Equal(http.StatusNoContent, w.Result().StatusCode)
+}
+
+func (suite *APIKeyTestSuite) TestRevokeReturnsNotFoundIfKeyDoesNotExist() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodDelete, "/users/1/api_keys/2", nil)
+	suite.mockAPIKeyService.On("Revoke", req.Context(), 1, 2).Return(model.NotFound("API key", sql.ErrNoRows))
+
+	suite.controller.Revoke(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(`{"status_text":"not found","code":"api_key_not_found","message":"API key not found"}
+`, suite.readBody(res))
+}
+
+func TestAPIKeyTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyTestSuite))
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/harbor-xyz/coding-project/contract"
+)
+
+// Booking serves the public endpoints invitees use to manage their booking through its management token.
+type Booking struct {
+	bookingService BookingService
+}
+
+// Get - Returns a booking
+// @Summary This API returns the booking a management token was issued for.
+// @Tags booking
+// @Accept  json
+// @Produce  json
+// @Param token path string true "management token"
+// @Param tz query string false "IANA time zone to render times in"
+// @Success 200 {object} contract.Booking
+// @Router /bookings/{token} [get]
+func (booking Booking) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	loc, err := timeZoneFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := booking.bookingService.GetBooking(ctx, chi.URLParam(r, "token"), loc)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Cancel - Cancels a booking
+// @Summary This API cancels the booking a management token was issued for, on behalf of the invitee.
+// @Tags booking
+// @Accept  json
+// @Produce  json
+// @Param cancel body contract.CancelEvent false "Cancel booking"
+// @Param token path string true "management token"
+// @Success 200 {object} contract.Booking
+// @Router /bookings/{token}/cancel [post]
+func (booking Booking) Cancel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := contract.CancelEvent{}
+	if r.ContentLength != 0 {
+		if err := render.Bind(r, &input); err != nil {
+			renderBindError(w, r, err)
+			return
+		}
+	}
+
+	resp, err := booking.bookingService.CancelBooking(ctx, chi.URLParam(r, "token"), input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Reschedule - Reschedules a booking
+// @Summary This API moves the booking a management token was issued for to another slot of the host, on behalf of the invitee. The response carries the token to use from now on.
+// @Tags booking
+// @Accept  json
+// @Produce  json
+// @Param reschedule body contract.RescheduleEvent true "Reschedule booking"
+// @Param token path string true "management token"
+// @Success 200 {object} contract.Booking
+// @Router /bookings/{token}/reschedule [post]
+func (booking Booking) Reschedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := contract.RescheduleEvent{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	resp, err := booking.bookingService.RescheduleBooking(ctx, chi.URLParam(r, "token"), input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func NewBooking(bookingService BookingService) Booking {
+	return Booking{bookingService: bookingService}
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/suite"
+)
+
+type BookingTestSuite struct {
+	suite.Suite
+	controller         Booking
+	mockBookingService *MockBookingService
+}
+
+func (suite *BookingTestSuite) SetupTest() {
+	suite.mockBookingService = &MockBookingService{}
+	suite.controller = NewBooking(suite.mockBookingService)
+}
+
+func withToken(req *http.Request, token string) *http.Request {
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("token", token)
+	return req.WithContext(context.WithValue(context.Background(), chi.RouteCtxKey, rctx))
+}
+
+func (suite *BookingTestSuite) TestGetHappyFlow() {
+	req := withToken(httptest.NewRequest(http.MethodGet, "/bookings/abc.def", nil), "abc.def")
+	w := httptest.NewRecorder()
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	suite.mockBookingService.On("GetBooking", req.Context(), "abc.def", (*time.Location)(nil)).Return(contract.Booking{
+		HostName: "host", InviteeEmail: "test@example.xyz", InviteeName: "test", StartTime: start, EndTime: start.Add(30 * time.Minute),
+		Status: "confirmed", ManagementToken: "abc.def",
+	}, nil)
+
+	suite.controller.Get(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal(`{"host_name":"host","invitee_email":"test@example.xyz","invitee_name":"test","invitee_notes":"","start_time":"2023-06-05T09:00:00Z","end_time":"2023-06-05T09:30:00Z","status":"confirmed","management_token":"abc.def"}
+`, string(body))
+}
+
+func (suite *BookingTestSuite) TestGetReturnsNotFoundForInvalidToken() {
+	req := withToken(httptest.NewRequest(http.MethodGet, "/bookings/abc.def", nil), "abc.def")
+	w := httptest.NewRecorder()
+	suite.mockBookingService.On("GetBooking", req.Context(), "abc.def", (*time.Location)(nil)).Return(contract.Booking{}, model.ErrInvalidToken)
+
+	suite.controller.Get(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(`{"status_text":"not found","code":"invalid_token","message":"booking token is invalid or has expired"}
+`, string(body))
+}
+
+func (suite *BookingTestSuite) TestCancelReturnsConflictWhenEventHasStarted() {
+	req := withToken(httptest.NewRequest(http.MethodPost, "/bookings/abc.def/cancel", strings.NewReader(`{"reason":"sick"}`)), "abc.def")
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.mockBookingService.On("CancelBooking", req.Context(), "abc.def", contract.CancelEvent{Reason: "sick"}).
+		Return(contract.Booking{}, model.ErrEventStarted)
+
+	suite.controller.Cancel(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusConflict, res.StatusCode)
+	suite.Equal(`{"status_text":"conflict","code":"event_started","message":"event has already started"}
+`, string(body))
+}
+
+func (suite *BookingTestSuite) TestRescheduleReturnsUnprocessableEntityForInvalidBody() {
+	req := withToken(httptest.NewRequest(http.MethodPost, "/bookings/abc.def/reschedule",
+		strings.NewReader(`{"slot_id":6,"start_time":"2023-06-05T09:00:00Z"}`)), "abc.def")
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.controller.Reschedule(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"only one of slot_id and start_time should be given","fields":{"slot_id":"only one of slot_id and start_time should be given"}}
+`, string(body))
+	suite.mockBookingService.AssertNotCalled(suite.T(), "RescheduleBooking")
+}
+
+func TestBookingTestSuite(t *testing.T) {
+	suite.Run(t, new(BookingTestSuite))
+}
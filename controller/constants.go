@@ -1,8 +1,25 @@
 package controller
 
+import "time"
+
 type contextKey string
 
 const (
 	ContextUserIDKey contextKey = "userID"
 	ContextSlotIDKey contextKey = "slotID"
+	// ContextCallerIDKey holds the ID of the authenticated user making the request
+	ContextCallerIDKey contextKey = "callerID"
+)
+
+// maxOverlapRange limits how far apart from and to can be when computing free time across users
+const maxOverlapRange = 90 * 24 * time.Hour
+
+// maxSlotRange limits how far apart from and to can be when computing free slots
+const maxSlotRange = 90 * 24 * time.Hour
+
+const (
+	// defaultPageSize is how many items a page of a list has when no limit is given
+	defaultPageSize = 20
+	// maxPageSize bounds the limit of a page of a list
+	maxPageSize = 100
 )
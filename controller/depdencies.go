@@ -2,6 +2,8 @@ package controller
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/harbor-xyz/coding-project/contract"
 	"github.com/harbor-xyz/coding-project/model"
@@ -9,18 +11,104 @@ import (
 
 type UserService interface {
 	Create(context.Context, contract.User) (contract.UserResponse, error)
+	Get(context.Context, int) (contract.UserResponse, error)
+	GetAll(context.Context, string, int, int) (contract.UserList, error)
+	Update(context.Context, int, contract.UserUpdate) (contract.UserResponse, error)
+	Delete(context.Context, int) error
+	SetSlug(context.Context, int, contract.UserSlug) (contract.UserSlug, error)
 	SetAvailability(context.Context, int, contract.UserAvailability) (model.UserAvailability, error)
 	GetAvailability(context.Context, int) (contract.UserAvailability, error)
-	GetAvailabilityOverlap(context.Context, int, int) (contract.UserAvailabilityOverlap, error)
+	GetAvailabilityOverlap(context.Context, int, int, time.Time, time.Time) (contract.UserAvailabilityOverlap, error)
+	GetFreeOverlap(context.Context, []int, time.Time, time.Time, time.Duration) (contract.AvailabilityOverlap, error)
+	CreateOverride(context.Context, int, contract.AvailabilityOverride) (contract.AvailabilityOverrideResponse, error)
+	GetOverrides(context.Context, int) (contract.AvailabilityOverrideList, error)
+	GetOverride(context.Context, int, int) (contract.AvailabilityOverrideResponse, error)
+	UpdateOverride(context.Context, int, int, contract.AvailabilityOverride) (contract.AvailabilityOverrideResponse, error)
+	DeleteOverride(context.Context, int, int) error
 }
 
 type EventService interface {
 	Create(context.Context, int, contract.Event) (contract.EventResponse, error)
-	GetAll(context.Context, int) (contract.EventListResponse, error)
+	GetAll(context.Context, int, *time.Location) (contract.EventListResponse, error)
+	Cancel(context.Context, int, int, string, contract.CancelEvent) (contract.EventResponse, error)
+	Reschedule(context.Context, int, int, string, contract.RescheduleEvent) (contract.EventResponse, error)
+	GetChanges(context.Context, int, int, *time.Location) (contract.EventChangeList, error)
+	CreateSeries(context.Context, int, contract.EventSeries) (contract.EventSeriesResponse, error)
+	GetSeries(context.Context, int, int, *time.Location) (contract.EventSeriesResponse, error)
+	CancelSeries(context.Context, int, int, string, contract.CancelEvent) (contract.EventSeriesResponse, error)
+	RescheduleSeries(context.Context, int, int, string, contract.RescheduleSeries) (contract.EventSeriesResponse, error)
+	ExportCalendar(context.Context, int) ([]byte, error)
+	CreateCalendarFeed(context.Context, int) (contract.CalendarFeed, error)
+	ExportCalendarFeed(context.Context, string) ([]byte, error)
+}
+
+type BookingService interface {
+	GetBooking(context.Context, string, *time.Location) (contract.Booking, error)
+	CancelBooking(context.Context, string, contract.CancelEvent) (contract.Booking, error)
+	RescheduleBooking(context.Context, string, contract.RescheduleEvent) (contract.Booking, error)
+}
+
+type PublicPageService interface {
+	Get(context.Context, string) (contract.PublicPage, error)
+	GetSlots(context.Context, string, string, time.Time, time.Time, *time.Location) (contract.PublicSlotList, error)
+	Book(context.Context, string, contract.PublicBooking) (contract.Booking, error)
 }
 
 type SlotService interface {
-	Create(context.Context, int, int) (int, error)
-	GetAll(context.Context, int) (contract.SlotList, error)
+	Create(context.Context, int, int, int) (int, error)
+	GetAll(context.Context, int, int, time.Time, time.Time, *time.Location) (contract.SlotList, error)
 	DeleteByID(context.Context, int) error
 }
+
+type EventTypeService interface {
+	Create(context.Context, int, contract.EventType) (contract.EventTypeResponse, error)
+	GetAll(context.Context, int) (contract.EventTypeList, error)
+	Get(context.Context, int, int) (contract.EventTypeResponse, error)
+	Update(context.Context, int, int, contract.EventType) (contract.EventTypeResponse, error)
+	Delete(context.Context, int, int) error
+}
+
+type TeamService interface {
+	Create(context.Context, int, contract.Team) (contract.TeamResponse, error)
+	GetAll(context.Context, int) (contract.TeamList, error)
+	Get(context.Context, int, int) (contract.TeamResponse, error)
+	Delete(context.Context, int, int) error
+	AddMember(context.Context, int, int, contract.TeamMember) (contract.TeamMemberResponse, error)
+	RemoveMember(context.Context, int, int, int) error
+	GetMemberships(context.Context, int) (contract.TeamMembershipList, error)
+	AcceptMembership(context.Context, int, int) error
+	LeaveTeam(context.Context, int, int) error
+	CreateEventType(context.Context, int, int, contract.TeamEventType) (contract.TeamEventTypeResponse, error)
+	GetEventTypes(context.Context, int, int) (contract.TeamEventTypeList, error)
+	GetEventType(context.Context, int, int, int) (contract.TeamEventTypeResponse, error)
+	DeleteEventType(context.Context, int, int, int) error
+	GetSlots(context.Context, int, int, int, time.Time, time.Time, *time.Location) (contract.TeamSlotList, error)
+	Book(context.Context, int, int, contract.TeamEvent) (contract.EventListResponse, error)
+}
+
+type ExternalCalendarService interface {
+	Create(context.Context, int, contract.ExternalCalendar) (contract.ExternalCalendarResponse, error)
+	GetAll(context.Context, int) (contract.ExternalCalendarList, error)
+	Import(context.Context, int, int, io.Reader) (contract.ExternalCalendarResponse, error)
+	Sync(context.Context, int, int) (contract.ExternalCalendarResponse, error)
+	Delete(context.Context, int, int) error
+}
+
+type WebhookService interface {
+	Create(context.Context, int, contract.Webhook) (contract.WebhookResponse, error)
+	GetAll(context.Context, int) (contract.WebhookList, error)
+	Delete(context.Context, int, int) error
+	GetDeliveries(context.Context, int, int) (contract.WebhookDeliveryList, error)
+}
+
+type APIKeyService interface {
+	Create(context.Context, int, contract.APIKey) (contract.APIKeyResponse, error)
+	GetAll(context.Context, int) (contract.APIKeyList, error)
+	Revoke(context.Context, int, int) error
+}
+
+type JobService interface {
+	GetAll(context.Context, string, string) (contract.JobList, error)
+	Get(context.Context, int) (contract.JobResponse, error)
+	Retry(context.Context, int) (contract.JobResponse, error)
+}
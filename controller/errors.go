@@ -0,0 +1,34 @@
+package controller
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// renderError renders an error returned while serving a request with the status of its kind. Unexpected errors are
+// logged, since clients are only told that something went wrong.
+func renderError(w http.ResponseWriter, r *http.Request, err error) {
+	resp := contract.ErrorRendererFor(err)
+	if resp.StatusCode == http.StatusInternalServerError {
+		log.Printf("error occurred while serving %s %s: %s", r.Method, r.URL.Path, err.Error())
+	}
+	render.Render(w, r, resp)
+}
+
+// renderBindError renders an error returned while binding a request body. Bodies which fail validation are reported
+// with the invalid field, while bodies which could not be decoded at all are bad requests.
+func renderBindError(w http.ResponseWriter, r *http.Request, err error) {
+	log.Printf("unable to bind request body: %s", err.Error())
+	var domainErr *model.Error
+	if errors.As(err, &domainErr) {
+		render.Render(w, r, contract.DomainErrorRenderer(domainErr))
+		return
+	}
+	render.Render(w, r, contract.ErrorRenderer(err))
+}
@@ -1,12 +1,15 @@
 package controller
 
 import (
+	"fmt"
 	"log"
 	"net/http"
 
+	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
 
 	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
 )
 
 type Event struct {
@@ -14,10 +17,11 @@ type Event struct {
 }
 
 // Create - Creates a new event
-// @Summary This API creates a new event for the user with invitee details.
+// @Summary This API creates a new event for the user with invitee details, either for an existing slot or for a free slot given by its start time.
 // @Tags event
 // @Accept  json
 // @Produce  json
+// @Security ApiKey
 // @Param event body contract.Event true "Add event"
 // @Param user_id path int true "user id"
 // @Success 200 {object} contract.EventResponse
@@ -27,8 +31,7 @@ func (event Event) Create(w http.ResponseWriter, r *http.Request) {
 	input := contract.Event{}
 
 	if err := render.Bind(r, &input); err != nil {
-		log.Printf("unable to bind request body: %s", err.Error())
-		render.Render(w, r, contract.ErrorRenderer(err))
+		renderBindError(w, r, err)
 		return
 	}
 
@@ -36,7 +39,7 @@ func (event Event) Create(w http.ResponseWriter, r *http.Request) {
 
 	resp, err := event.eventService.Create(ctx, userID, input)
 	if err != nil {
-		render.Render(w, r, contract.ServerErrorRenderer(err))
+		renderError(w, r, err)
 		return
 	}
 
@@ -49,22 +52,213 @@ func (event Event) Create(w http.ResponseWriter, r *http.Request) {
 // @Tags event
 // @Accept  json
 // @Produce  json
+// @Security ApiKey
 // @Param user_id path int true "user id"
+// @Param tz query string false "IANA time zone to render times in"
 // @Success 200 {object} contract.EventListResponse
 // @Router /users/{user_id}/events [get]
 func (event Event) GetAll(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	userID := ctx.Value(ContextUserIDKey).(int)
 
-	resp, err := event.eventService.GetAll(ctx, userID)
+	loc, err := timeZoneFromQuery(r)
 	if err != nil {
-		render.Render(w, r, contract.ServerErrorRenderer(err))
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := event.eventService.GetAll(ctx, userID, loc)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Cancel - Cancels an event
+// @Summary This API cancels an event of the user which has not started yet and opens its slot for booking again.
+// @Tags event
+// @Accept  json
+// @Produce  json
+// @Security ApiKey
+// @Param cancel body contract.CancelEvent false "Cancel event"
+// @Param user_id path int true "user id"
+// @Param event_id path int true "event id"
+// @Success 200 {object} contract.EventResponse
+// @Router /users/{user_id}/events/{event_id}/cancel [post]
+func (event Event) Cancel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	eventID, err := idFromURL(r, "eventID", "event ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	input := contract.CancelEvent{}
+	if r.ContentLength != 0 {
+		if err := render.Bind(r, &input); err != nil {
+			renderBindError(w, r, err)
+			return
+		}
+	}
+
+	resp, err := event.eventService.Cancel(ctx, userID, eventID, model.ChangedByHost, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Reschedule - Reschedules an event
+// @Summary This API moves an event of the user which has not started yet to another slot, either an existing slot or a free slot given by its start time.
+// @Tags event
+// @Accept  json
+// @Produce  json
+// @Security ApiKey
+// @Param reschedule body contract.RescheduleEvent true "Reschedule event"
+// @Param user_id path int true "user id"
+// @Param event_id path int true "event id"
+// @Success 200 {object} contract.EventResponse
+// @Router /users/{user_id}/events/{event_id}/reschedule [post]
+func (event Event) Reschedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	eventID, err := idFromURL(r, "eventID", "event ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	input := contract.RescheduleEvent{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	resp, err := event.eventService.Reschedule(ctx, userID, eventID, model.ChangedByHost, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// GetChanges - Returns the history of an event
+// @Summary This API returns the cancellations and reschedules of an event, oldest first.
+// @Tags event
+// @Accept  json
+// @Produce  json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param event_id path int true "event id"
+// @Param tz query string false "IANA time zone to render times in"
+// @Success 200 {object} contract.EventChangeList
+// @Router /users/{user_id}/events/{event_id}/changes [get]
+func (event Event) GetChanges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	eventID, err := idFromURL(r, "eventID", "event ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	loc, err := timeZoneFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := event.eventService.GetChanges(ctx, userID, eventID, loc)
+	if err != nil {
+		renderError(w, r, err)
 		return
 	}
 
 	render.JSON(w, r, resp)
 }
 
+// ExportCalendar - Returns events for user as an iCalendar file
+// @Summary This API returns all events of a user, including cancelled ones, as an iCalendar (RFC 5545) file.
+// @Tags event
+// @Produce  text/calendar
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Success 200 {string} string
+// @Router /users/{user_id}/events.ics [get]
+func (event Event) ExportCalendar(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	data, err := event.eventService.ExportCalendar(ctx, userID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	writeCalendar(w, data)
+}
+
+// CreateCalendarFeed - Creates a calendar feed URL for user
+// @Summary This API generates a secret URL serving the user's events as an iCalendar feed for calendar apps to subscribe to. Generating a new URL revokes the previous one.
+// @Tags event
+// @Produce  json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Success 201 {object} contract.CalendarFeed
+// @Router /users/{user_id}/calendar_feed [post]
+func (event Event) CreateCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := event.eventService.CreateCalendarFeed(ctx, userID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	resp.URL = fmt.Sprintf("%s://%s%s", scheme, r.Host, resp.URL)
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+// GetCalendarFeed - Returns a calendar feed
+// @Summary This API serves the events of the user a calendar feed token belongs to as an iCalendar file.
+// @Tags event
+// @Produce  text/calendar
+// @Param token path string true "calendar feed token"
+// @Success 200 {string} string
+// @Router /calendar_feeds/{token}.ics [get]
+func (event Event) GetCalendarFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	data, err := event.eventService.ExportCalendarFeed(ctx, chi.URLParam(r, "token"))
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	writeCalendar(w, data)
+}
+
+func writeCalendar(w http.ResponseWriter, data []byte) {
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(data); err != nil {
+		log.Printf("unable to write calendar: %s", err.Error())
+	}
+}
+
 func NewEvent(eventService EventService) Event {
 	return Event{eventService: eventService}
 }
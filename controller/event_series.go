@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// CreateSeries - Books a recurring event
+// @Summary This API books the free slots starting at a start time and repeating weekly, biweekly or monthly, either a number of times or until a date. Either every occurrence is booked, or none of them is and the occurrences which are not available are reported in the fields of the error.
+// @Tags event
+// @Accept  json
+// @Produce  json
+// @Security ApiKey
+// @Param series body contract.EventSeries true "Add event series"
+// @Param user_id path int true "user id"
+// @Success 201 {object} contract.EventSeriesResponse
+// @Router /users/{user_id}/event_series [post]
+func (event Event) CreateSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := contract.EventSeries{}
+
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := event.eventService.CreateSeries(ctx, userID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+// GetSeries - Returns a recurring event
+// @Summary This API returns a series of recurring events along with its occurrences, earliest first.
+// @Tags event
+// @Accept  json
+// @Produce  json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param series_id path int true "series id"
+// @Param tz query string false "IANA time zone to render times in"
+// @Success 200 {object} contract.EventSeriesResponse
+// @Router /users/{user_id}/event_series/{series_id} [get]
+func (event Event) GetSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	seriesID, err := idFromURL(r, "seriesID", "series ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	loc, err := timeZoneFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := event.eventService.GetSeries(ctx, userID, seriesID, loc)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// CancelSeries - Cancels a recurring event
+// @Summary This API cancels the occurrences of a series which have not started yet. Single occurrences are cancelled like any other event.
+// @Tags event
+// @Accept  json
+// @Produce  json
+// @Security ApiKey
+// @Param cancel body contract.CancelEvent false "Cancel event series"
+// @Param user_id path int true "user id"
+// @Param series_id path int true "series id"
+// @Success 200 {object} contract.EventSeriesResponse
+// @Router /users/{user_id}/event_series/{series_id}/cancel [post]
+func (event Event) CancelSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	seriesID, err := idFromURL(r, "seriesID", "series ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	input := contract.CancelEvent{}
+	if r.ContentLength != 0 {
+		if err := render.Bind(r, &input); err != nil {
+			renderBindError(w, r, err)
+			return
+		}
+	}
+
+	resp, err := event.eventService.CancelSeries(ctx, userID, seriesID, model.ChangedByHost, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// RescheduleSeries - Reschedules a recurring event
+// @Summary This API moves the occurrences of a series which have not started yet to the free slots starting at a new start time and repeating at the frequency of the series. Either every occurrence is moved, or none of them is. Single occurrences are rescheduled like any other event.
+// @Tags event
+// @Accept  json
+// @Produce  json
+// @Security ApiKey
+// @Param reschedule body contract.RescheduleSeries true "Reschedule event series"
+// @Param user_id path int true "user id"
+// @Param series_id path int true "series id"
+// @Success 200 {object} contract.EventSeriesResponse
+// @Router /users/{user_id}/event_series/{series_id}/reschedule [post]
+func (event Event) RescheduleSeries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	seriesID, err := idFromURL(r, "seriesID", "series ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	input := contract.RescheduleSeries{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	resp, err := event.eventService.RescheduleSeries(ctx, userID, seriesID, model.ChangedByHost, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
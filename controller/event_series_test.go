@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *EventTestSuite) TestCreateSeriesHappyFlow() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/event_series",
+		strings.NewReader(`{"start_time":"2023-06-05T10:00:00Z","frequency":"weekly","count":4,"invitee_email":"test@example.xyz","invitee_name":"test"}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("CreateSeries", req.Context(), 1, contract.EventSeries{StartTime: time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC),
+		Frequency: model.FrequencyWeekly, Count: 4, InviteeName: "test", InviteeEmail: "test@example.xyz"}).
+		Return(contract.EventSeriesResponse{ID: 4, UserID: 1, Frequency: model.FrequencyWeekly, Count: 4}, nil)
+
+	suite.controller.CreateSeries(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	suite.Equal(http.StatusCreated, res.StatusCode)
+	suite.mockEventService.AssertExpectations(suite.T())
+}
+
+func (suite *EventTestSuite) TestCreateSeriesReportsOccurrencesWhichAreNotAvailable() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/event_series",
+		strings.NewReader(`{"start_time":"2023-06-05T10:00:00Z","frequency":"weekly","count":4,"invitee_email":"test@example.xyz","invitee_name":"test"}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("CreateSeries", req.Context(), 1, mock.Anything).Return(contract.EventSeriesResponse{},
+		model.ErrOccurrencesUnavailable.WithFields(map[string]string{"2023-06-12T10:00:00Z": "requested time is not available"}))
+
+	suite.controller.CreateSeries(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	suite.NoError(err)
+	suite.Equal(http.StatusConflict, res.StatusCode)
+	suite.Equal(`{"status_text":"conflict","code":"occurrences_unavailable","message":"some occurrences of the series are not available","fields":{"2023-06-12T10:00:00Z":"requested time is not available"}}
+`, string(body))
+}
+
+func (suite *EventTestSuite) TestCreateSeriesReturnsUnprocessableEntityWhenBothCountAndUntilAreGiven() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/event_series",
+		strings.NewReader(`{"start_time":"2023-06-05T10:00:00Z","frequency":"weekly","count":4,"until":"2023-07-05T10:00:00Z","invitee_email":"test@example.xyz","invitee_name":"test"}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.controller.CreateSeries(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.mockEventService.AssertNotCalled(suite.T(), "CreateSeries", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestCancelSeriesIsRecordedAsCancelledByHost() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/event_series/4/cancel", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("seriesID", "4")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("CancelSeries", req.Context(), 1, 4, model.ChangedByHost, contract.CancelEvent{}).
+		Return(contract.EventSeriesResponse{ID: 4}, nil)
+
+	suite.controller.CancelSeries(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.mockEventService.AssertExpectations(suite.T())
+}
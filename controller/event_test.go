@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"io"
 	"net/http"
@@ -11,7 +12,9 @@ import (
 	"time"
 
 	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
 
+	"github.com/go-chi/chi"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -63,7 +66,7 @@ func (suite *EventTestSuite) TestCreateShouldReturnErrorWhenServiceReturnsError(
 		suite.Error(errors.New("expected error to be nil got"), err)
 	}
 	suite.Equal(http.StatusInternalServerError, res.StatusCode)
-	suite.Equal(`{"status_text":"internal server error","message":"some error"}
+	suite.Equal(`{"status_text":"internal server error","code":"internal_error","message":"internal server error"}
 `, string(body)) // This newline is needed because chi returns the response ending with a \n
 	suite.mockEventService.AssertExpectations(suite.T())
 }
@@ -73,7 +76,7 @@ func (suite *EventTestSuite) TestGetAllHappyPath() {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodGet, "/users/1/events", nil)
 	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
-	suite.mockEventService.On("GetAll", req.Context(), 1).Return(contract.EventListResponse{
+	suite.mockEventService.On("GetAll", req.Context(), 1, (*time.Location)(nil)).Return(contract.EventListResponse{
 		Events: []contract.EventResponse{
 			{
 				ID:           1,
@@ -107,7 +110,7 @@ func (suite *EventTestSuite) TestGetAllReturnsServerErrorWhenServiceReturnsError
 	req := httptest.NewRequest(http.MethodGet, "/users/1/events", nil)
 	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
 	req.Header.Add("Content-Type", "application/json")
-	suite.mockEventService.On("GetAll", req.Context(), 1).Return(contract.EventListResponse{}, errors.New("some error"))
+	suite.mockEventService.On("GetAll", req.Context(), 1, (*time.Location)(nil)).Return(contract.EventListResponse{}, errors.New("some error"))
 
 	suite.controller.GetAll(w, req)
 
@@ -119,7 +122,329 @@ func (suite *EventTestSuite) TestGetAllReturnsServerErrorWhenServiceReturnsError
 	}
 
 	suite.Equal(http.StatusInternalServerError, w.Result().StatusCode)
-	suite.Equal(`{"status_text":"internal server error","message":"some error"}
+	suite.Equal(`{"status_text":"internal server error","code":"internal_error","message":"internal server error"}
+`, string(body))
+}
+
+func (suite *EventTestSuite) TestGetAllPassesRequestedTimeZone() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/1/events?tz=Asia/Kolkata", nil)
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	suite.mockEventService.On("GetAll", req.Context(), 1, loc).Return(contract.EventListResponse{}, nil)
+
+	suite.controller.GetAll(w, req)
+
+	suite.Equal(http.StatusOK, w.Result().StatusCode)
+	suite.mockEventService.AssertExpectations(suite.T())
+}
+
+func (suite *EventTestSuite) TestGetAllReturnsUnprocessableEntityForInvalidTimeZone() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/1/events?tz=Mars/Olympus", nil)
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+
+	suite.controller.GetAll(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"invalid tz","fields":{"tz":"invalid tz"}}
+`, string(body))
+	suite.mockEventService.AssertNotCalled(suite.T(), "GetAll")
+}
+
+func (suite *EventTestSuite) TestCreateReturnsUnprocessableEntityWhenSlotBelongsToAnotherEventType() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/events",
+		strings.NewReader(`{"slot_id":1,"event_type_id":2,"invitee_email":"test@example.xyz","invitee_name":"test"}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("Create", req.Context(), 1, contract.Event{
+		SlotID: 1, EventTypeID: 2, InviteeName: "test", InviteeEmail: "test@example.xyz"}).
+		Return(contract.EventResponse{}, model.ErrEventTypeMismatch)
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"event_type_mismatch","message":"slot does not belong to the event type","fields":{"slot_id":"slot does not belong to the event type"}}
+`, string(body))
+}
+
+func (suite *EventTestSuite) TestCreateReturnsConflictWhenSlotIsAlreadyBooked() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/events",
+		strings.NewReader(`{"slot_id":1,"invitee_email":"test@example.xyz","invitee_name":"test"}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("Create", req.Context(), 1, contract.Event{SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"}).
+		Return(contract.EventResponse{}, model.ErrSlotAlreadyBooked)
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusConflict, res.StatusCode)
+	suite.Equal(`{"status_text":"conflict","code":"slot_already_booked","message":"slot is already booked"}
+`, string(body))
+}
+
+func (suite *EventTestSuite) TestCreateReturnsNotFoundWhenSlotDoesNotBelongToUser() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/events",
+		strings.NewReader(`{"slot_id":1,"invitee_email":"test@example.xyz","invitee_name":"test"}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("Create", req.Context(), 1, contract.Event{SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"}).
+		Return(contract.EventResponse{}, model.NotFound("slot", sql.ErrNoRows))
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(`{"status_text":"not found","code":"slot_not_found","message":"slot not found"}
+`, string(body))
+}
+
+func (suite *EventTestSuite) TestCreateByStartTimeReturnsConflictWhenTimeIsNotFree() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/events",
+		strings.NewReader(`{"start_time":"2023-06-05T09:00:00Z","invitee_email":"test@example.xyz","invitee_name":"test"}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("Create", req.Context(), 1, contract.Event{
+		StartTime: time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC), InviteeName: "test", InviteeEmail: "test@example.xyz"}).
+		Return(contract.EventResponse{}, model.ErrSlotUnavailable)
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusConflict, res.StatusCode)
+	suite.Equal(`{"status_text":"conflict","code":"slot_unavailable","message":"requested time is not available"}
+`, string(body))
+}
+
+func (suite *EventTestSuite) TestCreateReturnsUnprocessableEntityWhenNeitherSlotNorStartTimeIsGiven() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/events",
+		strings.NewReader(`{"invitee_email":"test@example.xyz","invitee_name":"test"}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"either slot_id or start_time is required","fields":{"slot_id":"either slot_id or start_time is required"}}
+`, string(body))
+	suite.mockEventService.AssertNotCalled(suite.T(), "Create")
+}
+
+func (suite *EventTestSuite) TestCancelHappyFlow() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/events/3/cancel", strings.NewReader(`{"reason":"sick"}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("eventID", "3")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	suite.mockEventService.On("Cancel", req.Context(), 1, 3, model.ChangedByHost, contract.CancelEvent{Reason: "sick"}).
+		Return(contract.EventResponse{ID: 3, UserID: 1, SlotID: 5, StartTime: start, EndTime: start.Add(time.Hour), Status: "cancelled", CancelReason: "sick"}, nil)
+
+	suite.controller.Cancel(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal(`{"id":3,"user_id":1,"slot_id":5,"event_type_id":0,"invitee_email":"","invitee_name":"","invitee_notes":"","start_time":"2023-06-05T09:00:00Z","end_time":"2023-06-05T10:00:00Z","status":"cancelled","cancel_reason":"sick","created_at":"0001-01-01T00:00:00Z"}
+`, string(body))
+}
+
+func (suite *EventTestSuite) TestCancelWithoutBodyReturnsConflictWhenEventIsAlreadyCancelled() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/events/3/cancel", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("eventID", "3")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("Cancel", req.Context(), 1, 3, model.ChangedByHost, contract.CancelEvent{}).
+		Return(contract.EventResponse{}, model.ErrEventCancelled)
+
+	suite.controller.Cancel(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusConflict, res.StatusCode)
+	suite.Equal(`{"status_text":"conflict","code":"event_cancelled","message":"event is already cancelled"}
+`, string(body))
+}
+
+func (suite *EventTestSuite) TestRescheduleReturnsUnprocessableEntityWithoutSlotOrStartTime() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/events/3/reschedule", strings.NewReader(`{"reason":"clash"}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("eventID", "3")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.controller.Reschedule(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"either slot_id or start_time is required","fields":{"slot_id":"either slot_id or start_time is required"}}
+`, string(body))
+	suite.mockEventService.AssertNotCalled(suite.T(), "Reschedule")
+}
+
+func (suite *EventTestSuite) TestRescheduleReturnsConflictWhenSlotIsAlreadyBooked() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/events/3/reschedule", strings.NewReader(`{"slot_id":6}`))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("eventID", "3")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("Reschedule", req.Context(), 1, 3, model.ChangedByHost, contract.RescheduleEvent{SlotID: 6}).
+		Return(contract.EventResponse{}, model.ErrSlotAlreadyBooked)
+
+	suite.controller.Reschedule(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusConflict, res.StatusCode)
+	suite.Equal(`{"status_text":"conflict","code":"slot_already_booked","message":"slot is already booked"}
+`, string(body))
+}
+
+func (suite *EventTestSuite) TestGetChangesReturnsNotFoundWhenEventDoesNotExist() {
+	req := httptest.NewRequest(http.MethodGet, "/users/1/events/3/changes", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("eventID", "3")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("GetChanges", req.Context(), 1, 3, (*time.Location)(nil)).
+		Return(contract.EventChangeList{}, model.NotFound("event", sql.ErrNoRows))
+
+	suite.controller.GetChanges(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(`{"status_text":"not found","code":"event_not_found","message":"event not found"}
+`, string(body))
+}
+
+func (suite *EventTestSuite) TestExportCalendarWritesICalendar() {
+	req := httptest.NewRequest(http.MethodGet, "/users/1/events.ics", nil)
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("ExportCalendar", req.Context(), 1).Return([]byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"), nil)
+
+	suite.controller.ExportCalendar(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal("text/calendar; charset=utf-8", res.Header.Get("Content-Type"))
+	suite.Equal("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", string(body))
+}
+
+func (suite *EventTestSuite) TestCreateCalendarFeedReturnsAbsoluteURL() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/calendar_feed", nil)
+	req.Host = "localhost:8080"
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("CreateCalendarFeed", req.Context(), 1).Return(contract.CalendarFeed{URL: "/calendar_feeds/abc.ics"}, nil)
+
+	suite.controller.CreateCalendarFeed(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusCreated, res.StatusCode)
+	suite.Equal(`{"url":"http://localhost:8080/calendar_feeds/abc.ics"}
+`, string(body))
+}
+
+func (suite *EventTestSuite) TestGetCalendarFeedReturnsNotFoundForUnknownToken() {
+	req := httptest.NewRequest(http.MethodGet, "/calendar_feeds/abc.ics", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("token", "abc")
+	req = req.WithContext(context.WithValue(context.Background(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+	suite.mockEventService.On("ExportCalendarFeed", req.Context(), "abc").Return([]byte(nil), model.NotFound("calendar feed", sql.ErrNoRows))
+
+	suite.controller.GetCalendarFeed(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(`{"status_text":"not found","code":"calendar_feed_not_found","message":"calendar feed not found"}
 `, string(body))
 }
 
@@ -0,0 +1,159 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/harbor-xyz/coding-project/contract"
+)
+
+type EventType struct {
+	eventTypeService EventTypeService
+}
+
+// Create - Creates an event type
+// @Summary This API creates an event type for a user with its own duration and optionally its own availability
+// @Tags event_type
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param event_type body contract.EventType true "Add event type"
+// @Param user_id path int true "user id"
+// @Success 201 {object} contract.EventTypeResponse
+// @Router /users/{user_id}/event_types [post]
+func (eventType EventType) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := contract.EventType{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := eventType.eventTypeService.Create(ctx, userID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+// GetAll - Gets a user's event types
+// @Summary This API returns all event types of a user
+// @Tags event_type
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Success 200 {object} contract.EventTypeList
+// @Router /users/{user_id}/event_types [get]
+func (eventType EventType) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := eventType.eventTypeService.GetAll(ctx, userID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Get - Gets an event type
+// @Summary This API returns an event type of a user
+// @Tags event_type
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param event_type_id path int true "event type id"
+// @Success 200 {object} contract.EventTypeResponse
+// @Router /users/{user_id}/event_types/{event_type_id} [get]
+func (eventType EventType) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	eventTypeID, err := idFromURL(r, "eventTypeID", "event type ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := eventType.eventTypeService.Get(ctx, userID, eventTypeID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Update - Updates an event type
+// @Summary This API replaces an event type of a user
+// @Tags event_type
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param event_type body contract.EventType true "Update event type"
+// @Param user_id path int true "user id"
+// @Param event_type_id path int true "event type id"
+// @Success 200 {object} contract.EventTypeResponse
+// @Router /users/{user_id}/event_types/{event_type_id} [put]
+func (eventType EventType) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	eventTypeID, err := idFromURL(r, "eventTypeID", "event type ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	input := contract.EventType{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	resp, err := eventType.eventTypeService.Update(ctx, userID, eventTypeID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Delete - Deletes an event type
+// @Summary This API deletes an event type of a user
+// @Tags event_type
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param event_type_id path int true "event type id"
+// @Router /users/{user_id}/event_types/{event_type_id} [delete]
+func (eventType EventType) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	eventTypeID, err := idFromURL(r, "eventTypeID", "event type ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	err = eventType.eventTypeService.Delete(ctx, userID, eventTypeID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func NewEventType(eventTypeService EventTypeService) EventType {
+	return EventType{eventTypeService: eventTypeService}
+}
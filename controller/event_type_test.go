@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/datatypes"
+)
+
+type EventTypeTestSuite struct {
+	suite.Suite
+	controller           EventType
+	mockEventTypeService *MockEventTypeService
+}
+
+func (suite *EventTypeTestSuite) SetupTest() {
+	suite.mockEventTypeService = &MockEventTypeService{}
+	suite.controller = NewEventType(suite.mockEventTypeService)
+}
+
+func (suite *EventTypeTestSuite) TestCreateHappyFlow() {
+	now := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/1/event_types", strings.NewReader(
+		`{"name":"Intro call","slug":"intro-call","duration_mins":15,"availability":[{"day":"monday","start_time":"09:00","end_time":"12:00"}]}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	availability := []model.DayAvailability{{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)}}
+	suite.mockEventTypeService.On("Create", req.Context(), 1, contract.EventType{
+		Name:         "Intro call",
+		Slug:         "intro-call",
+		DurationMins: 15,
+		Availability: availability,
+	}).Return(contract.EventTypeResponse{ID: 1, UserID: 1, Name: "Intro call", Slug: "intro-call", DurationMins: 15, Availability: availability, CreatedAt: now}, nil)
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusCreated, res.StatusCode)
+	suite.Equal(`{"id":1,"user_id":1,"name":"Intro call","slug":"intro-call","duration_mins":15,"description":"","location":"","availability":[{"day":"monday","start_time":"09:00:00","end_time":"12:00:00"}],"created_at":"2023-06-05T10:00:00Z"}
+`, string(body))
+	suite.mockEventTypeService.AssertExpectations(suite.T())
+}
+
+func (suite *EventTypeTestSuite) TestCreateReturnsUnprocessableEntityForInvalidSlug() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/1/event_types", strings.NewReader(`{"name":"Intro call","slug":"Intro Call","duration_mins":15}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"slug should only contain lowercase letters, digits and hyphens","fields":{"slug":"slug should only contain lowercase letters, digits and hyphens"}}
+`, string(body))
+	suite.mockEventTypeService.AssertNotCalled(suite.T(), "Create")
+}
+
+func (suite *EventTypeTestSuite) TestCreateReturnsConflictForDuplicateSlug() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/1/event_types", strings.NewReader(`{"name":"Intro call","slug":"intro-call","duration_mins":15}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	suite.mockEventTypeService.On("Create", req.Context(), 1, contract.EventType{Name: "Intro call", Slug: "intro-call", DurationMins: 15}).
+		Return(contract.EventTypeResponse{}, model.ErrDuplicateSlug)
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusConflict, res.StatusCode)
+	suite.Equal(`{"status_text":"conflict","code":"duplicate_slug","message":"slug is already in use"}
+`, string(body))
+}
+
+func (suite *EventTypeTestSuite) TestGetReturnsNotFoundWhenEventTypeDoesNotExist() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/1/event_types/5", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("eventTypeID", "5")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	suite.mockEventTypeService.On("Get", req.Context(), 1, 5).Return(contract.EventTypeResponse{}, model.NotFound("event type", sql.ErrNoRows))
+
+	suite.controller.Get(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(`{"status_text":"not found","code":"event_type_not_found","message":"event type not found"}
+`, string(body))
+}
+
+func (suite *EventTypeTestSuite) TestDeleteHappyFlow() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/users/1/event_types/5", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("eventTypeID", "5")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	suite.mockEventTypeService.On("Delete", req.Context(), 1, 5).Return(nil)
+
+	suite.controller.Delete(w, req)
+
+	suite.Equal(http.StatusNoContent, w.Result().StatusCode)
+	suite.mockEventTypeService.AssertExpectations(suite.T())
+}
+
+func TestEventTypeTestSuite(t *testing.T) {
+	suite.Run(t, new(EventTypeTestSuite))
+}
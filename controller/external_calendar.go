@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// maxUploadMemory is the part of a multipart upload kept in memory, the rest is spilled to disk
+const maxUploadMemory = 1 << 20
+
+type ExternalCalendar struct {
+	externalCalendarService ExternalCalendarService
+}
+
+// Create - Adds an external calendar
+// @Summary This API adds an external calendar whose events block the user's time. An ICS calendar with a URL or a CalDAV calendar is read and imported straight away, and is synced again by the scheduler.
+// @Tags external_calendar
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param external_calendar body contract.ExternalCalendar true "Add external calendar"
+// @Param user_id path int true "user id"
+// @Success 201 {object} contract.ExternalCalendarResponse
+// @Router /users/{user_id}/external_calendars [post]
+func (calendar ExternalCalendar) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := contract.ExternalCalendar{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := calendar.externalCalendarService.Create(ctx, userID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+// GetAll - Gets a user's external calendars
+// @Summary This API returns all external calendars of a user
+// @Tags external_calendar
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Success 200 {object} contract.ExternalCalendarList
+// @Router /users/{user_id}/external_calendars [get]
+func (calendar ExternalCalendar) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := calendar.externalCalendarService.GetAll(ctx, userID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Import - Imports an ICS file into an external calendar
+// @Summary This API replaces the busy times of an external calendar with the events of an ICS file, sent either as the request body or as the file field of a multipart form. Recurring events are expanded for the next 180 days.
+// @Tags external_calendar
+// @Accept text/calendar
+// @Accept mpfd
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param external_calendar_id path int true "external calendar id"
+// @Success 200 {object} contract.ExternalCalendarResponse
+// @Router /users/{user_id}/external_calendars/{external_calendar_id}/import [post]
+func (calendar ExternalCalendar) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	calendarID, err := idFromURL(r, "externalCalendarID", "external calendar ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	var body io.Reader = r.Body
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		if err := r.ParseMultipartForm(maxUploadMemory); err != nil {
+			render.Render(w, r, contract.ErrorRenderer(errors.New("invalid multipart form")))
+			return
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			renderError(w, r, model.Validation("file", "file is required"))
+			return
+		}
+		defer file.Close()
+		body = file
+	}
+
+	resp, err := calendar.externalCalendarService.Import(ctx, userID, calendarID, body)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Sync - Syncs an external calendar
+// @Summary This API fetches an external calendar from its URL again and replaces its busy times
+// @Tags external_calendar
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param external_calendar_id path int true "external calendar id"
+// @Success 200 {object} contract.ExternalCalendarResponse
+// @Router /users/{user_id}/external_calendars/{external_calendar_id}/sync [post]
+func (calendar ExternalCalendar) Sync(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	calendarID, err := idFromURL(r, "externalCalendarID", "external calendar ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := calendar.externalCalendarService.Sync(ctx, userID, calendarID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Delete - Deletes an external calendar
+// @Summary This API deletes an external calendar of a user along with its busy times
+// @Tags external_calendar
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param external_calendar_id path int true "external calendar id"
+// @Router /users/{user_id}/external_calendars/{external_calendar_id} [delete]
+func (calendar ExternalCalendar) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	calendarID, err := idFromURL(r, "externalCalendarID", "external calendar ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	err = calendar.externalCalendarService.Delete(ctx, userID, calendarID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func NewExternalCalendar(externalCalendarService ExternalCalendarService) ExternalCalendar {
+	return ExternalCalendar{externalCalendarService: externalCalendarService}
+}
@@ -0,0 +1,206 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const testICS = "BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n"
+
+type ExternalCalendarTestSuite struct {
+	suite.Suite
+	controller                  ExternalCalendar
+	mockExternalCalendarService *MockExternalCalendarService
+}
+
+func (suite *ExternalCalendarTestSuite) SetupTest() {
+	suite.mockExternalCalendarService = &MockExternalCalendarService{}
+	suite.controller = NewExternalCalendar(suite.mockExternalCalendarService)
+}
+
+func (suite *ExternalCalendarTestSuite) request(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("externalCalendarID", "2")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	return req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+}
+
+func (suite *ExternalCalendarTestSuite) readBody(res *http.Response) string {
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	return string(body)
+}
+
+func (suite *ExternalCalendarTestSuite) TestCreateHappyFlow() {
+	syncedAt := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	blocks := 3
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/users/1/external_calendars", strings.NewReader(`{"name":"Work","url":"webcal://example.xyz/work.ics"}`))
+	req.Header.Add("Content-Type", "application/json")
+	suite.mockExternalCalendarService.On("Create", req.Context(), 1, contract.ExternalCalendar{Name: "Work", Provider: model.ProviderICS, URL: "webcal://example.xyz/work.ics"}).
+		Return(contract.ExternalCalendarResponse{ID: 2, UserID: 1, Name: "Work", Provider: model.ProviderICS, URL: "webcal://example.xyz/work.ics", SyncedAt: &syncedAt, BusyBlocks: &blocks}, nil)
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusCreated, res.StatusCode)
+	suite.Equal(`{"id":2,"user_id":1,"name":"Work","provider":"ics","url":"webcal://example.xyz/work.ics","write_back":false,"synced_at":"2023-06-01T00:00:00Z","busy_blocks":3}
+`, suite.readBody(res))
+}
+
+func (suite *ExternalCalendarTestSuite) TestCreateReturnsUnprocessableEntityForUnsupportedURL() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/users/1/external_calendars", strings.NewReader(`{"name":"Work","url":"file:///etc/passwd"}`))
+	req.Header.Add("Content-Type", "application/json")
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"url should be an absolute http, https or webcal URL","fields":{"url":"url should be an absolute http, https or webcal URL"}}
+`, suite.readBody(res))
+	suite.mockExternalCalendarService.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ExternalCalendarTestSuite) TestCreateCalDAVCalendarDoesNotReturnPassword() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/users/1/external_calendars", strings.NewReader(
+		`{"name":"Work","provider":"caldav","url":"https://dav.example.xyz/calendars/test/","username":"test","password":"secret","write_back":true}`))
+	req.Header.Add("Content-Type", "application/json")
+	suite.mockExternalCalendarService.On("Create", req.Context(), 1, contract.ExternalCalendar{Name: "Work", Provider: model.ProviderCalDAV,
+		URL: "https://dav.example.xyz/calendars/test/", Username: "test", Password: "secret", WriteBack: true}).
+		Return(contract.ExternalCalendarResponse{ID: 2, UserID: 1, Name: "Work", Provider: model.ProviderCalDAV, URL: "https://dav.example.xyz/calendars/test/",
+			Username: "test", WriteBack: true}, nil)
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusCreated, res.StatusCode)
+	suite.Equal(`{"id":2,"user_id":1,"name":"Work","provider":"caldav","url":"https://dav.example.xyz/calendars/test/","username":"test","write_back":true}
+`, suite.readBody(res))
+}
+
+func (suite *ExternalCalendarTestSuite) TestCreateReturnsUnprocessableEntityForInvalidProviderSettings() {
+	cases := map[string][2]string{
+		`{"name":"Work","provider":"exchange","url":"https://example.xyz/"}`:         {"provider", "provider should be one of ics and caldav"},
+		`{"name":"Work","provider":"caldav"}`:                                        {"url", "url is required for caldav calendars"},
+		`{"name":"Work","url":"https://example.xyz/work.ics","write_back":true}`:     {"write_back", "write_back is only supported by caldav calendars"},
+		`{"name":"Work","provider":"caldav","url":"webcal://example.xyz/calendar/"}`: {"url", "url of caldav calendars should be an http or https URL"},
+	}
+	for body, c := range cases {
+		field, message := c[0], c[1]
+		w := httptest.NewRecorder()
+		req := suite.request(http.MethodPost, "/users/1/external_calendars", strings.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+
+		suite.controller.Create(w, req)
+
+		res := w.Result()
+		suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+		suite.Equal(fmt.Sprintf("{\"status_text\":\"unprocessable entity\",\"code\":\"validation_failed\",\"message\":%q,\"fields\":{%q:%q}}\n", message, field, message), suite.readBody(res))
+	}
+	suite.mockExternalCalendarService.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ExternalCalendarTestSuite) TestCreateReturnsUnprocessableEntityIfCalendarCannotBeFetched() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/users/1/external_calendars", strings.NewReader(`{"name":"Work","url":"https://example.xyz/work.ics"}`))
+	req.Header.Add("Content-Type", "application/json")
+	suite.mockExternalCalendarService.On("Create", req.Context(), 1, mock.Anything).
+		Return(contract.ExternalCalendarResponse{}, model.ErrInvalidCalendar.Withf("fetching calendar failed with status 404"))
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"invalid_calendar","message":"calendar could not be imported: fetching calendar failed with status 404"}
+`, suite.readBody(res))
+}
+
+func (suite *ExternalCalendarTestSuite) TestImportReadsRawBody() {
+	blocks := 0
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/users/1/external_calendars/2/import", strings.NewReader(testICS))
+	req.Header.Add("Content-Type", "text/calendar")
+	suite.mockExternalCalendarService.On("Import", req.Context(), 1, 2, mock.MatchedBy(func(r io.Reader) bool {
+		data, _ := io.ReadAll(r)
+		return string(data) == testICS
+	})).Return(contract.ExternalCalendarResponse{ID: 2, UserID: 1, Name: "Work", Provider: model.ProviderICS, BusyBlocks: &blocks}, nil)
+
+	suite.controller.Import(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal(`{"id":2,"user_id":1,"name":"Work","provider":"ics","write_back":false,"busy_blocks":0}
+`, suite.readBody(res))
+}
+
+func (suite *ExternalCalendarTestSuite) TestImportReadsMultipartUpload() {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, _ := form.CreateFormFile("file", "work.ics")
+	part.Write([]byte(testICS))
+	form.Close()
+
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/users/1/external_calendars/2/import", &body)
+	req.Header.Add("Content-Type", form.FormDataContentType())
+	suite.mockExternalCalendarService.On("Import", mock.Anything, 1, 2, mock.MatchedBy(func(r io.Reader) bool {
+		data, _ := io.ReadAll(r)
+		return string(data) == testICS
+	})).Return(contract.ExternalCalendarResponse{ID: 2, UserID: 1, Name: "Work"}, nil)
+
+	suite.controller.Import(w, req)
+
+	suite.Equal(http.StatusOK, w.Result().StatusCode)
+	suite.mockExternalCalendarService.AssertExpectations(suite.T())
+}
+
+func (suite *ExternalCalendarTestSuite) TestImportReturnsNotFoundIfCalendarDoesNotExist() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/users/1/external_calendars/2/import", strings.NewReader(testICS))
+	suite.mockExternalCalendarService.On("Import", req.Context(), 1, 2, mock.Anything).
+		Return(contract.ExternalCalendarResponse{}, model.NotFound("external calendar", sql.ErrNoRows))
+
+	suite.controller.Import(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(`{"status_text":"not found","code":"external_calendar_not_found","message":"external calendar not found"}
+`, suite.readBody(res))
+}
+
+func (suite *ExternalCalendarTestSuite) TestDeleteHappyFlow() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodDelete, "/users/1/external_calendars/2", nil)
+	suite.mockExternalCalendarService.On("Delete", req.Context(), 1, 2).Return(nil)
+
+	suite.controller.Delete(w, req)
+
+	suite.Equal(http.StatusNoContent, w.Result().StatusCode)
+}
+
+func TestExternalCalendarTestSuite(t *testing.T) {
+	suite.Run(t, new(ExternalCalendarTestSuite))
+}
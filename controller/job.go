@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+type Job struct {
+	jobService JobService
+}
+
+// GetAll - Gets the latest jobs
+// @Summary This API returns the latest 100 jobs of the queue, newest first, optionally narrowed down to a status and a kind
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param status query string false "pending, running, succeeded or dead"
+// @Param kind query string false "kind of job, such as webhook.publish"
+// @Success 200 {object} contract.JobList
+// @Router /admin/jobs [get]
+func (job Job) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	status := r.URL.Query().Get("status")
+	if _, ok := model.JobStatuses[status]; status != "" && !ok {
+		renderError(w, r, model.Validation("status", fmt.Sprintf("invalid status %s", status)))
+		return
+	}
+
+	resp, err := job.jobService.GetAll(ctx, status, r.URL.Query().Get("kind"))
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Get - Gets a job
+// @Summary This API returns a job of the queue along with its payload and last error
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param job_id path int true "job id"
+// @Success 200 {object} contract.JobResponse
+// @Router /admin/jobs/{job_id} [get]
+func (job Job) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID, err := idFromURL(r, "jobID", "job ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := job.jobService.Get(ctx, jobID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Retry - Retries a dead job
+// @Summary This API runs a dead job again as soon as possible, with a fresh set of attempts
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param job_id path int true "job id"
+// @Success 200 {object} contract.JobResponse
+// @Router /admin/jobs/{job_id}/retry [post]
+func (job Job) Retry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID, err := idFromURL(r, "jobID", "job ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := job.jobService.Retry(ctx, jobID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func NewJob(jobService JobService) Job {
+	return Job{jobService: jobService}
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type JobTestSuite struct {
+	suite.Suite
+	controller     Job
+	mockJobService *MockJobService
+}
+
+func (suite *JobTestSuite) SetupTest() {
+	suite.mockJobService = &MockJobService{}
+	suite.controller = NewJob(suite.mockJobService)
+}
+
+func (suite *JobTestSuite) request(method, target string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("jobID", "2")
+	return req.WithContext(context.WithValue(context.Background(), chi.RouteCtxKey, rctx))
+}
+
+func (suite *JobTestSuite) readBody(res *http.Response) string {
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	return string(body)
+}
+
+func (suite *JobTestSuite) TestGetAllFiltersByStatusAndKind() {
+	createdAt := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodGet, "/admin/jobs?status=dead&kind=webhook.publish")
+	suite.mockJobService.On("GetAll", req.Context(), "dead", "webhook.publish").Return(contract.JobList{Jobs: []contract.JobResponse{
+		{ID: 2, Kind: "webhook.publish", Status: "dead", Attempts: 10, MaxAttempts: 10, LastError: "some error", FinishedAt: &createdAt, CreatedAt: createdAt,
+			Payload: json.RawMessage(`{"user_id":1}`)},
+	}}, nil)
+
+	suite.controller.GetAll(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal(`{"jobs":[{"id":2,"kind":"webhook.publish","status":"dead","attempts":10,"max_attempts":10,"last_error":"some error","finished_at":"2023-06-01T00:00:00Z","created_at":"2023-06-01T00:00:00Z","payload":{"user_id":1}}]}
+`, suite.readBody(res))
+}
+
+func (suite *JobTestSuite) TestGetAllReturnsUnprocessableEntityForUnknownStatus() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodGet, "/admin/jobs?status=stuck")
+
+	suite.controller.GetAll(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Contains(suite.readBody(res), "invalid status stuck")
+	suite.mockJobService.AssertNotCalled(suite.T(), "GetAll", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *JobTestSuite) TestGetReturnsNotFoundIfJobDoesNotExist() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodGet, "/admin/jobs/2")
+	suite.mockJobService.On("Get", req.Context(), 2).Return(contract.JobResponse{}, model.NotFound("job", sql.ErrNoRows))
+
+	suite.controller.Get(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Contains(suite.readBody(res), "job not found")
+}
+
+func (suite *JobTestSuite) TestRetryHappyFlow() {
+	runAt := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/admin/jobs/2/retry")
+	suite.mockJobService.On("Retry", req.Context(), 2).Return(contract.JobResponse{ID: 2, Kind: "webhook.publish", Status: "pending", MaxAttempts: 10,
+		RunAt: &runAt, CreatedAt: runAt, Payload: json.RawMessage(`{}`)}, nil)
+
+	suite.controller.Retry(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Contains(suite.readBody(res), `"status":"pending"`)
+}
+
+func (suite *JobTestSuite) TestRetryReturnsConflictIfJobIsNotDead() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/admin/jobs/2/retry")
+	suite.mockJobService.On("Retry", req.Context(), 2).Return(contract.JobResponse{}, model.ErrJobNotDead)
+
+	suite.controller.Retry(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusConflict, res.StatusCode)
+	suite.Contains(suite.readBody(res), "only dead jobs can be retried")
+}
+
+func TestJobTestSuite(t *testing.T) {
+	suite.Run(t, new(JobTestSuite))
+}
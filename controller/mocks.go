@@ -2,6 +2,8 @@ package controller
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/harbor-xyz/coding-project/contract"
 	"github.com/harbor-xyz/coding-project/model"
@@ -18,6 +20,31 @@ func (mock *MockUserService) Create(ctx context.Context, input contract.User) (c
 	return args.Get(0).(contract.UserResponse), args.Error(1)
 }
 
+func (mock *MockUserService) Get(ctx context.Context, userID int) (contract.UserResponse, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).(contract.UserResponse), args.Error(1)
+}
+
+func (mock *MockUserService) GetAll(ctx context.Context, email string, limit, offset int) (contract.UserList, error) {
+	args := mock.Called(ctx, email, limit, offset)
+	return args.Get(0).(contract.UserList), args.Error(1)
+}
+
+func (mock *MockUserService) Update(ctx context.Context, userID int, input contract.UserUpdate) (contract.UserResponse, error) {
+	args := mock.Called(ctx, userID, input)
+	return args.Get(0).(contract.UserResponse), args.Error(1)
+}
+
+func (mock *MockUserService) Delete(ctx context.Context, userID int) error {
+	args := mock.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (mock *MockUserService) SetSlug(ctx context.Context, userID int, input contract.UserSlug) (contract.UserSlug, error) {
+	args := mock.Called(ctx, userID, input)
+	return args.Get(0).(contract.UserSlug), args.Error(1)
+}
+
 func (mock *MockUserService) SetAvailability(ctx context.Context, userID int, input contract.UserAvailability) (model.UserAvailability, error) {
 	args := mock.Called(ctx, userID, input)
 	return args.Get(0).(model.UserAvailability), args.Error(1)
@@ -28,11 +55,41 @@ func (mock *MockUserService) GetAvailability(ctx context.Context, userID int) (c
 	return args.Get(0).(contract.UserAvailability), args.Error(1)
 }
 
-func (mock *MockUserService) GetAvailabilityOverlap(ctx context.Context, user1ID, user2ID int) (contract.UserAvailabilityOverlap, error) {
-	args := mock.Called(ctx, user1ID, user2ID)
+func (mock *MockUserService) GetAvailabilityOverlap(ctx context.Context, user1ID, user2ID int, from, to time.Time) (contract.UserAvailabilityOverlap, error) {
+	args := mock.Called(ctx, user1ID, user2ID, from, to)
 	return args.Get(0).(contract.UserAvailabilityOverlap), args.Error(1)
 }
 
+func (mock *MockUserService) GetFreeOverlap(ctx context.Context, userIDs []int, from, to time.Time, minDuration time.Duration) (contract.AvailabilityOverlap, error) {
+	args := mock.Called(ctx, userIDs, from, to, minDuration)
+	return args.Get(0).(contract.AvailabilityOverlap), args.Error(1)
+}
+
+func (mock *MockUserService) CreateOverride(ctx context.Context, userID int, input contract.AvailabilityOverride) (contract.AvailabilityOverrideResponse, error) {
+	args := mock.Called(ctx, userID, input)
+	return args.Get(0).(contract.AvailabilityOverrideResponse), args.Error(1)
+}
+
+func (mock *MockUserService) GetOverrides(ctx context.Context, userID int) (contract.AvailabilityOverrideList, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).(contract.AvailabilityOverrideList), args.Error(1)
+}
+
+func (mock *MockUserService) GetOverride(ctx context.Context, userID, overrideID int) (contract.AvailabilityOverrideResponse, error) {
+	args := mock.Called(ctx, userID, overrideID)
+	return args.Get(0).(contract.AvailabilityOverrideResponse), args.Error(1)
+}
+
+func (mock *MockUserService) UpdateOverride(ctx context.Context, userID, overrideID int, input contract.AvailabilityOverride) (contract.AvailabilityOverrideResponse, error) {
+	args := mock.Called(ctx, userID, overrideID, input)
+	return args.Get(0).(contract.AvailabilityOverrideResponse), args.Error(1)
+}
+
+func (mock *MockUserService) DeleteOverride(ctx context.Context, userID, overrideID int) error {
+	args := mock.Called(ctx, userID, overrideID)
+	return args.Error(0)
+}
+
 type MockEventService struct {
 	mock.Mock
 }
@@ -42,22 +99,110 @@ func (mock *MockEventService) Create(ctx context.Context, userID int, input cont
 	return args.Get(0).(contract.EventResponse), args.Error(1)
 }
 
-func (mock *MockEventService) GetAll(ctx context.Context, userID int) (contract.EventListResponse, error) {
-	args := mock.Called(ctx, userID)
+func (mock *MockEventService) GetAll(ctx context.Context, userID int, loc *time.Location) (contract.EventListResponse, error) {
+	args := mock.Called(ctx, userID, loc)
 	return args.Get(0).(contract.EventListResponse), args.Error(1)
 }
 
+func (mock *MockEventService) Cancel(ctx context.Context, userID, eventID int, changedBy string, input contract.CancelEvent) (contract.EventResponse, error) {
+	args := mock.Called(ctx, userID, eventID, changedBy, input)
+	return args.Get(0).(contract.EventResponse), args.Error(1)
+}
+
+func (mock *MockEventService) Reschedule(ctx context.Context, userID, eventID int, changedBy string, input contract.RescheduleEvent) (contract.EventResponse, error) {
+	args := mock.Called(ctx, userID, eventID, changedBy, input)
+	return args.Get(0).(contract.EventResponse), args.Error(1)
+}
+
+func (mock *MockEventService) GetChanges(ctx context.Context, userID, eventID int, loc *time.Location) (contract.EventChangeList, error) {
+	args := mock.Called(ctx, userID, eventID, loc)
+	return args.Get(0).(contract.EventChangeList), args.Error(1)
+}
+
+func (mock *MockEventService) CreateSeries(ctx context.Context, userID int, input contract.EventSeries) (contract.EventSeriesResponse, error) {
+	args := mock.Called(ctx, userID, input)
+	return args.Get(0).(contract.EventSeriesResponse), args.Error(1)
+}
+
+func (mock *MockEventService) GetSeries(ctx context.Context, userID, seriesID int, loc *time.Location) (contract.EventSeriesResponse, error) {
+	args := mock.Called(ctx, userID, seriesID, loc)
+	return args.Get(0).(contract.EventSeriesResponse), args.Error(1)
+}
+
+func (mock *MockEventService) CancelSeries(ctx context.Context, userID, seriesID int, changedBy string, input contract.CancelEvent) (contract.EventSeriesResponse, error) {
+	args := mock.Called(ctx, userID, seriesID, changedBy, input)
+	return args.Get(0).(contract.EventSeriesResponse), args.Error(1)
+}
+
+func (mock *MockEventService) RescheduleSeries(ctx context.Context, userID, seriesID int, changedBy string, input contract.RescheduleSeries) (contract.EventSeriesResponse, error) {
+	args := mock.Called(ctx, userID, seriesID, changedBy, input)
+	return args.Get(0).(contract.EventSeriesResponse), args.Error(1)
+}
+
+func (mock *MockEventService) ExportCalendar(ctx context.Context, userID int) ([]byte, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+func (mock *MockEventService) CreateCalendarFeed(ctx context.Context, userID int) (contract.CalendarFeed, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).(contract.CalendarFeed), args.Error(1)
+}
+
+func (mock *MockEventService) ExportCalendarFeed(ctx context.Context, token string) ([]byte, error) {
+	args := mock.Called(ctx, token)
+	return args.Get(0).([]byte), args.Error(1)
+}
+
+type MockPublicPageService struct {
+	mock.Mock
+}
+
+func (mock *MockPublicPageService) Get(ctx context.Context, slug string) (contract.PublicPage, error) {
+	args := mock.Called(ctx, slug)
+	return args.Get(0).(contract.PublicPage), args.Error(1)
+}
+
+func (mock *MockPublicPageService) GetSlots(ctx context.Context, slug, eventTypeSlug string, from, to time.Time, loc *time.Location) (contract.PublicSlotList, error) {
+	args := mock.Called(ctx, slug, eventTypeSlug, from, to, loc)
+	return args.Get(0).(contract.PublicSlotList), args.Error(1)
+}
+
+func (mock *MockPublicPageService) Book(ctx context.Context, slug string, input contract.PublicBooking) (contract.Booking, error) {
+	args := mock.Called(ctx, slug, input)
+	return args.Get(0).(contract.Booking), args.Error(1)
+}
+
+type MockBookingService struct {
+	mock.Mock
+}
+
+func (mock *MockBookingService) GetBooking(ctx context.Context, token string, loc *time.Location) (contract.Booking, error) {
+	args := mock.Called(ctx, token, loc)
+	return args.Get(0).(contract.Booking), args.Error(1)
+}
+
+func (mock *MockBookingService) CancelBooking(ctx context.Context, token string, input contract.CancelEvent) (contract.Booking, error) {
+	args := mock.Called(ctx, token, input)
+	return args.Get(0).(contract.Booking), args.Error(1)
+}
+
+func (mock *MockBookingService) RescheduleBooking(ctx context.Context, token string, input contract.RescheduleEvent) (contract.Booking, error) {
+	args := mock.Called(ctx, token, input)
+	return args.Get(0).(contract.Booking), args.Error(1)
+}
+
 type MockSlotService struct {
 	mock.Mock
 }
 
-func (mock *MockSlotService) Create(ctx context.Context, userID, numDays int) (int, error) {
-	args := mock.Called(ctx, userID, numDays)
+func (mock *MockSlotService) Create(ctx context.Context, userID, eventTypeID, numDays int) (int, error) {
+	args := mock.Called(ctx, userID, eventTypeID, numDays)
 	return args.Int(0), args.Error(1)
 }
 
-func (mock *MockSlotService) GetAll(ctx context.Context, userID int) (contract.SlotList, error) {
-	args := mock.Called(ctx, userID)
+func (mock *MockSlotService) GetAll(ctx context.Context, userID, eventTypeID int, from, to time.Time, loc *time.Location) (contract.SlotList, error) {
+	args := mock.Called(ctx, userID, eventTypeID, from, to, loc)
 	return args.Get(0).(contract.SlotList), args.Error(1)
 }
 
@@ -65,3 +210,202 @@ func (mock *MockSlotService) DeleteByID(ctx context.Context, slotID int) error {
 	args := mock.Called(ctx, slotID)
 	return args.Error(0)
 }
+
+type MockEventTypeService struct {
+	mock.Mock
+}
+
+func (mock *MockEventTypeService) Create(ctx context.Context, userID int, input contract.EventType) (contract.EventTypeResponse, error) {
+	args := mock.Called(ctx, userID, input)
+	return args.Get(0).(contract.EventTypeResponse), args.Error(1)
+}
+
+func (mock *MockEventTypeService) GetAll(ctx context.Context, userID int) (contract.EventTypeList, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).(contract.EventTypeList), args.Error(1)
+}
+
+func (mock *MockEventTypeService) Get(ctx context.Context, userID, eventTypeID int) (contract.EventTypeResponse, error) {
+	args := mock.Called(ctx, userID, eventTypeID)
+	return args.Get(0).(contract.EventTypeResponse), args.Error(1)
+}
+
+func (mock *MockEventTypeService) Update(ctx context.Context, userID, eventTypeID int, input contract.EventType) (contract.EventTypeResponse, error) {
+	args := mock.Called(ctx, userID, eventTypeID, input)
+	return args.Get(0).(contract.EventTypeResponse), args.Error(1)
+}
+
+func (mock *MockEventTypeService) Delete(ctx context.Context, userID, eventTypeID int) error {
+	args := mock.Called(ctx, userID, eventTypeID)
+	return args.Error(0)
+}
+
+type MockTeamService struct {
+	mock.Mock
+}
+
+func (mock *MockTeamService) Create(ctx context.Context, userID int, input contract.Team) (contract.TeamResponse, error) {
+	args := mock.Called(ctx, userID, input)
+	return args.Get(0).(contract.TeamResponse), args.Error(1)
+}
+
+func (mock *MockTeamService) GetAll(ctx context.Context, userID int) (contract.TeamList, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).(contract.TeamList), args.Error(1)
+}
+
+func (mock *MockTeamService) Get(ctx context.Context, userID, teamID int) (contract.TeamResponse, error) {
+	args := mock.Called(ctx, userID, teamID)
+	return args.Get(0).(contract.TeamResponse), args.Error(1)
+}
+
+func (mock *MockTeamService) Delete(ctx context.Context, userID, teamID int) error {
+	args := mock.Called(ctx, userID, teamID)
+	return args.Error(0)
+}
+
+func (mock *MockTeamService) AddMember(ctx context.Context, userID, teamID int, input contract.TeamMember) (contract.TeamMemberResponse, error) {
+	args := mock.Called(ctx, userID, teamID, input)
+	return args.Get(0).(contract.TeamMemberResponse), args.Error(1)
+}
+
+func (mock *MockTeamService) RemoveMember(ctx context.Context, userID, teamID, memberID int) error {
+	args := mock.Called(ctx, userID, teamID, memberID)
+	return args.Error(0)
+}
+
+func (mock *MockTeamService) GetMemberships(ctx context.Context, userID int) (contract.TeamMembershipList, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).(contract.TeamMembershipList), args.Error(1)
+}
+
+func (mock *MockTeamService) AcceptMembership(ctx context.Context, userID, teamID int) error {
+	args := mock.Called(ctx, userID, teamID)
+	return args.Error(0)
+}
+
+func (mock *MockTeamService) LeaveTeam(ctx context.Context, userID, teamID int) error {
+	args := mock.Called(ctx, userID, teamID)
+	return args.Error(0)
+}
+
+func (mock *MockTeamService) CreateEventType(ctx context.Context, userID, teamID int, input contract.TeamEventType) (contract.TeamEventTypeResponse, error) {
+	args := mock.Called(ctx, userID, teamID, input)
+	return args.Get(0).(contract.TeamEventTypeResponse), args.Error(1)
+}
+
+func (mock *MockTeamService) GetEventTypes(ctx context.Context, userID, teamID int) (contract.TeamEventTypeList, error) {
+	args := mock.Called(ctx, userID, teamID)
+	return args.Get(0).(contract.TeamEventTypeList), args.Error(1)
+}
+
+func (mock *MockTeamService) GetEventType(ctx context.Context, userID, teamID, eventTypeID int) (contract.TeamEventTypeResponse, error) {
+	args := mock.Called(ctx, userID, teamID, eventTypeID)
+	return args.Get(0).(contract.TeamEventTypeResponse), args.Error(1)
+}
+
+func (mock *MockTeamService) DeleteEventType(ctx context.Context, userID, teamID, eventTypeID int) error {
+	args := mock.Called(ctx, userID, teamID, eventTypeID)
+	return args.Error(0)
+}
+
+func (mock *MockTeamService) GetSlots(ctx context.Context, userID, teamID, eventTypeID int, from, to time.Time, loc *time.Location) (contract.TeamSlotList, error) {
+	args := mock.Called(ctx, userID, teamID, eventTypeID, from, to, loc)
+	return args.Get(0).(contract.TeamSlotList), args.Error(1)
+}
+
+func (mock *MockTeamService) Book(ctx context.Context, userID, teamID int, input contract.TeamEvent) (contract.EventListResponse, error) {
+	args := mock.Called(ctx, userID, teamID, input)
+	return args.Get(0).(contract.EventListResponse), args.Error(1)
+}
+
+type MockExternalCalendarService struct {
+	mock.Mock
+}
+
+func (mock *MockExternalCalendarService) Create(ctx context.Context, userID int, input contract.ExternalCalendar) (contract.ExternalCalendarResponse, error) {
+	args := mock.Called(ctx, userID, input)
+	return args.Get(0).(contract.ExternalCalendarResponse), args.Error(1)
+}
+
+func (mock *MockExternalCalendarService) GetAll(ctx context.Context, userID int) (contract.ExternalCalendarList, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).(contract.ExternalCalendarList), args.Error(1)
+}
+
+func (mock *MockExternalCalendarService) Import(ctx context.Context, userID, calendarID int, r io.Reader) (contract.ExternalCalendarResponse, error) {
+	args := mock.Called(ctx, userID, calendarID, r)
+	return args.Get(0).(contract.ExternalCalendarResponse), args.Error(1)
+}
+
+func (mock *MockExternalCalendarService) Sync(ctx context.Context, userID, calendarID int) (contract.ExternalCalendarResponse, error) {
+	args := mock.Called(ctx, userID, calendarID)
+	return args.Get(0).(contract.ExternalCalendarResponse), args.Error(1)
+}
+
+func (mock *MockExternalCalendarService) Delete(ctx context.Context, userID, calendarID int) error {
+	args := mock.Called(ctx, userID, calendarID)
+	return args.Error(0)
+}
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (mock *MockWebhookService) Create(ctx context.Context, userID int, input contract.Webhook) (contract.WebhookResponse, error) {
+	args := mock.Called(ctx, userID, input)
+	return args.Get(0).(contract.WebhookResponse), args.Error(1)
+}
+
+func (mock *MockWebhookService) GetAll(ctx context.Context, userID int) (contract.WebhookList, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).(contract.WebhookList), args.Error(1)
+}
+
+func (mock *MockWebhookService) Delete(ctx context.Context, userID, webhookID int) error {
+	args := mock.Called(ctx, userID, webhookID)
+	return args.Error(0)
+}
+
+func (mock *MockWebhookService) GetDeliveries(ctx context.Context, userID, webhookID int) (contract.WebhookDeliveryList, error) {
+	args := mock.Called(ctx, userID, webhookID)
+	return args.Get(0).(contract.WebhookDeliveryList), args.Error(1)
+}
+
+type MockJobService struct {
+	mock.Mock
+}
+
+func (mock *MockJobService) GetAll(ctx context.Context, status, kind string) (contract.JobList, error) {
+	args := mock.Called(ctx, status, kind)
+	return args.Get(0).(contract.JobList), args.Error(1)
+}
+
+func (mock *MockJobService) Get(ctx context.Context, jobID int) (contract.JobResponse, error) {
+	args := mock.Called(ctx, jobID)
+	return args.Get(0).(contract.JobResponse), args.Error(1)
+}
+
+func (mock *MockJobService) Retry(ctx context.Context, jobID int) (contract.JobResponse, error) {
+	args := mock.Called(ctx, jobID)
+	return args.Get(0).(contract.JobResponse), args.Error(1)
+}
+
+type MockAPIKeyService struct {
+	mock.Mock
+}
+
+func (mock *MockAPIKeyService) Create(ctx context.Context, userID int, input contract.APIKey) (contract.APIKeyResponse, error) {
+	args := mock.Called(ctx, userID, input)
+	return args.Get(0).(contract.APIKeyResponse), args.Error(1)
+}
+
+func (mock *MockAPIKeyService) GetAll(ctx context.Context, userID int) (contract.APIKeyList, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).(contract.APIKeyList), args.Error(1)
+}
+
+func (mock *MockAPIKeyService) Revoke(ctx context.Context, userID, apiKeyID int) error {
+	args := mock.Called(ctx, userID, apiKeyID)
+	return args.Error(0)
+}
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// timeZoneFromQuery returns the location requested through the tz query parameter,
+// or nil if the caller did not ask for one.
+func timeZoneFromQuery(r *http.Request) (*time.Location, error) {
+	tz := r.URL.Query().Get("tz")
+	if tz == "" {
+		return nil, nil
+	}
+	loc, err := contract.ParseTimeZone(tz)
+	if err != nil {
+		return nil, model.Validation("tz", "invalid tz")
+	}
+	return loc, nil
+}
+
+// timeRangeFromQuery parses the from and to query parameters. Both accept either an RFC 3339
+// timestamp or a date, in which case to covers the whole of that date. Zero times are returned
+// when neither of them is given.
+func timeRangeFromQuery(r *http.Request) (time.Time, time.Time, error) {
+	fromParam := r.URL.Query().Get("from")
+	toParam := r.URL.Query().Get("to")
+	if fromParam == "" && toParam == "" {
+		return time.Time{}, time.Time{}, nil
+	}
+	if fromParam == "" || toParam == "" {
+		return time.Time{}, time.Time{}, model.Validation(missingRangeParam(fromParam), "both from and to are required")
+	}
+
+	from, err := parseTimeParam(fromParam, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, model.Validation("from", "invalid from")
+	}
+	to, err := parseTimeParam(toParam, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, model.Validation("to", "invalid to")
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, model.Validation("to", "from should be before to")
+	}
+	return from, to, nil
+}
+
+// missingRangeParam returns which of from and to is missing when only one of them is given.
+func missingRangeParam(fromParam string) string {
+	if fromParam == "" {
+		return "from"
+	}
+	return "to"
+}
+
+func parseTimeParam(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(contract.DateLayout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+	return t, nil
+}
+
+// idFromURL parses a numeric ID from the given URL parameter. name is used to describe the ID in errors.
+func idFromURL(r *http.Request, param, name string) (int, error) {
+	field := strings.ReplaceAll(strings.ToLower(name), " ", "_")
+	value := chi.URLParam(r, param)
+	if value == "" {
+		return 0, model.Validation(field, fmt.Sprintf("%s is required", name))
+	}
+	id, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, model.Validation(field, fmt.Sprintf("invalid %s", name))
+	}
+	return id, nil
+}
+
+// paginationFromQuery parses the optional limit and offset query parameters, defaulting to the first page of
+// defaultPageSize items.
+func paginationFromQuery(r *http.Request) (int, int, error) {
+	limit, offset := defaultPageSize, 0
+	if param := r.URL.Query().Get("limit"); param != "" {
+		value, err := strconv.Atoi(param)
+		if err != nil || value <= 0 || value > maxPageSize {
+			return 0, 0, model.Validation("limit", fmt.Sprintf("limit should be between 1 and %d", maxPageSize))
+		}
+		limit = value
+	}
+	if param := r.URL.Query().Get("offset"); param != "" {
+		value, err := strconv.Atoi(param)
+		if err != nil || value < 0 {
+			return 0, 0, model.Validation("offset", "invalid offset")
+		}
+		offset = value
+	}
+	return limit, offset, nil
+}
+
+// eventTypeIDFromQuery parses the optional event_type_id query parameter. 0 is returned when it is not given.
+func eventTypeIDFromQuery(r *http.Request) (int, error) {
+	param := r.URL.Query().Get("event_type_id")
+	if param == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(param)
+	if err != nil || id <= 0 {
+		return 0, model.Validation("event_type_id", "invalid event_type_id")
+	}
+	return id, nil
+}
+
+// userIDsFromQuery parses the comma separated user_ids query parameter, dropping duplicates.
+func userIDsFromQuery(r *http.Request) ([]int, error) {
+	param := r.URL.Query().Get("user_ids")
+	if param == "" {
+		return nil, model.Validation("user_ids", "user_ids is required")
+	}
+
+	userIDs := make([]int, 0)
+	seen := make(map[int]bool)
+	for _, value := range strings.Split(param, ",") {
+		id, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return nil, model.Validation("user_ids", "invalid user_ids")
+		}
+		if !seen[id] {
+			seen[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+	return userIDs, nil
+}
+
+// containsCaller reports whether the authenticated user making the request is one of the given users.
+func containsCaller(ctx context.Context, userIDs []int) bool {
+	callerID, ok := ctx.Value(ContextCallerIDKey).(int)
+	if !ok {
+		return false
+	}
+	for _, id := range userIDs {
+		if id == callerID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/render"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// PublicPage serves the public booking pages of users, which invitees reach through the user's slug.
+type PublicPage struct {
+	publicPageService PublicPageService
+}
+
+// Get - Returns a public booking page
+// @Summary This API returns the public booking page of a user: their name, time zone and event types.
+// @Tags public
+// @Accept  json
+// @Produce  json
+// @Param slug path string true "slug of the user"
+// @Success 200 {object} contract.PublicPage
+// @Router /p/{slug} [get]
+func (page PublicPage) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	resp, err := page.publicPageService.Get(ctx, chi.URLParam(r, "slug"))
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// GetSlots - Gets the bookable times of an event type
+// @Summary This API returns the free slots of an event type on a public booking page. Without from and to, the next 14 days are returned.
+// @Tags public
+// @Accept  json
+// @Produce  json
+// @Param slug path string true "slug of the user"
+// @Param event_type_slug path string true "slug of the event type"
+// @Param from query string false "start of the range, RFC 3339 timestamp or date"
+// @Param to query string false "end of the range, RFC 3339 timestamp or date"
+// @Param tz query string false "IANA time zone to render times in"
+// @Success 200 {object} contract.PublicSlotList
+// @Router /p/{slug}/{event_type_slug}/slots [get]
+func (page PublicPage) GetSlots(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	loc, err := timeZoneFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	from, to, err := timeRangeFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	if to.Sub(from) > maxSlotRange {
+		renderError(w, r, model.Validation("to", "range should not exceed 90 days"))
+		return
+	}
+
+	resp, err := page.publicPageService.GetSlots(ctx, chi.URLParam(r, "slug"), chi.URLParam(r, "eventTypeSlug"), from, to, loc)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Book - Books a time through a public booking page
+// @Summary This API books the free slot of an event type starting at the given time, and returns the booking along with the invitee's management token.
+// @Tags public
+// @Accept  json
+// @Produce  json
+// @Param booking body contract.PublicBooking true "Book"
+// @Param slug path string true "slug of the user"
+// @Success 201 {object} contract.Booking
+// @Router /p/{slug}/book [post]
+func (page PublicPage) Book(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := contract.PublicBooking{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	resp, err := page.publicPageService.Book(ctx, chi.URLParam(r, "slug"), input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+func NewPublicPage(publicPageService PublicPageService) PublicPage {
+	return PublicPage{publicPageService: publicPageService}
+}
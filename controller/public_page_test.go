@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type PublicPageTestSuite struct {
+	suite.Suite
+	controller            PublicPage
+	mockPublicPageService *MockPublicPageService
+}
+
+func (suite *PublicPageTestSuite) SetupTest() {
+	suite.mockPublicPageService = &MockPublicPageService{}
+	suite.controller = NewPublicPage(suite.mockPublicPageService)
+}
+
+func (suite *PublicPageTestSuite) request(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("slug", "jane")
+	rctx.URLParams.Add("eventTypeSlug", "intro")
+	return req.WithContext(context.WithValue(context.Background(), chi.RouteCtxKey, rctx))
+}
+
+func (suite *PublicPageTestSuite) readBody(res *http.Response) string {
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	return string(body)
+}
+
+func (suite *PublicPageTestSuite) TestGetHappyFlow() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodGet, "/p/jane", nil)
+	suite.mockPublicPageService.On("Get", req.Context(), "jane").Return(contract.PublicPage{HostName: "Jane", TimeZone: "UTC",
+		EventTypes: []contract.PublicEventType{{Name: "Intro call", Slug: "intro", DurationMins: 30}}}, nil)
+
+	suite.controller.Get(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal(`{"host_name":"Jane","time_zone":"UTC","event_types":[{"name":"Intro call","slug":"intro","duration_mins":30}]}
+`, suite.readBody(res))
+}
+
+func (suite *PublicPageTestSuite) TestGetReturnsNotFoundForUnknownSlug() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodGet, "/p/jane", nil)
+	suite.mockPublicPageService.On("Get", req.Context(), "jane").Return(contract.PublicPage{}, model.NotFound("page", sql.ErrNoRows))
+
+	suite.controller.Get(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Result().StatusCode)
+}
+
+func (suite *PublicPageTestSuite) TestGetSlotsPassesRangeAndTimeZone() {
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	loc, _ := time.LoadLocation("Europe/Berlin")
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodGet, "/p/jane/intro/slots?from=2023-06-05T00:00:00Z&to=2023-06-06T00:00:00Z&tz=Europe/Berlin", nil)
+	start := time.Date(2023, 6, 5, 11, 0, 0, 0, loc)
+	suite.mockPublicPageService.On("GetSlots", req.Context(), "jane", "intro", from, from.Add(24*time.Hour), loc).Return(contract.PublicSlotList{
+		Slots: []contract.PublicSlot{{StartTime: start, EndTime: start.Add(30 * time.Minute)}},
+	}, nil)
+
+	suite.controller.GetSlots(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal(`{"slots":[{"start_time":"2023-06-05T11:00:00+02:00","end_time":"2023-06-05T11:30:00+02:00"}]}
+`, suite.readBody(res))
+}
+
+func (suite *PublicPageTestSuite) TestGetSlotsRejectsRangesLongerThan90Days() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodGet, "/p/jane/intro/slots?from=2023-06-01&to=2023-12-01", nil)
+
+	suite.controller.GetSlots(w, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, w.Result().StatusCode)
+	suite.mockPublicPageService.AssertNotCalled(suite.T(), "GetSlots", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything,
+		mock.Anything)
+}
+
+func (suite *PublicPageTestSuite) TestBookHappyFlow() {
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/p/jane/book", strings.NewReader(
+		`{"event_type_slug":"intro","start_time":"2023-06-05T09:00:00Z","invitee_name":"test","invitee_email":"test@example.xyz"}`))
+	req.Header.Add("Content-Type", "application/json")
+	suite.mockPublicPageService.On("Book", req.Context(), "jane", contract.PublicBooking{EventTypeSlug: "intro", StartTime: start, InviteeName: "test",
+		InviteeEmail: "test@example.xyz"}).Return(contract.Booking{HostName: "Jane", EventTypeName: "Intro call", InviteeName: "test",
+		InviteeEmail: "test@example.xyz", StartTime: start, EndTime: start.Add(30 * time.Minute), Status: "confirmed", ManagementToken: "abc.def"}, nil)
+
+	suite.controller.Book(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusCreated, res.StatusCode)
+	suite.Contains(suite.readBody(res), `"management_token":"abc.def"`)
+}
+
+func (suite *PublicPageTestSuite) TestBookReturnsUnprocessableEntityWithoutEventType() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/p/jane/book", strings.NewReader(
+		`{"start_time":"2023-06-05T09:00:00Z","invitee_name":"test","invitee_email":"test@example.xyz"}`))
+	req.Header.Add("Content-Type", "application/json")
+
+	suite.controller.Book(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"event_type_slug is required","fields":{"event_type_slug":"event_type_slug is required"}}
+`, suite.readBody(res))
+}
+
+func (suite *PublicPageTestSuite) TestBookReturnsConflictIfTimeIsTaken() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/p/jane/book", strings.NewReader(
+		`{"event_type_slug":"intro","start_time":"2023-06-05T09:00:00Z","invitee_name":"test","invitee_email":"test@example.xyz"}`))
+	req.Header.Add("Content-Type", "application/json")
+	suite.mockPublicPageService.On("Book", req.Context(), "jane", mock.Anything).Return(contract.Booking{}, model.ErrSlotUnavailable)
+
+	suite.controller.Book(w, req)
+
+	suite.Equal(http.StatusConflict, w.Result().StatusCode)
+}
+
+func TestPublicPageTestSuite(t *testing.T) {
+	suite.Run(t, new(PublicPageTestSuite))
+}
@@ -1,14 +1,12 @@
 package controller
 
 import (
-	"errors"
 	"net/http"
 	"strconv"
 
-	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
 
-	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
 )
 
 type Slot struct {
@@ -20,7 +18,9 @@ type Slot struct {
 // @Tags slot
 // @Accept  json
 // @Produce  json
+// @Security ApiKey
 // @Param num_days query int true "number of days to create slots"
+// @Param event_type_id query int false "event type to create slots for"
 // @Param user_id path int true "user id"
 // @Router /users/{user_id}/slots [post]
 func (slot Slot) Create(w http.ResponseWriter, r *http.Request) {
@@ -29,18 +29,23 @@ func (slot Slot) Create(w http.ResponseWriter, r *http.Request) {
 
 	numDaysParam := r.URL.Query().Get("num_days")
 	if numDaysParam == "" {
-		render.Render(w, r, contract.ErrorRenderer(errors.New("num_days is required")))
+		renderError(w, r, model.Validation("num_days", "num_days is required"))
 		return
 	}
 	numDays, err := strconv.Atoi(numDaysParam)
 	if err != nil {
-		render.Render(w, r, contract.ErrorRenderer(errors.New("invalid num_days")))
+		renderError(w, r, model.Validation("num_days", "invalid num_days"))
+		return
+	}
+	eventTypeID, err := eventTypeIDFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
 		return
 	}
 
-	numSlots, err := slot.slotService.Create(ctx, userID, numDays)
+	numSlots, err := slot.slotService.Create(ctx, userID, eventTypeID, numDays)
 	if err != nil {
-		render.Render(w, r, contract.ServerErrorRenderer(err))
+		renderError(w, r, err)
 		return
 	}
 
@@ -48,21 +53,46 @@ func (slot Slot) Create(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, map[string]int{"num_slots": numSlots})
 }
 
-// GetAll - Gets slots for a user
-// @Summary This API returns slots for a user starting today till 14 days.
+// GetAll - Gets free slots for a user
+// @Summary This API computes the free slots of a user from their availability, overrides and bookings. Without from and to, the next 14 days are returned.
 // @Tags slot
 // @Accept  json
 // @Produce  json
+// @Security ApiKey
 // @Param user_id path int true "user id"
+// @Param from query string false "start of the range, RFC 3339 timestamp or date"
+// @Param to query string false "end of the range, RFC 3339 timestamp or date"
+// @Param event_type_id query int false "event type to compute slots for"
+// @Param tz query string false "IANA time zone to render times in"
 // @Success 200 {object} contract.SlotList
 // @Router /users/{user_id}/slots [get]
 func (slot Slot) GetAll(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	userID := ctx.Value(ContextUserIDKey).(int)
 
-	slots, err := slot.slotService.GetAll(ctx, userID)
+	loc, err := timeZoneFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	eventTypeID, err := eventTypeIDFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	from, to, err := timeRangeFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	if to.Sub(from) > maxSlotRange {
+		renderError(w, r, model.Validation("to", "range should not exceed 90 days"))
+		return
+	}
+
+	slots, err := slot.slotService.GetAll(ctx, userID, eventTypeID, from, to, loc)
 	if err != nil {
-		render.Render(w, r, contract.ServerErrorRenderer(err))
+		renderError(w, r, err)
 		return
 	}
 
@@ -74,23 +104,20 @@ func (slot Slot) GetAll(w http.ResponseWriter, r *http.Request) {
 // @Tags slot
 // @Accept  json
 // @Produce  json
+// @Security ApiKey
 // @Param user_id path int true "user id"
 // @Param slot_id path int true "slot id"
 // @Router /users/{user_id}/slots/{slot_id} [delete]
 func (slot Slot) Delete(w http.ResponseWriter, r *http.Request) {
-	slotID := chi.URLParam(r, "slotID")
-	if slotID == "" {
-		render.Render(w, r, contract.ErrorRenderer(errors.New("user ID is required")))
-		return
-	}
-	id, err := strconv.Atoi(slotID)
+	id, err := idFromURL(r, "slotID", "slot ID")
 	if err != nil {
-		render.Render(w, r, contract.ErrorRenderer(errors.New("invalid user ID")))
+		renderError(w, r, err)
+		return
 	}
 
 	err = slot.slotService.DeleteByID(r.Context(), id)
 	if err != nil {
-		render.Render(w, r, contract.ServerErrorRenderer(err))
+		renderError(w, r, err)
 		return
 	}
 
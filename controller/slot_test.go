@@ -2,12 +2,17 @@ package controller
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
+	"github.com/harbor-xyz/coding-project/contract"
+
+	"github.com/harbor-xyz/coding-project/model"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -26,7 +31,7 @@ func (suite *SlotTestSuite) TestCreateHappyFlow() {
 	req := httptest.NewRequest(http.MethodPost, "/users/1/slot?num_days=14", nil)
 	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
 	w := httptest.NewRecorder()
-	suite.mockSlotService.On("Create", req.Context(), 1, 14).Return(60, nil)
+	suite.mockSlotService.On("Create", req.Context(), 1, 0, 14).Return(60, nil)
 
 	suite.controller.Create(w, req)
 
@@ -46,7 +51,7 @@ func (suite *SlotTestSuite) TestCreatShouldReturnErrorWhenServiceReturnsError()
 	req := httptest.NewRequest(http.MethodPost, "/users/1/slot?num_days=14", nil)
 	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
 	w := httptest.NewRecorder()
-	suite.mockSlotService.On("Create", req.Context(), 1, 14).Return(-1, errors.New("some error"))
+	suite.mockSlotService.On("Create", req.Context(), 1, 0, 14).Return(-1, errors.New("some error"))
 
 	suite.controller.Create(w, req)
 
@@ -57,11 +62,75 @@ func (suite *SlotTestSuite) TestCreatShouldReturnErrorWhenServiceReturnsError()
 		suite.Error(errors.New("expected error to be nil got"), err)
 	}
 	suite.Equal(http.StatusInternalServerError, res.StatusCode)
-	suite.Equal(`{"status_text":"internal server error","message":"some error"}
+	suite.Equal(`{"status_text":"internal server error","code":"internal_error","message":"internal server error"}
 `, string(body)) // This newline is needed because chi returns the response ending with a \n
 	suite.mockSlotService.AssertExpectations(suite.T())
 }
 
+func (suite *SlotTestSuite) TestCreateForEventTypeReturnsNotFoundWhenEventTypeDoesNotExist() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/slot?num_days=14&event_type_id=3", nil)
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	w := httptest.NewRecorder()
+	suite.mockSlotService.On("Create", req.Context(), 1, 3, 14).Return(-1, model.NotFound("event type", sql.ErrNoRows))
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(`{"status_text":"not found","code":"event_type_not_found","message":"event type not found"}
+`, string(body))
+	suite.mockSlotService.AssertExpectations(suite.T())
+}
+
+func (suite *SlotTestSuite) TestGetAllComputesSlotsForTheRequestedRange() {
+	req := httptest.NewRequest(http.MethodGet, "/users/1/slots?from=2023-06-05&to=2023-06-05&event_type_id=2", nil)
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	w := httptest.NewRecorder()
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	suite.mockSlotService.On("GetAll", req.Context(), 1, 2, from, from.AddDate(0, 0, 1), (*time.Location)(nil)).Return(contract.SlotList{
+		Slots: []contract.Slot{
+			{UserID: 1, EventTypeID: 2, StartTime: from.Add(9 * time.Hour), EndTime: from.Add(10 * time.Hour), Status: "created", RemainingSeats: 1},
+		},
+	}, nil)
+
+	suite.controller.GetAll(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal(`{"slots":[{"user_id":1,"event_type_id":2,"start_time":"2023-06-05T09:00:00Z","end_time":"2023-06-05T10:00:00Z","status":"created","remaining_seats":1}]}
+`, string(body))
+	suite.mockSlotService.AssertExpectations(suite.T())
+}
+
+func (suite *SlotTestSuite) TestGetAllReturnsUnprocessableEntityForTooLongRange() {
+	req := httptest.NewRequest(http.MethodGet, "/users/1/slots?from=2023-06-05&to=2023-12-05", nil)
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	w := httptest.NewRecorder()
+
+	suite.controller.GetAll(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"range should not exceed 90 days","fields":{"to":"range should not exceed 90 days"}}
+`, string(body))
+	suite.mockSlotService.AssertNotCalled(suite.T(), "GetAll")
+}
+
 func TestSlotTestSuite(t *testing.T) {
 	suite.Run(t, new(SlotTestSuite))
 }
@@ -0,0 +1,478 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+type Team struct {
+	teamService TeamService
+}
+
+// Create - Creates a team
+// @Summary This API creates a team owned by the user, optionally along with its first members. The owner does not have to be a member.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param team body contract.Team true "Add team"
+// @Param user_id path int true "user id"
+// @Success 201 {object} contract.TeamResponse
+// @Router /users/{user_id}/teams [post]
+func (team Team) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := contract.Team{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := team.teamService.Create(ctx, userID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+// GetAll - Gets a user's teams
+// @Summary This API returns all teams owned by a user along with their members
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Success 200 {object} contract.TeamList
+// @Router /users/{user_id}/teams [get]
+func (team Team) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := team.teamService.GetAll(ctx, userID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Get - Gets a team
+// @Summary This API returns a team of a user along with its members and how the bookings of the team are shared among them
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param team_id path int true "team id"
+// @Success 200 {object} contract.TeamResponse
+// @Router /users/{user_id}/teams/{team_id} [get]
+func (team Team) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	teamID, err := idFromURL(r, "teamID", "team ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := team.teamService.Get(ctx, userID, teamID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Delete - Deletes a team
+// @Summary This API deletes a team along with its event types. The bookings made through the team stay with its members.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param team_id path int true "team id"
+// @Router /users/{user_id}/teams/{team_id} [delete]
+func (team Team) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	teamID, err := idFromURL(r, "teamID", "team ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	if err := team.teamService.Delete(ctx, userID, teamID); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AddMember - Adds a member to a team
+// @Summary This API adds a user to a team. Members other than the owner are only booked through the team once they accept. Round robin bookings go to the free members of the highest priority first.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param member body contract.TeamMember true "Add team member"
+// @Param user_id path int true "user id"
+// @Param team_id path int true "team id"
+// @Success 201 {object} contract.TeamMemberResponse
+// @Router /users/{user_id}/teams/{team_id}/members [post]
+func (team Team) AddMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	teamID, err := idFromURL(r, "teamID", "team ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	input := contract.TeamMember{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	resp, err := team.teamService.AddMember(ctx, userID, teamID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+// RemoveMember - Removes a member from a team
+// @Summary This API removes a user from a team. Their bookings made through the team stay with them.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param team_id path int true "team id"
+// @Param member_id path int true "user id of the member"
+// @Router /users/{user_id}/teams/{team_id}/members/{member_id} [delete]
+func (team Team) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	teamID, err := idFromURL(r, "teamID", "team ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	memberID, err := idFromURL(r, "memberID", "member ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	if err := team.teamService.RemoveMember(ctx, userID, teamID, memberID); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetMemberships - Gets the teams a user has been added to
+// @Summary This API returns the teams a user has been added to, along with whether they accepted to be booked through them
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Success 200 {object} contract.TeamMembershipList
+// @Router /users/{user_id}/team_memberships [get]
+func (team Team) GetMemberships(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := team.teamService.GetMemberships(ctx, userID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// AcceptMembership - Accepts to be booked through a team
+// @Summary This API lets a team the user has been added to read their calendar and book them. Until then, the team leaves them out of its slots and bookings.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param team_id path int true "team id"
+// @Router /users/{user_id}/team_memberships/{team_id}/accept [post]
+func (team Team) AcceptMembership(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	teamID, err := idFromURL(r, "teamID", "team ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	if err := team.teamService.AcceptMembership(ctx, userID, teamID); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// LeaveTeam - Leaves a team
+// @Summary This API removes the user from a team they have been added to, which also declines a membership they have not accepted. Their bookings made through the team stay with them.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param team_id path int true "team id"
+// @Router /users/{user_id}/team_memberships/{team_id} [delete]
+func (team Team) LeaveTeam(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	teamID, err := idFromURL(r, "teamID", "team ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	if err := team.teamService.LeaveTeam(ctx, userID, teamID); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreateEventType - Creates a team event type
+// @Summary This API creates an event type of a team, either collective, booking every member at the times they are all free, or round_robin, booking one of the members free at the time
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param event_type body contract.TeamEventType true "Add team event type"
+// @Param user_id path int true "user id"
+// @Param team_id path int true "team id"
+// @Success 201 {object} contract.TeamEventTypeResponse
+// @Router /users/{user_id}/teams/{team_id}/event_types [post]
+func (team Team) CreateEventType(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	teamID, err := idFromURL(r, "teamID", "team ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	input := contract.TeamEventType{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	resp, err := team.teamService.CreateEventType(ctx, userID, teamID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+// GetEventTypes - Gets a team's event types
+// @Summary This API returns all event types of a team
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param team_id path int true "team id"
+// @Success 200 {object} contract.TeamEventTypeList
+// @Router /users/{user_id}/teams/{team_id}/event_types [get]
+func (team Team) GetEventTypes(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	teamID, err := idFromURL(r, "teamID", "team ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := team.teamService.GetEventTypes(ctx, userID, teamID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// GetEventType - Gets a team event type
+// @Summary This API returns an event type of a team
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param team_id path int true "team id"
+// @Param team_event_type_id path int true "team event type id"
+// @Success 200 {object} contract.TeamEventTypeResponse
+// @Router /users/{user_id}/teams/{team_id}/event_types/{team_event_type_id} [get]
+func (team Team) GetEventType(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	teamID, eventTypeID, err := teamEventTypeIDsFromURL(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := team.teamService.GetEventType(ctx, userID, teamID, eventTypeID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// DeleteEventType - Deletes a team event type
+// @Summary This API deletes an event type of a team. The bookings made through it stay with the members.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param team_id path int true "team id"
+// @Param team_event_type_id path int true "team event type id"
+// @Router /users/{user_id}/teams/{team_id}/event_types/{team_event_type_id} [delete]
+func (team Team) DeleteEventType(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	teamID, eventTypeID, err := teamEventTypeIDsFromURL(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	if err := team.teamService.DeleteEventType(ctx, userID, teamID, eventTypeID); err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSlots - Gets the free slots of a team event type
+// @Summary This API returns the free slots of a team event type along with the members who can be booked for each of them: every member of a collective event type, and the free ones of a round robin one. The next 14 days are used when no range is given.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param team_id path int true "team id"
+// @Param team_event_type_id path int true "team event type id"
+// @Param from query string false "start of the range, RFC 3339 timestamp or date"
+// @Param to query string false "end of the range, RFC 3339 timestamp or date"
+// @Param tz query string false "IANA time zone to render times in"
+// @Success 200 {object} contract.TeamSlotList
+// @Router /users/{user_id}/teams/{team_id}/event_types/{team_event_type_id}/slots [get]
+func (team Team) GetSlots(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	teamID, eventTypeID, err := teamEventTypeIDsFromURL(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	loc, err := timeZoneFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	from, to, err := timeRangeFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	if to.Sub(from) > maxSlotRange {
+		renderError(w, r, model.Validation("to", "range should not exceed 90 days"))
+		return
+	}
+
+	resp, err := team.teamService.GetSlots(ctx, userID, teamID, eventTypeID, from, to, loc)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Book - Books a team event type
+// @Summary This API books the free time of a team event type starting at the given start time. Collective event types book every member of the team, round robin ones the free member of the highest priority who was booked least recently. Returns the events of the members booked.
+// @Tags team
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param event body contract.TeamEvent true "Book team event type"
+// @Param user_id path int true "user id"
+// @Param team_id path int true "team id"
+// @Success 201 {object} contract.EventListResponse
+// @Router /users/{user_id}/teams/{team_id}/events [post]
+func (team Team) Book(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	teamID, err := idFromURL(r, "teamID", "team ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	input := contract.TeamEvent{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	resp, err := team.teamService.Book(ctx, userID, teamID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+// teamEventTypeIDsFromURL returns the team and team event type IDs of the URL.
+func teamEventTypeIDsFromURL(r *http.Request) (int, int, error) {
+	teamID, err := idFromURL(r, "teamID", "team ID")
+	if err != nil {
+		return 0, 0, err
+	}
+	eventTypeID, err := idFromURL(r, "teamEventTypeID", "team event type ID")
+	if err != nil {
+		return 0, 0, err
+	}
+	return teamID, eventTypeID, nil
+}
+
+func NewTeam(teamService TeamService) Team {
+	return Team{teamService: teamService}
+}
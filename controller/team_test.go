@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/suite"
+)
+
+type TeamTestSuite struct {
+	suite.Suite
+	controller      Team
+	mockTeamService *MockTeamService
+}
+
+func (suite *TeamTestSuite) SetupTest() {
+	suite.mockTeamService = &MockTeamService{}
+	suite.controller = NewTeam(suite.mockTeamService)
+}
+
+// teamRequest makes a request of the owner 1 to the team 2.
+func (suite *TeamTestSuite) teamRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("teamID", "2")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	req.Header.Add("Content-Type", "application/json")
+	return req
+}
+
+func (suite *TeamTestSuite) TestCreateEventTypeReturnsUnprocessableEntityForUnknownSchedulingType() {
+	w := httptest.NewRecorder()
+	req := suite.teamRequest(http.MethodPost, "/users/1/teams/2/event_types", `{"name":"Demo","slug":"demo","scheduling_type":"random","duration_mins":30}`)
+
+	suite.controller.CreateEventType(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"scheduling_type should be either collective or round_robin","fields":{"scheduling_type":"scheduling_type should be either collective or round_robin"}}
+`, string(body))
+	suite.mockTeamService.AssertNotCalled(suite.T(), "CreateEventType")
+}
+
+func (suite *TeamTestSuite) TestAddMemberReturnsConflictIfUserIsAMember() {
+	w := httptest.NewRecorder()
+	req := suite.teamRequest(http.MethodPost, "/users/1/teams/2/members", `{"user_id":5,"priority":1}`)
+	suite.mockTeamService.On("AddMember", req.Context(), 1, 2, contract.TeamMember{UserID: 5, Priority: 1}).
+		Return(contract.TeamMemberResponse{}, model.ErrDuplicateMember)
+
+	suite.controller.AddMember(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusConflict, res.StatusCode)
+	suite.Equal(`{"status_text":"conflict","code":"duplicate_member","message":"user is already a member of the team"}
+`, string(body))
+}
+
+func (suite *TeamTestSuite) TestAcceptMembershipAcceptsForTheUserOfThePath() {
+	w := httptest.NewRecorder()
+	req := suite.teamRequest(http.MethodPost, "/users/1/team_memberships/2/accept", "")
+	suite.mockTeamService.On("AcceptMembership", req.Context(), 1, 2).Return(nil)
+
+	suite.controller.AcceptMembership(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	suite.Equal(http.StatusNoContent, res.StatusCode)
+	suite.mockTeamService.AssertExpectations(suite.T())
+}
+
+func (suite *TeamTestSuite) TestBookReturnsEventsOfMembersBooked() {
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC)
+	w := httptest.NewRecorder()
+	req := suite.teamRequest(http.MethodPost, "/users/1/teams/2/events",
+		`{"team_event_type_id":3,"start_time":"2023-06-05T10:00:00Z","invitee_email":"test@example.xyz","invitee_name":"test"}`)
+	suite.mockTeamService.On("Book", req.Context(), 1, 2, contract.TeamEvent{TeamEventTypeID: 3, StartTime: start, InviteeEmail: "test@example.xyz", InviteeName: "test"}).
+		Return(contract.EventListResponse{Events: []contract.EventResponse{
+			{ID: 1, UserID: 5, SlotID: 7, TeamEventTypeID: 3, InviteeEmail: "test@example.xyz", InviteeName: "test", StartTime: start,
+				EndTime: start.Add(30 * time.Minute), Status: "confirmed", CreatedAt: start},
+		}}, nil)
+
+	suite.controller.Book(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusCreated, res.StatusCode)
+	suite.Equal(`{"events":[{"id":1,"user_id":5,"slot_id":7,"event_type_id":0,"team_event_type_id":3,"invitee_email":"test@example.xyz","invitee_name":"test","invitee_notes":"","start_time":"2023-06-05T10:00:00Z","end_time":"2023-06-05T10:30:00Z","status":"confirmed","created_at":"2023-06-05T10:00:00Z"}]}
+`, string(body))
+}
+
+func TestTeamTestSuite(t *testing.T) {
+	suite.Run(t, new(TeamTestSuite))
+}
@@ -1,15 +1,14 @@
 package controller
 
 import (
-	"database/sql"
-	"errors"
-	"log"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/go-chi/render"
 
 	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
 )
 
 type User struct {
@@ -17,7 +16,7 @@ type User struct {
 }
 
 // Create - Creates a new user
-// @Summary This API creates a new user
+// @Summary This API creates a new user, along with their first API key which is only returned here
 // @Tags user
 // @Accept json
 // @Produce json
@@ -28,14 +27,13 @@ func (user User) Create(w http.ResponseWriter, r *http.Request) {
 	input := contract.User{}
 
 	if err := render.Bind(r, &input); err != nil {
-		log.Printf("unable to bind request body: %s", err.Error())
-		render.Render(w, r, contract.ErrorRenderer(err))
+		renderBindError(w, r, err)
 		return
 	}
 
 	resp, err := user.userService.Create(ctx, input)
 	if err != nil {
-		render.Render(w, r, contract.ServerErrorRenderer(err))
+		renderError(w, r, err)
 		return
 	}
 
@@ -43,11 +41,141 @@ func (user User) Create(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, resp)
 }
 
+// SetSlug - Changes the slug of a user's public booking page
+// @Summary This API changes the path of a user's public booking page at /p/{slug}. The previous path stops working straight away.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param slug body contract.UserSlug true "Set slug"
+// @Param user_id path int true "user id"
+// @Success 200 {object} contract.UserSlug
+// @Router /users/{user_id}/slug [put]
+func (user User) SetSlug(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := contract.UserSlug{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := user.userService.SetSlug(ctx, userID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Get - Gets a user
+// @Summary This API returns a user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Success 200 {object} contract.UserResponse
+// @Router /users/{user_id} [get]
+func (user User) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := user.userService.Get(ctx, userID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// GetAll - Gets users
+// @Summary This API returns a page of users ordered by ID, optionally only the ones whose email contains the given text, along with how many users match in total
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security AdminToken
+// @Param email query string false "text to search emails for, ignoring case"
+// @Param limit query int false "number of users to return, 20 by default and at most 100"
+// @Param offset query int false "number of users to skip"
+// @Success 200 {object} contract.UserList
+// @Router /users [get]
+func (user User) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	limit, offset, err := paginationFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := user.userService.GetAll(ctx, r.URL.Query().Get("email"), limit, offset)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Update - Updates a user
+// @Summary This API changes the name, email, time zone or slug of a user, leaving out the fields not given. A new time zone only applies to availability set afterwards.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user body contract.UserUpdate true "Update user"
+// @Param user_id path int true "user id"
+// @Success 200 {object} contract.UserResponse
+// @Router /users/{user_id} [patch]
+func (user User) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := contract.UserUpdate{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := user.userService.Update(ctx, userID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Delete - Deletes a user
+// @Summary This API deletes a user along with their availability, event types, slots, events, calendars, webhooks and API keys. Invitees of upcoming events are not notified.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Router /users/{user_id} [delete]
+func (user User) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	err := user.userService.Delete(ctx, userID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 // SetAvailability - Sets a user's availability
 // @Summary This API creates or updates a user's availability
 // @Tags user
 // @Accept json
 // @Produce json
+// @Security ApiKey
 // @Param event body contract.UserAvailability true "Add user"
 // @Param user_id path int true "user id"
 // @Router /users/{user_id}/availability [post]
@@ -55,8 +183,7 @@ func (user User) SetAvailability(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	input := contract.UserAvailability{}
 	if err := render.Bind(r, &input); err != nil {
-		log.Printf("unable to bind request body: %s", err.Error())
-		render.Render(w, r, contract.ErrorRenderer(err))
+		renderBindError(w, r, err)
 		return
 	}
 
@@ -65,7 +192,7 @@ func (user User) SetAvailability(w http.ResponseWriter, r *http.Request) {
 	_, err := user.userService.SetAvailability(ctx, userID, input)
 
 	if err != nil {
-		render.Render(w, r, contract.ServerErrorRenderer(err))
+		renderError(w, r, err)
 		return
 	}
 
@@ -77,6 +204,7 @@ func (user User) SetAvailability(w http.ResponseWriter, r *http.Request) {
 // @Tags user
 // @Accept json
 // @Produce json
+// @Security ApiKey
 // @Param user_id path int true "user id"
 // @Success 200 {object} contract.UserAvailability
 // @Router /users/{user_id}/availability [get]
@@ -86,11 +214,7 @@ func (user User) GetAvailability(w http.ResponseWriter, r *http.Request) {
 
 	availability, err := user.userService.GetAvailability(ctx, userID)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			render.Render(w, r, contract.NotFoundErrorRenderer(err))
-			return
-		}
-		render.Render(w, r, contract.ServerErrorRenderer(err))
+		renderError(w, r, err)
 		return
 	}
 
@@ -102,41 +226,251 @@ func (user User) GetAvailability(w http.ResponseWriter, r *http.Request) {
 // @Tags user
 // @Accept json
 // @Produce json
+// @Security ApiKey
 // @Param user_id path int true "user id"
 // @Param second_user_id query int true "second user id"
-// @Success 200 {object} contract.UserAvailability
+// @Param from query string false "start of the range for concrete overlapping intervals, RFC 3339 timestamp or date"
+// @Param to query string false "end of the range for concrete overlapping intervals, at most 90 days after from, RFC 3339 timestamp or date"
+// @Success 200 {object} contract.UserAvailabilityOverlap
 // @Router /users/{user_id}/availability_overlap [get]
 func (user User) GetAvailabilityOverlap(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	user1ID := ctx.Value(ContextUserIDKey).(int)
 	userIDParam := r.URL.Query().Get("second_user_id")
 	if userIDParam == "" {
-		render.Render(w, r, contract.ErrorRenderer(errors.New("user ID is required")))
+		renderError(w, r, model.Validation("second_user_id", "user ID is required"))
 		return
 	}
 	user2ID, err := strconv.Atoi(userIDParam)
 	if err != nil {
-		render.Render(w, r, contract.ErrorRenderer(errors.New("invalid user ID")))
+		renderError(w, r, model.Validation("second_user_id", "invalid user ID"))
 		return
 	}
 
 	if user1ID == user2ID {
-		render.Render(w, r, contract.ErrorRenderer(errors.New("user IDs should be different")))
+		renderError(w, r, model.Validation("second_user_id", "user IDs should be different"))
 		return
 	}
 
-	overlap, err := user.userService.GetAvailabilityOverlap(ctx, user1ID, user2ID)
+	from, to, err := timeRangeFromQuery(r)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			render.Render(w, r, contract.NotFoundErrorRenderer(err))
+		renderError(w, r, err)
+		return
+	}
+	if to.Sub(from) > maxOverlapRange {
+		renderError(w, r, model.Validation("to", "range should not exceed 90 days"))
+		return
+	}
+
+	overlap, err := user.userService.GetAvailabilityOverlap(ctx, user1ID, user2ID, from, to)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, overlap)
+
+}
+
+// GetFreeOverlap - Gets the free time common to a group of users
+// @Summary This API returns the concrete intervals during which all the given users are available and not booked. The caller has to be one of the users.
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_ids query string true "comma separated user ids"
+// @Param from query string true "start of the range, RFC 3339 timestamp or date"
+// @Param to query string true "end of the range, at most 90 days after from, RFC 3339 timestamp or date"
+// @Param duration query int false "minimum length of the intervals in minutes"
+// @Success 200 {object} contract.AvailabilityOverlap
+// @Router /availability_overlap [get]
+func (user User) GetFreeOverlap(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	userIDs, err := userIDsFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	from, to, err := timeRangeFromQuery(r)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+	if from.IsZero() {
+		renderError(w, r, model.Validation("from", "from and to are required"))
+		return
+	}
+	if to.Sub(from) > maxOverlapRange {
+		renderError(w, r, model.Validation("to", "range should not exceed 90 days"))
+		return
+	}
+
+	var duration int
+	if durationParam := r.URL.Query().Get("duration"); durationParam != "" {
+		duration, err = strconv.Atoi(durationParam)
+		if err != nil || duration <= 0 {
+			renderError(w, r, model.Validation("duration", "invalid duration"))
 			return
 		}
-		render.Render(w, r, contract.ServerErrorRenderer(err))
+	}
+
+	// Callers can only compare their own free time with others'
+	if !containsCaller(ctx, userIDs) {
+		renderError(w, r, model.ErrForbidden)
+		return
+	}
+
+	overlap, err := user.userService.GetFreeOverlap(ctx, userIDs, from, to, time.Duration(duration)*time.Minute)
+	if err != nil {
+		renderError(w, r, err)
 		return
 	}
 
 	render.JSON(w, r, overlap)
+}
+
+// CreateOverride - Creates an availability override
+// @Summary This API replaces a user's weekly availability for a date or range of dates, or marks them unavailable
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param override body contract.AvailabilityOverride true "Add availability override"
+// @Param user_id path int true "user id"
+// @Success 201 {object} contract.AvailabilityOverrideResponse
+// @Router /users/{user_id}/availability/overrides [post]
+func (user User) CreateOverride(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := contract.AvailabilityOverride{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := user.userService.CreateOverride(ctx, userID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+// GetOverrides - Gets a user's availability overrides
+// @Summary This API returns all availability overrides of a user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Success 200 {object} contract.AvailabilityOverrideList
+// @Router /users/{user_id}/availability/overrides [get]
+func (user User) GetOverrides(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := user.userService.GetOverrides(ctx, userID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// GetOverride - Gets an availability override
+// @Summary This API returns an availability override of a user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param override_id path int true "override id"
+// @Success 200 {object} contract.AvailabilityOverrideResponse
+// @Router /users/{user_id}/availability/overrides/{override_id} [get]
+func (user User) GetOverride(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	overrideID, err := idFromURL(r, "overrideID", "override ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := user.userService.GetOverride(ctx, userID, overrideID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// UpdateOverride - Updates an availability override
+// @Summary This API replaces an availability override of a user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param override body contract.AvailabilityOverride true "Update availability override"
+// @Param user_id path int true "user id"
+// @Param override_id path int true "override id"
+// @Success 200 {object} contract.AvailabilityOverrideResponse
+// @Router /users/{user_id}/availability/overrides/{override_id} [put]
+func (user User) UpdateOverride(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	overrideID, err := idFromURL(r, "overrideID", "override ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	input := contract.AvailabilityOverride{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	resp, err := user.userService.UpdateOverride(ctx, userID, overrideID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// DeleteOverride - Deletes an availability override
+// @Summary This API deletes an availability override of a user
+// @Tags user
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param override_id path int true "override id"
+// @Router /users/{user_id}/availability/overrides/{override_id} [delete]
+func (user User) DeleteOverride(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	overrideID, err := idFromURL(r, "overrideID", "override ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	err = user.userService.DeleteOverride(ctx, userID, overrideID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
 
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func NewUser(userService UserService) User {
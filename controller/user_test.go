@@ -4,15 +4,19 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/harbor-xyz/coding-project/contract"
 	"github.com/harbor-xyz/coding-project/model"
 
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/datatypes"
 )
@@ -29,11 +33,21 @@ func (suite *UserTestSuite) SetupTest() {
 
 }
 
+func (suite *UserTestSuite) readBody(res *http.Response) string {
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	return string(body)
+}
+
 func (suite *UserTestSuite) TestCreateHappyFlow() {
 	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"test","email":"test@example.xyz"}`))
 	req.Header.Add("Content-Type", "application/json")
 	w := httptest.NewRecorder()
-	suite.mockService.On("Create", req.Context(), contract.User{Name: "test", Email: "test@example.xyz"}).Return(contract.UserResponse{ID: 1}, nil)
+	suite.mockService.On("Create", req.Context(), contract.User{Name: "test", Email: "test@example.xyz"}).Return(contract.UserResponse{ID: 1,
+		Name: "test", Email: "test@example.xyz", TimeZone: "UTC", Slug: "test", APIKey: "cal_key", CreatedAt: time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)}, nil)
 
 	suite.controller.Create(w, req)
 
@@ -44,12 +58,146 @@ func (suite *UserTestSuite) TestCreateHappyFlow() {
 		suite.Error(errors.New("expected error to be nil got"), err)
 	}
 	suite.Equal(http.StatusCreated, res.StatusCode)
-	suite.Equal(`{"id":1}
+	suite.Equal(`{"id":1,"name":"test","email":"test@example.xyz","time_zone":"UTC","slug":"test","api_key":"cal_key","created_at":"2023-06-01T00:00:00Z"}
 `, string(body))
 	suite.mockService.AssertExpectations(suite.T())
 }
 
-func (suite *UserTestSuite) TestCreateShouldReturnBadRequestWhenRequestBodyIsIncomplete() {
+func (suite *UserTestSuite) TestCreateReturnsConflictIfEmailIsTaken() {
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"test","email":"test@example.xyz"}`))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.mockService.On("Create", req.Context(), contract.User{Name: "test", Email: "test@example.xyz"}).Return(contract.UserResponse{}, model.ErrDuplicateEmail)
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusConflict, res.StatusCode)
+	suite.Equal(`{"status_text":"conflict","code":"duplicate_email","message":"email is already in use"}
+`, suite.readBody(res))
+}
+
+func (suite *UserTestSuite) TestGetReturnsNotFoundIfUserDoesNotExist() {
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ContextUserIDKey, 1))
+	w := httptest.NewRecorder()
+	suite.mockService.On("Get", req.Context(), 1).Return(contract.UserResponse{}, model.NotFound("user", sql.ErrNoRows))
+
+	suite.controller.Get(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(`{"status_text":"not found","code":"user_not_found","message":"user not found"}
+`, suite.readBody(res))
+}
+
+func (suite *UserTestSuite) TestGetAllPassesSearchAndPagination() {
+	req := httptest.NewRequest(http.MethodGet, "/users?email=example&limit=10&offset=30", nil)
+	w := httptest.NewRecorder()
+	suite.mockService.On("GetAll", req.Context(), "example", 10, 30).Return(contract.UserList{Users: []contract.UserResponse{}, Total: 30}, nil)
+
+	suite.controller.GetAll(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal(`{"users":[],"total":30}
+`, suite.readBody(res))
+}
+
+func (suite *UserTestSuite) TestGetAllDefaultsToFirstPage() {
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	w := httptest.NewRecorder()
+	suite.mockService.On("GetAll", req.Context(), "", 20, 0).Return(contract.UserList{Users: []contract.UserResponse{}}, nil)
+
+	suite.controller.GetAll(w, req)
+
+	suite.Equal(http.StatusOK, w.Result().StatusCode)
+}
+
+func (suite *UserTestSuite) TestGetAllReturnsUnprocessableEntityForInvalidPagination() {
+	cases := map[string][2]string{
+		"/users?limit=0":    {"limit", "limit should be between 1 and 100"},
+		"/users?limit=101":  {"limit", "limit should be between 1 and 100"},
+		"/users?offset=-1":  {"offset", "invalid offset"},
+		"/users?offset=one": {"offset", "invalid offset"},
+	}
+	for target, c := range cases {
+		field, message := c[0], c[1]
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		w := httptest.NewRecorder()
+
+		suite.controller.GetAll(w, req)
+
+		res := w.Result()
+		suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+		suite.Equal(fmt.Sprintf("{\"status_text\":\"unprocessable entity\",\"code\":\"validation_failed\",\"message\":%q,\"fields\":{%q:%q}}\n", message, field, message), suite.readBody(res))
+	}
+	suite.mockService.AssertNotCalled(suite.T(), "GetAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UserTestSuite) TestUpdateOnlyPassesGivenFields() {
+	req := httptest.NewRequest(http.MethodPatch, "/users/1", strings.NewReader(`{"name":"Jane Doe"}`))
+	req.Header.Add("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), ContextUserIDKey, 1))
+	w := httptest.NewRecorder()
+	name := "Jane Doe"
+	suite.mockService.On("Update", req.Context(), 1, contract.UserUpdate{Name: &name}).Return(contract.UserResponse{ID: 1, Name: "Jane Doe"}, nil)
+
+	suite.controller.Update(w, req)
+
+	suite.Equal(http.StatusOK, w.Result().StatusCode)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestUpdateReturnsUnprocessableEntityForInvalidInput() {
+	cases := map[string][2]string{
+		`{"name":""}`:                  {"name", "name should not be empty"},
+		`{"email":""}`:                 {"email", "email should not be empty"},
+		`{"time_zone":"Mars/Olympus"}`: {"time_zone", "invalid time_zone"},
+		`{"slug":"Jane Doe"}`:          {"slug", "slug should only contain lowercase letters, digits and hyphens"},
+	}
+	for body, c := range cases {
+		field, message := c[0], c[1]
+		req := httptest.NewRequest(http.MethodPatch, "/users/1", strings.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+		req = req.WithContext(context.WithValue(req.Context(), ContextUserIDKey, 1))
+		w := httptest.NewRecorder()
+
+		suite.controller.Update(w, req)
+
+		res := w.Result()
+		suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+		suite.Equal(fmt.Sprintf("{\"status_text\":\"unprocessable entity\",\"code\":\"validation_failed\",\"message\":%q,\"fields\":{%q:%q}}\n", message, field, message), suite.readBody(res))
+	}
+	suite.mockService.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UserTestSuite) TestUpdateReturnsConflictIfEmailIsTaken() {
+	req := httptest.NewRequest(http.MethodPatch, "/users/1", strings.NewReader(`{"email":"john@example.xyz"}`))
+	req.Header.Add("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), ContextUserIDKey, 1))
+	w := httptest.NewRecorder()
+	email := "john@example.xyz"
+	suite.mockService.On("Update", req.Context(), 1, contract.UserUpdate{Email: &email}).Return(contract.UserResponse{}, model.ErrDuplicateEmail)
+
+	suite.controller.Update(w, req)
+
+	suite.Equal(http.StatusConflict, w.Result().StatusCode)
+}
+
+func (suite *UserTestSuite) TestDeleteHappyFlow() {
+	req := httptest.NewRequest(http.MethodDelete, "/users/1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ContextUserIDKey, 1))
+	w := httptest.NewRecorder()
+	suite.mockService.On("Delete", req.Context(), 1).Return(nil)
+
+	suite.controller.Delete(w, req)
+
+	suite.Equal(http.StatusNoContent, w.Result().StatusCode)
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestCreateShouldReturnUnprocessableEntityWhenRequestBodyIsIncomplete() {
 	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"test"}`))
 	req.Header.Add("Content-Type", "application/json")
 	w := httptest.NewRecorder()
@@ -62,12 +210,26 @@ func (suite *UserTestSuite) TestCreateShouldReturnBadRequestWhenRequestBodyIsInc
 	if err != nil {
 		suite.Error(errors.New("expected error to be nil got"), err)
 	}
-	suite.Equal(http.StatusBadRequest, res.StatusCode)
-	suite.Equal(`{"status_text":"bad request","message":"email is required"}
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"email is required","fields":{"email":"email is required"}}
 `, string(body)) // This newline is needed because chi returns the response ending with a \n
 	suite.mockService.AssertNotCalled(suite.T(), "Created")
 }
 
+func (suite *UserTestSuite) TestCreateShouldReturnBadRequestWhenRequestBodyIsMalformed() {
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":`))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusBadRequest, res.StatusCode)
+	suite.Equal(`{"status_text":"bad request","code":"bad_request","message":"unexpected EOF"}
+`, suite.readBody(res))
+	suite.mockService.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
 func (suite *UserTestSuite) TestCreateShouldReturnServerErrorWhenServiceReturnsError() {
 	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"test","email":"test@example.xyz"}`))
 	req.Header.Add("Content-Type", "application/json")
@@ -83,7 +245,7 @@ func (suite *UserTestSuite) TestCreateShouldReturnServerErrorWhenServiceReturnsE
 		suite.Error(errors.New("expected error to be nil got"), err)
 	}
 	suite.Equal(http.StatusInternalServerError, res.StatusCode)
-	suite.Equal(`{"status_text":"internal server error","message":"some error"}
+	suite.Equal(`{"status_text":"internal server error","code":"internal_error","message":"internal server error"}
 `, string(body)) // This newline is needed because chi returns the response ending with a \n
 	suite.mockService.AssertExpectations(suite.T())
 }
@@ -137,7 +299,7 @@ func (suite *UserTestSuite) TestSetAvailabilityHappyPath() {
 	suite.mockService.AssertExpectations(suite.T())
 }
 
-func (suite *UserTestSuite) TestSetAvailabilityShouldReturnBadRequestWhenRequestBodyIsIncomplete() {
+func (suite *UserTestSuite) TestSetAvailabilityShouldReturnUnprocessableEntityWhenRequestBodyIsIncomplete() {
 	req := httptest.NewRequest(http.MethodPost, "/users/1/availability", strings.NewReader(`{"meeting_duration_mins": 30}`))
 	req.Header.Add("Content-Type", "application/json")
 	w := httptest.NewRecorder()
@@ -150,12 +312,89 @@ func (suite *UserTestSuite) TestSetAvailabilityShouldReturnBadRequestWhenRequest
 	if err != nil {
 		suite.Error(errors.New("expected error to be nil got"), err)
 	}
-	suite.Equal(http.StatusBadRequest, res.StatusCode)
-	suite.Equal(`{"status_text":"bad request","message":"at least one day's availability is required"}
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"at least one day's availability is required","fields":{"availability":"at least one day's availability is required"}}
 `, string(body)) // This newline is needed because chi returns the response ending with a \n
 	suite.mockService.AssertNotCalled(suite.T(), "SetAvailability")
 }
 
+func (suite *UserTestSuite) TestSetAvailabilityShouldReturnUnprocessableEntityWhenWindowsOverlap() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/availability", strings.NewReader(
+		`{
+			"availability":[
+				{"day": "monday", "start_time": "09:00", "end_time": "12:00"},
+				{"day": "monday", "start_time": "11:00", "end_time": "18:00"}
+			],
+			"meeting_duration_mins": 30
+		}`))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.controller.SetAvailability(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"availability windows overlap for monday","fields":{"availability":"availability windows overlap for monday"}}
+`, string(body))
+	suite.mockService.AssertNotCalled(suite.T(), "SetAvailability")
+}
+
+func (suite *UserTestSuite) TestSetAvailabilityShouldReturnUnprocessableEntityWhenWindowIsInverted() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/availability", strings.NewReader(
+		`{
+			"availability":[
+				{"day": "monday", "start_time": "12:00", "end_time": "12:00"}
+			],
+			"meeting_duration_mins": 30
+		}`))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.controller.SetAvailability(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"end_time should be after start_time for monday","fields":{"availability":"end_time should be after start_time for monday"}}
+`, string(body))
+	suite.mockService.AssertNotCalled(suite.T(), "SetAvailability")
+}
+
+func (suite *UserTestSuite) TestSetAvailabilityShouldReturnUnprocessableEntityWhenSchedulingRuleIsNegative() {
+	req := httptest.NewRequest(http.MethodPost, "/users/1/availability", strings.NewReader(
+		`{
+			"availability":[
+				{"day": "monday", "start_time": "09:00", "end_time": "12:00"}
+			],
+			"meeting_duration_mins": 30,
+			"scheduling_rules": {"before_buffer_mins": 10, "min_notice_mins": -60}
+		}`))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.controller.SetAvailability(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"min_notice_mins should not be negative","fields":{"scheduling_rules.min_notice_mins":"min_notice_mins should not be negative"}}
+`, string(body))
+	suite.mockService.AssertNotCalled(suite.T(), "SetAvailability")
+}
+
 func (suite *UserTestSuite) TestSetAvailabilityShouldReturnServerErrorWhenServiceReturnsError() {
 	w := httptest.NewRecorder()
 	req := httptest.NewRequest(http.MethodPost, "/users/1/availability", strings.NewReader(
@@ -201,7 +440,7 @@ func (suite *UserTestSuite) TestSetAvailabilityShouldReturnServerErrorWhenServic
 		suite.Error(errors.New("expected error to be nil got"), err)
 	}
 	suite.Equal(http.StatusInternalServerError, res.StatusCode)
-	suite.Equal(`{"status_text":"internal server error","message":"some error"}
+	suite.Equal(`{"status_text":"internal server error","code":"internal_error","message":"internal server error"}
 `, string(body)) // This newline is needed because chi returns the response ending with a \n
 	suite.mockService.AssertExpectations(suite.T())
 }
@@ -237,7 +476,7 @@ func (suite *UserTestSuite) TestGetAvailabilityHappyPath() {
 	}
 
 	suite.Equal(http.StatusOK, w.Result().StatusCode)
-	suite.Equal(`{"availability":[{"day":"monday","start_time":"10:00:00","end_time":"17:00:00"},{"day":"tuesday","start_time":"09:00:00","end_time":"17:00:00"}],"meeting_duration_mins":30}
+	suite.Equal(`{"availability":[{"day":"monday","start_time":"10:00:00","end_time":"17:00:00"},{"day":"tuesday","start_time":"09:00:00","end_time":"17:00:00"}],"meeting_duration_mins":30,"time_zone":"","scheduling_rules":{"before_buffer_mins":0,"after_buffer_mins":0,"min_notice_mins":0,"max_horizon_days":0,"max_per_day":0,"max_per_week":0}}
 `, string(body))
 }
 
@@ -258,7 +497,7 @@ func (suite *UserTestSuite) TestGetAvailabilityReturnsServerErrorWhenServiceRetu
 	}
 
 	suite.Equal(http.StatusInternalServerError, w.Result().StatusCode)
-	suite.Equal(`{"status_text":"internal server error","message":"some error"}
+	suite.Equal(`{"status_text":"internal server error","code":"internal_error","message":"internal server error"}
 `, string(body))
 }
 
@@ -267,7 +506,7 @@ func (suite *UserTestSuite) TestGetAvailabilityReturnsNotFoundErrorWhenAvailabil
 	req := httptest.NewRequest(http.MethodGet, "/users/1/availability", nil)
 	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
 	req.Header.Add("Content-Type", "application/json")
-	suite.mockService.On("GetAvailability", req.Context(), 1).Return(contract.UserAvailability{}, sql.ErrNoRows)
+	suite.mockService.On("GetAvailability", req.Context(), 1).Return(contract.UserAvailability{}, model.NotFound("availability", sql.ErrNoRows))
 
 	suite.controller.GetAvailability(w, req)
 
@@ -279,7 +518,7 @@ func (suite *UserTestSuite) TestGetAvailabilityReturnsNotFoundErrorWhenAvailabil
 	}
 
 	suite.Equal(http.StatusNotFound, w.Result().StatusCode)
-	suite.Equal(`{"status_text":"not found","message":"sql: no rows in result set"}
+	suite.Equal(`{"status_text":"not found","code":"availability_not_found","message":"availability not found"}
 `, string(body))
 }
 
@@ -288,7 +527,7 @@ func (suite *UserTestSuite) TestGetAvailabilityOverlapHappyPath() {
 	req := httptest.NewRequest(http.MethodGet, "/users/1/availability_overlap?second_user_id=2", nil)
 	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
 	req.Header.Add("Content-Type", "application/json")
-	suite.mockService.On("GetAvailabilityOverlap", req.Context(), 1, 2).Return(contract.UserAvailabilityOverlap{
+	suite.mockService.On("GetAvailabilityOverlap", req.Context(), 1, 2, time.Time{}, time.Time{}).Return(contract.UserAvailabilityOverlap{
 		Overlap: []model.DayAvailability{
 			{
 				Day:       "monday",
@@ -322,7 +561,7 @@ func (suite *UserTestSuite) TestGetAvailabilityOverlapReturnsNullResponseWhenNoO
 	req := httptest.NewRequest(http.MethodGet, "/users/1/availability_overlap?second_user_id=2", nil)
 	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
 	req.Header.Add("Content-Type", "application/json")
-	suite.mockService.On("GetAvailabilityOverlap", req.Context(), 1, 2).Return(contract.UserAvailabilityOverlap{}, nil)
+	suite.mockService.On("GetAvailabilityOverlap", req.Context(), 1, 2, time.Time{}, time.Time{}).Return(contract.UserAvailabilityOverlap{}, nil)
 
 	suite.controller.GetAvailabilityOverlap(w, req)
 
@@ -343,7 +582,7 @@ func (suite *UserTestSuite) TestGetAvailabilityOverlapReturnsErrorWhenServiceRet
 	req := httptest.NewRequest(http.MethodGet, "/users/1/availability_overlap?second_user_id=2", nil)
 	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
 	req.Header.Add("Content-Type", "application/json")
-	suite.mockService.On("GetAvailabilityOverlap", req.Context(), 1, 2).Return(contract.UserAvailabilityOverlap{}, errors.New("some error"))
+	suite.mockService.On("GetAvailabilityOverlap", req.Context(), 1, 2, time.Time{}, time.Time{}).Return(contract.UserAvailabilityOverlap{}, errors.New("some error"))
 
 	suite.controller.GetAvailabilityOverlap(w, req)
 
@@ -355,8 +594,291 @@ func (suite *UserTestSuite) TestGetAvailabilityOverlapReturnsErrorWhenServiceRet
 	}
 
 	suite.Equal(http.StatusInternalServerError, w.Result().StatusCode)
-	suite.Equal(`{"status_text":"internal server error","message":"some error"}
+	suite.Equal(`{"status_text":"internal server error","code":"internal_error","message":"internal server error"}
+`, string(body))
+}
+
+func (suite *UserTestSuite) TestGetAvailabilityOverlapPassesRequestedTimeRange() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/1/availability_overlap?second_user_id=2&from=2023-06-05&to=2023-06-06", nil)
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 6, 7, 0, 0, 0, 0, time.UTC)
+	suite.mockService.On("GetAvailabilityOverlap", req.Context(), 1, 2, from, to).Return(contract.UserAvailabilityOverlap{
+		Intervals: []contract.Interval{{StartTime: from.Add(9 * time.Hour), EndTime: from.Add(10 * time.Hour)}},
+	}, nil)
+
+	suite.controller.GetAvailabilityOverlap(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal(`{"overlap":null,"intervals":[{"start_time":"2023-06-05T09:00:00Z","end_time":"2023-06-05T10:00:00Z"}]}
+`, string(body))
+}
+
+func (suite *UserTestSuite) TestGetAvailabilityOverlapReturnsUnprocessableEntityWhenRangeIsIncomplete() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/1/availability_overlap?second_user_id=2&from=2023-06-05", nil)
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+
+	suite.controller.GetAvailabilityOverlap(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"both from and to are required","fields":{"to":"both from and to are required"}}
+`, string(body))
+}
+
+func (suite *UserTestSuite) TestGetAvailabilityOverlapReturnsUnprocessableEntityWhenRangeIsTooLong() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/1/availability_overlap?second_user_id=2&from=2023-06-05&to=2024-06-05", nil)
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+
+	suite.controller.GetAvailabilityOverlap(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"range should not exceed 90 days","fields":{"to":"range should not exceed 90 days"}}
+`, string(body))
+	suite.mockService.AssertNotCalled(suite.T(), "GetAvailabilityOverlap")
+}
+
+func (suite *UserTestSuite) TestGetFreeOverlapHappyPath() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/availability_overlap?user_ids=1,2,3,2&from=2023-06-05T00:00:00Z&to=2023-06-07T00:00:00Z&duration=30", nil)
+	req = req.WithContext(context.WithValue(context.Background(), ContextCallerIDKey, 2))
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 6, 7, 0, 0, 0, 0, time.UTC)
+	suite.mockService.On("GetFreeOverlap", req.Context(), []int{1, 2, 3}, from, to, 30*time.Minute).Return(contract.AvailabilityOverlap{
+		UserIDs:      []int{1, 2, 3},
+		From:         from,
+		To:           to,
+		DurationMins: 30,
+		Intervals:    []contract.Interval{{StartTime: from.Add(9 * time.Hour), EndTime: from.Add(10 * time.Hour)}},
+	}, nil)
+
+	suite.controller.GetFreeOverlap(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal(`{"user_ids":[1,2,3],"from":"2023-06-05T00:00:00Z","to":"2023-06-07T00:00:00Z","duration_mins":30,"intervals":[{"start_time":"2023-06-05T09:00:00Z","end_time":"2023-06-05T10:00:00Z"}]}
+`, string(body))
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestGetFreeOverlapReturnsUnprocessableEntityForInvalidParameters() {
+	cases := map[string][2]string{
+		"/availability_overlap?from=2023-06-05&to=2023-06-07":                         {"user_ids", "user_ids is required"},
+		"/availability_overlap?user_ids=1,a&from=2023-06-05&to=2023-06-07":            {"user_ids", "invalid user_ids"},
+		"/availability_overlap?user_ids=1,2":                                          {"from", "from and to are required"},
+		"/availability_overlap?user_ids=1,2&from=2023-06-05&to=2023-12-07":            {"to", "range should not exceed 90 days"},
+		"/availability_overlap?user_ids=1,2&from=2023-06-05&to=2023-06-07&duration=0": {"duration", "invalid duration"},
+	}
+	for url, c := range cases {
+		field, message := c[0], c[1]
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+
+		suite.controller.GetFreeOverlap(w, req)
+
+		res := w.Result()
+		body, err := io.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			suite.Error(errors.New("expected error to be nil got"), err)
+		}
+		suite.Equal(http.StatusUnprocessableEntity, res.StatusCode, url)
+		suite.Equal(fmt.Sprintf("{\"status_text\":\"unprocessable entity\",\"code\":\"validation_failed\",\"message\":%q,\"fields\":{%q:%q}}\n", message, field, message), string(body), url)
+	}
+	suite.mockService.AssertNotCalled(suite.T(), "GetFreeOverlap")
+}
+
+func (suite *UserTestSuite) TestGetFreeOverlapReturnsForbiddenWhenCallerIsNotOneOfTheUsers() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/availability_overlap?user_ids=1,2&from=2023-06-05&to=2023-06-07", nil)
+	req = req.WithContext(context.WithValue(context.Background(), ContextCallerIDKey, 3))
+
+	suite.controller.GetFreeOverlap(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	suite.Equal(http.StatusForbidden, res.StatusCode)
+	suite.mockService.AssertNotCalled(suite.T(), "GetFreeOverlap")
+}
+
+func (suite *UserTestSuite) TestCreateOverrideHappyPath() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/1/availability/overrides", strings.NewReader(
+		`{"start_date":"2023-12-24","availability":[{"start_time":"10:00","end_time":"12:00"}]}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	availability := []model.Availability{{StartTime: datatypes.NewTime(10, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)}}
+	suite.mockService.On("CreateOverride", req.Context(), 1, contract.AvailabilityOverride{
+		StartDate:    "2023-12-24",
+		EndDate:      "2023-12-24",
+		Availability: availability,
+	}).Return(contract.AvailabilityOverrideResponse{ID: 1, StartDate: "2023-12-24", EndDate: "2023-12-24", Availability: availability}, nil)
+
+	suite.controller.CreateOverride(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusCreated, res.StatusCode)
+	suite.Equal(`{"id":1,"start_date":"2023-12-24","end_date":"2023-12-24","unavailable":false,"availability":[{"start_time":"10:00:00","end_time":"12:00:00"}]}
 `, string(body))
+	suite.mockService.AssertExpectations(suite.T())
+}
+
+func (suite *UserTestSuite) TestCreateOverrideReturnsUnprocessableEntityWhenNeitherWindowsNorUnavailableAreGiven() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/1/availability/overrides", strings.NewReader(`{"start_date":"2023-12-24"}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+
+	suite.controller.CreateOverride(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+	suite.Equal(`{"status_text":"unprocessable entity","code":"validation_failed","message":"either availability or unavailable is required","fields":{"availability":"either availability or unavailable is required"}}
+`, string(body))
+	suite.mockService.AssertNotCalled(suite.T(), "CreateOverride")
+}
+
+func (suite *UserTestSuite) TestCreateOverrideReturnsConflictWhenOverridesOverlap() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users/1/availability/overrides", strings.NewReader(
+		`{"start_date":"2023-12-24","end_date":"2023-12-26","unavailable":true}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	suite.mockService.On("CreateOverride", req.Context(), 1, contract.AvailabilityOverride{
+		StartDate:   "2023-12-24",
+		EndDate:     "2023-12-26",
+		Unavailable: true,
+	}).Return(contract.AvailabilityOverrideResponse{}, model.ErrOverlappingOverride)
+
+	suite.controller.CreateOverride(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusConflict, res.StatusCode)
+	suite.Equal(`{"status_text":"conflict","code":"overlapping_override","message":"override overlaps an existing override"}
+`, string(body))
+}
+
+func (suite *UserTestSuite) TestGetOverrideReturnsNotFoundWhenOverrideDoesNotExist() {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/users/1/availability/overrides/5", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("overrideID", "5")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	req = req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+	suite.mockService.On("GetOverride", req.Context(), 1, 5).Return(contract.AvailabilityOverrideResponse{}, model.NotFound("override", sql.ErrNoRows))
+
+	suite.controller.GetOverride(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(`{"status_text":"not found","code":"override_not_found","message":"override not found"}
+`, string(body))
+}
+
+func (suite *UserTestSuite) TestCreateReturnsConflictIfSlugIsTaken() {
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"test","email":"test@example.xyz","slug":"jane"}`))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.mockService.On("Create", req.Context(), contract.User{Name: "test", Email: "test@example.xyz", Slug: "jane"}).
+		Return(contract.UserResponse{}, model.ErrDuplicateSlug)
+
+	suite.controller.Create(w, req)
+
+	suite.Equal(http.StatusConflict, w.Result().StatusCode)
+}
+
+func (suite *UserTestSuite) TestSetSlugHappyFlow() {
+	req := httptest.NewRequest(http.MethodPut, "/users/1/slug", strings.NewReader(`{"slug":"jane-doe"}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.mockService.On("SetSlug", req.Context(), 1, contract.UserSlug{Slug: "jane-doe"}).Return(contract.UserSlug{Slug: "jane-doe"}, nil)
+
+	suite.controller.SetSlug(w, req)
+
+	res := w.Result()
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal(`{"slug":"jane-doe"}
+`, string(body))
+}
+
+func (suite *UserTestSuite) TestSetSlugReturnsUnprocessableEntityForInvalidSlug() {
+	req := httptest.NewRequest(http.MethodPut, "/users/1/slug", strings.NewReader(`{"slug":"Jane Doe"}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	suite.controller.SetSlug(w, req)
+
+	suite.Equal(http.StatusUnprocessableEntity, w.Result().StatusCode)
+	suite.mockService.AssertNotCalled(suite.T(), "SetSlug", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UserTestSuite) TestSetSlugReturnsConflictIfAnotherUserHasIt() {
+	req := httptest.NewRequest(http.MethodPut, "/users/1/slug", strings.NewReader(`{"slug":"jane"}`))
+	req = req.WithContext(context.WithValue(context.Background(), ContextUserIDKey, 1))
+	req.Header.Add("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	suite.mockService.On("SetSlug", req.Context(), 1, contract.UserSlug{Slug: "jane"}).Return(contract.UserSlug{}, model.ErrDuplicateSlug)
+
+	suite.controller.SetSlug(w, req)
+
+	suite.Equal(http.StatusConflict, w.Result().StatusCode)
 }
 
 func TestUserTest(t *testing.T) {
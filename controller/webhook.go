@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"github.com/harbor-xyz/coding-project/contract"
+)
+
+type Webhook struct {
+	webhookService WebhookService
+}
+
+// Create - Adds a webhook
+// @Summary This API subscribes a URL to booking and availability events of the user. Payloads are signed with the webhook secret, which is only returned here.
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param webhook body contract.Webhook true "Add webhook"
+// @Param user_id path int true "user id"
+// @Success 201 {object} contract.WebhookResponse
+// @Router /users/{user_id}/webhooks [post]
+func (webhook Webhook) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	input := contract.Webhook{}
+	if err := render.Bind(r, &input); err != nil {
+		renderBindError(w, r, err)
+		return
+	}
+
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := webhook.webhookService.Create(ctx, userID, input)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, resp)
+}
+
+// GetAll - Gets a user's webhooks
+// @Summary This API returns all webhooks of a user
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Success 200 {object} contract.WebhookList
+// @Router /users/{user_id}/webhooks [get]
+func (webhook Webhook) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+
+	resp, err := webhook.webhookService.GetAll(ctx, userID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+// Delete - Deletes a webhook
+// @Summary This API deletes a webhook of a user along with its deliveries
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param webhook_id path int true "webhook id"
+// @Router /users/{user_id}/webhooks/{webhook_id} [delete]
+func (webhook Webhook) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	webhookID, err := idFromURL(r, "webhookID", "webhook ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	err = webhook.webhookService.Delete(ctx, userID, webhookID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetDeliveries - Gets the delivery log of a webhook
+// @Summary This API returns the latest 100 deliveries of a webhook, newest first, with the outcome of their last attempt
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Security ApiKey
+// @Param user_id path int true "user id"
+// @Param webhook_id path int true "webhook id"
+// @Success 200 {object} contract.WebhookDeliveryList
+// @Router /users/{user_id}/webhooks/{webhook_id}/deliveries [get]
+func (webhook Webhook) GetDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userID := ctx.Value(ContextUserIDKey).(int)
+	webhookID, err := idFromURL(r, "webhookID", "webhook ID")
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	resp, err := webhook.webhookService.GetDeliveries(ctx, userID, webhookID)
+	if err != nil {
+		renderError(w, r, err)
+		return
+	}
+
+	render.JSON(w, r, resp)
+}
+
+func NewWebhook(webhookService WebhookService) Webhook {
+	return Webhook{webhookService: webhookService}
+}
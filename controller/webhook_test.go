@@ -0,0 +1,124 @@
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+	controller         Webhook
+	mockWebhookService *MockWebhookService
+}
+
+func (suite *WebhookTestSuite) SetupTest() {
+	suite.mockWebhookService = &MockWebhookService{}
+	suite.controller = NewWebhook(suite.mockWebhookService)
+}
+
+func (suite *WebhookTestSuite) request(method, target string, body io.Reader) *http.Request {
+	req := httptest.NewRequest(method, target, body)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("webhookID", "2")
+	ctx := context.WithValue(context.Background(), ContextUserIDKey, 1)
+	return req.WithContext(context.WithValue(ctx, chi.RouteCtxKey, rctx))
+}
+
+func (suite *WebhookTestSuite) readBody(res *http.Response) string {
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		suite.Error(errors.New("expected error to be nil got"), err)
+	}
+	return string(body)
+}
+
+func (suite *WebhookTestSuite) TestCreateHappyFlow() {
+	createdAt := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodPost, "/users/1/webhooks", strings.NewReader(`{"url":"https://example.xyz/hook","events":["booking.created"]}`))
+	req.Header.Add("Content-Type", "application/json")
+	suite.mockWebhookService.On("Create", req.Context(), 1, contract.Webhook{URL: "https://example.xyz/hook", Events: []string{model.WebhookBookingCreated}}).
+		Return(contract.WebhookResponse{ID: 2, UserID: 1, URL: "https://example.xyz/hook", Secret: "secret", Events: []string{model.WebhookBookingCreated},
+			CreatedAt: createdAt}, nil)
+
+	suite.controller.Create(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusCreated, res.StatusCode)
+	suite.Equal(`{"id":2,"user_id":1,"url":"https://example.xyz/hook","secret":"secret","events":["booking.created"],"created_at":"2023-06-01T00:00:00Z"}
+`, suite.readBody(res))
+}
+
+func (suite *WebhookTestSuite) TestCreateReturnsUnprocessableEntityForInvalidInput() {
+	cases := map[string][2]string{
+		`{"url":"ftp://example.xyz/hook","events":["booking.created"]}`:                    {"url", "url should be an absolute http or https URL"},
+		`{"url":"https://example.xyz/hook"}`:                                               {"events", "at least one event is required"},
+		`{"url":"https://example.xyz/hook","events":["booking.deleted"]}`:                  {"events", "event should be one of booking.created, booking.cancelled, booking.rescheduled, availability.updated"},
+		`{"url":"https://example.xyz/hook","secret":"short","events":["booking.created"]}`: {"secret", "secret should be at least 16 characters long"},
+	}
+	for body, c := range cases {
+		field, message := c[0], c[1]
+		w := httptest.NewRecorder()
+		req := suite.request(http.MethodPost, "/users/1/webhooks", strings.NewReader(body))
+		req.Header.Add("Content-Type", "application/json")
+
+		suite.controller.Create(w, req)
+
+		res := w.Result()
+		suite.Equal(http.StatusUnprocessableEntity, res.StatusCode)
+		suite.Equal(fmt.Sprintf("{\"status_text\":\"unprocessable entity\",\"code\":\"validation_failed\",\"message\":%q,\"fields\":{%q:%q}}\n", message, field, message), suite.readBody(res))
+	}
+	suite.mockWebhookService.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookTestSuite) TestDeleteReturnsNotFoundIfWebhookDoesNotExist() {
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodDelete, "/users/1/webhooks/2", nil)
+	suite.mockWebhookService.On("Delete", req.Context(), 1, 2).Return(model.NotFound("webhook", sql.ErrNoRows))
+
+	suite.controller.Delete(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusNotFound, res.StatusCode)
+	suite.Equal(`{"status_text":"not found","code":"webhook_not_found","message":"webhook not found"}
+`, suite.readBody(res))
+}
+
+func (suite *WebhookTestSuite) TestGetDeliveriesHappyFlow() {
+	createdAt := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	nextAttemptAt := createdAt.Add(time.Minute)
+	w := httptest.NewRecorder()
+	req := suite.request(http.MethodGet, "/users/1/webhooks/2/deliveries", nil)
+	suite.mockWebhookService.On("GetDeliveries", req.Context(), 1, 2).Return(contract.WebhookDeliveryList{Deliveries: []contract.WebhookDelivery{{
+		ID: 3, WebhookID: 2, Event: model.WebhookBookingCreated, Status: "pending", Attempts: 1, ResponseStatus: 500, Error: "webhook returned status 500",
+		NextAttemptAt: &nextAttemptAt, CreatedAt: createdAt, Payload: json.RawMessage(`{"event":"booking.created"}`),
+	}}}, nil)
+
+	suite.controller.GetDeliveries(w, req)
+
+	res := w.Result()
+	suite.Equal(http.StatusOK, res.StatusCode)
+	suite.Equal(`{"deliveries":[{"id":3,"webhook_id":2,"event":"booking.created","status":"pending","attempts":1,"response_status":500,"error":"webhook returned status 500","next_attempt_at":"2023-06-01T00:01:00Z","created_at":"2023-06-01T00:00:00Z","payload":{"event":"booking.created"}}]}
+`, suite.readBody(res))
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
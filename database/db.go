@@ -26,7 +26,25 @@ func Init(username, password, database string) {
 		panic(err)
 	}
 
-	err = db.AutoMigrate(&model.User{}, &model.UserAvailability{}, &model.Slot{}, &model.Event{})
+	err = db.AutoMigrate(&model.User{}, &model.UserAvailability{}, &model.Slot{}, &model.Event{}, &model.AvailabilityOverride{}, &model.EventType{}, &model.EventChange{},
+		&model.ExternalCalendar{}, &model.BusyBlock{}, &model.Webhook{}, &model.WebhookDelivery{}, &model.Notification{},
+		&model.Job{}, &model.APIKey{}, &model.Team{}, &model.TeamMember{}, &model.TeamEventType{}, &model.EventSeries{})
+	if err != nil {
+		panic(err)
+	}
+
+	// Slots used to be unique across all events, which prevents booking a slot again after its event is cancelled, and
+	// then across confirmed events, which prevents group event types from booking a slot several times
+	for _, index := range []string{"idx_events_slot_id", "idx_events_confirmed_slot_id"} {
+		if db.Migrator().HasIndex(&model.Event{}, index) {
+			if err = db.Migrator().DropIndex(&model.Event{}, index); err != nil {
+				panic(err)
+			}
+		}
+	}
+
+	// Slots booked before seats were counted hold a single seat
+	err = db.Model(&model.Slot{}).Where("status = ? AND booked_seats = 0", model.StatusBooked).Update("booked_seats", 1).Error
 	if err != nil {
 		panic(err)
 	}
@@ -16,8 +16,13 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/users": {
-            "post": {
+        "/admin/jobs": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -25,25 +30,40 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "This API creates a new user",
+                "summary": "This API returns the latest 100 jobs of the queue, newest first, optionally narrowed down to a status and a kind",
                 "parameters": [
                     {
-                        "description": "Add user",
-                        "name": "event",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "pending, running, succeeded or dead",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "kind of job, such as webhook.publish",
+                        "name": "kind",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/contract.User"
+                            "$ref": "#/definitions/contract.JobList"
                         }
                     }
-                ],
-                "responses": {}
+                }
             }
         },
-        "/users/{user_id}/availability": {
+        "/admin/jobs/{job_id}": {
             "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -51,14 +71,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "This API returns a user's availability",
+                "summary": "This API returns a job of the queue along with its payload and last error",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
+                        "description": "job id",
+                        "name": "job_id",
                         "in": "path",
                         "required": true
                     }
@@ -67,12 +87,19 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/contract.UserAvailability"
+                            "$ref": "#/definitions/contract.JobResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/jobs/{job_id}/retry": {
             "post": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -80,32 +107,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "This API creates or updates a user's availability",
+                "summary": "This API runs a dead job again as soon as possible, with a fresh set of attempts",
                 "parameters": [
-                    {
-                        "description": "Add user",
-                        "name": "event",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/contract.UserAvailability"
-                        }
-                    },
                     {
                         "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
+                        "description": "job id",
+                        "name": "job_id",
                         "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {}
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.JobResponse"
+                        }
+                    }
+                }
             }
         },
-        "/users/{user_id}/availability_overlap": {
+        "/availability_overlap": {
             "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
                 "consumes": [
                     "application/json"
                 ],
@@ -115,34 +145,47 @@ const docTemplate = `{
                 "tags": [
                     "user"
                 ],
-                "summary": "This API returns a user's availability overlap with another user",
+                "summary": "This API returns the concrete intervals during which all the given users are available and not booked. The caller has to be one of the users.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
-                        "in": "path",
+                        "type": "string",
+                        "description": "comma separated user ids",
+                        "name": "user_ids",
+                        "in": "query",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "second user id",
-                        "name": "second_user_id",
+                        "type": "string",
+                        "description": "start of the range, RFC 3339 timestamp or date",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "end of the range, at most 90 days after from, RFC 3339 timestamp or date",
+                        "name": "to",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "minimum length of the intervals in minutes",
+                        "name": "duration",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/contract.UserAvailability"
+                            "$ref": "#/definitions/contract.AvailabilityOverlap"
                         }
                     }
                 }
             }
         },
-        "/users/{user_id}/events": {
+        "/bookings/{token}": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -151,27 +194,35 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "event"
+                    "booking"
                 ],
-                "summary": "This API returns all events for a given user ID.",
+                "summary": "This API returns the booking a management token was issued for.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
+                        "type": "string",
+                        "description": "management token",
+                        "name": "token",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone to render times in",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/contract.EventListResponse"
+                            "$ref": "#/definitions/contract.Booking"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/bookings/{token}/cancel": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -180,23 +231,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "event"
+                    "booking"
                 ],
-                "summary": "This API creates a new event for the user with invitee details.",
+                "summary": "This API cancels the booking a management token was issued for, on behalf of the invitee.",
                 "parameters": [
                     {
-                        "description": "Add event",
-                        "name": "event",
+                        "description": "Cancel booking",
+                        "name": "cancel",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/contract.CancelEvent"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "management token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.Booking"
+                        }
+                    }
+                }
+            }
+        },
+        "/bookings/{token}/reschedule": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "booking"
+                ],
+                "summary": "This API moves the booking a management token was issued for to another slot of the host, on behalf of the invitee. The response carries the token to use from now on.",
+                "parameters": [
+                    {
+                        "description": "Reschedule booking",
+                        "name": "reschedule",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/contract.Event"
+                            "$ref": "#/definitions/contract.RescheduleEvent"
                         }
                     },
                     {
-                        "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
+                        "type": "string",
+                        "description": "management token",
+                        "name": "token",
                         "in": "path",
                         "required": true
                     }
@@ -205,13 +295,41 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/contract.EventResponse"
+                            "$ref": "#/definitions/contract.Booking"
                         }
                     }
                 }
             }
         },
-        "/users/{user_id}/slots": {
+        "/calendar_feeds/{token}.ics": {
+            "get": {
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "This API serves the events of the user a calendar feed token belongs to as an iCalendar file.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "calendar feed token",
+                        "name": "token",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/p/{slug}": {
             "get": {
                 "consumes": [
                     "application/json"
@@ -220,14 +338,14 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "slot"
+                    "public"
                 ],
-                "summary": "This API returns slots for a user starting today till 14 days.",
+                "summary": "This API returns the public booking page of a user: their name, time zone and event types.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
+                        "type": "string",
+                        "description": "slug of the user",
+                        "name": "slug",
                         "in": "path",
                         "required": true
                     }
@@ -236,11 +354,13 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/contract.SlotList"
+                            "$ref": "#/definitions/contract.PublicPage"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/p/{slug}/book": {
             "post": {
                 "consumes": [
                     "application/json"
@@ -249,30 +369,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "slot"
+                    "public"
                 ],
-                "summary": "This API creates slots for a user for given number of days.",
+                "summary": "This API books the free slot of an event type starting at the given time, and returns the booking along with the invitee's management token.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "number of days to create slots",
-                        "name": "num_days",
-                        "in": "query",
-                        "required": true
+                        "description": "Book",
+                        "name": "booking",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.PublicBooking"
+                        }
                     },
                     {
-                        "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
+                        "type": "string",
+                        "description": "slug of the user",
+                        "name": "slug",
                         "in": "path",
                         "required": true
                     }
                 ],
-                "responses": {}
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contract.Booking"
+                        }
+                    }
+                }
             }
         },
-        "/users/{user_id}/slots/{slot_id}": {
-            "delete": {
+        "/p/{slug}/{event_type_slug}/slots": {
+            "get": {
                 "consumes": [
                     "application/json"
                 ],
@@ -280,117 +409,3560 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "slot"
+                    "public"
                 ],
-                "summary": "This API returns deletes a slot by ID.",
+                "summary": "This API returns the free slots of an event type on a public booking page. Without from and to, the next 14 days are returned.",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "user id",
-                        "name": "user_id",
+                        "type": "string",
+                        "description": "slug of the user",
+                        "name": "slug",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "slot id",
-                        "name": "slot_id",
+                        "type": "string",
+                        "description": "slug of the event type",
+                        "name": "event_type_slug",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start of the range, RFC 3339 timestamp or date",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end of the range, RFC 3339 timestamp or date",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone to render times in",
+                        "name": "tz",
+                        "in": "query"
                     }
                 ],
-                "responses": {}
-            }
-        }
-    },
-    "definitions": {
-        "contract.Event": {
-            "type": "object",
-            "properties": {
-                "invitee_email": {
-                    "type": "string"
-                },
-                "invitee_name": {
-                    "type": "string"
-                },
-                "invitee_notes": {
-                    "type": "string"
-                },
-                "slot_id": {
-                    "type": "integer"
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.PublicSlotList"
+                        }
+                    }
                 }
             }
         },
-        "contract.EventListResponse": {
-            "type": "object",
-            "properties": {
-                "events": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/contract.EventResponse"
+        "/users": {
+            "get": {
+                "security": [
+                    {
+                        "AdminToken": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API returns a page of users ordered by ID, optionally only the ones whose email contains the given text, along with how many users match in total",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "text to search emails for, ignoring case",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of users to return, 20 by default and at most 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "number of users to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.UserList"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API creates a new user, along with their first API key which is only returned here",
+                "parameters": [
+                    {
+                        "description": "Add user",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.User"
+                        }
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API returns a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.UserResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API deletes a user along with their availability, event types, slots, events, calendars, webhooks and API keys. Invitees of upcoming events are not notified.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API changes the name, email, time zone or slug of a user, leaving out the fields not given. A new time zone only applies to availability set afterwards.",
+                "parameters": [
+                    {
+                        "description": "Update user",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.UserUpdate"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.UserResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/api_keys": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api_key"
+                ],
+                "summary": "This API returns all API keys of a user, without the keys themselves",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.APIKeyList"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api_key"
+                ],
+                "summary": "This API creates a new API key for the user. The key is only returned here, only its prefix is returned afterwards.",
+                "parameters": [
+                    {
+                        "description": "Add API key",
+                        "name": "api_key",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.APIKey"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contract.APIKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/api_keys/{api_key_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api_key"
+                ],
+                "summary": "This API revokes an API key of a user, which stops authenticating requests straight away",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "API key id",
+                        "name": "api_key_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/availability": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API returns a user's availability",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.UserAvailability"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API creates or updates a user's availability",
+                "parameters": [
+                    {
+                        "description": "Add user",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.UserAvailability"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/availability/overrides": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API returns all availability overrides of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.AvailabilityOverrideList"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API replaces a user's weekly availability for a date or range of dates, or marks them unavailable",
+                "parameters": [
+                    {
+                        "description": "Add availability override",
+                        "name": "override",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.AvailabilityOverride"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contract.AvailabilityOverrideResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/availability/overrides/{override_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API returns an availability override of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "override id",
+                        "name": "override_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.AvailabilityOverrideResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API replaces an availability override of a user",
+                "parameters": [
+                    {
+                        "description": "Update availability override",
+                        "name": "override",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.AvailabilityOverride"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "override id",
+                        "name": "override_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.AvailabilityOverrideResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API deletes an availability override of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "override id",
+                        "name": "override_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/availability_overlap": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API returns a user's availability overlap with another user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "second user id",
+                        "name": "second_user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start of the range for concrete overlapping intervals, RFC 3339 timestamp or date",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end of the range for concrete overlapping intervals, at most 90 days after from, RFC 3339 timestamp or date",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.UserAvailabilityOverlap"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/calendar_feed": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "This API generates a secret URL serving the user's events as an iCalendar feed for calendar apps to subscribe to. Generating a new URL revokes the previous one.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contract.CalendarFeed"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/event_series": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "This API books the free slots starting at a start time and repeating weekly, biweekly or monthly, either a number of times or until a date. Either every occurrence is booked, or none of them is and the occurrences which are not available are reported in the fields of the error.",
+                "parameters": [
+                    {
+                        "description": "Add event series",
+                        "name": "series",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventSeries"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventSeriesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/event_series/{series_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "This API returns a series of recurring events along with its occurrences, earliest first.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "series id",
+                        "name": "series_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone to render times in",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventSeriesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/event_series/{series_id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "This API cancels the occurrences of a series which have not started yet. Single occurrences are cancelled like any other event.",
+                "parameters": [
+                    {
+                        "description": "Cancel event series",
+                        "name": "cancel",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/contract.CancelEvent"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "series id",
+                        "name": "series_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventSeriesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/event_series/{series_id}/reschedule": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "This API moves the occurrences of a series which have not started yet to the free slots starting at a new start time and repeating at the frequency of the series. Either every occurrence is moved, or none of them is. Single occurrences are rescheduled like any other event.",
+                "parameters": [
+                    {
+                        "description": "Reschedule event series",
+                        "name": "reschedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.RescheduleSeries"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "series id",
+                        "name": "series_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventSeriesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/event_types": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event_type"
+                ],
+                "summary": "This API returns all event types of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventTypeList"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event_type"
+                ],
+                "summary": "This API creates an event type for a user with its own duration and optionally its own availability",
+                "parameters": [
+                    {
+                        "description": "Add event type",
+                        "name": "event_type",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventType"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventTypeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/event_types/{event_type_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event_type"
+                ],
+                "summary": "This API returns an event type of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "event type id",
+                        "name": "event_type_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventTypeResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event_type"
+                ],
+                "summary": "This API replaces an event type of a user",
+                "parameters": [
+                    {
+                        "description": "Update event type",
+                        "name": "event_type",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventType"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "event type id",
+                        "name": "event_type_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventTypeResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event_type"
+                ],
+                "summary": "This API deletes an event type of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "event type id",
+                        "name": "event_type_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/events": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "This API returns all events for a given user ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone to render times in",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventListResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "This API creates a new event for the user with invitee details, either for an existing slot or for a free slot given by its start time.",
+                "parameters": [
+                    {
+                        "description": "Add event",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.Event"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/events.ics": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "produces": [
+                    "text/calendar"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "This API returns all events of a user, including cancelled ones, as an iCalendar (RFC 5545) file.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/events/{event_id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "This API cancels an event of the user which has not started yet and opens its slot for booking again.",
+                "parameters": [
+                    {
+                        "description": "Cancel event",
+                        "name": "cancel",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/contract.CancelEvent"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "event id",
+                        "name": "event_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/events/{event_id}/changes": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "This API returns the cancellations and reschedules of an event, oldest first.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "event id",
+                        "name": "event_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone to render times in",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventChangeList"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/events/{event_id}/reschedule": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "event"
+                ],
+                "summary": "This API moves an event of the user which has not started yet to another slot, either an existing slot or a free slot given by its start time.",
+                "parameters": [
+                    {
+                        "description": "Reschedule event",
+                        "name": "reschedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.RescheduleEvent"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "event id",
+                        "name": "event_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/external_calendars": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "external_calendar"
+                ],
+                "summary": "This API returns all external calendars of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.ExternalCalendarList"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "external_calendar"
+                ],
+                "summary": "This API adds an external calendar whose events block the user's time. An ICS calendar with a URL or a CalDAV calendar is read and imported straight away, and is synced again by the scheduler.",
+                "parameters": [
+                    {
+                        "description": "Add external calendar",
+                        "name": "external_calendar",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.ExternalCalendar"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contract.ExternalCalendarResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/external_calendars/{external_calendar_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "external_calendar"
+                ],
+                "summary": "This API deletes an external calendar of a user along with its busy times",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "external calendar id",
+                        "name": "external_calendar_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/external_calendars/{external_calendar_id}/import": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "text/calendar",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "external_calendar"
+                ],
+                "summary": "This API replaces the busy times of an external calendar with the events of an ICS file, sent either as the request body or as the file field of a multipart form. Recurring events are expanded for the next 180 days.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "external calendar id",
+                        "name": "external_calendar_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.ExternalCalendarResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/external_calendars/{external_calendar_id}/sync": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "external_calendar"
+                ],
+                "summary": "This API fetches an external calendar from its URL again and replaces its busy times",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "external calendar id",
+                        "name": "external_calendar_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.ExternalCalendarResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/slots": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "slot"
+                ],
+                "summary": "This API computes the free slots of a user from their availability, overrides and bookings. Without from and to, the next 14 days are returned.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start of the range, RFC 3339 timestamp or date",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end of the range, RFC 3339 timestamp or date",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "event type to compute slots for",
+                        "name": "event_type_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone to render times in",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.SlotList"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "slot"
+                ],
+                "summary": "This API creates slots for a user for given number of days.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "number of days to create slots",
+                        "name": "num_days",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "event type to create slots for",
+                        "name": "event_type_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/slots/{slot_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "slot"
+                ],
+                "summary": "This API returns deletes a slot by ID.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "slot id",
+                        "name": "slot_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/slug": {
+            "put": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "This API changes the path of a user's public booking page at /p/{slug}. The previous path stops working straight away.",
+                "parameters": [
+                    {
+                        "description": "Set slug",
+                        "name": "slug",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.UserSlug"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.UserSlug"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/team_memberships": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API returns the teams a user has been added to, along with whether they accepted to be booked through them",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.TeamMembershipList"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/team_memberships/{team_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API removes the user from a team they have been added to, which also declines a membership they have not accepted. Their bookings made through the team stay with them.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/team_memberships/{team_id}/accept": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API lets a team the user has been added to read their calendar and book them. Until then, the team leaves them out of its slots and bookings.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/teams": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API returns all teams owned by a user along with their members",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.TeamList"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API creates a team owned by the user, optionally along with its first members. The owner does not have to be a member.",
+                "parameters": [
+                    {
+                        "description": "Add team",
+                        "name": "team",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.Team"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contract.TeamResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/teams/{team_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API returns a team of a user along with its members and how the bookings of the team are shared among them",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.TeamResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API deletes a team along with its event types. The bookings made through the team stay with its members.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/teams/{team_id}/event_types": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API returns all event types of a team",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.TeamEventTypeList"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API creates an event type of a team, either collective, booking every member at the times they are all free, or round_robin, booking one of the members free at the time",
+                "parameters": [
+                    {
+                        "description": "Add team event type",
+                        "name": "event_type",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.TeamEventType"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contract.TeamEventTypeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/teams/{team_id}/event_types/{team_event_type_id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API returns an event type of a team",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team event type id",
+                        "name": "team_event_type_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.TeamEventTypeResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API deletes an event type of a team. The bookings made through it stay with the members.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team event type id",
+                        "name": "team_event_type_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/teams/{team_id}/event_types/{team_event_type_id}/slots": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API returns the free slots of a team event type along with the members who can be booked for each of them: every member of a collective event type, and the free ones of a round robin one. The next 14 days are used when no range is given.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team event type id",
+                        "name": "team_event_type_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "start of the range, RFC 3339 timestamp or date",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "end of the range, RFC 3339 timestamp or date",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone to render times in",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.TeamSlotList"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/teams/{team_id}/events": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API books the free time of a team event type starting at the given start time. Collective event types book every member of the team, round robin ones the free member of the highest priority who was booked least recently. Returns the events of the members booked.",
+                "parameters": [
+                    {
+                        "description": "Book team event type",
+                        "name": "event",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.TeamEvent"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contract.EventListResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/teams/{team_id}/members": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API adds a user to a team. Members other than the owner are only booked through the team once they accept. Round robin bookings go to the free members of the highest priority first.",
+                "parameters": [
+                    {
+                        "description": "Add team member",
+                        "name": "member",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.TeamMember"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contract.TeamMemberResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/teams/{team_id}/members/{member_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "team"
+                ],
+                "summary": "This API removes a user from a team. Their bookings made through the team stay with them.",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "team id",
+                        "name": "team_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id of the member",
+                        "name": "member_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "This API returns all webhooks of a user",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.WebhookList"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "This API subscribes a URL to booking and availability events of the user. Payloads are signed with the webhook secret, which is only returned here.",
+                "parameters": [
+                    {
+                        "description": "Add webhook",
+                        "name": "webhook",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/contract.Webhook"
+                        }
+                    },
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/contract.WebhookResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{user_id}/webhooks/{webhook_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "This API deletes a webhook of a user along with its deliveries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "webhook id",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {}
+            }
+        },
+        "/users/{user_id}/webhooks/{webhook_id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKey": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "This API returns the latest 100 deliveries of a webhook, newest first, with the outcome of their last attempt",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "user id",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "webhook id",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/contract.WebhookDeliveryList"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "contract.APIKey": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.APIKeyList": {
+            "type": "object",
+            "properties": {
+                "api_keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.APIKeyResponse"
+                    }
+                }
+            }
+        },
+        "contract.APIKeyResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.AvailabilityOverlap": {
+            "type": "object",
+            "properties": {
+                "duration_mins": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "intervals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.Interval"
+                    }
+                },
+                "to": {
+                    "type": "string"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "contract.AvailabilityOverride": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Availability"
+                    }
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "unavailable": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "contract.AvailabilityOverrideList": {
+            "type": "object",
+            "properties": {
+                "overrides": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.AvailabilityOverrideResponse"
+                    }
+                }
+            }
+        },
+        "contract.AvailabilityOverrideResponse": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Availability"
+                    }
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "unavailable": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "contract.Booking": {
+            "type": "object",
+            "properties": {
+                "cancel_reason": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "event_type_name": {
+                    "type": "string"
+                },
+                "host_name": {
+                    "type": "string"
+                },
+                "invitee_email": {
+                    "type": "string"
+                },
+                "invitee_name": {
+                    "type": "string"
+                },
+                "invitee_notes": {
+                    "type": "string"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "management_token": {
+                    "description": "ManagementToken is the token to use for the booking from now on. It changes when the booking is rescheduled.",
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.CalendarFeed": {
+            "type": "object",
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.CancelEvent": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.Event": {
+            "type": "object",
+            "properties": {
+                "event_type_id": {
+                    "type": "integer"
+                },
+                "invitee_email": {
+                    "type": "string"
+                },
+                "invitee_name": {
+                    "type": "string"
+                },
+                "invitee_notes": {
+                    "type": "string"
+                },
+                "slot_id": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.EventChange": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "previous_end_time": {
+                    "type": "string"
+                },
+                "previous_slot_id": {
+                    "type": "integer"
+                },
+                "previous_start_time": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.EventChangeList": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.EventChange"
+                    }
+                }
+            }
+        },
+        "contract.EventListResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.EventResponse"
+                    }
+                }
+            }
+        },
+        "contract.EventResponse": {
+            "type": "object",
+            "properties": {
+                "cancel_reason": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_time": {
+                    "type": "string"
+                },
+                "event_type_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invitee_email": {
+                    "type": "string"
+                },
+                "invitee_name": {
+                    "type": "string"
+                },
+                "invitee_notes": {
+                    "type": "string"
+                },
+                "management_token": {
+                    "description": "ManagementToken lets the invitee view, cancel and reschedule the booking through /bookings/{token} until the\nevent is rescheduled or ends",
+                    "type": "string"
+                },
+                "series_id": {
+                    "description": "SeriesID is set for the occurrences of a recurring booking",
+                    "type": "integer"
+                },
+                "slot_id": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "team_event_type_id": {
+                    "description": "TeamEventTypeID is set for bookings made through an event type of a team",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "contract.EventSeries": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "event_type_id": {
+                    "type": "integer"
+                },
+                "frequency": {
+                    "description": "Frequency is one of weekly, biweekly or monthly. Monthly occurrences fall on the day of the month of StartTime,\nskipping months without that day.",
+                    "type": "string"
+                },
+                "invitee_email": {
+                    "type": "string"
+                },
+                "invitee_name": {
+                    "type": "string"
+                },
+                "invitee_notes": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "until": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.EventSeriesResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_type_id": {
+                    "type": "integer"
+                },
+                "events": {
+                    "description": "Events are the occurrences of the series, earliest first, including the cancelled ones",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.EventResponse"
+                    }
+                },
+                "frequency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invitee_email": {
+                    "type": "string"
+                },
+                "invitee_name": {
+                    "type": "string"
+                },
+                "invitee_notes": {
+                    "type": "string"
+                },
+                "until": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "contract.EventType": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DayAvailability"
+                    }
+                },
+                "capacity": {
+                    "description": "Capacity is how many invitees can book each slot, one when not given",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration_mins": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scheduling_rules": {
+                    "description": "SchedulingRules replace the user's scheduling rules for this event type when given",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SchedulingRules"
+                        }
+                    ]
+                },
+                "slot_increment_mins": {
+                    "description": "SlotIncrementMins replaces the user's slot increment for this event type when given",
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.EventTypeList": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.EventTypeResponse"
+                    }
+                }
+            }
+        },
+        "contract.EventTypeResponse": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DayAvailability"
+                    }
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration_mins": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scheduling_rules": {
+                    "$ref": "#/definitions/model.SchedulingRules"
+                },
+                "slot_increment_mins": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "contract.ExternalCalendar": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "write_back": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "contract.ExternalCalendarList": {
+            "type": "object",
+            "properties": {
+                "external_calendars": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.ExternalCalendarResponse"
+                    }
+                }
+            }
+        },
+        "contract.ExternalCalendarResponse": {
+            "type": "object",
+            "properties": {
+                "busy_blocks": {
+                    "description": "BusyBlocks is the number of busy blocks imported by the last sync, only returned by imports and syncs",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string"
+                },
+                "write_back": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "contract.Interval": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.JobList": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.JobResponse"
+                    }
+                }
+            }
+        },
+        "contract.JobResponse": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "run_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.PublicBooking": {
+            "type": "object",
+            "properties": {
+                "event_type_slug": {
+                    "type": "string"
+                },
+                "invitee_email": {
+                    "type": "string"
+                },
+                "invitee_name": {
+                    "type": "string"
+                },
+                "invitee_notes": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.PublicEventType": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "duration_mins": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.PublicPage": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.PublicEventType"
+                    }
+                },
+                "host_name": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.PublicSlot": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.PublicSlotList": {
+            "type": "object",
+            "properties": {
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.PublicSlot"
+                    }
+                }
+            }
+        },
+        "contract.RescheduleEvent": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "slot_id": {
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.RescheduleSeries": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.Slot": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string"
+                },
+                "event_type_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "remaining_seats": {
+                    "description": "RemainingSeats is how many more invitees can book the slot",
+                    "type": "integer"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "contract.SlotList": {
+            "type": "object",
+            "properties": {
+                "slots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.Slot"
+                    }
+                }
+            }
+        },
+        "contract.Team": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.TeamMember"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.TeamEvent": {
+            "type": "object",
+            "properties": {
+                "invitee_email": {
+                    "type": "string"
+                },
+                "invitee_name": {
+                    "type": "string"
+                },
+                "invitee_notes": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "team_event_type_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "contract.TeamEventType": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "duration_mins": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scheduling_type": {
+                    "description": "SchedulingType is either collective, offering the times when every member is free, or round_robin, offering\nthe times when any member is free",
+                    "type": "string"
+                },
+                "slot_increment_mins": {
+                    "description": "SlotIncrementMins replaces the members' slot increments for this event type when given",
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.TeamEventTypeList": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.TeamEventTypeResponse"
+                    }
+                }
+            }
+        },
+        "contract.TeamEventTypeResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "duration_mins": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scheduling_type": {
+                    "type": "string"
+                },
+                "slot_increment_mins": {
+                    "type": "integer"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "team_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "contract.TeamList": {
+            "type": "object",
+            "properties": {
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.TeamResponse"
                     }
                 }
             }
         },
-        "contract.EventResponse": {
+        "contract.TeamMember": {
             "type": "object",
             "properties": {
-                "created_at": {
-                    "type": "string"
+                "priority": {
+                    "type": "integer"
                 },
-                "end_time": {
-                    "type": "string"
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "contract.TeamMemberResponse": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "boolean"
                 },
-                "id": {
+                "bookings": {
                     "type": "integer"
                 },
-                "invitee_email": {
+                "last_booked_at": {
                     "type": "string"
                 },
-                "invitee_name": {
-                    "type": "string"
+                "priority": {
+                    "type": "integer"
                 },
-                "invitee_notes": {
-                    "type": "string"
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "contract.TeamMembership": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "boolean"
                 },
-                "slot_id": {
+                "owner_id": {
                     "type": "integer"
                 },
-                "start_time": {
-                    "type": "string"
+                "priority": {
+                    "type": "integer"
                 },
-                "user_id": {
+                "team_id": {
                     "type": "integer"
+                },
+                "team_name": {
+                    "type": "string"
                 }
             }
         },
-        "contract.Slot": {
+        "contract.TeamMembershipList": {
             "type": "object",
             "properties": {
-                "end_time": {
+                "memberships": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.TeamMembership"
+                    }
+                }
+            }
+        },
+        "contract.TeamResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "start_time": {
-                    "type": "string"
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.TeamMemberResponse"
+                    }
                 },
-                "status": {
+                "name": {
                     "type": "string"
                 },
-                "user_id": {
+                "owner_id": {
                     "type": "integer"
                 }
             }
         },
-        "contract.SlotList": {
+        "contract.TeamSlot": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "contract.TeamSlotList": {
             "type": "object",
             "properties": {
                 "slots": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/contract.Slot"
+                        "$ref": "#/definitions/contract.TeamSlot"
                     }
                 }
             }
@@ -403,6 +3975,13 @@ const docTemplate = `{
                 },
                 "name": {
                     "type": "string"
+                },
+                "slug": {
+                    "description": "Slug is the path of the user's public booking page. One is derived from the name when it is not given.",
+                    "type": "string"
+                },
+                "time_zone": {
+                    "type": "string"
                 }
             }
         },
@@ -417,6 +3996,212 @@ const docTemplate = `{
                 },
                 "meeting_duration_mins": {
                     "type": "integer"
+                },
+                "scheduling_rules": {
+                    "$ref": "#/definitions/model.SchedulingRules"
+                },
+                "slot_increment_mins": {
+                    "description": "SlotIncrementMins is how often slots start, every meeting_duration_mins when not set",
+                    "type": "integer"
+                },
+                "time_zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.UserAvailabilityOverlap": {
+            "type": "object",
+            "properties": {
+                "intervals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.Interval"
+                    }
+                },
+                "overlap": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DayAvailability"
+                    }
+                }
+            }
+        },
+        "contract.UserList": {
+            "type": "object",
+            "properties": {
+                "total": {
+                    "type": "integer"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.UserResponse"
+                    }
+                }
+            }
+        },
+        "contract.UserResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.UserSlug": {
+            "type": "object",
+            "properties": {
+                "slug": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.UserUpdate": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "time_zone": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.Webhook": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "contract.WebhookDelivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "response_status": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "webhook_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "contract.WebhookDeliveryList": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.WebhookDelivery"
+                    }
+                }
+            }
+        },
+        "contract.WebhookList": {
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/contract.WebhookResponse"
+                    }
+                }
+            }
+        },
+        "contract.WebhookResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Availability": {
+            "type": "object",
+            "properties": {
+                "end_time": {
+                    "type": "string"
+                },
+                "start_time": {
+                    "type": "string"
                 }
             }
         },
@@ -454,6 +4239,47 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "model.SchedulingRules": {
+            "type": "object",
+            "properties": {
+                "after_buffer_mins": {
+                    "type": "integer"
+                },
+                "before_buffer_mins": {
+                    "description": "BeforeBufferMins and AfterBufferMins are kept free of other bookings and busy times around every booking",
+                    "type": "integer"
+                },
+                "max_horizon_days": {
+                    "description": "MaxHorizonDays is how many days ahead bookings can be made",
+                    "type": "integer"
+                },
+                "max_per_day": {
+                    "description": "MaxPerDay and MaxPerWeek cap the bookings of the user starting on a day, or in a week starting on Monday",
+                    "type": "integer"
+                },
+                "max_per_week": {
+                    "type": "integer"
+                },
+                "min_notice_mins": {
+                    "description": "MinNoticeMins is how long ahead of its start a booking has to be made",
+                    "type": "integer"
+                }
+            }
+        }
+    },
+    "securityDefinitions": {
+        "AdminToken": {
+            "description": "The admin token, as \"Bearer \u003cADMIN_TOKEN\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
+        },
+        "ApiKey": {
+            "description": "An API key of the user, as \"Bearer \u003ckey\u003e\", or a JWT signed with JWT_SECRET whose subject is the user ID",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
@@ -0,0 +1,280 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const dateLayout = "20060102"
+
+// Parse reads the VEVENTs of an iCalendar stream. Floating times and dates, which are not tied to a time zone, are
+// interpreted in loc, as are times in a time zone which is not known.
+func Parse(r io.Reader, loc *time.Location) (Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return Calendar{}, err
+	}
+
+	cal := Calendar{}
+	var event *Event
+	// depth of the components nested in the current event, such as VALARM, which are skipped
+	nested := 0
+	inCalendar := false
+	inFreeBusy := false
+	for i, raw := range lines {
+		if raw == "" {
+			continue
+		}
+		prop, err := parseProperty(raw)
+		if err != nil {
+			return Calendar{}, fmt.Errorf("line %d: %w", i+1, err)
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCALENDAR"):
+			inCalendar = true
+		case !inCalendar:
+			return Calendar{}, fmt.Errorf("line %d: content outside of VCALENDAR", i+1)
+		case prop.name == "BEGIN" && event != nil:
+			nested++
+		case prop.name == "END" && event != nil && nested > 0:
+			nested--
+		case nested > 0:
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			event = &Event{}
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VFREEBUSY"):
+			inFreeBusy = true
+		case prop.name == "END" && strings.EqualFold(prop.value, "VFREEBUSY"):
+			inFreeBusy = false
+		case inFreeBusy && prop.name == "FREEBUSY":
+			periods, err := parseFreeBusy(prop)
+			if err != nil {
+				return Calendar{}, fmt.Errorf("line %d: invalid FREEBUSY: %w", i+1, err)
+			}
+			cal.BusyPeriods = append(cal.BusyPeriods, periods...)
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT") && event != nil:
+			if event.Start.IsZero() {
+				return Calendar{}, fmt.Errorf("line %d: event %q has no DTSTART", i+1, event.UID)
+			}
+			if event.End.IsZero() && event.duration != 0 {
+				event.End = event.Start.Add(event.duration)
+			}
+			if event.End.IsZero() {
+				// An event without an end lasts a day if it is on a date and takes no time otherwise
+				event.End = event.Start
+				if event.AllDay {
+					event.End = event.Start.AddDate(0, 0, 1)
+				}
+			}
+			cal.Events = append(cal.Events, *event)
+			event = nil
+		case event != nil:
+			if err := event.set(prop, loc); err != nil {
+				return Calendar{}, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		case prop.name == "PRODID":
+			cal.ProdID = prop.value
+		case prop.name == "X-WR-CALNAME":
+			cal.Name = unescape(prop.value)
+		}
+	}
+	if !inCalendar {
+		return Calendar{}, fmt.Errorf("not an iCalendar file")
+	}
+
+	return cal, nil
+}
+
+func (event *Event) set(prop property, loc *time.Location) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		event.UID = prop.value
+	case "SUMMARY":
+		event.Summary = unescape(prop.value)
+	case "DESCRIPTION":
+		event.Description = unescape(prop.value)
+	case "LOCATION":
+		event.Location = unescape(prop.value)
+	case "STATUS":
+		event.Status = strings.ToUpper(prop.value)
+	case "TRANSP":
+		event.Transparent = strings.EqualFold(prop.value, "TRANSPARENT")
+	case "DTSTART":
+		event.Start, event.AllDay, err = parseTime(prop, loc)
+	case "DTEND":
+		event.End, _, err = parseTime(prop, loc)
+	case "DURATION":
+		event.duration, err = parseDuration(prop.value)
+	case "RRULE":
+		var recurrence Recurrence
+		recurrence, err = parseRecurrence(prop.value, loc)
+		event.Recurrence = &recurrence
+	case "EXDATE":
+		for _, value := range strings.Split(prop.value, ",") {
+			var exDate time.Time
+			exDate, _, err = parseTime(property{name: prop.name, params: prop.params, value: value}, loc)
+			if err != nil {
+				break
+			}
+			event.ExceptionDates = append(event.ExceptionDates, exDate)
+		}
+	case "RECURRENCE-ID":
+		event.RecurrenceID, _, err = parseTime(prop, loc)
+	case "CREATED":
+		event.Created, _, err = parseTime(prop, loc)
+	case "LAST-MODIFIED":
+		event.LastModified, _, err = parseTime(prop, loc)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid %s: %w", prop.name, err)
+	}
+	return nil
+}
+
+// parseTime parses a DATE or DATE-TIME value. The returned bool reports whether the value is a date.
+func parseTime(prop property, loc *time.Location) (time.Time, bool, error) {
+	value := strings.TrimSpace(prop.value)
+	if strings.EqualFold(prop.params["VALUE"], "DATE") || len(value) == len(dateLayout) {
+		t, err := time.ParseInLocation(dateLayout, value, loc)
+		return t, true, err
+	}
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse(dateTimeLayout, value)
+		return t, false, err
+	}
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if tz, err := time.LoadLocation(strings.TrimPrefix(tzid, "/")); err == nil {
+			loc = tz
+		}
+	}
+	t, err := time.ParseInLocation(strings.TrimSuffix(dateTimeLayout, "Z"), value, loc)
+	return t, false, err
+}
+
+// parseFreeBusy parses the busy periods of a FREEBUSY property, given either by their start and end or by their start
+// and duration. Periods marked as free are left out.
+func parseFreeBusy(prop property) ([]Period, error) {
+	if strings.EqualFold(prop.params["FBTYPE"], "FREE") {
+		return nil, nil
+	}
+
+	periods := make([]Period, 0)
+	for _, value := range strings.Split(prop.value, ",") {
+		startValue, endValue, found := strings.Cut(strings.TrimSpace(value), "/")
+		if !found {
+			return nil, fmt.Errorf("malformed period %q", value)
+		}
+		start, err := time.Parse(dateTimeLayout, startValue)
+		if err != nil {
+			return nil, err
+		}
+		end, err := time.Parse(dateTimeLayout, endValue)
+		if err != nil {
+			duration, durationErr := parseDuration(endValue)
+			if durationErr != nil {
+				return nil, fmt.Errorf("malformed period %q", value)
+			}
+			end = start.Add(duration)
+		}
+		periods = append(periods, Period{Start: start, End: end})
+	}
+	return periods, nil
+}
+
+// parseDuration parses a DURATION value such as PT1H30M or P1W, see RFC 5545 section 3.3.6.
+func parseDuration(value string) (time.Duration, error) {
+	sign := time.Duration(1)
+	switch {
+	case strings.HasPrefix(value, "-"):
+		sign, value = -1, value[1:]
+	case strings.HasPrefix(value, "+"):
+		value = value[1:]
+	}
+	if !strings.HasPrefix(value, "P") || len(value) < 3 {
+		return 0, fmt.Errorf("malformed duration %q", value)
+	}
+
+	units := map[byte]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour, 'H': time.Hour, 'M': time.Minute, 'S': time.Second}
+	var duration time.Duration
+	number := 0
+	inTime := false
+	for i := 1; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c >= '0' && c <= '9':
+			number = number*10 + int(c-'0')
+		case c == 'T':
+			inTime = true
+		case units[c] != 0 && (c != 'M' || inTime):
+			duration += time.Duration(number) * units[c]
+			number = 0
+		default:
+			return 0, fmt.Errorf("malformed duration %q", value)
+		}
+	}
+	return sign * duration, nil
+}
+
+func unescape(value string) string {
+	return strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n").Replace(value)
+}
+
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// parseProperty splits a content line into its name, parameters and value. Parameter values may be quoted, in
+// which case they can contain colons and semicolons.
+func parseProperty(line string) (property, error) {
+	prop := property{params: make(map[string]string)}
+	quoted := false
+	start := 0
+	var paramName string
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '=' && prop.name != "" && paramName == "":
+			paramName = strings.ToUpper(line[start:i])
+			start = i + 1
+		case c == ';' || c == ':':
+			part := line[start:i]
+			if prop.name == "" {
+				prop.name = strings.ToUpper(part)
+			} else if paramName != "" {
+				prop.params[paramName] = strings.Trim(part, `"`)
+				paramName = ""
+			}
+			start = i + 1
+			if c == ':' {
+				prop.value = line[start:]
+				return prop, nil
+			}
+		}
+	}
+	return property{}, fmt.Errorf("malformed content line %q", line)
+}
+
+// unfold reads the content lines of the stream, joining folded lines back together.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lines := make([]string, 0)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
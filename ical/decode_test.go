@@ -0,0 +1,175 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type DecodeTestSuite struct {
+	suite.Suite
+}
+
+func (suite *DecodeTestSuite) parse(lines ...string) (Calendar, error) {
+	return Parse(strings.NewReader(strings.Join(lines, "\r\n")+"\r\n"), time.UTC)
+}
+
+func (suite *DecodeTestSuite) TestParseReadsEvents() {
+	cal, err := suite.parse(
+		"BEGIN:VCALENDAR",
+		"PRODID:-//test//EN",
+		"X-WR-CALNAME:Work",
+		"BEGIN:VTIMEZONE",
+		"TZID:Asia/Kolkata",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:1@test",
+		"DTSTART;TZID=Asia/Kolkata:20230605T143000",
+		"DTEND;TZID=Asia/Kolkata:20230605T150000",
+		"SUMMARY:Planning\\, weekly",
+		"DESCRIPTION:first line\\nsecond ",
+		" line",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:2@test",
+		"DURATION:PT1H30M",
+		"DTSTART:20230606T090000Z",
+		"STATUS:CANCELLED",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:3@test",
+		"DTSTART;VALUE=DATE:20230607",
+		"TRANSP:TRANSPARENT",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+	suite.NoError(err)
+
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	suite.Equal("-//test//EN", cal.ProdID)
+	suite.Equal("Work", cal.Name)
+	suite.Len(cal.Events, 3)
+	suite.True(time.Date(2023, 6, 5, 14, 30, 0, 0, loc).Equal(cal.Events[0].Start))
+	suite.True(time.Date(2023, 6, 5, 15, 0, 0, 0, loc).Equal(cal.Events[0].End))
+	suite.Equal("Planning, weekly", cal.Events[0].Summary)
+	suite.Equal("first line\nsecond line", cal.Events[0].Description)
+	suite.Equal(time.Date(2023, 6, 6, 10, 30, 0, 0, time.UTC), cal.Events[1].End)
+	suite.Equal(StatusCancelled, cal.Events[1].Status)
+	suite.True(cal.Events[2].AllDay)
+	suite.True(cal.Events[2].Transparent)
+	suite.Equal(time.Date(2023, 6, 8, 0, 0, 0, 0, time.UTC), cal.Events[2].End)
+}
+
+func (suite *DecodeTestSuite) TestParseReadsRecurrence() {
+	cal, err := suite.parse(
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"UID:1@test",
+		"DTSTART:20230605T090000",
+		"DTEND:20230605T093000",
+		"RRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR;UNTIL=20231231",
+		"EXDATE:20230630T090000,20230825T090000",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:1@test",
+		"RECURRENCE-ID:20231027T090000",
+		"DTSTART:20231027T100000",
+		"DTEND:20231027T103000",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	)
+	suite.NoError(err)
+
+	suite.Equal(&Recurrence{
+		Frequency: FrequencyMonthly,
+		Interval:  2,
+		Until:     time.Date(2023, 12, 31, 23, 59, 59, 0, time.UTC),
+		ByDay:     []WeekdayNum{{Weekday: time.Friday, N: -1}},
+		WeekStart: time.Monday,
+	}, cal.Events[0].Recurrence)
+	suite.Equal([]time.Time{
+		time.Date(2023, 6, 30, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 8, 25, 9, 0, 0, 0, time.UTC),
+	}, cal.Events[0].ExceptionDates)
+	suite.Equal(time.Date(2023, 10, 27, 9, 0, 0, 0, time.UTC), cal.Events[1].RecurrenceID)
+}
+
+func (suite *DecodeTestSuite) TestParseReadsFreeBusy() {
+	cal, err := suite.parse(
+		"BEGIN:VCALENDAR",
+		"BEGIN:VFREEBUSY",
+		"DTSTART:20230605T000000Z",
+		"DTEND:20230606T000000Z",
+		"FREEBUSY;FBTYPE=BUSY:20230605T090000Z/20230605T100000Z,20230605T140000Z/PT30M",
+		"FREEBUSY;FBTYPE=FREE:20230605T110000Z/20230605T120000Z",
+		"FREEBUSY:20230605T160000Z/20230605T170000Z",
+		"END:VFREEBUSY",
+		"END:VCALENDAR",
+	)
+	suite.NoError(err)
+
+	day := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	suite.Equal([]Period{
+		{Start: day.Add(9 * time.Hour), End: day.Add(10 * time.Hour)},
+		{Start: day.Add(14 * time.Hour), End: day.Add(14*time.Hour + 30*time.Minute)},
+		{Start: day.Add(16 * time.Hour), End: day.Add(17 * time.Hour)},
+	}, cal.BusyPeriods)
+	suite.Equal(cal.BusyPeriods[1:], cal.Busy(day.Add(12*time.Hour), day.AddDate(0, 0, 1)))
+}
+
+func (suite *DecodeTestSuite) TestParseFailsOnInvalidInput() {
+	_, err := suite.parse("<html></html>")
+	suite.ErrorContains(err, "malformed content line")
+
+	_, err = suite.parse("SUMMARY:test", "BEGIN:VCALENDAR", "END:VCALENDAR")
+	suite.ErrorContains(err, "content outside of VCALENDAR")
+
+	_, err = suite.parse("BEGIN:VCALENDAR", "BEGIN:VEVENT", "UID:1@test", "END:VEVENT", "END:VCALENDAR")
+	suite.ErrorContains(err, `event "1@test" has no DTSTART`)
+
+	_, err = suite.parse("BEGIN:VCALENDAR", "BEGIN:VEVENT", "DTSTART:yesterday", "END:VEVENT", "END:VCALENDAR")
+	suite.ErrorContains(err, "invalid DTSTART")
+
+	_, err = suite.parse("BEGIN:VCALENDAR", "BEGIN:VEVENT", "DTSTART:20230605T090000Z", "RRULE:FREQ=HOURLY",
+		"END:VEVENT", "END:VCALENDAR")
+	suite.ErrorContains(err, `frequency "HOURLY" is not supported`)
+
+	_, err = suite.parse("")
+	suite.ErrorContains(err, "not an iCalendar file")
+}
+
+func (suite *DecodeTestSuite) TestParseDuration() {
+	for value, expected := range map[string]time.Duration{
+		"PT15M":     15 * time.Minute,
+		"P1DT2H":    26 * time.Hour,
+		"P2W":       14 * 24 * time.Hour,
+		"-PT30S":    -30 * time.Second,
+		"+PT1H0M0S": time.Hour,
+	} {
+		duration, err := parseDuration(value)
+		suite.NoError(err)
+		suite.Equal(expected, duration, value)
+	}
+
+	_, err := parseDuration("P1M")
+	suite.Error(err)
+}
+
+func (suite *DecodeTestSuite) TestParsePropertyWithQuotedParams() {
+	prop, err := parseProperty(`ATTENDEE;CN="Doe; John: PhD";ROLE=REQ-PARTICIPANT:mailto:john@example.xyz`)
+	suite.NoError(err)
+	suite.Equal(property{
+		name:   "ATTENDEE",
+		params: map[string]string{"CN": "Doe; John: PhD", "ROLE": "REQ-PARTICIPANT"},
+		value:  "mailto:john@example.xyz",
+	}, prop)
+}
+
+func TestDecodeTestSuite(t *testing.T) {
+	suite.Run(t, new(DecodeTestSuite))
+}
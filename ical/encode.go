@@ -0,0 +1,213 @@
+// Package ical renders and parses calendars in the iCalendar format defined by RFC 5545.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Methods of calendars sent by email, see RFC 5546
+const (
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+// maxLineOctets is the length after which content lines are folded, see RFC 5545 section 3.1
+const maxLineOctets = 75
+
+const dateTimeLayout = "20060102T150405Z"
+
+// Calendar is a VCALENDAR holding events.
+type Calendar struct {
+	ProdID string
+	// Name is shown by calendar apps subscribing to the calendar
+	Name string
+	// Method is set on calendars sent by email, so that mail clients offer to add or remove their events
+	Method string
+	// Stamp is when a calendar with a Method is sent, which is the DTSTAMP of its events
+	Stamp  time.Time
+	Events []Event
+	// BusyPeriods are the busy times of the VFREEBUSY components of a parsed calendar
+	BusyPeriods []Period
+}
+
+// Person is an organizer or attendee of an event.
+type Person struct {
+	Name  string
+	Email string
+}
+
+// Event is a VEVENT. Times are written in UTC.
+type Event struct {
+	UID   string
+	Start time.Time
+	End   time.Time
+	// AllDay is set on parsed events which start on a date rather than at a time
+	AllDay bool
+	// Transparent events do not block time on the calendar
+	Transparent bool
+	// Recurrence is the RRULE of a recurring event
+	Recurrence *Recurrence
+	// ExceptionDates are starts of occurrences which are left out of the recurrence
+	ExceptionDates []time.Time
+	// RecurrenceID is set on an event replacing the occurrence of a recurring event with the same UID starting then
+	RecurrenceID time.Time
+	Summary      string
+	Description  string
+	Location     string
+	Status       string
+	Organizer    Person
+	Attendees    []Person
+	Created      time.Time
+	LastModified time.Time
+	// Sequence is the revision of the event, which is written on calendars with a Method so that mail clients
+	// apply a later revision over an earlier one, see RFC 5546 section 2.1.4
+	Sequence int
+
+	// duration is the DURATION of a parsed event, which sets its end when it has no DTEND
+	duration time.Duration
+}
+
+// Encode writes the calendar to w.
+func (cal Calendar) Encode(w io.Writer) error {
+	enc := encoder{w: w}
+	enc.line("BEGIN", "VCALENDAR")
+	enc.line("VERSION", "2.0")
+	enc.line("PRODID", escape(cal.ProdID))
+	enc.line("CALSCALE", "GREGORIAN")
+	if cal.Method != "" {
+		enc.line("METHOD", cal.Method)
+	}
+	if cal.Name != "" {
+		enc.line("X-WR-CALNAME", escape(cal.Name))
+	}
+	for _, event := range cal.Events {
+		event.encode(&enc, cal)
+	}
+	enc.line("END", "VCALENDAR")
+	return enc.err
+}
+
+// Bytes returns the calendar as written by Encode.
+func (cal Calendar) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := cal.Encode(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (event Event) encode(enc *encoder, cal Calendar) {
+	// DTSTAMP is when a calendar with a METHOD is sent, and the last revision of the event otherwise, see RFC 5545
+	// section 3.8.7.2
+	stamp := event.LastModified
+	if stamp.IsZero() {
+		stamp = event.Created
+	}
+	if cal.Method != "" && !cal.Stamp.IsZero() {
+		stamp = cal.Stamp
+	}
+
+	enc.line("BEGIN", "VEVENT")
+	enc.line("UID", escape(event.UID))
+	enc.line("DTSTAMP", formatTime(stamp))
+	if cal.Method != "" {
+		enc.line("SEQUENCE", strconv.Itoa(event.Sequence))
+	}
+	enc.line("DTSTART", formatTime(event.Start))
+	enc.line("DTEND", formatTime(event.End))
+	if event.Summary != "" {
+		enc.line("SUMMARY", escape(event.Summary))
+	}
+	if event.Description != "" {
+		enc.line("DESCRIPTION", escape(event.Description))
+	}
+	if event.Location != "" {
+		enc.line("LOCATION", escape(event.Location))
+	}
+	if event.Status != "" {
+		enc.line("STATUS", event.Status)
+	}
+	if event.Organizer.Email != "" {
+		enc.line("ORGANIZER"+commonName(event.Organizer), "mailto:"+event.Organizer.Email)
+	}
+	for _, attendee := range event.Attendees {
+		partStat := "ACCEPTED"
+		if event.Status == StatusCancelled {
+			partStat = "DECLINED"
+		}
+		enc.line("ATTENDEE"+commonName(attendee)+";ROLE=REQ-PARTICIPANT;PARTSTAT="+partStat, "mailto:"+attendee.Email)
+	}
+	if !event.Created.IsZero() {
+		enc.line("CREATED", formatTime(event.Created))
+	}
+	if !event.LastModified.IsZero() {
+		enc.line("LAST-MODIFIED", formatTime(event.LastModified))
+	}
+	enc.line("END", "VEVENT")
+}
+
+func commonName(person Person) string {
+	if person.Name == "" {
+		return ""
+	}
+	return ";CN=" + quoteParam(person.Name)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// escape escapes a TEXT value, see RFC 5545 section 3.3.11.
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(value)
+}
+
+// quoteParam quotes a parameter value if it contains characters which are not allowed unquoted. Double quotes
+// cannot be escaped in parameter values so they are dropped.
+func quoteParam(value string) string {
+	value = strings.ReplaceAll(value, `"`, "")
+	if strings.ContainsAny(value, ":;,") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+// line writes a content line, folding it so that no line is longer than 75 octets.
+func (enc *encoder) line(name, value string) {
+	if enc.err != nil {
+		return
+	}
+	_, enc.err = io.WriteString(enc.w, fold(fmt.Sprintf("%s:%s", name, value)))
+}
+
+func fold(line string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > maxLineOctets {
+			// Continuation lines start with a space which counts towards their length
+			b.WriteString("\r\n ")
+			length = 1
+		}
+		b.WriteRune(r)
+		length += size
+	}
+	b.WriteString("\r\n")
+	return b.String()
+}
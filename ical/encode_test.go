@@ -0,0 +1,120 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type EncodeTestSuite struct {
+	suite.Suite
+}
+
+func (suite *EncodeTestSuite) TestEncodeWritesEventsInUTC() {
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+	start := time.Date(2023, 6, 5, 14, 30, 0, 0, loc)
+	cal := Calendar{
+		ProdID: "-//test//EN",
+		Name:   "Bookings",
+		Events: []Event{{
+			UID:          "event-1@test",
+			Start:        start,
+			End:          start.Add(30 * time.Minute),
+			Summary:      "Intro call with test",
+			Location:     "Zoom",
+			Status:       StatusConfirmed,
+			Organizer:    Person{Name: "host", Email: "host@example.xyz"},
+			Attendees:    []Person{{Name: "test", Email: "test@example.xyz"}},
+			Created:      time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+			LastModified: time.Date(2023, 6, 2, 0, 0, 0, 0, time.UTC),
+		}},
+	}
+
+	data, err := cal.Bytes()
+	suite.NoError(err)
+	suite.Equal(strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//test//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:Bookings",
+		"BEGIN:VEVENT",
+		"UID:event-1@test",
+		"DTSTAMP:20230602T000000Z",
+		"DTSTART:20230605T090000Z",
+		"DTEND:20230605T093000Z",
+		"SUMMARY:Intro call with test",
+		"LOCATION:Zoom",
+		"STATUS:CONFIRMED",
+		"ORGANIZER;CN=host:mailto:host@example.xyz",
+		"ATTENDEE;CN=test;ROLE=REQ-PARTICIPANT;PARTSTAT=ACCEPTED:mailto:test@example",
+		" .xyz",
+		"CREATED:20230601T000000Z",
+		"LAST-MODIFIED:20230602T000000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n"), string(data))
+}
+
+func (suite *EncodeTestSuite) TestEncodeMarksCancelledEvents() {
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	data, err := Calendar{ProdID: "-//test//EN", Events: []Event{{
+		UID: "event-1@test", Start: start, End: start.Add(time.Hour), Status: StatusCancelled,
+		Attendees: []Person{{Email: "test@example.xyz"}},
+	}}}.Bytes()
+
+	suite.NoError(err)
+	suite.Contains(string(data), "STATUS:CANCELLED\r\n")
+	suite.Contains(string(data), "ATTENDEE;ROLE=REQ-PARTICIPANT;PARTSTAT=DECLINED:mailto:test@example.xyz\r\n")
+}
+
+func (suite *EncodeTestSuite) TestEncodeWritesMethodOfCalendarsSentByEmail() {
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	data, err := Calendar{ProdID: "-//test//EN", Method: MethodCancel, Events: []Event{{
+		UID: "event-1@test", Start: start, End: start.Add(time.Hour), Status: StatusCancelled,
+	}}}.Bytes()
+
+	suite.NoError(err)
+	suite.Contains(string(data), "CALSCALE:GREGORIAN\r\nMETHOD:CANCEL\r\n")
+}
+
+func (suite *EncodeTestSuite) TestEncodeStampsCalendarsSentByEmailWithSendTimeAndSequence() {
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	data, err := Calendar{ProdID: "-//test//EN", Method: MethodRequest, Stamp: time.Date(2023, 6, 3, 8, 0, 0, 0, time.UTC), Events: []Event{{
+		UID: "event-1@test", Start: start, End: start.Add(time.Hour), Sequence: 2, LastModified: time.Date(2023, 6, 2, 0, 0, 0, 0, time.UTC),
+	}}}.Bytes()
+
+	suite.NoError(err)
+	suite.Contains(string(data), "DTSTAMP:20230603T080000Z\r\nSEQUENCE:2\r\n")
+	suite.Contains(string(data), "LAST-MODIFIED:20230602T000000Z\r\n")
+}
+
+func (suite *EncodeTestSuite) TestEncodeEscapesText() {
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	data, err := Calendar{ProdID: "-//test//EN", Events: []Event{{
+		UID: "event-1@test", Start: start, End: start.Add(time.Hour),
+		Description: "bring notes; slides, and a\\b\nthanks",
+		Organizer:   Person{Name: "Doe, John", Email: "host@example.xyz"},
+	}}}.Bytes()
+
+	suite.NoError(err)
+	suite.Contains(string(data), `DESCRIPTION:bring notes\; slides\, and a\\b\nthanks`+"\r\n")
+	suite.Contains(string(data), "ORGANIZER;CN=\"Doe, John\":mailto:host@example.xyz\r\n")
+}
+
+func (suite *EncodeTestSuite) TestFoldKeepsLinesWithin75Octets() {
+	line := "DESCRIPTION:" + strings.Repeat("é", 100)
+
+	folded := fold(line)
+	for _, part := range strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n") {
+		suite.LessOrEqual(len(part), maxLineOctets)
+	}
+	suite.Equal(line, strings.ReplaceAll(strings.TrimSuffix(folded, "\r\n"), "\r\n ", ""))
+}
+
+func TestEncodeTestSuite(t *testing.T) {
+	suite.Run(t, new(EncodeTestSuite))
+}
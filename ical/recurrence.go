@@ -0,0 +1,411 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FrequencyDaily   = "DAILY"
+	FrequencyWeekly  = "WEEKLY"
+	FrequencyMonthly = "MONTHLY"
+	FrequencyYearly  = "YEARLY"
+)
+
+// maxPeriods bounds the number of periods (days, weeks, months or years) a recurrence is expanded over, so that
+// rules which never match again do not loop forever
+const maxPeriods = 50000
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// WeekdayNum is a BYDAY entry. N is the nth occurrence of the weekday in the month or year, counting from the end
+// when negative, and zero for every occurrence.
+type WeekdayNum struct {
+	Weekday time.Weekday
+	N       int
+}
+
+// Recurrence is a RRULE, see RFC 5545 section 3.3.10. Rules on parts of a day (BYHOUR and the like) are not supported.
+type Recurrence struct {
+	Frequency string
+	Interval  int
+	// Count limits the number of occurrences when not zero
+	Count int
+	// Until is the last time an occurrence may start when not zero
+	Until      time.Time
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	BySetPos   []int
+	WeekStart  time.Weekday
+}
+
+// Period is a span of time taken by an occurrence of an event.
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Busy returns the periods between from and to blocked by the calendar's events and free/busy information. Cancelled
+// and transparent events are left out, and occurrences of recurring events which were modified are replaced by their
+// modified version.
+func (cal Calendar) Busy(from, to time.Time) []Period {
+	modified := make(map[string]map[int64]bool)
+	for _, event := range cal.Events {
+		if event.RecurrenceID.IsZero() {
+			continue
+		}
+		if modified[event.UID] == nil {
+			modified[event.UID] = make(map[int64]bool)
+		}
+		modified[event.UID][event.RecurrenceID.Unix()] = true
+	}
+
+	periods := make([]Period, 0)
+	for _, period := range cal.BusyPeriods {
+		if period.End.After(from) && period.Start.Before(to) {
+			periods = append(periods, period)
+		}
+	}
+	for _, event := range cal.Events {
+		if event.Status == StatusCancelled || event.Transparent {
+			continue
+		}
+		for _, period := range event.occurrences(from, to) {
+			if event.RecurrenceID.IsZero() && modified[event.UID][period.Start.Unix()] {
+				continue
+			}
+			periods = append(periods, period)
+		}
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Start.Before(periods[j].Start) })
+	return periods
+}
+
+// occurrences returns the occurrences of the event overlapping with the range between from and to.
+func (event Event) occurrences(from, to time.Time) []Period {
+	starts := []time.Time{event.Start}
+	if event.Recurrence != nil && event.RecurrenceID.IsZero() {
+		starts = event.Recurrence.starts(event.Start, to)
+	}
+
+	excluded := make(map[int64]bool, len(event.ExceptionDates))
+	for _, exDate := range event.ExceptionDates {
+		excluded[exDate.Unix()] = true
+	}
+
+	duration := event.End.Sub(event.Start)
+	days := int(duration.Hours()+12) / 24
+	periods := make([]Period, 0)
+	for _, start := range starts {
+		if excluded[start.Unix()] {
+			continue
+		}
+		end := start.Add(duration)
+		if event.AllDay {
+			// days do not always take 24 hours
+			end = start.AddDate(0, 0, days)
+		}
+		if end.After(from) && start.Before(to) && end.After(start) {
+			periods = append(periods, Period{Start: start, End: end})
+		}
+	}
+	return periods
+}
+
+// starts returns the starts of the occurrences of a recurrence beginning at dtstart, up to the first one at or
+// after to. The first occurrence is always at dtstart.
+func (rule Recurrence) starts(dtstart, to time.Time) []time.Time {
+	starts := []time.Time{dtstart}
+	interval := rule.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	for period := 1; period < maxPeriods; period++ {
+		candidates := rule.period(dtstart, (period-1)*interval)
+		for _, candidate := range candidates {
+			if !candidate.After(dtstart) {
+				continue
+			}
+			if (rule.Count > 0 && len(starts) >= rule.Count) ||
+				(!rule.Until.IsZero() && candidate.After(rule.Until)) || !candidate.Before(to) {
+				return starts
+			}
+			starts = append(starts, candidate)
+		}
+	}
+	return starts
+}
+
+// period returns the candidate occurrences in the nth day, week, month or year after the one of dtstart, sorted.
+func (rule Recurrence) period(dtstart time.Time, n int) []time.Time {
+	year, month, day := dtstart.Date()
+	loc := dtstart.Location()
+	dates := make([]time.Time, 0)
+
+	switch rule.Frequency {
+	case FrequencyDaily:
+		date := time.Date(year, month, day+n, 0, 0, 0, 0, loc)
+		if rule.matchesMonth(date) && rule.matchesMonthDay(date) && rule.matchesWeekday(date) {
+			dates = append(dates, date)
+		}
+	case FrequencyWeekly:
+		offset := (int(dtstart.Weekday()) - int(rule.WeekStart) + 7) % 7
+		weekStart := time.Date(year, month, day-offset+7*n, 0, 0, 0, 0, loc)
+		weekdays := []time.Weekday{dtstart.Weekday()}
+		if len(rule.ByDay) > 0 {
+			weekdays = weekdays[:0]
+			for _, byDay := range rule.ByDay {
+				weekdays = append(weekdays, byDay.Weekday)
+			}
+		}
+		for _, weekday := range weekdays {
+			date := weekStart.AddDate(0, 0, (int(weekday)-int(rule.WeekStart)+7)%7)
+			if rule.matchesMonth(date) {
+				dates = append(dates, date)
+			}
+		}
+	case FrequencyMonthly:
+		first := time.Date(year, month+time.Month(n), 1, 0, 0, 0, 0, loc)
+		if rule.matchesMonth(first) {
+			dates = rule.daysOfMonth(first, day)
+		}
+	case FrequencyYearly:
+		if len(rule.ByDay) > 0 && len(rule.ByMonth) == 0 && len(rule.ByMonthDay) == 0 {
+			// the nth weekday counts over the whole year
+			first := time.Date(year+n, time.January, 1, 0, 0, 0, 0, loc)
+			dates = rule.weekdaysIn(first, first.AddDate(1, 0, 0))
+			break
+		}
+		months := rule.ByMonth
+		if len(months) == 0 {
+			months = []time.Month{month}
+		}
+		for _, m := range months {
+			dates = append(dates, rule.daysOfMonth(time.Date(year+n, m, 1, 0, 0, 0, 0, loc), day)...)
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	dates = rule.selectPositions(dates)
+
+	hour, minute, second := dtstart.Clock()
+	starts := make([]time.Time, 0, len(dates))
+	for _, date := range dates {
+		y, m, d := date.Date()
+		starts = append(starts, time.Date(y, m, d, hour, minute, second, 0, loc))
+	}
+	return starts
+}
+
+// daysOfMonth returns the days of the month starting at first matching the rule, or the day of the month of
+// DTSTART if the rule does not name any days.
+func (rule Recurrence) daysOfMonth(first time.Time, day int) []time.Time {
+	next := first.AddDate(0, 1, 0)
+	length := next.AddDate(0, 0, -1).Day()
+	dates := make([]time.Time, 0)
+
+	switch {
+	case len(rule.ByMonthDay) > 0:
+		for _, monthDay := range rule.ByMonthDay {
+			if monthDay < 0 {
+				monthDay += length + 1
+			}
+			if monthDay < 1 || monthDay > length {
+				continue
+			}
+			date := first.AddDate(0, 0, monthDay-1)
+			if rule.matchesWeekday(date) {
+				dates = append(dates, date)
+			}
+		}
+	case len(rule.ByDay) > 0:
+		dates = rule.weekdaysIn(first, next)
+	case day <= length:
+		// months without the day of DTSTART are skipped, see RFC 5545 section 3.3.10
+		dates = append(dates, first.AddDate(0, 0, day-1))
+	}
+	return dates
+}
+
+// weekdaysIn returns the days between from and to matching the BYDAY entries of the rule.
+func (rule Recurrence) weekdaysIn(from, to time.Time) []time.Time {
+	dates := make([]time.Time, 0)
+	for _, byDay := range rule.ByDay {
+		matching := make([]time.Time, 0)
+		first := from.AddDate(0, 0, (int(byDay.Weekday)-int(from.Weekday())+7)%7)
+		for date := first; date.Before(to); date = date.AddDate(0, 0, 7) {
+			matching = append(matching, date)
+		}
+		switch {
+		case byDay.N == 0:
+			dates = append(dates, matching...)
+		case byDay.N > 0 && byDay.N <= len(matching):
+			dates = append(dates, matching[byDay.N-1])
+		case byDay.N < 0 && -byDay.N <= len(matching):
+			dates = append(dates, matching[len(matching)+byDay.N])
+		}
+	}
+	return dates
+}
+
+// selectPositions applies BYSETPOS to the sorted candidates of a period.
+func (rule Recurrence) selectPositions(dates []time.Time) []time.Time {
+	if len(rule.BySetPos) == 0 {
+		return dates
+	}
+	selected := make([]time.Time, 0, len(rule.BySetPos))
+	for _, pos := range rule.BySetPos {
+		if pos < 0 {
+			pos += len(dates) + 1
+		}
+		if pos >= 1 && pos <= len(dates) {
+			selected = append(selected, dates[pos-1])
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Before(selected[j]) })
+	return selected
+}
+
+func (rule Recurrence) matchesMonth(date time.Time) bool {
+	if len(rule.ByMonth) == 0 {
+		return true
+	}
+	for _, month := range rule.ByMonth {
+		if date.Month() == month {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule Recurrence) matchesMonthDay(date time.Time) bool {
+	if len(rule.ByMonthDay) == 0 {
+		return true
+	}
+	length := time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, date.Location()).Day()
+	for _, monthDay := range rule.ByMonthDay {
+		if date.Day() == monthDay || date.Day() == length+monthDay+1 {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule Recurrence) matchesWeekday(date time.Time) bool {
+	if len(rule.ByDay) == 0 {
+		return true
+	}
+	for _, byDay := range rule.ByDay {
+		if date.Weekday() == byDay.Weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRecurrence parses the value of a RRULE property. A date-only UNTIL is interpreted in loc and covers the
+// whole day.
+func parseRecurrence(value string, loc *time.Location) (Recurrence, error) {
+	rule := Recurrence{Interval: 1, WeekStart: time.Monday}
+	for _, part := range strings.Split(value, ";") {
+		name, partValue, found := strings.Cut(part, "=")
+		if !found {
+			return Recurrence{}, fmt.Errorf("malformed rule part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(name) {
+		case "FREQ":
+			rule.Frequency = strings.ToUpper(partValue)
+		case "INTERVAL":
+			rule.Interval, err = strconv.Atoi(partValue)
+		case "COUNT":
+			rule.Count, err = strconv.Atoi(partValue)
+		case "UNTIL":
+			var isDate bool
+			rule.Until, isDate, err = parseTime(property{params: map[string]string{}, value: partValue}, loc)
+			if isDate {
+				rule.Until = rule.Until.AddDate(0, 0, 1).Add(-time.Second)
+			}
+		case "BYDAY":
+			for _, entry := range strings.Split(partValue, ",") {
+				var byDay WeekdayNum
+				byDay, err = parseWeekdayNum(entry)
+				if err != nil {
+					break
+				}
+				rule.ByDay = append(rule.ByDay, byDay)
+			}
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseInts(partValue, -31, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseInts(partValue, 1, 12)
+			for _, month := range months {
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+			}
+		case "BYSETPOS":
+			rule.BySetPos, err = parseInts(partValue, -366, 366)
+		case "WKST":
+			weekday, ok := weekdays[strings.ToUpper(partValue)]
+			if !ok {
+				err = fmt.Errorf("unknown weekday %q", partValue)
+			}
+			rule.WeekStart = weekday
+		default:
+			err = fmt.Errorf("%s is not supported", name)
+		}
+		if err != nil {
+			return Recurrence{}, fmt.Errorf("rule part %s: %w", name, err)
+		}
+	}
+
+	switch rule.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	default:
+		return Recurrence{}, fmt.Errorf("frequency %q is not supported", rule.Frequency)
+	}
+	if rule.Interval < 1 || rule.Count < 0 {
+		return Recurrence{}, fmt.Errorf("interval and count must be positive")
+	}
+	return rule, nil
+}
+
+func parseWeekdayNum(entry string) (WeekdayNum, error) {
+	entry = strings.ToUpper(strings.TrimSpace(entry))
+	if len(entry) < 2 {
+		return WeekdayNum{}, fmt.Errorf("unknown weekday %q", entry)
+	}
+	weekday, ok := weekdays[entry[len(entry)-2:]]
+	if !ok {
+		return WeekdayNum{}, fmt.Errorf("unknown weekday %q", entry)
+	}
+	byDay := WeekdayNum{Weekday: weekday}
+	if ordinal := entry[:len(entry)-2]; ordinal != "" {
+		n, err := strconv.Atoi(ordinal)
+		if err != nil || n == 0 || n < -53 || n > 53 {
+			return WeekdayNum{}, fmt.Errorf("invalid ordinal in %q", entry)
+		}
+		byDay.N = n
+	}
+	return byDay, nil
+}
+
+func parseInts(value string, low, high int) ([]int, error) {
+	values := make([]int, 0)
+	for _, entry := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(entry))
+		if err != nil || n == 0 || n < low || n > high {
+			return nil, fmt.Errorf("invalid value %q", entry)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
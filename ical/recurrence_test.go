@@ -0,0 +1,121 @@
+package ical
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type RecurrenceTestSuite struct {
+	suite.Suite
+}
+
+func (suite *RecurrenceTestSuite) starts(rule string, dtstart time.Time, to time.Time) []time.Time {
+	recurrence, err := parseRecurrence(rule, time.UTC)
+	suite.NoError(err)
+	return recurrence.starts(dtstart, to)
+}
+
+func (suite *RecurrenceTestSuite) TestWeeklyOnSeveralDays() {
+	// 2023-06-05 is a Monday
+	dtstart := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	suite.Equal([]time.Time{
+		time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 6, 7, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 6, 19, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 6, 21, 9, 0, 0, 0, time.UTC),
+	}, suite.starts("FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=4", dtstart, dtstart.AddDate(1, 0, 0)))
+}
+
+func (suite *RecurrenceTestSuite) TestDailyStopsAtUntilAndWindow() {
+	dtstart := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	suite.Len(suite.starts("FREQ=DAILY;UNTIL=20230609T090000Z", dtstart, dtstart.AddDate(1, 0, 0)), 5)
+	suite.Len(suite.starts("FREQ=DAILY", dtstart, dtstart.AddDate(0, 0, 10)), 10)
+	suite.Equal([]time.Time{
+		time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 6, 10, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 6, 11, 9, 0, 0, 0, time.UTC),
+	}, suite.starts("FREQ=DAILY;BYDAY=SA,SU", dtstart, dtstart.AddDate(0, 0, 7)))
+}
+
+func (suite *RecurrenceTestSuite) TestMonthlySkipsShortMonths() {
+	dtstart := time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC)
+	suite.Equal([]time.Time{
+		time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 3, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 5, 31, 9, 0, 0, 0, time.UTC),
+	}, suite.starts("FREQ=MONTHLY;COUNT=3", dtstart, dtstart.AddDate(1, 0, 0)))
+}
+
+func (suite *RecurrenceTestSuite) TestMonthlyOnLastWorkday() {
+	dtstart := time.Date(2023, 6, 30, 9, 0, 0, 0, time.UTC)
+	suite.Equal([]time.Time{
+		time.Date(2023, 6, 30, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 7, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 8, 31, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 9, 29, 9, 0, 0, 0, time.UTC),
+	}, suite.starts("FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1;COUNT=4", dtstart, dtstart.AddDate(1, 0, 0)))
+}
+
+func (suite *RecurrenceTestSuite) TestYearly() {
+	dtstart := time.Date(2023, 11, 23, 9, 0, 0, 0, time.UTC)
+	suite.Equal([]time.Time{
+		time.Date(2023, 11, 23, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 11, 28, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 11, 27, 9, 0, 0, 0, time.UTC),
+	}, suite.starts("FREQ=YEARLY;BYMONTH=11;BYDAY=4TH;COUNT=3", dtstart, dtstart.AddDate(5, 0, 0)))
+
+	dtstart = time.Date(2024, 2, 29, 9, 0, 0, 0, time.UTC)
+	suite.Equal([]time.Time{dtstart, dtstart.AddDate(4, 0, 0)},
+		suite.starts("FREQ=YEARLY", dtstart, time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func (suite *RecurrenceTestSuite) TestRuleWhichNeverMatchesTerminates() {
+	dtstart := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	suite.Equal([]time.Time{dtstart}, suite.starts("FREQ=MONTHLY;BYMONTHDAY=30;BYMONTH=2", dtstart,
+		dtstart.AddDate(1000, 0, 0)))
+}
+
+func (suite *RecurrenceTestSuite) TestBusyExpandsRecurringEvents() {
+	dtstart := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	rule, _ := parseRecurrence("FREQ=DAILY;COUNT=5", time.UTC)
+	cal := Calendar{Events: []Event{
+		{
+			UID:            "1@test",
+			Start:          dtstart,
+			End:            dtstart.Add(time.Hour),
+			Recurrence:     &rule,
+			ExceptionDates: []time.Time{dtstart.AddDate(0, 0, 2)},
+		},
+		{
+			UID:          "1@test",
+			RecurrenceID: dtstart.AddDate(0, 0, 3),
+			Start:        dtstart.AddDate(0, 0, 3).Add(4 * time.Hour),
+			End:          dtstart.AddDate(0, 0, 3).Add(5 * time.Hour),
+		},
+		{UID: "2@test", Start: dtstart, End: dtstart.Add(time.Hour), Status: StatusCancelled},
+		{UID: "3@test", Start: dtstart, End: dtstart.Add(time.Hour), Transparent: true},
+	}}
+
+	suite.Equal([]Period{
+		{Start: dtstart.AddDate(0, 0, 1), End: dtstart.AddDate(0, 0, 1).Add(time.Hour)},
+		{Start: dtstart.AddDate(0, 0, 3).Add(4 * time.Hour), End: dtstart.AddDate(0, 0, 3).Add(5 * time.Hour)},
+		{Start: dtstart.AddDate(0, 0, 4), End: dtstart.AddDate(0, 0, 4).Add(time.Hour)},
+	}, cal.Busy(dtstart.Add(time.Hour), dtstart.AddDate(0, 1, 0)))
+}
+
+func (suite *RecurrenceTestSuite) TestParseRecurrenceRejectsUnsupportedParts() {
+	_, err := parseRecurrence("FREQ=DAILY;BYHOUR=9", time.UTC)
+	suite.ErrorContains(err, "BYHOUR is not supported")
+
+	_, err = parseRecurrence("FREQ=WEEKLY;BYDAY=XX", time.UTC)
+	suite.ErrorContains(err, `unknown weekday "XX"`)
+
+	_, err = parseRecurrence("FREQ=WEEKLY;INTERVAL=0", time.UTC)
+	suite.Error(err)
+}
+
+func TestRecurrenceTestSuite(t *testing.T) {
+	suite.Run(t, new(RecurrenceTestSuite))
+}
@@ -0,0 +1,158 @@
+// Package mailtest provides an in-process SMTP server for tests, which accepts every message and keeps it.
+package mailtest
+
+import (
+	"io"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Message is a message received by the server.
+type Message struct {
+	From string
+	To   []string
+	Data []byte
+}
+
+// Parse parses the received message.
+func (msg Message) Parse() (*mail.Message, error) {
+	return mail.ReadMessage(strings.NewReader(string(msg.Data)))
+}
+
+// Server is a fake SMTP server. It does not offer STARTTLS nor authentication.
+type Server struct {
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	messages []Message
+	// reject makes the server answer RCPT commands with a permanent error
+	reject bool
+}
+
+// NewServer starts a server listening on a local port, which should be closed when done.
+func NewServer() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(err)
+	}
+	server := &Server{listener: listener}
+	server.wg.Add(1)
+	go server.serve()
+	return server
+}
+
+// Host returns the host the server listens on.
+func (server *Server) Host() string {
+	return server.listener.Addr().(*net.TCPAddr).IP.String()
+}
+
+// Port returns the port the server listens on.
+func (server *Server) Port() int {
+	return server.listener.Addr().(*net.TCPAddr).Port
+}
+
+// Messages returns the messages received so far.
+func (server *Server) Messages() []Message {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	return append([]Message(nil), server.messages...)
+}
+
+// RejectRecipients makes the server refuse every recipient, as if their mailboxes did not exist.
+func (server *Server) RejectRecipients() {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+	server.reject = true
+}
+
+// Close stops the server and waits for the open connections to finish.
+func (server *Server) Close() {
+	server.listener.Close()
+	server.wg.Wait()
+}
+
+func (server *Server) serve() {
+	defer server.wg.Done()
+	for {
+		conn, err := server.listener.Accept()
+		if err != nil {
+			return
+		}
+		server.wg.Add(1)
+		go func() {
+			defer server.wg.Done()
+			server.handle(conn)
+		}()
+	}
+}
+
+func (server *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	text := textproto.NewConn(conn)
+	reply := func(code int, message string) {
+		text.PrintfLine("%d %s", code, message)
+	}
+
+	reply(220, "mailtest ESMTP")
+	var msg Message
+	for {
+		line, err := text.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			reply(250, "mailtest")
+		case "MAIL":
+			msg = Message{From: address(arg)}
+			reply(250, "OK")
+		case "RCPT":
+			server.mu.Lock()
+			reject := server.reject
+			server.mu.Unlock()
+			if reject {
+				reply(550, "no such user")
+				continue
+			}
+			msg.To = append(msg.To, address(arg))
+			reply(250, "OK")
+		case "DATA":
+			reply(354, "end data with <CR><LF>.<CR><LF>")
+			data, err := io.ReadAll(text.DotReader())
+			if err != nil {
+				return
+			}
+			msg.Data = data
+			server.mu.Lock()
+			server.messages = append(server.messages, msg)
+			server.mu.Unlock()
+			reply(250, "queued as "+strconv.Itoa(len(server.Messages())))
+		case "RSET":
+			msg = Message{}
+			reply(250, "OK")
+		case "NOOP":
+			reply(250, "OK")
+		case "QUIT":
+			reply(221, "bye")
+			return
+		default:
+			reply(502, "command not implemented")
+		}
+	}
+}
+
+// address returns the address of a MAIL FROM:<address> or RCPT TO:<address> argument.
+func address(arg string) string {
+	_, value, _ := strings.Cut(arg, ":")
+	value = strings.TrimSpace(value)
+	if end := strings.Index(value, ">"); end >= 0 {
+		value = value[:end]
+	}
+	return strings.TrimPrefix(value, "<")
+}
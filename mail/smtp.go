@@ -0,0 +1,95 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"log"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const sendTimeout = 30 * time.Second
+
+// SMTP sends messages through an SMTP server, upgrading the connection with STARTTLS whenever the server offers it.
+type SMTP struct {
+	host     string
+	addr     string
+	username string
+	password string
+}
+
+// Send delivers the message to every recipient.
+func (sender SMTP) Send(ctx context.Context, msg Message) error {
+	data, err := msg.Bytes()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", sender.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, sender.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: sender.host}); err != nil {
+			return err
+		}
+	}
+	if sender.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", sender.username, sender.password, sender.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(msg.From.Address); err != nil {
+		return err
+	}
+	for _, to := range msg.To {
+		if err := client.Rcpt(to.Address); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func NewSMTP(host string, port int, username, password string) SMTP {
+	return SMTP{host: host, addr: net.JoinHostPort(host, strconv.Itoa(port)), username: username, password: password}
+}
+
+// Log only logs the messages it is given, for setups without an SMTP server.
+type Log struct{}
+
+func (Log) Send(ctx context.Context, msg Message) error {
+	to := make([]string, 0, len(msg.To))
+	for _, address := range msg.To {
+		to = append(to, address.Address)
+	}
+	log.Printf("SMTP is not configured, dropping email %q to %s", msg.Subject, strings.Join(to, ", "))
+	return nil
+}
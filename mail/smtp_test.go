@@ -0,0 +1,109 @@
+package mail
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/mail/mailtest"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SMTPTestSuite struct {
+	suite.Suite
+	server *mailtest.Server
+	sender SMTP
+	ctx    context.Context
+}
+
+func (suite *SMTPTestSuite) SetupTest() {
+	suite.server = mailtest.NewServer()
+	suite.sender = NewSMTP(suite.server.Host(), suite.server.Port(), "", "")
+	suite.ctx = context.Background()
+}
+
+func (suite *SMTPTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *SMTPTestSuite) message() Message {
+	return Message{
+		From:    mail.Address{Name: "calendly", Address: "noreply@example.xyz"},
+		To:      []mail.Address{{Name: "Jürgen", Address: "test@example.xyz"}},
+		Subject: "Confirmed: Intro call with Jürgen",
+		Body:    "Your booking is confirmed.\nSee you then!",
+		Date:    time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (suite *SMTPTestSuite) TestSendDeliversPlainTextMessage() {
+	suite.NoError(suite.sender.Send(suite.ctx, suite.message()))
+
+	messages := suite.server.Messages()
+	suite.Len(messages, 1)
+	suite.Equal("noreply@example.xyz", messages[0].From)
+	suite.Equal([]string{"test@example.xyz"}, messages[0].To)
+
+	parsed, err := messages[0].Parse()
+	suite.NoError(err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	suite.NoError(err)
+	suite.Equal("Confirmed: Intro call with Jürgen", subject)
+	suite.Equal("Thu, 01 Jun 2023 00:00:00 +0000", parsed.Header.Get("Date"))
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	suite.NoError(err)
+	suite.Equal("Your booking is confirmed.\nSee you then!\n", string(body))
+}
+
+func (suite *SMTPTestSuite) TestSendAttachesFiles() {
+	msg := suite.message()
+	ics := []byte("BEGIN:VCALENDAR\r\nMETHOD:REQUEST\r\nEND:VCALENDAR\r\n")
+	msg.Attachments = []Attachment{{Filename: "invite.ics", ContentType: "text/calendar; charset=utf-8; method=REQUEST", Data: ics}}
+
+	suite.NoError(suite.sender.Send(suite.ctx, msg))
+
+	parsed, err := suite.server.Messages()[0].Parse()
+	suite.NoError(err)
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	suite.NoError(err)
+	suite.Equal("multipart/mixed", mediaType)
+
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	body, err := parts.NextPart()
+	suite.NoError(err)
+	text, _ := io.ReadAll(body)
+	suite.Equal("Your booking is confirmed.\nSee you then!", string(text))
+
+	attachment, err := parts.NextPart()
+	suite.NoError(err)
+	suite.Equal("invite.ics", attachment.FileName())
+	suite.Equal("text/calendar; charset=utf-8; method=REQUEST", attachment.Header.Get("Content-Type"))
+	data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, attachment))
+	suite.NoError(err)
+	suite.Equal(ics, data)
+}
+
+func (suite *SMTPTestSuite) TestSendReturnsErrorIfRecipientIsRejected() {
+	suite.server.RejectRecipients()
+
+	err := suite.sender.Send(suite.ctx, suite.message())
+	suite.ErrorContains(err, "550")
+	suite.Empty(suite.server.Messages())
+}
+
+func (suite *SMTPTestSuite) TestSendReturnsErrorIfServerIsUnreachable() {
+	suite.server.Close()
+
+	suite.Error(suite.sender.Send(suite.ctx, suite.message()))
+}
+
+func TestSMTPTestSuite(t *testing.T) {
+	suite.Run(t, new(SMTPTestSuite))
+}
@@ -1,11 +1,17 @@
 package main
 
 import (
+	"context"
+	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	_ "time/tzdata"
 
 	"github.com/harbor-xyz/coding-project/database"
 	_ "github.com/harbor-xyz/coding-project/docs"
+	"github.com/harbor-xyz/coding-project/scheduler"
 	"github.com/harbor-xyz/coding-project/server"
 )
 
@@ -13,9 +19,45 @@ import (
 // @version 1.0
 // @description Calendly Backend APIs
 // @BasePath /
+// @securityDefinitions.apikey ApiKey
+// @in header
+// @name Authorization
+// @description An API key of the user, as "Bearer <key>", or a JWT signed with JWT_SECRET whose subject is the user ID
+// @securityDefinitions.apikey AdminToken
+// @in header
+// @name Authorization
+// @description The admin token, as "Bearer <ADMIN_TOKEN>"
 func main() {
 	database.Init(os.Getenv("POSTGRES_USER"), os.Getenv("POSTGRES_PASSWORD"), os.Getenv("POSTGRES_DB"))
 
-	r := server.Init()
-	http.ListenAndServe(":8080", r)
+	schedulerConfig, err := scheduler.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+	serverConfig, err := server.ConfigFromEnv()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go scheduler.Init(schedulerConfig).Run(ctx)
+	go scheduler.InitWebhookDispatcher(schedulerConfig).Run(ctx)
+	go scheduler.InitNotificationDispatcher(schedulerConfig).Run(ctx)
+	workerDone := make(chan struct{})
+	go func() {
+		scheduler.InitWorker(schedulerConfig).Run(ctx)
+		close(workerDone)
+	}()
+
+	srv := &http.Server{Addr: ":8080", Handler: server.Init(serverConfig)}
+	go func() {
+		<-ctx.Done()
+		srv.Shutdown(context.Background())
+	}()
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+	// Let the jobs already claimed finish, so that they are not run again once their lease expires
+	<-workerDone
 }
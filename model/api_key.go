@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// APIKey authenticates requests made on behalf of a user. Only the SHA-256 hash of the key is stored, along with its
+// first characters so that users can tell their keys apart.
+type APIKey struct {
+	ID      uint   `gorm:"primaryKey"`
+	UserID  uint   `gorm:"index"`
+	Name    string `gorm:"not null"`
+	Prefix  string `gorm:"not null"`
+	KeyHash string `gorm:"uniqueIndex"`
+	// LastUsedAt is when the key last authenticated a request, zero if it never did
+	LastUsedAt time.Time
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
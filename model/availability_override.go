@@ -0,0 +1,32 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// AvailabilityOverride replaces the weekly availability of a user for every date between
+// StartDate and EndDate (both inclusive), either with its own windows or by marking the dates unavailable.
+type AvailabilityOverride struct {
+	ID           uint           `gorm:"primaryKey"`
+	UserID       uint           `gorm:"index"`
+	StartDate    datatypes.Date `gorm:"not null"`
+	EndDate      datatypes.Date `gorm:"not null"`
+	Unavailable  bool
+	Availability datatypes.JSONSlice[Availability]
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+}
+
+// Covers reports whether the calendar date of t falls within the override.
+func (override AvailabilityOverride) Covers(t time.Time) bool {
+	date := Date(t)
+	return !date.Before(Date(time.Time(override.StartDate))) && !date.After(Date(time.Time(override.EndDate)))
+}
+
+// Date strips the time of day off t, returning its calendar date as midnight UTC.
+func Date(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -12,6 +12,18 @@ const (
 	Sunday    Day = "sunday"
 )
 
+// Days lists the days of the week in the order they are presented to users.
+var Days = []Day{Monday, Tuesday, Wednesday, Thursday, Friday, Saturday, Sunday}
+
+func (day Day) IsValid() bool {
+	for _, d := range Days {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
 func GetDayFromInt(day int) Day {
 	dayMap := map[int]Day{
 		0: Sunday,
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ErrorKind int
+
+const (
+	KindNotFound ErrorKind = iota + 1
+	KindConflict
+	KindValidation
+	KindForbidden
+	KindUnauthorized
+)
+
+// Error is an error clients are told about. The APIs report it with the status of its kind, its code, which clients
+// can rely on, and its message. Any other error is reported as an internal error without its message.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	// Fields tells which fields of the request are invalid, or which of its parts failed, and why
+	Fields map[string]string
+	// Err is the cause of the error, which is logged but not reported to clients
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is matches errors of the same code, so that errors made from the ones below, such as with Withf, still match them.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Withf returns a copy of the error whose message is followed by the given details.
+func (e *Error) Withf(format string, args ...interface{}) *Error {
+	err := *e
+	err.Message = e.Message + ": " + fmt.Sprintf(format, args...)
+	return &err
+}
+
+// WithFields returns a copy of the error telling which fields, or parts, of the request failed, and why.
+func (e *Error) WithFields(fields map[string]string) *Error {
+	err := *e
+	err.Fields = fields
+	return &err
+}
+
+// Wrap returns a copy of the error caused by err, whose message is not reported.
+func (e *Error) Wrap(err error) *Error {
+	wrapped := *e
+	wrapped.Err = err
+	return &wrapped
+}
+
+// NotFound returns the error of a resource, such as "event type", which does not exist. err is the error it was
+// looked up with, usually sql.ErrNoRows.
+func NotFound(resource string, err error) *Error {
+	return &Error{
+		Kind:    KindNotFound,
+		Code:    strings.ReplaceAll(strings.ToLower(resource), " ", "_") + "_not_found",
+		Message: resource + " not found",
+		Err:     err,
+	}
+}
+
+// Validation returns the error of an invalid field of a request.
+func Validation(field, message string) *Error {
+	return &Error{Kind: KindValidation, Code: "validation_failed", Message: message, Fields: map[string]string{field: message}}
+}
+
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func Forbidden(message string) *Error {
+	return &Error{Kind: KindForbidden, Code: "forbidden", Message: message}
+}
+
+func Unauthorized(message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: "unauthorized", Message: message}
+}
+
+var (
+	ErrOverlappingOverride = Conflict("overlapping_override", "override overlaps an existing override")
+	ErrDuplicateSlug       = Conflict("duplicate_slug", "slug is already in use")
+	ErrDuplicateEmail      = Conflict("duplicate_email", "email is already in use")
+	ErrSlotsExist          = Conflict("slots_exist", "slots already exist")
+	ErrEventTypeMismatch   = &Error{Kind: KindValidation, Code: "event_type_mismatch", Message: "slot does not belong to the event type",
+		Fields: map[string]string{"slot_id": "slot does not belong to the event type"}}
+	ErrSlotUnavailable   = Conflict("slot_unavailable", "requested time is not available")
+	ErrSlotAlreadyBooked = Conflict("slot_already_booked", "slot is already booked")
+	ErrBookingLimit      = Conflict("booking_limit_reached", "maximum number of bookings is reached")
+	ErrEventCancelled    = Conflict("event_cancelled", "event is already cancelled")
+	ErrEventStarted      = Conflict("event_started", "event has already started")
+	// ErrInvalidToken is reported as a missing booking, since the token is how invitees find their booking
+	ErrInvalidToken    = &Error{Kind: KindNotFound, Code: "invalid_token", Message: "booking token is invalid or has expired"}
+	ErrInvalidCalendar = &Error{Kind: KindValidation, Code: "invalid_calendar", Message: "calendar could not be imported"}
+	ErrJobNotDead      = Conflict("job_not_dead", "only dead jobs can be retried")
+	ErrDuplicateMember = Conflict("duplicate_member", "user is already a member of the team")
+	// ErrTeamEvent is returned when rescheduling a team booking, which has to be cancelled and booked again instead
+	ErrTeamEvent = Conflict("team_event", "bookings of team event types cannot be rescheduled")
+	// ErrOccurrencesUnavailable is returned with the start times of the occurrences of a series which cannot be booked
+	ErrOccurrencesUnavailable = Conflict("occurrences_unavailable", "some occurrences of the series are not available")
+	ErrSeriesOver             = Conflict("series_over", "series has no upcoming occurrences")
+	ErrInvalidCredentials     = Unauthorized("API key or token is invalid")
+	ErrForbidden              = Forbidden("resources of other users cannot be accessed")
+)
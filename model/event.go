@@ -2,16 +2,43 @@ package model
 
 import "time"
 
+type eventStatus int
+
+const (
+	EventConfirmed eventStatus = 0
+	EventCancelled eventStatus = 1
+)
+
+func (s eventStatus) String() string {
+	switch s {
+	case EventConfirmed:
+		return "confirmed"
+	case EventCancelled:
+		return "cancelled"
+	}
+	return ""
+}
+
 type Event struct {
-	ID           uint `gorm:"primaryKey"`
-	UserID       uint
-	SlotID       uint   `gorm:"uniqueIndex"`
+	ID     uint `gorm:"primaryKey"`
+	UserID uint
+	// Slots of group event types are booked by several events, up to the capacity of the slot
+	SlotID      uint `gorm:"index:idx_events_booked_slot_id"`
+	EventTypeID uint
+	// TeamEventTypeID is set instead of EventTypeID for bookings made through an event type of a team
+	TeamEventTypeID uint `gorm:"index"`
+	// SeriesID links the occurrences of a recurring booking
+	SeriesID     uint   `gorm:"index"`
 	InviteeEmail string `gorm:"not null"`
 	InviteeName  string `gorm:"not null"`
 	InviteeNotes string
 	StartTime    time.Time `gorm:"not null"`
 	EndTime      time.Time `gorm:"not null"`
-	CreatedAt    time.Time `gorm:"autoCreateTime"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
-	DeletedAt    time.Time
+	Status       eventStatus
+	CancelReason string
+	// Revision counts the reschedules of the event, so that management tokens handed out before one stop working
+	Revision  uint      `gorm:"not null;default:0"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	DeletedAt time.Time
 }
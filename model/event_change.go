@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+const (
+	ChangeCancelled   = "cancelled"
+	ChangeRescheduled = "rescheduled"
+)
+
+const (
+	ChangedByHost    = "host"
+	ChangedByInvitee = "invitee"
+)
+
+// EventChange records a cancellation or a reschedule of an event along with the times the event had before it.
+type EventChange struct {
+	ID                uint   `gorm:"primaryKey"`
+	EventID           uint   `gorm:"index"`
+	Action            string `gorm:"not null"`
+	ChangedBy         string `gorm:"not null"`
+	Reason            string
+	PreviousSlotID    uint
+	PreviousStartTime time.Time
+	PreviousEndTime   time.Time
+	StartTime         time.Time
+	EndTime           time.Time
+	CreatedAt         time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,65 @@
+package model
+
+import "time"
+
+const (
+	FrequencyWeekly   = "weekly"
+	FrequencyBiweekly = "biweekly"
+	FrequencyMonthly  = "monthly"
+)
+
+// MaxOccurrences bounds how many occurrences a series can have, whether given by a count or an end date
+const MaxOccurrences = 52
+
+// EventSeries links the occurrences of a recurring booking, so that they can be cancelled or rescheduled together as
+// well as one by one.
+type EventSeries struct {
+	ID          uint `gorm:"primaryKey"`
+	UserID      uint `gorm:"index"`
+	EventTypeID uint
+	Frequency   string `gorm:"not null"`
+	// Count or Until, whichever was given, bound the occurrences
+	Count        int
+	Until        time.Time
+	InviteeEmail string `gorm:"not null"`
+	InviteeName  string `gorm:"not null"`
+	InviteeNotes string
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime"`
+
+	Events []Event `gorm:"foreignKey:SeriesID"`
+}
+
+// Occurrences returns the start times of the series starting at start, repeated in loc so that they keep their time
+// of day across daylight saving changes. Monthly occurrences fall on the day of the month of start, and months
+// without that day are skipped. At most MaxOccurrences+1 start times are returned, so that callers can tell when a
+// series is too long.
+func (series EventSeries) Occurrences(start time.Time, loc *time.Location) []time.Time {
+	first := start.In(loc)
+	occurrences := make([]time.Time, 0)
+	for i := 0; len(occurrences) <= MaxOccurrences; i++ {
+		var t time.Time
+		switch series.Frequency {
+		case FrequencyWeekly:
+			t = first.AddDate(0, 0, 7*i)
+		case FrequencyBiweekly:
+			t = first.AddDate(0, 0, 14*i)
+		case FrequencyMonthly:
+			t = time.Date(first.Year(), first.Month()+time.Month(i), first.Day(), first.Hour(), first.Minute(), first.Second(), first.Nanosecond(), loc)
+			if t.Day() != first.Day() {
+				continue
+			}
+		default:
+			return occurrences
+		}
+
+		if series.Count > 0 && len(occurrences) == series.Count {
+			break
+		}
+		if !series.Until.IsZero() && t.After(series.Until) {
+			break
+		}
+		occurrences = append(occurrences, t)
+	}
+	return occurrences
+}
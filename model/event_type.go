@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// EventType is a kind of meeting a user offers, with its own duration and optionally its own weekly availability.
+type EventType struct {
+	ID           uint   `gorm:"primaryKey"`
+	UserID       uint   `gorm:"uniqueIndex:idx_event_types_user_id_slug"`
+	Name         string `gorm:"not null"`
+	Slug         string `gorm:"not null;uniqueIndex:idx_event_types_user_id_slug"`
+	DurationMins int    `gorm:"not null"`
+	// SlotIncrementMins, if set, replaces the user's slot increment for this event type
+	SlotIncrementMins int
+	// Capacity is how many invitees can book each slot. Slots of event types without one are booked by one invitee.
+	Capacity     int
+	Description  string
+	Location     string
+	Availability datatypes.JSONSlice[DayAvailability]
+	// SchedulingRules, if set, replace the user's scheduling rules for this event type
+	SchedulingRules *SchedulingRules `gorm:"serializer:json"`
+	CreatedAt       time.Time        `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time        `gorm:"autoUpdateTime"`
+}
+
+// ApplyTo returns the user's availability adjusted for this event type: the meeting duration is the event type's
+// and its custom slot increment, weekly availability and scheduling rules, if any, replace the user's.
+func (eventType EventType) ApplyTo(availability UserAvailability) UserAvailability {
+	availability.MeetingDurationMins = eventType.DurationMins
+	if eventType.SlotIncrementMins > 0 {
+		availability.SlotIncrementMins = eventType.SlotIncrementMins
+	}
+	if len(eventType.Availability) > 0 {
+		availability.Availability = eventType.Availability
+	}
+	if eventType.SchedulingRules != nil {
+		availability.SchedulingRules = *eventType.SchedulingRules
+	}
+	return availability
+}
+
+// Seats returns how many invitees can book each slot of this event type.
+func (eventType EventType) Seats() int {
+	if eventType.Capacity > 1 {
+		return eventType.Capacity
+	}
+	return 1
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+const (
+	// ProviderICS calendars are imported from ICS uploads or fetched from an ICS URL
+	ProviderICS = "ics"
+	// ProviderCalDAV calendars are read through free/busy queries to a CalDAV calendar collection
+	ProviderCalDAV = "caldav"
+)
+
+// ExternalCalendar is a calendar kept outside of the app, such as a work calendar, whose events block the user's
+// time. Which provider reads it depends on Provider.
+type ExternalCalendar struct {
+	ID       uint   `gorm:"primaryKey"`
+	UserID   uint   `gorm:"index"`
+	Name     string `gorm:"not null"`
+	Provider string `gorm:"not null;default:ics"`
+	URL      string
+	Username string
+	Password string
+	// WriteBack calendars get the user's bookings written to them
+	WriteBack bool
+	SyncedAt  time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// BusyBlock is a time during which the user is busy as per one of their external calendars. Occurrences of
+// recurring events are stored as separate blocks.
+type BusyBlock struct {
+	ID                 uint      `gorm:"primaryKey"`
+	UserID             uint      `gorm:"index:idx_busy_blocks_user_id_start_time"`
+	ExternalCalendarID uint      `gorm:"index"`
+	StartTime          time.Time `gorm:"index:idx_busy_blocks_user_id_start_time"`
+	EndTime            time.Time
+}
@@ -0,0 +1,62 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type jobStatus int
+
+const (
+	JobPending   jobStatus = 0
+	JobRunning   jobStatus = 1
+	JobSucceeded jobStatus = 2
+	// JobDead marks jobs which failed too often to be retried automatically
+	JobDead jobStatus = 3
+)
+
+func (s jobStatus) String() string {
+	switch s {
+	case JobPending:
+		return "pending"
+	case JobRunning:
+		return "running"
+	case JobSucceeded:
+		return "succeeded"
+	case JobDead:
+		return "dead"
+	}
+	return ""
+}
+
+// JobStatuses maps the names of job statuses to them
+var JobStatuses = map[string]jobStatus{
+	JobPending.String():   JobPending,
+	JobRunning.String():   JobRunning,
+	JobSucceeded.String(): JobSucceeded,
+	JobDead.String():      JobDead,
+}
+
+// Job is a unit of work run in the background by the worker pool. Jobs are enqueued in the same transaction as the
+// change they follow up on, so that they are run if and only if the change is saved.
+type Job struct {
+	ID      uint   `gorm:"primaryKey"`
+	Kind    string `gorm:"not null;index"`
+	Payload datatypes.JSON
+	Status  jobStatus `gorm:"index:idx_jobs_status_run_at"`
+	// Attempts counts the runs of the job, including the one in progress
+	Attempts    int
+	MaxAttempts int
+	// RunAt is when a pending job is run next
+	RunAt time.Time `gorm:"index:idx_jobs_status_run_at"`
+	// LockedUntil is when a running job is given up on, as its worker is presumed dead, and can be claimed again
+	LockedUntil time.Time
+	LastError   string
+	FinishedAt  time.Time
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+}
+
+// EventJobs returns the jobs to enqueue along with a change of an event, given the event as saved.
+type EventJobs func(Event) ([]Job, error)
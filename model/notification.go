@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+const (
+	NotificationConfirmation = "confirmation"
+	NotificationCancellation = "cancellation"
+	NotificationReschedule   = "reschedule"
+	NotificationReminder     = "reminder"
+)
+
+const (
+	RecipientHost    = "host"
+	RecipientInvitee = "invitee"
+)
+
+// Notification is an email about an event to its host or invitee. Pending notifications make up the outbox which is
+// worked through in the background once they are due, which is straight away for all but reminders.
+type Notification struct {
+	ID        uint   `gorm:"primaryKey"`
+	EventID   uint   `gorm:"index"`
+	Kind      string `gorm:"not null"`
+	Recipient string `gorm:"not null"`
+	// EventStartTime is the start time of the event when the notification was queued. Reminders of events which
+	// have been rescheduled since are skipped.
+	EventStartTime time.Time
+	SendAt         time.Time      `gorm:"index:idx_notifications_status_send_at"`
+	Status         deliveryStatus `gorm:"index:idx_notifications_status_send_at"`
+	Attempts       int
+	Error          string
+	SentAt         time.Time
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+
+	Event Event
+}
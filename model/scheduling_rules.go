@@ -0,0 +1,69 @@
+package model
+
+import "time"
+
+// SchedulingRules limit when and how often a user can be booked. A zero value leaves its limit out.
+type SchedulingRules struct {
+	// BeforeBufferMins and AfterBufferMins are kept free of other bookings and busy times around every booking
+	BeforeBufferMins int `json:"before_buffer_mins"`
+	AfterBufferMins  int `json:"after_buffer_mins"`
+	// MinNoticeMins is how long ahead of its start a booking has to be made
+	MinNoticeMins int `json:"min_notice_mins"`
+	// MaxHorizonDays is how many days ahead bookings can be made
+	MaxHorizonDays int `json:"max_horizon_days"`
+	// MaxPerDay and MaxPerWeek cap the bookings of the user starting on a day, or in a week starting on Monday
+	MaxPerDay  int `json:"max_per_day"`
+	MaxPerWeek int `json:"max_per_week"`
+}
+
+// BookingWindow returns the earliest and latest start of a booking made at now. latest is zero without a horizon.
+func (rules SchedulingRules) BookingWindow(now time.Time) (earliest, latest time.Time) {
+	earliest = now.Add(time.Duration(rules.MinNoticeMins) * time.Minute)
+	if rules.MaxHorizonDays > 0 {
+		latest = now.AddDate(0, 0, rules.MaxHorizonDays)
+	}
+	return earliest, latest
+}
+
+// Limits returns the limits a booking starting at start is checked against, with days and weeks in loc.
+func (rules SchedulingRules) Limits(start time.Time, loc *time.Location) BookingLimits {
+	limits := BookingLimits{
+		BeforeBuffer: time.Duration(rules.BeforeBufferMins) * time.Minute,
+		AfterBuffer:  time.Duration(rules.AfterBufferMins) * time.Minute,
+	}
+
+	t := start.In(loc)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	if rules.MaxPerDay > 0 {
+		limits.Caps = append(limits.Caps, BookingCap{Start: day, End: day.AddDate(0, 0, 1), Max: rules.MaxPerDay})
+	}
+	if rules.MaxPerWeek > 0 {
+		// Weekdays count from Sunday, weeks start on Monday
+		week := day.AddDate(0, 0, -(int(t.Weekday())+6)%7)
+		limits.Caps = append(limits.Caps, BookingCap{Start: week, End: week.AddDate(0, 0, 7), Max: rules.MaxPerWeek})
+	}
+	return limits
+}
+
+// BookingLimits are what a booking is checked against, along with the other confirmed events of its user, when it
+// is saved.
+type BookingLimits struct {
+	BeforeBuffer time.Duration
+	AfterBuffer  time.Duration
+	Caps         []BookingCap
+}
+
+// BookingCap is the maximum number of confirmed events of a user starting between Start and End.
+type BookingCap struct {
+	Start time.Time
+	End   time.Time
+	Max   int
+}
+
+// Booking is an event to be booked along with the slot it takes a seat of and the limits it is checked against, for
+// bookings saved along with others, such as the members of a team or the occurrences of a series.
+type Booking struct {
+	Event  Event
+	Slot   Slot
+	Limits BookingLimits
+}
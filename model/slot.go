@@ -28,14 +28,18 @@ func (s status) String() string {
 }
 
 type Slot struct {
-	ID        uint `gorm:"primaryKey"`
-	UserID    uint
-	StartTime time.Time
-	EndTime   time.Time
-	Status    status
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
-	DeletedAt time.Time
+	ID          uint `gorm:"primaryKey"`
+	UserID      uint
+	EventTypeID uint `gorm:"index"`
+	StartTime   time.Time
+	EndTime     time.Time
+	Status      status
+	// Capacity is how many invitees can book the slot, which stays open until BookedSeats reaches it
+	Capacity    int       `gorm:"not null;default:1"`
+	BookedSeats int       `gorm:"not null;default:0"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime"`
+	DeletedAt   time.Time
 
-	Event Event
+	Events []Event
 }
@@ -0,0 +1,63 @@
+package model
+
+import "time"
+
+const (
+	// SchedulingCollective event types are only offered when every member of the team is free, and book all of them
+	SchedulingCollective = "collective"
+	// SchedulingRoundRobin event types are offered when any member of the team is free, and book one of them
+	SchedulingRoundRobin = "round_robin"
+)
+
+// Team is a group of users who are booked together or in turns through the team's event types. It is managed by
+// its owner, who does not have to be a member.
+type Team struct {
+	ID        uint      `gorm:"primaryKey"`
+	OwnerID   uint      `gorm:"index"`
+	Name      string    `gorm:"not null"`
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+
+	Members []TeamMember
+}
+
+// TeamMember is a user of a team. Round robin bookings go to the free members of the highest priority first, and
+// among them to the one booked least recently.
+type TeamMember struct {
+	TeamID   uint `gorm:"primaryKey"`
+	UserID   uint `gorm:"primaryKey;index"`
+	Priority int
+	// Accepted is set once the user accepts to be booked through the team. Until then, the team neither reads their
+	// calendar nor books them.
+	Accepted bool `gorm:"not null;default:false"`
+	// LastBookedAt and Bookings track how the bookings of the team are shared among its members
+	LastBookedAt time.Time
+	Bookings     int
+	CreatedAt    time.Time `gorm:"autoCreateTime"`
+}
+
+// TeamEventType is a kind of meeting a team offers, booked with its members as per its scheduling type.
+type TeamEventType struct {
+	ID             uint   `gorm:"primaryKey"`
+	TeamID         uint   `gorm:"uniqueIndex:idx_team_event_types_team_id_slug"`
+	Name           string `gorm:"not null"`
+	Slug           string `gorm:"not null;uniqueIndex:idx_team_event_types_team_id_slug"`
+	SchedulingType string `gorm:"not null"`
+	DurationMins   int    `gorm:"not null"`
+	// SlotIncrementMins, if set, replaces the members' slot increments for this event type
+	SlotIncrementMins int
+	Description       string
+	Location          string
+	CreatedAt         time.Time `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime"`
+}
+
+// ApplyTo returns a member's availability adjusted for this event type: the meeting duration is the event type's and
+// its custom slot increment, if any, replaces the member's.
+func (eventType TeamEventType) ApplyTo(availability UserAvailability) UserAvailability {
+	availability.MeetingDurationMins = eventType.DurationMins
+	if eventType.SlotIncrementMins > 0 {
+		availability.SlotIncrementMins = eventType.SlotIncrementMins
+	}
+	return availability
+}
@@ -5,13 +5,25 @@ import (
 )
 
 type User struct {
-	ID        uint      `gorm:"primaryKey"`
-	Name      string    `gorm:"not null"`
-	Email     string    `gorm:"uniqueIndex"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID       uint   `gorm:"primaryKey"`
+	Name     string `gorm:"not null"`
+	Email    string `gorm:"uniqueIndex"`
+	TimeZone string `gorm:"not null;default:UTC"`
+	// Slug identifies the user's public booking page. Users created before slugs existed have none.
+	Slug string `gorm:"uniqueIndex:idx_users_slug,where:slug <> ''"`
+	// FeedTokenHash is the SHA-256 hash of the secret token in the user's calendar feed URL
+	FeedTokenHash string    `gorm:"index"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
 
 	Availability UserAvailability
 	Slot         Slot
 	Event        Event
+	// APIKeys are created along with the user
+	APIKeys []APIKey
+}
+
+// Location returns the user's time zone. An empty time zone is treated as UTC.
+func (user User) Location() (*time.Location, error) {
+	return time.LoadLocation(user.TimeZone)
 }
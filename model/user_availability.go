@@ -1,14 +1,15 @@
 package model
 
 import (
+	"sort"
 	"time"
 
 	"gorm.io/datatypes"
 )
 
 type Availability struct {
-	StartTime datatypes.Time
-	EndTime   datatypes.Time
+	StartTime datatypes.Time `json:"start_time"`
+	EndTime   datatypes.Time `json:"end_time"`
 }
 
 type DayAvailability struct {
@@ -21,17 +22,43 @@ type UserAvailability struct {
 	UserID              uint `gorm:"uniqueIndex"`
 	Availability        datatypes.JSONSlice[DayAvailability]
 	MeetingDurationMins int
-	CreatedAt           time.Time `gorm:"autoCreateTime"`
-	UpdatedAt           time.Time `gorm:"autoUpdateTime"`
+	// SlotIncrementMins is how often slots start, every MeetingDurationMins when not set
+	SlotIncrementMins int
+	TimeZone          string `gorm:"not null;default:UTC"`
+	SchedulingRules   `gorm:"embedded"`
+	CreatedAt         time.Time `gorm:"autoCreateTime"`
+	UpdatedAt         time.Time `gorm:"autoUpdateTime"`
+	// SlotsSyncedAt is when the scheduler last generated slots from this availability
+	SlotsSyncedAt time.Time
 }
 
-func (availability UserAvailability) GetAvailabilityMap() map[Day]Availability {
-	m := make(map[Day]Availability)
+// GetAvailabilityMap groups the availability windows by day. Windows of a day are sorted by their start time.
+func (availability UserAvailability) GetAvailabilityMap() map[Day][]Availability {
+	m := make(map[Day][]Availability)
 	for _, a := range availability.Availability {
-		m[a.Day] = Availability{
+		m[a.Day] = append(m[a.Day], Availability{
 			StartTime: a.StartTime,
 			EndTime:   a.EndTime,
-		}
+		})
+	}
+	for _, windows := range m {
+		sort.Slice(windows, func(i, j int) bool {
+			return windows[i].StartTime < windows[j].StartTime
+		})
 	}
 	return m
 }
+
+// SlotIncrement returns how often slots start within the availability windows.
+func (availability UserAvailability) SlotIncrement() time.Duration {
+	if availability.SlotIncrementMins > 0 {
+		return time.Duration(availability.SlotIncrementMins) * time.Minute
+	}
+	return time.Duration(availability.MeetingDurationMins) * time.Minute
+}
+
+// Location returns the time zone in which the weekly availability is expressed.
+// An empty time zone is treated as UTC.
+func (availability UserAvailability) Location() (*time.Location, error) {
+	return time.LoadLocation(availability.TimeZone)
+}
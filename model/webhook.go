@@ -0,0 +1,84 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	WebhookBookingCreated      = "booking.created"
+	WebhookBookingCancelled    = "booking.cancelled"
+	WebhookBookingRescheduled  = "booking.rescheduled"
+	WebhookAvailabilityUpdated = "availability.updated"
+)
+
+// WebhookEvents are the events webhooks can subscribe to
+var WebhookEvents = []string{WebhookBookingCreated, WebhookBookingCancelled, WebhookBookingRescheduled, WebhookAvailabilityUpdated}
+
+// Webhook is a URL of the user's own systems which gets the events it is subscribed to POSTed to it.
+type Webhook struct {
+	ID     uint   `gorm:"primaryKey"`
+	UserID uint   `gorm:"index"`
+	URL    string `gorm:"not null"`
+	// Secret signs the payloads delivered to the webhook
+	Secret    string `gorm:"not null"`
+	Events    datatypes.JSONSlice[string]
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+}
+
+// Subscribes tells whether the webhook is subscribed to the event.
+func (webhook Webhook) Subscribes(event string) bool {
+	for _, e := range webhook.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+type deliveryStatus int
+
+const (
+	DeliveryPending   deliveryStatus = 0
+	DeliverySucceeded deliveryStatus = 1
+	DeliveryFailed    deliveryStatus = 2
+	// DeliverySkipped marks notifications which no longer apply, such as reminders of cancelled events
+	DeliverySkipped deliveryStatus = 3
+)
+
+func (s deliveryStatus) String() string {
+	switch s {
+	case DeliveryPending:
+		return "pending"
+	case DeliverySucceeded:
+		return "succeeded"
+	case DeliveryFailed:
+		return "failed"
+	case DeliverySkipped:
+		return "skipped"
+	}
+	return ""
+}
+
+// WebhookDelivery is a payload to be POSTed to a webhook. Pending deliveries make up the outbox which is worked
+// through in the background, and are kept along with the outcome of their last attempt as the delivery log.
+type WebhookDelivery struct {
+	ID        uint   `gorm:"primaryKey"`
+	WebhookID uint   `gorm:"index"`
+	Event     string `gorm:"not null"`
+	Payload   datatypes.JSON
+	Status    deliveryStatus `gorm:"index:idx_webhook_deliveries_status_next_attempt_at"`
+	Attempts  int
+	// NextAttemptAt is when a pending delivery is attempted next
+	NextAttemptAt time.Time `gorm:"index:idx_webhook_deliveries_status_next_attempt_at"`
+	// ResponseStatus is the HTTP status returned by the last attempt, 0 if the webhook could not be reached
+	ResponseStatus int
+	Error          string
+	DeliveredAt    time.Time
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+
+	Webhook Webhook
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"gorm.io/gorm"
+)
+
+type APIKey struct {
+	db *gorm.DB
+}
+
+func (apiKey APIKey) Create(ctx context.Context, obj model.APIKey) (model.APIKey, error) {
+	err := apiKey.db.Create(&obj).Error
+	if err != nil {
+		log.Printf("error occurred while saving API key in DB: %s", err.Error())
+		return model.APIKey{}, err
+	}
+
+	return obj, nil
+}
+
+func (apiKey APIKey) GetAll(ctx context.Context, userID int) ([]model.APIKey, error) {
+	keys := make([]model.APIKey, 0)
+	err := apiKey.db.Order("id").Find(&keys, "user_id = $1", userID).Error
+	if err != nil {
+		log.Printf("error occurred while fetching API keys from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// GetByHash returns the API key with the given hash.
+func (apiKey APIKey) GetByHash(ctx context.Context, hash string) (model.APIKey, error) {
+	obj := model.APIKey{}
+	res := apiKey.db.Find(&obj, "key_hash = $1", hash)
+	if res.Error != nil {
+		log.Printf("error occurred while fetching API key from DB: %s", res.Error.Error())
+		return model.APIKey{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return model.APIKey{}, sql.ErrNoRows
+	}
+
+	return obj, nil
+}
+
+// SetLastUsedAt records when the API key last authenticated a request.
+func (apiKey APIKey) SetLastUsedAt(ctx context.Context, apiKeyID int, usedAt time.Time) error {
+	err := apiKey.db.Model(&model.APIKey{}).Where("id = ?", apiKeyID).Update("last_used_at", usedAt).Error
+	if err != nil {
+		log.Printf("error occurred while updating API key in DB: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// Delete revokes the API key, which stops authenticating requests straight away.
+func (apiKey APIKey) Delete(ctx context.Context, userID, apiKeyID int) error {
+	res := apiKey.db.Delete(&model.APIKey{}, "id = ? AND user_id = ?", apiKeyID, userID)
+	if res.Error != nil {
+		log.Printf("error occurred while deleting API key from DB: %s", res.Error.Error())
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func NewAPIKey(db *gorm.DB) APIKey {
+	return APIKey{db: db}
+}
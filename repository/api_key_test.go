@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type APIKeyTestSuite struct {
+	suite.Suite
+	repo APIKey
+	mock sqlmock.Sqlmock
+}
+
+func (suite *APIKeyTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		suite.NoError(err)
+	}
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       mockDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	suite.NoError(err)
+
+	suite.repo = APIKey{db: db}
+	suite.mock = mock
+}
+
+func (suite *APIKeyTestSuite) TestGetByHashReturnsKey() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "api_keys" WHERE key_hash = $1`)).
+		WithArgs("hash").WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "key_hash"}).AddRow(2, 1, "hash"))
+
+	resp, err := suite.repo.GetByHash(context.Background(), "hash")
+	suite.NoError(err)
+	suite.Equal(1, int(resp.UserID))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *APIKeyTestSuite) TestGetByHashReturnsErrNoRowsIfKeyDoesNotExist() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "api_keys" WHERE key_hash = $1`)).
+		WithArgs("hash").WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "key_hash"}))
+
+	_, err := suite.repo.GetByHash(context.Background(), "hash")
+	suite.Equal(sql.ErrNoRows, err)
+}
+
+func (suite *APIKeyTestSuite) TestSetLastUsedAtOnlyUpdatesLastUsedAt() {
+	usedAt := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "api_keys" SET "last_used_at"=$1 WHERE id = $2`)).
+		WithArgs(usedAt, 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	suite.NoError(suite.repo.SetLastUsedAt(context.Background(), 2, usedAt))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *APIKeyTestSuite) TestDeleteReturnsErrNoRowsIfKeyBelongsToAnotherUser() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "api_keys" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectCommit()
+
+	suite.Equal(sql.ErrNoRows, suite.repo.Delete(context.Background(), 1, 2))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestAPIKeyTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyTestSuite))
+}
@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"gorm.io/gorm"
+)
+
+type AvailabilityOverride struct {
+	db *gorm.DB
+}
+
+func (override AvailabilityOverride) Create(ctx context.Context, obj model.AvailabilityOverride) (model.AvailabilityOverride, error) {
+	err := override.db.Create(&obj).Error
+	if err != nil {
+		log.Printf("error occurred while saving availability override in DB: %s", err.Error())
+		return model.AvailabilityOverride{}, err
+	}
+
+	return obj, nil
+}
+
+func (override AvailabilityOverride) GetAll(ctx context.Context, userID int) ([]model.AvailabilityOverride, error) {
+	overrides := make([]model.AvailabilityOverride, 0)
+	err := override.db.Order("start_date").Find(&overrides, "user_id = $1", userID).Error
+	if err != nil {
+		log.Printf("error occurred while fetching availability overrides from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return overrides, nil
+}
+
+// GetInRange returns the overrides of a user which cover at least one date between from and to (both inclusive).
+func (override AvailabilityOverride) GetInRange(ctx context.Context, userID int, from, to time.Time) ([]model.AvailabilityOverride, error) {
+	overrides := make([]model.AvailabilityOverride, 0)
+	err := override.db.Order("start_date").Find(&overrides, "user_id = $1 AND start_date <= $2 AND end_date >= $3", userID, model.Date(to), model.Date(from)).Error
+	if err != nil {
+		log.Printf("error occurred while fetching availability overrides from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return overrides, nil
+}
+
+func (override AvailabilityOverride) GetByID(ctx context.Context, userID, overrideID int) (model.AvailabilityOverride, error) {
+	obj := model.AvailabilityOverride{}
+	res := override.db.Find(&obj, "id = $1 AND user_id = $2", overrideID, userID)
+	if res.Error != nil {
+		log.Printf("error occurred while fetching availability override from DB: %s", res.Error.Error())
+		return model.AvailabilityOverride{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		log.Printf("availability override %d not found for user: %d", overrideID, userID)
+		return model.AvailabilityOverride{}, sql.ErrNoRows
+	}
+
+	return obj, nil
+}
+
+func (override AvailabilityOverride) Update(ctx context.Context, obj model.AvailabilityOverride) (model.AvailabilityOverride, error) {
+	err := override.db.Model(&obj).Select("start_date", "end_date", "unavailable", "availability").Updates(obj).Error
+	if err != nil {
+		log.Printf("error occurred while updating availability override in DB: %s", err.Error())
+		return model.AvailabilityOverride{}, err
+	}
+
+	return obj, nil
+}
+
+func (override AvailabilityOverride) Delete(ctx context.Context, userID, overrideID int) error {
+	res := override.db.Delete(&model.AvailabilityOverride{}, "id = $1 AND user_id = $2", overrideID, userID)
+	if res.Error != nil {
+		log.Printf("error occurred while deleting availability override from DB: %s", res.Error.Error())
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func NewAvailabilityOverride(db *gorm.DB) AvailabilityOverride {
+	return AvailabilityOverride{db: db}
+}
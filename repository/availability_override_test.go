@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/datatypes"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type AvailabilityOverrideTestSuite struct {
+	suite.Suite
+	repo AvailabilityOverride
+	mock sqlmock.Sqlmock
+}
+
+func (suite *AvailabilityOverrideTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		suite.NoError(err)
+	}
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       mockDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	suite.NoError(err)
+
+	suite.repo = AvailabilityOverride{db: db}
+	suite.mock = mock
+}
+
+func (suite *AvailabilityOverrideTestSuite) TestCreateHappyFlow() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "availability_overrides" ("user_id","start_date","end_date","unavailable","availability","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WithArgs(1, sqlmock.AnyArg(), sqlmock.AnyArg(), true, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectCommit()
+
+	date := datatypes.Date(time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC))
+	resp, err := suite.repo.Create(context.Background(), model.AvailabilityOverride{UserID: 1, StartDate: date, EndDate: date, Unavailable: true})
+
+	suite.NoError(err)
+	suite.Equal(1, int(resp.ID))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *AvailabilityOverrideTestSuite) TestGetInRangeQueriesOverridesCoveringTheRange() {
+	from := time.Date(2023, 12, 20, 10, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 12, 27, 10, 0, 0, 0, time.UTC)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "availability_overrides" WHERE user_id = $1 AND start_date <= $2 AND end_date >= $3 ORDER BY start_date`)).
+		WithArgs(1, time.Date(2023, 12, 27, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 20, 0, 0, 0, 0, time.UTC)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "start_date", "end_date", "unavailable"}).
+			AddRow(1, 1, time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC), time.Date(2023, 12, 26, 0, 0, 0, 0, time.UTC), true))
+
+	resp, err := suite.repo.GetInRange(context.Background(), 1, from, to)
+	suite.NoError(err)
+	suite.Equal(1, len(resp))
+	suite.True(resp[0].Unavailable)
+}
+
+func (suite *AvailabilityOverrideTestSuite) TestGetByIDReturnsErrNoRowsIfOverrideDoesNotExist() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "availability_overrides" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(2, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	resp, err := suite.repo.GetByID(context.Background(), 1, 2)
+	suite.Equal(sql.ErrNoRows, err)
+	suite.Empty(resp)
+}
+
+func (suite *AvailabilityOverrideTestSuite) TestDeleteReturnsErrorWhenDBFails() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "availability_overrides" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(2, 1).WillReturnError(errors.New("some error"))
+	suite.mock.ExpectRollback()
+
+	err := suite.repo.Delete(context.Background(), 1, 2)
+	suite.Equal("some error", err.Error())
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestAvailabilityOverrideTestSuite(t *testing.T) {
+	suite.Run(t, new(AvailabilityOverrideTestSuite))
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"gorm.io/gorm"
+)
+
+type BusyBlock struct {
+	db *gorm.DB
+}
+
+// GetInRange returns the busy blocks of a user which overlap with the time between from and to.
+func (block BusyBlock) GetInRange(ctx context.Context, userID int, from, to time.Time) ([]model.BusyBlock, error) {
+	blocks := make([]model.BusyBlock, 0)
+	err := block.db.Order("start_time").Find(&blocks, "user_id = $1 AND start_time < $2 AND end_time > $3", userID, to, from).Error
+	if err != nil {
+		log.Printf("error occurred while fetching busy blocks from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return blocks, nil
+}
+
+func NewBusyBlock(db *gorm.DB) BusyBlock {
+	return BusyBlock{db: db}
+}
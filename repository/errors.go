@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolationCode is the SQLSTATE of a violation of a unique index
+const uniqueViolationCode = "23505"
+
+// userUniqueErrors maps the unique indexes of users to the errors reported when they are violated
+var userUniqueErrors = map[string]error{
+	"idx_users_email": model.ErrDuplicateEmail,
+	"idx_users_slug":  model.ErrDuplicateSlug,
+}
+
+// eventTypeUniqueErrors maps the unique indexes of event types to the errors reported when they are violated
+var eventTypeUniqueErrors = map[string]error{
+	"idx_event_types_user_id_slug": model.ErrDuplicateSlug,
+}
+
+// teamMemberUniqueErrors maps the unique indexes of team members to the errors reported when they are violated
+var teamMemberUniqueErrors = map[string]error{
+	"team_members_pkey": model.ErrDuplicateMember,
+}
+
+// teamEventTypeUniqueErrors maps the unique indexes of team event types to the errors reported when they are violated
+var teamEventTypeUniqueErrors = map[string]error{
+	"idx_team_event_types_team_id_slug": model.ErrDuplicateSlug,
+}
+
+// translateUniqueViolation returns the error of the unique index err violates, or err itself if it violates none
+// of them.
+func translateUniqueViolation(err error, uniqueErrors map[string]error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		if translated, ok := uniqueErrors[pgErr.ConstraintName]; ok {
+			return translated
+		}
+	}
+	return err
+}
@@ -2,7 +2,11 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"log"
+	"sort"
+	"time"
 
 	"github.com/harbor-xyz/coding-project/model"
 
@@ -34,6 +38,532 @@ func (event Event) GetAll(ctx context.Context, userID int) ([]model.Event, error
 	return events, nil
 }
 
+// GetInRange returns the confirmed events of a user which overlap with the time between from and to.
+func (event Event) GetInRange(ctx context.Context, userID int, from, to time.Time) ([]model.Event, error) {
+	events := make([]model.Event, 0)
+	err := event.db.Order("start_time").Find(&events, "user_id = $1 AND start_time < $2 AND end_time > $3 AND status = $4", userID, to, from, model.EventConfirmed).Error
+	if err != nil {
+		log.Printf("error occurred while fetching events from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (event Event) GetByID(ctx context.Context, userID, eventID int) (model.Event, error) {
+	obj := model.Event{}
+	res := event.db.Find(&obj, "id = $1 AND user_id = $2", eventID, userID)
+	if res.Error != nil {
+		log.Printf("error occurred while fetching event from DB: %s", res.Error.Error())
+		return model.Event{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		log.Printf("event %d not found for user: %d", eventID, userID)
+		return model.Event{}, sql.ErrNoRows
+	}
+
+	return obj, nil
+}
+
+// GetChanges returns the cancellations and reschedules of an event, oldest first.
+func (event Event) GetChanges(ctx context.Context, eventID int) ([]model.EventChange, error) {
+	changes := make([]model.EventChange, 0)
+	err := event.db.Order("created_at").Find(&changes, "event_id = $1", eventID).Error
+	if err != nil {
+		log.Printf("error occurred while fetching event changes from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// BookSlot saves the event, books a seat of its slot and enqueues the jobs following up on the booking in a single
+// transaction. A seat is only booked if the slot belongs to the event's user, is still open and has not started yet,
+// so concurrent bookings of the last seat cannot both succeed. sql.ErrNoRows is returned if the slot does not exist for
+// the user or was deleted. The event is checked against the limits along with the other events of the user.
+func (event Event) BookSlot(ctx context.Context, obj model.Event, limits model.BookingLimits, jobs model.EventJobs) (model.Event, error) {
+	err := event.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUserBookings(tx, obj.UserID); err != nil {
+			return err
+		}
+
+		slot, err := claimSlot(tx, obj.UserID, obj.SlotID)
+		if err != nil {
+			return err
+		}
+
+		obj.EventTypeID = slot.EventTypeID
+		obj.StartTime = slot.StartTime
+		obj.EndTime = slot.EndTime
+		if err := createEvent(tx, &obj, limits); err != nil {
+			return err
+		}
+		return enqueueEventJobs(tx, obj, jobs)
+	})
+	if err != nil {
+		log.Printf("error occurred while booking slot %d: %s", obj.SlotID, err.Error())
+		return model.Event{}, err
+	}
+
+	return obj, nil
+}
+
+// BookTime books a seat of the given slot and saves its event along with the jobs following up on the booking in a
+// single transaction, provided the user has no other event at that time and the event is within the limits. The slot
+// is saved first, unless it is of a group event type whose slot at that time is already booked by other invitees, in
+// which case the event joins them.
+func (event Event) BookTime(ctx context.Context, obj model.Event, slot model.Slot, limits model.BookingLimits, jobs model.EventJobs) (model.Event, error) {
+	err := event.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUserBookings(tx, obj.UserID); err != nil {
+			return err
+		}
+
+		booked, err := book(tx, model.Booking{Event: obj, Slot: slot, Limits: limits}, jobs)
+		obj = booked
+		return err
+	})
+	if err != nil {
+		log.Printf("error occurred while booking time %s: %s", slot.StartTime, err.Error())
+		return model.Event{}, err
+	}
+
+	return obj, nil
+}
+
+// BookTeam saves the bookings of the members of a team for one of its event types, along with the jobs following up on
+// each of them, in a single transaction. Each booking is saved the same way as BookTime does, so either every member
+// is booked or none of them is. The members booked are recorded as booked at the time of booking, so that round
+// robin event types can share the bookings among the members.
+func (event Event) BookTeam(ctx context.Context, teamID uint, bookings []model.Booking, jobs model.EventJobs) ([]model.Event, error) {
+	// Users are locked in the same order by every booking so that two team bookings cannot wait for each other
+	bookings = append([]model.Booking(nil), bookings...)
+	sort.Slice(bookings, func(i, j int) bool { return bookings[i].Event.UserID < bookings[j].Event.UserID })
+
+	events := make([]model.Event, 0, len(bookings))
+	err := event.db.Transaction(func(tx *gorm.DB) error {
+		events = events[:0]
+		hosts := make([]uint, 0, len(bookings))
+		for _, booking := range bookings {
+			if err := lockUserBookings(tx, booking.Event.UserID); err != nil {
+				return err
+			}
+
+			obj, err := book(tx, booking, jobs)
+			if err != nil {
+				return err
+			}
+			events = append(events, obj)
+			hosts = append(hosts, obj.UserID)
+		}
+
+		return tx.Model(&model.TeamMember{}).Where("team_id = ? AND user_id IN ?", teamID, hosts).
+			Updates(map[string]interface{}{"last_booked_at": time.Now(), "bookings": gorm.Expr("bookings + 1")}).Error
+	})
+	if err != nil {
+		log.Printf("error occurred while booking team %d: %s", teamID, err.Error())
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// BookSeries saves the series along with the bookings of its occurrences and the jobs following up on each of them in
+// a single transaction. Each occurrence is booked the same way as BookTime does, so either every occurrence is booked
+// or none of them is. Domain errors tell which occurrence failed.
+func (event Event) BookSeries(ctx context.Context, series model.EventSeries, bookings []model.Booking, jobs model.EventJobs) (model.EventSeries, error) {
+	err := event.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUserBookings(tx, series.UserID); err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Events").Create(&series).Error; err != nil {
+			return err
+		}
+
+		series.Events = make([]model.Event, 0, len(bookings))
+		for _, booking := range bookings {
+			booking.Event.SeriesID = series.ID
+			obj, err := book(tx, booking, jobs)
+			if err != nil {
+				return occurrenceError(err, booking.Slot.StartTime)
+			}
+			series.Events = append(series.Events, obj)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("error occurred while booking series of user %d: %s", series.UserID, err.Error())
+		return model.EventSeries{}, err
+	}
+
+	return series, nil
+}
+
+// GetSeries returns the series of the user along with its occurrences, earliest first. sql.ErrNoRows is returned if
+// the series does not exist for the user.
+func (event Event) GetSeries(ctx context.Context, userID, seriesID int) (model.EventSeries, error) {
+	obj := model.EventSeries{}
+	res := event.db.Preload("Events", func(db *gorm.DB) *gorm.DB { return db.Order("start_time") }).
+		Find(&obj, "id = $1 AND user_id = $2", seriesID, userID)
+	if res.Error != nil {
+		log.Printf("error occurred while fetching event series from DB: %s", res.Error.Error())
+		return model.EventSeries{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		log.Printf("event series %d not found for user: %d", seriesID, userID)
+		return model.EventSeries{}, sql.ErrNoRows
+	}
+
+	return obj, nil
+}
+
+// CancelSeries cancels the given occurrences of a series the same way as Cancel does, in a single transaction, so
+// that either every occurrence is cancelled or none of them is.
+func (event Event) CancelSeries(ctx context.Context, events []model.Event, change model.EventChange, jobs model.EventJobs) ([]model.Event, error) {
+	cancelled := make([]model.Event, 0, len(events))
+	err := event.db.Transaction(func(tx *gorm.DB) error {
+		cancelled = cancelled[:0]
+		// The occurrences of a series are all of the same user
+		if len(events) > 0 {
+			if err := lockUserBookings(tx, events[0].UserID); err != nil {
+				return err
+			}
+		}
+
+		for _, obj := range events {
+			obj, err := cancel(tx, obj, change, jobs)
+			if err != nil {
+				return occurrenceError(err, obj.StartTime)
+			}
+			cancelled = append(cancelled, obj)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("error occurred while cancelling event series: %s", err.Error())
+		return nil, err
+	}
+
+	return cancelled, nil
+}
+
+// RescheduleSeries moves each occurrence of a series to the slot of its booking the same way as Reschedule does, in
+// a single transaction, so that either every occurrence is moved or none of them is. The occurrences free their
+// slots before any of them is moved, so that an occurrence can move to the slot of another one, and those which are
+// still to be moved neither overlap the others nor count towards their caps.
+func (event Event) RescheduleSeries(ctx context.Context, bookings []model.Booking, change model.EventChange, jobs model.EventJobs) ([]model.Event, error) {
+	moved := make([]model.Event, 0, len(bookings))
+	err := event.db.Transaction(func(tx *gorm.DB) error {
+		moved = moved[:0]
+		if len(bookings) > 0 {
+			if err := lockUserBookings(tx, bookings[0].Event.UserID); err != nil {
+				return err
+			}
+		}
+
+		pending := make([]uint, 0, len(bookings))
+		for _, booking := range bookings {
+			if err := releaseSlot(tx, booking.Event.SlotID); err != nil {
+				return err
+			}
+			pending = append(pending, booking.Event.ID)
+		}
+		for i, booking := range bookings {
+			slot, err := takeSeat(tx, booking.Event.UserID, booking.Slot)
+			if err != nil {
+				return occurrenceError(err, booking.Slot.StartTime)
+			}
+			obj, err := moveEvent(tx, booking.Event, slot, booking.Limits, pending[i+1:], change, jobs)
+			if err != nil {
+				return occurrenceError(err, booking.Slot.StartTime)
+			}
+			moved = append(moved, obj)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("error occurred while rescheduling event series: %s", err.Error())
+		return nil, err
+	}
+
+	return moved, nil
+}
+
+// Cancel cancels a confirmed event, frees its seat of the slot for booking again, records the change and enqueues the jobs
+// following up on it in a single transaction. model.ErrEventCancelled is returned if the event was cancelled in the
+// meantime.
+func (event Event) Cancel(ctx context.Context, obj model.Event, change model.EventChange, jobs model.EventJobs) (model.Event, error) {
+	err := event.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUserBookings(tx, obj.UserID); err != nil {
+			return err
+		}
+
+		cancelled, err := cancel(tx, obj, change, jobs)
+		obj = cancelled
+		return err
+	})
+	if err != nil {
+		log.Printf("error occurred while cancelling event %d: %s", obj.ID, err.Error())
+		return model.Event{}, err
+	}
+
+	return obj, nil
+}
+
+// Reschedule moves a confirmed event to the given slot, records the change and enqueues the jobs following up on it
+// in a single transaction. A seat of the slot is booked the same way as BookSlot does for a slot with an ID, or as
+// BookTime does otherwise. The seat of the previous slot is freed for booking again. The event is checked against the
+// limits at its new time.
+func (event Event) Reschedule(ctx context.Context, obj model.Event, slot model.Slot, limits model.BookingLimits, change model.EventChange, jobs model.EventJobs) (model.Event, error) {
+	err := event.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockUserBookings(tx, obj.UserID); err != nil {
+			return err
+		}
+
+		moved, err := reschedule(tx, model.Booking{Event: obj, Slot: slot, Limits: limits}, change, jobs)
+		obj = moved
+		return err
+	})
+	if err != nil {
+		log.Printf("error occurred while rescheduling event %d: %s", obj.ID, err.Error())
+		return model.Event{}, err
+	}
+
+	return obj, nil
+}
+
+// book takes a seat of the booking's slot and saves its event along with the jobs following up on it, provided the
+// event is within its limits. The user's bookings are expected to be locked.
+func book(tx *gorm.DB, booking model.Booking, jobs model.EventJobs) (model.Event, error) {
+	obj := booking.Event
+	slot, err := takeSeat(tx, obj.UserID, booking.Slot)
+	if err != nil {
+		return obj, err
+	}
+
+	obj.SlotID = slot.ID
+	obj.EventTypeID = slot.EventTypeID
+	obj.StartTime = slot.StartTime
+	obj.EndTime = slot.EndTime
+	if err := createEvent(tx, &obj, booking.Limits); err != nil {
+		return obj, err
+	}
+	return obj, enqueueEventJobs(tx, obj, jobs)
+}
+
+// cancel cancels a confirmed event, frees its seat of the slot and records the change along with the jobs following
+// up on it. The user's bookings are expected to be locked.
+func cancel(tx *gorm.DB, obj model.Event, change model.EventChange, jobs model.EventJobs) (model.Event, error) {
+	res := tx.Model(&model.Event{}).Where("id = ? AND status = ?", obj.ID, model.EventConfirmed).
+		Updates(map[string]interface{}{"status": model.EventCancelled, "cancel_reason": change.Reason})
+	if res.Error != nil {
+		return obj, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return obj, model.ErrEventCancelled
+	}
+
+	if err := releaseSlot(tx, obj.SlotID); err != nil {
+		return obj, err
+	}
+
+	obj.Status = model.EventCancelled
+	obj.CancelReason = change.Reason
+	change.EventID = obj.ID
+	change.Action = model.ChangeCancelled
+	change.PreviousSlotID = obj.SlotID
+	change.PreviousStartTime = obj.StartTime
+	change.PreviousEndTime = obj.EndTime
+	if err := tx.Create(&change).Error; err != nil {
+		return obj, err
+	}
+	return obj, enqueueEventJobs(tx, obj, jobs)
+}
+
+// reschedule moves the booking's confirmed event to its slot, frees the seat of its previous slot and records the
+// change along with the jobs following up on it. The user's bookings are expected to be locked.
+func reschedule(tx *gorm.DB, booking model.Booking, change model.EventChange, jobs model.EventJobs) (model.Event, error) {
+	slot, err := takeSeat(tx, booking.Event.UserID, booking.Slot)
+	if err != nil {
+		return booking.Event, err
+	}
+
+	if err := releaseSlot(tx, booking.Event.SlotID); err != nil {
+		return booking.Event, err
+	}
+	return moveEvent(tx, booking.Event, slot, booking.Limits, nil, change, jobs)
+}
+
+// moveEvent moves the confirmed event to the slot whose seat it has taken, provided it is within the limits without
+// the events with the ignored IDs, and records the change along with the jobs following up on it.
+func moveEvent(tx *gorm.DB, previous model.Event, slot model.Slot, limits model.BookingLimits, ignored []uint, change model.EventChange, jobs model.EventJobs) (model.Event, error) {
+	obj := previous
+	obj.SlotID = slot.ID
+	obj.StartTime = slot.StartTime
+	obj.EndTime = slot.EndTime
+	obj.Revision = previous.Revision + 1
+	if err := checkLimits(tx, obj, limits, ignored); err != nil {
+		return obj, err
+	}
+
+	res := tx.Model(&model.Event{}).Where("id = ? AND status = ?", obj.ID, model.EventConfirmed).
+		Updates(map[string]interface{}{"slot_id": obj.SlotID, "start_time": obj.StartTime, "end_time": obj.EndTime,
+			"revision": gorm.Expr("revision + 1")})
+	if res.Error != nil {
+		return obj, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return obj, model.ErrEventCancelled
+	}
+
+	change.EventID = obj.ID
+	change.Action = model.ChangeRescheduled
+	change.PreviousSlotID = previous.SlotID
+	change.PreviousStartTime = previous.StartTime
+	change.PreviousEndTime = previous.EndTime
+	change.StartTime = obj.StartTime
+	change.EndTime = obj.EndTime
+	if err := tx.Create(&change).Error; err != nil {
+		return obj, err
+	}
+	return obj, enqueueEventJobs(tx, obj, jobs)
+}
+
+// occurrenceError tells which occurrence of a series a domain error is about.
+func occurrenceError(err error, startTime time.Time) error {
+	var domainErr *model.Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Withf("for the occurrence starting at %s", startTime.Format(time.RFC3339))
+	}
+	return err
+}
+
+// bookingLockNamespace keeps the per user booking locks apart from other advisory locks
+const bookingLockNamespace = 1
+
+// lockUserBookings serialises the bookings of a user until the end of the transaction, so that the checks for
+// overlapping events and caps cannot race with another booking.
+func lockUserBookings(tx *gorm.DB, userID uint) error {
+	return tx.Exec("SELECT pg_advisory_xact_lock(?, ?)", bookingLockNamespace, userID).Error
+}
+
+// claimSlot books a seat of the slot if it belongs to the user, is still open and has not started yet, and returns
+// it. The slot is booked once its last seat is.
+func claimSlot(tx *gorm.DB, userID, slotID uint) (model.Slot, error) {
+	res := tx.Model(&model.Slot{}).Where("id = ? AND user_id = ? AND status = ? AND start_time > ?", slotID, userID, model.StatusCreated, time.Now()).
+		Updates(map[string]interface{}{
+			"booked_seats": gorm.Expr("booked_seats + 1"),
+			"status":       gorm.Expr("CASE WHEN booked_seats + 1 >= capacity THEN ? ELSE ? END", model.StatusBooked, model.StatusCreated),
+		})
+	if res.Error != nil {
+		return model.Slot{}, res.Error
+	}
+
+	slot := model.Slot{}
+	find := tx.Find(&slot, "id = $1 AND user_id = $2", slotID, userID)
+	if find.Error != nil {
+		return model.Slot{}, find.Error
+	}
+	if find.RowsAffected == 0 || slot.Status == model.StatusDeleted {
+		return model.Slot{}, sql.ErrNoRows
+	}
+	if res.RowsAffected == 0 {
+		if slot.Status == model.StatusBooked {
+			return model.Slot{}, model.ErrSlotAlreadyBooked
+		}
+		return model.Slot{}, model.ErrSlotUnavailable
+	}
+
+	return slot, nil
+}
+
+// takeSeat books a seat of the user's slot, which is saved first unless it has an ID. A slot without an ID reuses the
+// open slot of the same event type and time of the user, if there is one, such as a slot freed by a cancellation or
+// one of a group event type which other invitees have booked, so that booking the same time again does not save
+// another slot. The user's bookings are expected to be locked, so no other booking can save that slot meanwhile.
+func takeSeat(tx *gorm.DB, userID uint, slot model.Slot) (model.Slot, error) {
+	if slot.ID == 0 {
+		open := model.Slot{}
+		res := tx.Order("id").Limit(1).Find(&open, "user_id = ? AND event_type_id = ? AND start_time = ? AND end_time = ? AND status = ?",
+			userID, slot.EventTypeID, slot.StartTime, slot.EndTime, model.StatusCreated)
+		if res.Error != nil {
+			return model.Slot{}, res.Error
+		}
+		slot.ID = open.ID
+	}
+	if slot.ID != 0 {
+		return claimSlot(tx, userID, slot.ID)
+	}
+
+	if slot.Capacity < 1 {
+		slot.Capacity = 1
+	}
+	slot.BookedSeats = 1
+	slot.Status = model.StatusCreated
+	if slot.BookedSeats >= slot.Capacity {
+		slot.Status = model.StatusBooked
+	}
+	if err := tx.Create(&slot).Error; err != nil {
+		return model.Slot{}, err
+	}
+	return slot, nil
+}
+
+// releaseSlot frees a seat of the slot, which opens it for booking again.
+func releaseSlot(tx *gorm.DB, slotID uint) error {
+	return tx.Model(&model.Slot{}).Where("id = ? AND status IN ?", slotID, []interface{}{model.StatusCreated, model.StatusBooked}).
+		Updates(map[string]interface{}{"booked_seats": gorm.Expr("GREATEST(booked_seats - 1, 0)"), "status": model.StatusCreated}).Error
+}
+
+// checkLimits returns model.ErrSlotUnavailable if another confirmed event of the user overlaps the event along with
+// its buffers, and model.ErrBookingLimit if the other confirmed events of the user already reach one of the caps.
+// Events booking the same slot as the event are the same meeting, so they neither overlap it nor count towards the
+// caps, and the events of any other slot only count once. The events with the ignored IDs, which are about to be
+// moved as well, do not count either.
+func checkLimits(tx *gorm.DB, obj model.Event, limits model.BookingLimits, ignored []uint) error {
+	others := "user_id = ? AND id <> ? AND slot_id <> ? AND status = ?"
+	args := []interface{}{obj.UserID, obj.ID, obj.SlotID, model.EventConfirmed}
+	if len(ignored) > 0 {
+		others += " AND id NOT IN ?"
+		args = append(args, ignored)
+	}
+
+	var overlapping int64
+	err := tx.Model(&model.Event{}).Where(others+" AND start_time < ? AND end_time > ?",
+		append(args[:len(args):len(args)], obj.EndTime.Add(limits.AfterBuffer), obj.StartTime.Add(-limits.BeforeBuffer))...).
+		Count(&overlapping).Error
+	if err != nil {
+		return err
+	}
+	if overlapping > 0 {
+		return model.ErrSlotUnavailable
+	}
+
+	for _, limit := range limits.Caps {
+		var booked int64
+		err := tx.Model(&model.Event{}).Distinct("slot_id").
+			Where(others+" AND start_time >= ? AND start_time < ?", append(args[:len(args):len(args)], limit.Start, limit.End)...).
+			Count(&booked).Error
+		if err != nil {
+			return err
+		}
+		if booked >= int64(limit.Max) {
+			return model.ErrBookingLimit
+		}
+	}
+	return nil
+}
+
+// createEvent saves the event unless it overlaps another event of the user or is not within the limits.
+func createEvent(tx *gorm.DB, obj *model.Event, limits model.BookingLimits) error {
+	if err := checkLimits(tx, *obj, limits, nil); err != nil {
+		return err
+	}
+	return tx.Create(obj).Error
+}
+
 func NewEvent(db *gorm.DB) Event {
 	return Event{db: db}
 }
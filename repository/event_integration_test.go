@@ -0,0 +1,98 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// EventIntegrationTestSuite books against the Postgres database in TEST_DATABASE_DSN, such as
+// "host=localhost port=5432 user=postgres password=postgres dbname=test sslmode=disable", and runs with
+// go test -tags integration ./repository
+type EventIntegrationTestSuite struct {
+	suite.Suite
+	db   *gorm.DB
+	repo Event
+	user model.User
+}
+
+func (suite *EventIntegrationTestSuite) SetupSuite() {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		suite.T().Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	suite.Require().NoError(err)
+	suite.Require().NoError(db.AutoMigrate(&model.User{}, &model.Slot{}, &model.Event{}, &model.EventChange{}, &model.Job{}))
+	suite.db = db
+	suite.repo = NewEvent(db)
+}
+
+func (suite *EventIntegrationTestSuite) SetupTest() {
+	suite.user = model.User{Name: "test", Email: time.Now().Format(time.RFC3339Nano) + "@example.xyz"}
+	suite.Require().NoError(suite.db.Omit("Availability", "Slot", "Event", "APIKeys").Create(&suite.user).Error)
+}
+
+func (suite *EventIntegrationTestSuite) TearDownTest() {
+	suite.db.Exec(`DELETE FROM events WHERE user_id = ?`, suite.user.ID)
+	suite.db.Exec(`DELETE FROM slots WHERE user_id = ?`, suite.user.ID)
+	suite.db.Exec(`DELETE FROM users WHERE id = ?`, suite.user.ID)
+}
+
+func (suite *EventIntegrationTestSuite) TestBookSlotOnlyBooksASlotOnceUnderConcurrentRequests() {
+	start := time.Now().Add(time.Hour).Truncate(time.Second)
+	slot := model.Slot{UserID: suite.user.ID, StartTime: start, EndTime: start.Add(30 * time.Minute), Status: model.StatusCreated, Capacity: 1}
+	suite.Require().NoError(suite.db.Omit("Events").Create(&slot).Error)
+
+	const requests = 20
+	var wg sync.WaitGroup
+	errs := make(chan error, requests)
+	ready := make(chan struct{})
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-ready
+			_, err := suite.repo.BookSlot(context.Background(), model.Event{UserID: suite.user.ID, SlotID: slot.ID,
+				InviteeName: "test", InviteeEmail: "test@example.xyz"}, model.BookingLimits{}, nil)
+			errs <- err
+		}()
+	}
+	close(ready)
+	wg.Wait()
+	close(errs)
+
+	booked, conflicts := 0, 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			booked++
+		case errors.Is(err, model.ErrSlotAlreadyBooked):
+			conflicts++
+		default:
+			suite.Fail("unexpected error", err.Error())
+		}
+	}
+	suite.Equal(1, booked)
+	suite.Equal(requests-1, conflicts)
+
+	var events int64
+	suite.NoError(suite.db.Model(&model.Event{}).Where("slot_id = ?", slot.ID).Count(&events).Error)
+	suite.Equal(int64(1), events)
+}
+
+func TestEventIntegrationTestSuite(t *testing.T) {
+	suite.Run(t, new(EventIntegrationTestSuite))
+}
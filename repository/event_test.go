@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -11,6 +12,7 @@ import (
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/datatypes"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -40,9 +42,9 @@ func (suite *EventTestSuite) SetupTest() {
 
 func (suite *EventTestSuite) TestCreateHappyFlow() {
 	suite.mock.ExpectBegin()
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "events" ("user_id","slot_id","invitee_email","invitee_name","invitee_notes","start_time","end_time","created_at","updated_at","deleted_at")
-	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`)).
-		WithArgs(1, 1, "test@example.xyz", "test", "test", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "events" ("user_id","slot_id","event_type_id","team_event_type_id","series_id","invitee_email","invitee_name","invitee_notes","start_time","end_time","status","cancel_reason","revision","created_at","updated_at","deleted_at")
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16) RETURNING "id"`)).
+		WithArgs(1, 1, 0, 0, 0, "test@example.xyz", "test", "test", sqlmock.AnyArg(), sqlmock.AnyArg(), model.EventConfirmed, "", 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id"}).AddRow(1))
 	suite.mock.ExpectCommit()
@@ -56,9 +58,9 @@ func (suite *EventTestSuite) TestCreateHappyFlow() {
 
 func (suite *EventTestSuite) TestCreateReturnsErrorWhenDBFails() {
 	suite.mock.ExpectBegin()
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "events" ("user_id","slot_id","invitee_email","invitee_name","invitee_notes","start_time","end_time","created_at","updated_at","deleted_at")
-	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10) RETURNING "id"`)).
-		WithArgs(1, 1, "test@example.xyz", "test", "test", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "events" ("user_id","slot_id","event_type_id","team_event_type_id","series_id","invitee_email","invitee_name","invitee_notes","start_time","end_time","status","cancel_reason","revision","created_at","updated_at","deleted_at")
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16) RETURNING "id"`)).
+		WithArgs(1, 1, 0, 0, 0, "test@example.xyz", "test", "test", sqlmock.AnyArg(), sqlmock.AnyArg(), model.EventConfirmed, "", 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnError(errors.New("some error"))
 	suite.mock.ExpectRollback()
 
@@ -92,6 +94,558 @@ func (suite *EventTestSuite) TestGetAllReturnsErrorIfDBReturnsError() {
 	suite.Nil(resp)
 }
 
+func (suite *EventTestSuite) TestGetInRangeReturnsOverlappingEvents() {
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 6, 6, 0, 0, 0, 0, time.UTC)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "events" WHERE user_id = $1 AND start_time < $2 AND end_time > $3 AND status = $4 ORDER BY start_time`)).
+		WithArgs(1, to, from, model.EventConfirmed).WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "user_id", "slot_id", "start_time", "end_time"},
+	).AddRow(1, 1, 1, from.Add(9*time.Hour), from.Add(10*time.Hour)))
+
+	resp, err := suite.repo.GetInRange(context.Background(), 1, from, to)
+	suite.NoError(err)
+	suite.Equal(1, len(resp))
+	suite.Equal(from.Add(9*time.Hour), resp[0].StartTime)
+}
+
+func (suite *EventTestSuite) TestBookSlotBooksOpenSlotAndSavesEvent() {
+	start := time.Now().Add(time.Hour)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "booked_seats"=booked_seats + 1,"status"=CASE WHEN booked_seats + 1 >= capacity THEN $1 ELSE $2 END,"updated_at"=$3 WHERE id = $4 AND user_id = $5 AND status = $6 AND start_time > $7`)).
+		WithArgs(model.StatusBooked, model.StatusCreated, sqlmock.AnyArg(), 1, 1, model.StatusCreated, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(1, 1).WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "user_id", "event_type_id", "start_time", "end_time", "status"},
+	).AddRow(1, 1, 2, start, start.Add(30*time.Minute), model.StatusBooked))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events" WHERE user_id = $1 AND id <> $2 AND slot_id <> $3 AND status = $4 AND start_time < $5 AND end_time > $6`)).
+		WithArgs(1, 0, 1, model.EventConfirmed, start.Add(30*time.Minute), start).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "events"`)).
+		WithArgs(1, 1, 2, 0, 0, "test@example.xyz", "test", "", start, start.Add(30*time.Minute), model.EventConfirmed, "", 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectCommit()
+
+	resp, err := suite.repo.BookSlot(context.Background(), model.Event{UserID: 1, SlotID: 1, InviteeEmail: "test@example.xyz", InviteeName: "test"}, model.BookingLimits{}, nil)
+
+	suite.NoError(err)
+	suite.Equal(1, int(resp.ID))
+	suite.Equal(2, int(resp.EventTypeID))
+	suite.Equal(start, resp.StartTime)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestBookSlotRollsBackIfJobsCannotBeBuilt() {
+	start := time.Now().Add(time.Hour)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "booked_seats"=booked_seats + 1`)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(1, 1).WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "user_id", "start_time", "end_time", "status"},
+	).AddRow(1, 1, start, start.Add(30*time.Minute), model.StatusBooked))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events"`)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "events"`)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectRollback()
+
+	resp, err := suite.repo.BookSlot(context.Background(), model.Event{UserID: 1, SlotID: 1}, model.BookingLimits{}, func(model.Event) ([]model.Job, error) {
+		return nil, errors.New("some error")
+	})
+
+	suite.EqualError(err, "some error")
+	suite.Empty(resp)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestBookSlotReturnsConflictIfSlotIsAlreadyBooked() {
+	start := time.Now().Add(time.Hour)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	// A concurrent booking has taken the last seat, so the conditional update matches no row
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "booked_seats"=booked_seats + 1,"status"=CASE WHEN booked_seats + 1 >= capacity THEN $1 ELSE $2 END,"updated_at"=$3 WHERE id = $4 AND user_id = $5 AND status = $6 AND start_time > $7`)).
+		WithArgs(model.StatusBooked, model.StatusCreated, sqlmock.AnyArg(), 1, 1, model.StatusCreated, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(1, 1).WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "user_id", "start_time", "end_time", "status"},
+	).AddRow(1, 1, start, start.Add(30*time.Minute), model.StatusBooked))
+	suite.mock.ExpectRollback()
+
+	resp, err := suite.repo.BookSlot(context.Background(), model.Event{UserID: 1, SlotID: 1, InviteeEmail: "test@example.xyz", InviteeName: "test"}, model.BookingLimits{}, nil)
+
+	suite.ErrorIs(err, model.ErrSlotAlreadyBooked)
+	suite.Empty(resp)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestBookSlotClaimsSlotUnderBookingLockOfUser() {
+	start := time.Now().Add(-time.Minute)
+	// Expectations are matched in order, so the lock is taken in the transaction of the booking before the slot is
+	// claimed, and a slot which the conditional update does not match is never booked
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "booked_seats"=booked_seats + 1,"status"=CASE WHEN booked_seats + 1 >= capacity THEN $1 ELSE $2 END,"updated_at"=$3 WHERE id = $4 AND user_id = $5 AND status = $6 AND start_time > $7`)).
+		WithArgs(model.StatusBooked, model.StatusCreated, sqlmock.AnyArg(), 1, 1, model.StatusCreated, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(1, 1).WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "user_id", "start_time", "end_time", "status"},
+	).AddRow(1, 1, start, start.Add(30*time.Minute), model.StatusCreated))
+	suite.mock.ExpectRollback()
+
+	resp, err := suite.repo.BookSlot(context.Background(), model.Event{UserID: 1, SlotID: 1, InviteeEmail: "test@example.xyz", InviteeName: "test"}, model.BookingLimits{}, nil)
+
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+	suite.Empty(resp)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestBookSlotReturnsNotFoundIfSlotDoesNotBelongToUser() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "booked_seats"=booked_seats + 1`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(1, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	suite.mock.ExpectRollback()
+
+	resp, err := suite.repo.BookSlot(context.Background(), model.Event{UserID: 1, SlotID: 1}, model.BookingLimits{}, nil)
+
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.Empty(resp)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestBookTimeReturnsConflictIfUserHasOverlappingEvent() {
+	start := time.Now().Add(time.Hour)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE user_id = $1`)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slots"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events"`)).
+		WithArgs(1, 0, 7, model.EventConfirmed, start.Add(time.Hour), start).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	suite.mock.ExpectRollback()
+
+	resp, err := suite.repo.BookTime(context.Background(), model.Event{UserID: 1}, model.Slot{UserID: 1, StartTime: start, EndTime: start.Add(time.Hour)}, model.BookingLimits{}, nil)
+
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+	suite.Empty(resp)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestBookTimeChecksBuffersAndCaps() {
+	start := time.Now().Add(time.Hour)
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE user_id = $1`)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slots"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events" WHERE user_id = $1 AND id <> $2 AND slot_id <> $3 AND status = $4 AND start_time < $5 AND end_time > $6`)).
+		WithArgs(1, 0, 7, model.EventConfirmed, start.Add(90*time.Minute), start.Add(-15*time.Minute)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(DISTINCT("slot_id")) FROM "events" WHERE user_id = $1 AND id <> $2 AND slot_id <> $3 AND status = $4 AND start_time >= $5 AND start_time < $6`)).
+		WithArgs(1, 0, 7, model.EventConfirmed, day, day.AddDate(0, 0, 1)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	suite.mock.ExpectRollback()
+
+	resp, err := suite.repo.BookTime(context.Background(), model.Event{UserID: 1}, model.Slot{UserID: 1, StartTime: start, EndTime: start.Add(time.Hour)},
+		model.BookingLimits{BeforeBuffer: 15 * time.Minute, AfterBuffer: 30 * time.Minute, Caps: []model.BookingCap{{Start: day, End: day.AddDate(0, 0, 1), Max: 2}}}, nil)
+
+	suite.ErrorIs(err, model.ErrBookingLimit)
+	suite.Empty(resp)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestBookTimeJoinsOpenSlotOfGroupEventType() {
+	start := time.Now().Add(time.Hour)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE user_id = $1 AND event_type_id = $2 AND start_time = $3 AND end_time = $4 AND status = $5 ORDER BY id LIMIT 1`)).
+		WithArgs(1, 2, start, start.Add(time.Hour), model.StatusCreated).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "event_type_id", "start_time", "end_time", "capacity", "booked_seats"}).
+			AddRow(7, 1, 2, start, start.Add(time.Hour), 3, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "booked_seats"=booked_seats + 1`)).
+		WithArgs(model.StatusBooked, model.StatusCreated, sqlmock.AnyArg(), 7, 1, model.StatusCreated, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(7, 1).WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "user_id", "event_type_id", "start_time", "end_time", "status", "capacity", "booked_seats"},
+	).AddRow(7, 1, 2, start, start.Add(time.Hour), model.StatusCreated, 3, 2))
+	// The other invitees of the slot do not overlap the event
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events" WHERE user_id = $1 AND id <> $2 AND slot_id <> $3`)).
+		WithArgs(1, 0, 7, model.EventConfirmed, start.Add(time.Hour), start).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "events"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	suite.mock.ExpectCommit()
+
+	resp, err := suite.repo.BookTime(context.Background(), model.Event{UserID: 1, InviteeEmail: "test@example.xyz", InviteeName: "test"},
+		model.Slot{UserID: 1, EventTypeID: 2, StartTime: start, EndTime: start.Add(time.Hour), Capacity: 3}, model.BookingLimits{}, nil)
+
+	suite.NoError(err)
+	suite.Equal(4, int(resp.ID))
+	suite.Equal(7, int(resp.SlotID))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestBookTimeReusesSlotFreedByCancellation() {
+	start := time.Now().Add(time.Hour)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE user_id = $1 AND event_type_id = $2 AND start_time = $3 AND end_time = $4 AND status = $5 ORDER BY id LIMIT 1`)).
+		WithArgs(1, 0, start, start.Add(time.Hour), model.StatusCreated).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "start_time", "end_time", "status", "capacity", "booked_seats"}).
+			AddRow(7, 1, start, start.Add(time.Hour), model.StatusCreated, 1, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "booked_seats"=booked_seats + 1`)).
+		WithArgs(model.StatusBooked, model.StatusCreated, sqlmock.AnyArg(), 7, 1, model.StatusCreated, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(7, 1).WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "user_id", "start_time", "end_time", "status", "capacity", "booked_seats"},
+	).AddRow(7, 1, start, start.Add(time.Hour), model.StatusBooked, 1, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events" WHERE user_id = $1 AND id <> $2 AND slot_id <> $3`)).
+		WithArgs(1, 0, 7, model.EventConfirmed, start.Add(time.Hour), start).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "events"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	suite.mock.ExpectCommit()
+
+	resp, err := suite.repo.BookTime(context.Background(), model.Event{UserID: 1, InviteeEmail: "test@example.xyz", InviteeName: "test"},
+		model.Slot{UserID: 1, StartTime: start, EndTime: start.Add(time.Hour), Capacity: 1}, model.BookingLimits{}, nil)
+
+	suite.NoError(err)
+	suite.Equal(7, int(resp.SlotID))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestBookTeamBooksEveryMemberAndRecordsTheirTurn() {
+	start := time.Now().Add(time.Hour)
+	suite.mock.ExpectBegin()
+	for i, userID := range []int{5, 6} {
+		suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+			WithArgs(bookingLockNamespace, userID).WillReturnResult(sqlmock.NewResult(0, 0))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE user_id = $1`)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slots"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7 + i))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events"`)).
+			WithArgs(userID, 0, 7+i, model.EventConfirmed, start.Add(30*time.Minute), start).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "events"`)).
+			WithArgs(userID, 7+i, 0, 3, 0, "test@example.xyz", "test", "", start, start.Add(30*time.Minute), model.EventConfirmed, "", 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1 + i))
+	}
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "team_members" SET "bookings"=bookings + 1,"last_booked_at"=$1 WHERE team_id = $2 AND user_id IN ($3,$4)`)).
+		WithArgs(sqlmock.AnyArg(), 2, 5, 6).WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectCommit()
+
+	// Members are booked in the order of their IDs whatever order they are given in
+	booking := func(userID uint) model.Booking {
+		return model.Booking{
+			Event: model.Event{UserID: userID, TeamEventTypeID: 3, InviteeEmail: "test@example.xyz", InviteeName: "test"},
+			Slot:  model.Slot{UserID: userID, StartTime: start, EndTime: start.Add(30 * time.Minute), Capacity: 1},
+		}
+	}
+	resp, err := suite.repo.BookTeam(context.Background(), 2, []model.Booking{booking(6), booking(5)}, nil)
+
+	suite.NoError(err)
+	suite.Len(resp, 2)
+	suite.Equal(5, int(resp[0].UserID))
+	suite.Equal(8, int(resp[1].SlotID))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestBookTeamBooksNoMemberIfOneIsTaken() {
+	start := time.Now().Add(time.Hour)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 5).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE user_id = $1`)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slots"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events"`)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "events"`)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 6).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE user_id = $1`)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slots"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(8))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events"`)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	suite.mock.ExpectRollback()
+
+	bookings := []model.Booking{
+		{Event: model.Event{UserID: 5}, Slot: model.Slot{UserID: 5, StartTime: start, EndTime: start.Add(30 * time.Minute)}},
+		{Event: model.Event{UserID: 6}, Slot: model.Slot{UserID: 6, StartTime: start, EndTime: start.Add(30 * time.Minute)}},
+	}
+	resp, err := suite.repo.BookTeam(context.Background(), 2, bookings, nil)
+
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+	suite.Nil(resp)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestBookSeriesLinksEveryOccurrenceToTheSeries() {
+	start := time.Now().Add(time.Hour)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "event_series"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	for i := 0; i < 2; i++ {
+		occurrence := start.AddDate(0, 0, 7*i)
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE user_id = $1`)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slots"`)).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7 + i))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events"`)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+		suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "events"`)).
+			WithArgs(1, 7+i, 2, 0, 4, "test@example.xyz", "test", "", occurrence, occurrence.Add(30*time.Minute), model.EventConfirmed, "", 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1 + i))
+	}
+	suite.mock.ExpectCommit()
+
+	bookings := make([]model.Booking, 0)
+	for i := 0; i < 2; i++ {
+		occurrence := start.AddDate(0, 0, 7*i)
+		bookings = append(bookings, model.Booking{
+			Event: model.Event{UserID: 1, InviteeEmail: "test@example.xyz", InviteeName: "test"},
+			Slot:  model.Slot{UserID: 1, EventTypeID: 2, StartTime: occurrence, EndTime: occurrence.Add(30 * time.Minute), Capacity: 1},
+		})
+	}
+	resp, err := suite.repo.BookSeries(context.Background(), model.EventSeries{UserID: 1, EventTypeID: 2, Frequency: model.FrequencyWeekly, Count: 2,
+		InviteeEmail: "test@example.xyz", InviteeName: "test"}, bookings, nil)
+
+	suite.NoError(err)
+	suite.Equal(4, int(resp.ID))
+	suite.Len(resp.Events, 2)
+	suite.Equal(4, int(resp.Events[1].SeriesID))
+	suite.Equal(8, int(resp.Events[1].SlotID))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestBookSeriesBooksNothingAndTellsWhichOccurrenceIsTaken() {
+	start := time.Date(2030, 6, 5, 10, 0, 0, 0, time.UTC)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "event_series"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE user_id = $1`)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slots"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events"`)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	suite.mock.ExpectRollback()
+
+	bookings := []model.Booking{
+		{Event: model.Event{UserID: 1}, Slot: model.Slot{UserID: 1, StartTime: start, EndTime: start.Add(30 * time.Minute)}},
+		{Event: model.Event{UserID: 1}, Slot: model.Slot{UserID: 1, StartTime: start.AddDate(0, 0, 7), EndTime: start.AddDate(0, 0, 7).Add(30 * time.Minute)}},
+	}
+	resp, err := suite.repo.BookSeries(context.Background(), model.EventSeries{UserID: 1, Frequency: model.FrequencyWeekly, Count: 2}, bookings, nil)
+
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+	suite.ErrorContains(err, "2030-06-05T10:00:00Z")
+	suite.Empty(resp)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestGetSeriesReturnsOccurrencesEarliestFirst() {
+	start := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "event_series" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(4, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "frequency"}).AddRow(4, 1, model.FrequencyWeekly))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "events" WHERE "events"."series_id" = $1 ORDER BY start_time`)).
+		WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "series_id", "start_time"}).
+		AddRow(1, 1, 4, start).AddRow(2, 1, 4, start.AddDate(0, 0, 7)))
+
+	resp, err := suite.repo.GetSeries(context.Background(), 1, 4)
+
+	suite.NoError(err)
+	suite.Equal(model.FrequencyWeekly, resp.Frequency)
+	suite.Len(resp.Events, 2)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestGetSeriesReturnsNotFoundIfSeriesDoesNotBelongToUser() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "event_series" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(4, 2).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	resp, err := suite.repo.GetSeries(context.Background(), 2, 4)
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.Empty(resp)
+}
+
+func (suite *EventTestSuite) TestGetByIDReturnsNotFoundIfEventDoesNotBelongToUser() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "events" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(1, 2).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	resp, err := suite.repo.GetByID(context.Background(), 2, 1)
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.Empty(resp)
+}
+
+func (suite *EventTestSuite) TestCancelCancelsEventFreesSlotAndRecordsChange() {
+	start := time.Now().Add(time.Hour)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "events" SET "cancel_reason"=$1,"status"=$2,"updated_at"=$3 WHERE id = $4 AND status = $5`)).
+		WithArgs("sick", model.EventCancelled, sqlmock.AnyArg(), 3, model.EventConfirmed).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "booked_seats"=GREATEST(booked_seats - 1, 0),"status"=$1,"updated_at"=$2 WHERE id = $3 AND status IN ($4,$5)`)).
+		WithArgs(model.StatusCreated, sqlmock.AnyArg(), 5, model.StatusCreated, model.StatusBooked).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "event_changes" ("event_id","action","changed_by","reason","previous_slot_id","previous_start_time","previous_end_time","start_time","end_time","created_at")`)).
+		WithArgs(3, model.ChangeCancelled, model.ChangedByHost, "sick", 5, start, start.Add(time.Hour), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectCommit()
+
+	resp, err := suite.repo.Cancel(context.Background(),
+		model.Event{ID: 3, UserID: 1, SlotID: 5, StartTime: start, EndTime: start.Add(time.Hour)},
+		model.EventChange{ChangedBy: model.ChangedByHost, Reason: "sick"}, nil)
+
+	suite.NoError(err)
+	suite.Equal(model.EventCancelled, resp.Status)
+	suite.Equal("sick", resp.CancelReason)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestCancelReturnsConflictIfEventIsAlreadyCancelled() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "events" SET`)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+
+	resp, err := suite.repo.Cancel(context.Background(), model.Event{ID: 3, UserID: 1, SlotID: 5}, model.EventChange{ChangedBy: model.ChangedByHost}, nil)
+
+	suite.ErrorIs(err, model.ErrEventCancelled)
+	suite.Empty(resp)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestRescheduleMovesEventToNewSlot() {
+	previous := time.Now().Add(time.Hour)
+	start := previous.Add(24 * time.Hour)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "booked_seats"=booked_seats + 1,"status"=CASE WHEN booked_seats + 1 >= capacity THEN $1 ELSE $2 END,"updated_at"=$3 WHERE id = $4 AND user_id = $5 AND status = $6 AND start_time > $7`)).
+		WithArgs(model.StatusBooked, model.StatusCreated, sqlmock.AnyArg(), 6, 1, model.StatusCreated, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(6, 1).WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "user_id", "start_time", "end_time", "status"},
+	).AddRow(6, 1, start, start.Add(time.Hour), model.StatusBooked))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "booked_seats"=GREATEST(booked_seats - 1, 0),"status"=$1,"updated_at"=$2 WHERE id = $3 AND status IN ($4,$5)`)).
+		WithArgs(model.StatusCreated, sqlmock.AnyArg(), 5, model.StatusCreated, model.StatusBooked).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events" WHERE user_id = $1 AND id <> $2 AND slot_id <> $3 AND status = $4 AND start_time < $5 AND end_time > $6`)).
+		WithArgs(1, 3, 6, model.EventConfirmed, start.Add(time.Hour), start).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "events" SET "end_time"=$1,"revision"=revision + 1,"slot_id"=$2,"start_time"=$3,"updated_at"=$4 WHERE id = $5 AND status = $6`)).
+		WithArgs(start.Add(time.Hour), 6, start, sqlmock.AnyArg(), 3, model.EventConfirmed).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "event_changes"`)).
+		WithArgs(3, model.ChangeRescheduled, model.ChangedByInvitee, "", 5, previous, previous.Add(time.Hour), start, start.Add(time.Hour), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "jobs" ("kind","payload","status","attempts","max_attempts","run_at","locked_until","last_error","finished_at","created_at","updated_at")`)).
+		WithArgs("test", `{"event_id":3}`, model.JobPending, 0, 10, start, sqlmock.AnyArg(), "", sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectCommit()
+
+	var rescheduled model.Event
+	resp, err := suite.repo.Reschedule(context.Background(),
+		model.Event{ID: 3, UserID: 1, SlotID: 5, StartTime: previous, EndTime: previous.Add(time.Hour)},
+		model.Slot{ID: 6}, model.BookingLimits{}, model.EventChange{ChangedBy: model.ChangedByInvitee}, func(obj model.Event) ([]model.Job, error) {
+			rescheduled = obj
+			return []model.Job{{Kind: "test", Payload: datatypes.JSON(`{"event_id":3}`), MaxAttempts: 10, RunAt: start}}, nil
+		})
+
+	suite.NoError(err)
+	suite.Equal(start, rescheduled.StartTime)
+	suite.Equal(6, int(resp.SlotID))
+	suite.Equal(start, resp.StartTime)
+	suite.Equal(uint(1), resp.Revision)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestRescheduleSeriesMovesOccurrenceToSlotOfNextOne() {
+	start := time.Now().Add(time.Hour)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_xact_lock($1, $2)`)).
+		WithArgs(bookingLockNamespace, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	// Both occurrences free their slots before either of them moves
+	for _, slotID := range []int{7, 8} {
+		suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "booked_seats"=GREATEST(booked_seats - 1, 0)`)).
+			WithArgs(model.StatusCreated, sqlmock.AnyArg(), slotID, model.StatusCreated, model.StatusBooked).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	// The first occurrence moves a week later, to the slot the second one has freed, which is not moved yet
+	next := start.AddDate(0, 0, 7)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE user_id = $1`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "start_time", "end_time", "status", "capacity"}).
+			AddRow(8, 1, next, next.Add(30*time.Minute), model.StatusCreated, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "booked_seats"=booked_seats + 1`)).
+		WithArgs(model.StatusBooked, model.StatusCreated, sqlmock.AnyArg(), 8, 1, model.StatusCreated, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(8, 1).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "start_time", "end_time", "status"}).
+		AddRow(8, 1, next, next.Add(30*time.Minute), model.StatusBooked))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events" WHERE user_id = $1 AND id <> $2 AND slot_id <> $3 AND status = $4 AND id NOT IN ($5) AND start_time < $6 AND end_time > $7`)).
+		WithArgs(1, 1, 8, model.EventConfirmed, 2, next.Add(30*time.Minute), next).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(DISTINCT("slot_id")) FROM "events" WHERE user_id = $1 AND id <> $2 AND slot_id <> $3 AND status = $4 AND id NOT IN ($5) AND start_time >= $6 AND start_time < $7`)).
+		WithArgs(1, 1, 8, model.EventConfirmed, 2, next, next.AddDate(0, 0, 1)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "events"`)).WithArgs(next.Add(30*time.Minute), 8, next, sqlmock.AnyArg(), 1, model.EventConfirmed).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "event_changes"`)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	// The second occurrence moves to a new slot, counting the first one where it has moved to
+	last := start.AddDate(0, 0, 14)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE user_id = $1`)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slots"`)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "events" WHERE user_id = $1 AND id <> $2 AND slot_id <> $3 AND status = $4 AND start_time < $5 AND end_time > $6`)).
+		WithArgs(1, 2, 9, model.EventConfirmed, last.Add(30*time.Minute), last).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT COUNT(DISTINCT("slot_id")) FROM "events" WHERE user_id = $1 AND id <> $2 AND slot_id <> $3 AND status = $4 AND start_time >= $5 AND start_time < $6`)).
+		WithArgs(1, 2, 9, model.EventConfirmed, last, last.AddDate(0, 0, 1)).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "events"`)).WithArgs(last.Add(30*time.Minute), 9, last, sqlmock.AnyArg(), 2, model.EventConfirmed).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "event_changes"`)).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	suite.mock.ExpectCommit()
+
+	bookings := make([]model.Booking, 0)
+	for i := 0; i < 2; i++ {
+		occurrence := start.AddDate(0, 0, 7*i)
+		moved := occurrence.AddDate(0, 0, 7)
+		bookings = append(bookings, model.Booking{
+			Event:  model.Event{ID: uint(i + 1), UserID: 1, SlotID: uint(i + 7), StartTime: occurrence, EndTime: occurrence.Add(30 * time.Minute)},
+			Slot:   model.Slot{UserID: 1, StartTime: moved, EndTime: moved.Add(30 * time.Minute), Capacity: 1},
+			Limits: model.BookingLimits{Caps: []model.BookingCap{{Max: 1, Start: moved, End: moved.AddDate(0, 0, 1)}}},
+		})
+	}
+	resp, err := suite.repo.RescheduleSeries(context.Background(), bookings, model.EventChange{ChangedBy: model.ChangedByHost}, nil)
+
+	suite.NoError(err)
+	suite.Equal([]uint{8, 9}, []uint{resp[0].SlotID, resp[1].SlotID})
+	suite.Equal(last, resp[1].StartTime)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTestSuite) TestGetChangesReturnsChangesOfEvent() {
+	now := time.Now()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "event_changes" WHERE event_id = $1 ORDER BY created_at`)).
+		WithArgs(3).WillReturnRows(sqlmock.NewRows(
+		[]string{"id", "event_id", "action", "changed_by", "previous_slot_id", "previous_start_time", "previous_end_time"},
+	).AddRow(1, 3, model.ChangeCancelled, model.ChangedByHost, 5, now, now.Add(time.Hour)))
+
+	resp, err := suite.repo.GetChanges(context.Background(), 3)
+	suite.NoError(err)
+	suite.Equal(1, len(resp))
+	suite.Equal(model.ChangeCancelled, resp[0].Action)
+}
+
 func TestEventTestSuite(t *testing.T) {
 	suite.Run(t, new(EventTestSuite))
 }
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"gorm.io/gorm"
+)
+
+type EventType struct {
+	db *gorm.DB
+}
+
+func (eventType EventType) Create(ctx context.Context, obj model.EventType) (model.EventType, error) {
+	err := eventType.db.Create(&obj).Error
+	if err != nil {
+		log.Printf("error occurred while saving event type in DB: %s", err.Error())
+		return model.EventType{}, translateUniqueViolation(err, eventTypeUniqueErrors)
+	}
+
+	return obj, nil
+}
+
+func (eventType EventType) GetAll(ctx context.Context, userID int) ([]model.EventType, error) {
+	eventTypes := make([]model.EventType, 0)
+	err := eventType.db.Order("id").Find(&eventTypes, "user_id = $1", userID).Error
+	if err != nil {
+		log.Printf("error occurred while fetching event types from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return eventTypes, nil
+}
+
+func (eventType EventType) GetByID(ctx context.Context, userID, eventTypeID int) (model.EventType, error) {
+	return eventType.get(ctx, "id = $1 AND user_id = $2", eventTypeID, userID)
+}
+
+func (eventType EventType) GetBySlug(ctx context.Context, userID int, slug string) (model.EventType, error) {
+	return eventType.get(ctx, "slug = $1 AND user_id = $2", slug, userID)
+}
+
+func (eventType EventType) get(ctx context.Context, query string, args ...interface{}) (model.EventType, error) {
+	obj := model.EventType{}
+	res := eventType.db.Find(&obj, append([]interface{}{query}, args...)...)
+	if res.Error != nil {
+		log.Printf("error occurred while fetching event type from DB: %s", res.Error.Error())
+		return model.EventType{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return model.EventType{}, sql.ErrNoRows
+	}
+
+	return obj, nil
+}
+
+func (eventType EventType) Update(ctx context.Context, obj model.EventType) (model.EventType, error) {
+	err := eventType.db.Model(&obj).Select("name", "slug", "duration_mins", "description", "location", "availability").Updates(obj).Error
+	if err != nil {
+		log.Printf("error occurred while updating event type in DB: %s", err.Error())
+		return model.EventType{}, translateUniqueViolation(err, eventTypeUniqueErrors)
+	}
+
+	return obj, nil
+}
+
+func (eventType EventType) Delete(ctx context.Context, userID, eventTypeID int) error {
+	res := eventType.db.Delete(&model.EventType{}, "id = $1 AND user_id = $2", eventTypeID, userID)
+	if res.Error != nil {
+		log.Printf("error occurred while deleting event type from DB: %s", res.Error.Error())
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func NewEventType(db *gorm.DB) EventType {
+	return EventType{db: db}
+}
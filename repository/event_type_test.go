@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type EventTypeTestSuite struct {
+	suite.Suite
+	repo EventType
+	mock sqlmock.Sqlmock
+}
+
+func (suite *EventTypeTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		suite.NoError(err)
+	}
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       mockDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	suite.NoError(err)
+
+	suite.repo = EventType{db: db}
+	suite.mock = mock
+}
+
+func (suite *EventTypeTestSuite) TestCreateHappyFlow() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "event_types" ("user_id","name","slug","duration_mins","slot_increment_mins","capacity","description","location","availability","scheduling_rules","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12) RETURNING "id"`)).
+		WithArgs(1, "Intro call", "intro-call", 15, 0, 0, "", "", sqlmock.AnyArg(), nil, sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectCommit()
+
+	resp, err := suite.repo.Create(context.Background(), model.EventType{UserID: 1, Name: "Intro call", Slug: "intro-call", DurationMins: 15})
+
+	suite.NoError(err)
+	suite.Equal(1, int(resp.ID))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *EventTypeTestSuite) TestCreateReturnsErrDuplicateSlugIfSlugIsTaken() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "event_types"`)).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_event_types_user_id_slug"})
+	suite.mock.ExpectRollback()
+
+	_, err := suite.repo.Create(context.Background(), model.EventType{UserID: 1, Name: "Intro call", Slug: "intro-call", DurationMins: 15})
+	suite.Equal(model.ErrDuplicateSlug, err)
+}
+
+func (suite *EventTypeTestSuite) TestGetBySlugReturnsDataIfExists() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "event_types" WHERE slug = $1 AND user_id = $2`)).
+		WithArgs("intro-call", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "slug", "duration_mins"}).AddRow(3, 1, "Intro call", "intro-call", 15))
+
+	resp, err := suite.repo.GetBySlug(context.Background(), 1, "intro-call")
+	suite.NoError(err)
+	suite.Equal(3, int(resp.ID))
+	suite.Equal(15, resp.DurationMins)
+}
+
+func (suite *EventTypeTestSuite) TestGetByIDReturnsErrNoRowsIfEventTypeDoesNotExist() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "event_types" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(2, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	resp, err := suite.repo.GetByID(context.Background(), 1, 2)
+	suite.Equal(sql.ErrNoRows, err)
+	suite.Empty(resp)
+}
+
+func (suite *EventTypeTestSuite) TestDeleteReturnsErrNoRowsIfNothingIsDeleted() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "event_types" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.Delete(context.Background(), 1, 2)
+	suite.Equal(sql.ErrNoRows, err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestEventTypeTestSuite(t *testing.T) {
+	suite.Run(t, new(EventTypeTestSuite))
+}
@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"gorm.io/gorm"
+)
+
+// busyBlockBatchSize keeps the inserts of large calendars below the limit of bind parameters of Postgres
+const busyBlockBatchSize = 500
+
+type ExternalCalendar struct {
+	db *gorm.DB
+}
+
+func (calendar ExternalCalendar) Create(ctx context.Context, obj model.ExternalCalendar) (model.ExternalCalendar, error) {
+	err := calendar.db.Create(&obj).Error
+	if err != nil {
+		log.Printf("error occurred while saving external calendar in DB: %s", err.Error())
+		return model.ExternalCalendar{}, err
+	}
+
+	return obj, nil
+}
+
+func (calendar ExternalCalendar) GetAll(ctx context.Context, userID int) ([]model.ExternalCalendar, error) {
+	calendars := make([]model.ExternalCalendar, 0)
+	err := calendar.db.Order("id").Find(&calendars, "user_id = $1", userID).Error
+	if err != nil {
+		log.Printf("error occurred while fetching external calendars from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return calendars, nil
+}
+
+// GetAllWithURL returns the external calendars of every user which are fetched from a URL.
+func (calendar ExternalCalendar) GetAllWithURL(ctx context.Context) ([]model.ExternalCalendar, error) {
+	calendars := make([]model.ExternalCalendar, 0)
+	err := calendar.db.Order("id").Find(&calendars, "url <> ''").Error
+	if err != nil {
+		log.Printf("error occurred while fetching external calendars from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return calendars, nil
+}
+
+func (calendar ExternalCalendar) GetByID(ctx context.Context, userID, calendarID int) (model.ExternalCalendar, error) {
+	obj := model.ExternalCalendar{}
+	res := calendar.db.Find(&obj, "id = $1 AND user_id = $2", calendarID, userID)
+	if res.Error != nil {
+		log.Printf("error occurred while fetching external calendar from DB: %s", res.Error.Error())
+		return model.ExternalCalendar{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		log.Printf("external calendar %d not found for user: %d", calendarID, userID)
+		return model.ExternalCalendar{}, sql.ErrNoRows
+	}
+
+	return obj, nil
+}
+
+// Delete removes the external calendar along with its busy blocks.
+func (calendar ExternalCalendar) Delete(ctx context.Context, userID, calendarID int) error {
+	return calendar.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&model.ExternalCalendar{}, "id = ? AND user_id = ?", calendarID, userID)
+		if res.Error != nil {
+			log.Printf("error occurred while deleting external calendar from DB: %s", res.Error.Error())
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return sql.ErrNoRows
+		}
+
+		if err := tx.Delete(&model.BusyBlock{}, "external_calendar_id = ?", calendarID).Error; err != nil {
+			log.Printf("error occurred while deleting busy blocks from DB: %s", err.Error())
+			return err
+		}
+		return nil
+	})
+}
+
+// ReplaceBusyBlocks swaps the busy blocks of the external calendar for the given ones and marks it as synced at
+// syncedAt, so that a failed import leaves the previous blocks in place.
+func (calendar ExternalCalendar) ReplaceBusyBlocks(ctx context.Context, obj model.ExternalCalendar, blocks []model.BusyBlock, syncedAt time.Time) (model.ExternalCalendar, error) {
+	err := calendar.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&model.BusyBlock{}, "external_calendar_id = ?", obj.ID).Error; err != nil {
+			log.Printf("error occurred while deleting busy blocks from DB: %s", err.Error())
+			return err
+		}
+
+		if len(blocks) > 0 {
+			if err := tx.CreateInBatches(&blocks, busyBlockBatchSize).Error; err != nil {
+				log.Printf("error occurred while saving busy blocks in DB: %s", err.Error())
+				return err
+			}
+		}
+
+		res := tx.Model(&obj).Update("synced_at", syncedAt)
+		if res.Error != nil {
+			log.Printf("error occurred while updating external calendar in DB: %s", res.Error.Error())
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			// The calendar was deleted in the meantime
+			return sql.ErrNoRows
+		}
+		return nil
+	})
+	if err != nil {
+		return model.ExternalCalendar{}, err
+	}
+
+	obj.SyncedAt = syncedAt
+	return obj, nil
+}
+
+func NewExternalCalendar(db *gorm.DB) ExternalCalendar {
+	return ExternalCalendar{db: db}
+}
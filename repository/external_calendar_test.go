@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type ExternalCalendarTestSuite struct {
+	suite.Suite
+	repo      ExternalCalendar
+	blockRepo BusyBlock
+	mock      sqlmock.Sqlmock
+}
+
+func (suite *ExternalCalendarTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		suite.NoError(err)
+	}
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       mockDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	suite.NoError(err)
+
+	suite.repo = ExternalCalendar{db: db}
+	suite.blockRepo = BusyBlock{db: db}
+	suite.mock = mock
+}
+
+func (suite *ExternalCalendarTestSuite) TestGetByIDReturnsErrNoRowsIfCalendarDoesNotBelongToUser() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "external_calendars" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(2, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	resp, err := suite.repo.GetByID(context.Background(), 1, 2)
+	suite.Equal(sql.ErrNoRows, err)
+	suite.Empty(resp)
+}
+
+func (suite *ExternalCalendarTestSuite) TestDeleteRemovesBusyBlocks() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "external_calendars" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "busy_blocks" WHERE external_calendar_id = $1`)).
+		WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 3))
+	suite.mock.ExpectCommit()
+
+	suite.NoError(suite.repo.Delete(context.Background(), 1, 2))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *ExternalCalendarTestSuite) TestDeleteReturnsErrNoRowsIfCalendarDoesNotExist() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "external_calendars" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+
+	suite.Equal(sql.ErrNoRows, suite.repo.Delete(context.Background(), 1, 2))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *ExternalCalendarTestSuite) TestReplaceBusyBlocksSwapsBlocksAndMarksCalendarSynced() {
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	syncedAt := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "busy_blocks" WHERE external_calendar_id = $1`)).
+		WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "busy_blocks" ("user_id","external_calendar_id","start_time","end_time") VALUES ($1,$2,$3,$4) RETURNING "id"`)).
+		WithArgs(1, 2, start, start.Add(time.Hour)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "external_calendars" SET "synced_at"=$1,"updated_at"=$2 WHERE "id" = $3`)).
+		WithArgs(syncedAt, sqlmock.AnyArg(), 2).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	resp, err := suite.repo.ReplaceBusyBlocks(context.Background(), model.ExternalCalendar{ID: 2, UserID: 1},
+		[]model.BusyBlock{{UserID: 1, ExternalCalendarID: 2, StartTime: start, EndTime: start.Add(time.Hour)}}, syncedAt)
+	suite.NoError(err)
+	suite.Equal(syncedAt, resp.SyncedAt)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *ExternalCalendarTestSuite) TestGetInRangeReturnsOverlappingBusyBlocks() {
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 1)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "busy_blocks" WHERE user_id = $1 AND start_time < $2 AND end_time > $3 ORDER BY start_time`)).
+		WithArgs(1, to, from).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "start_time", "end_time"}).
+			AddRow(1, 1, from.Add(9*time.Hour), from.Add(10*time.Hour)))
+
+	resp, err := suite.blockRepo.GetInRange(context.Background(), 1, from, to)
+	suite.NoError(err)
+	suite.Len(resp, 1)
+}
+
+func TestExternalCalendarTestSuite(t *testing.T) {
+	suite.Run(t, new(ExternalCalendarTestSuite))
+}
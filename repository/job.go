@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Job struct {
+	db *gorm.DB
+}
+
+// Claim marks the next due job as running until lockedUntil and returns it, or sql.ErrNoRows if no job is due. Jobs
+// locked by other workers are skipped rather than waited for. Running jobs whose lock has expired are due again, as
+// their worker is presumed dead.
+func (job Job) Claim(ctx context.Context, now, lockedUntil time.Time) (model.Job, error) {
+	obj := model.Job{}
+	err := job.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).Order("run_at").Limit(1).
+			Find(&obj, "(status = ? AND run_at <= ?) OR (status = ? AND locked_until <= ?)", model.JobPending, now, model.JobRunning, now)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return sql.ErrNoRows
+		}
+
+		obj.Status = model.JobRunning
+		obj.Attempts++
+		obj.LockedUntil = lockedUntil
+		return tx.Model(&model.Job{ID: obj.ID}).Select("status", "attempts", "locked_until").Updates(obj).Error
+	})
+	if err == sql.ErrNoRows {
+		return model.Job{}, err
+	}
+	if err != nil {
+		log.Printf("error occurred while claiming job from DB: %s", err.Error())
+		return model.Job{}, err
+	}
+
+	return obj, nil
+}
+
+// Finish records the outcome of the run of a claimed job. sql.ErrNoRows is returned if the job has been claimed again
+// in the meantime, after its lock expired.
+func (job Job) Finish(ctx context.Context, obj model.Job) error {
+	res := job.db.Model(&model.Job{}).Where("id = ? AND status = ? AND attempts = ?", obj.ID, model.JobRunning, obj.Attempts).
+		Select("status", "run_at", "locked_until", "last_error", "finished_at").Updates(obj)
+	if res.Error != nil {
+		log.Printf("error occurred while finishing job in DB: %s", res.Error.Error())
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		log.Printf("job %d was claimed again before attempt %d finished", obj.ID, obj.Attempts)
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// GetAll returns the latest jobs, newest first. The jobs can be narrowed down to a status and a kind.
+func (job Job) GetAll(ctx context.Context, status *int, kind string, limit int) ([]model.Job, error) {
+	jobs := make([]model.Job, 0)
+	query := job.db.Order("id DESC").Limit(limit)
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	if err := query.Find(&jobs).Error; err != nil {
+		log.Printf("error occurred while fetching jobs from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+func (job Job) GetByID(ctx context.Context, jobID int) (model.Job, error) {
+	obj := model.Job{}
+	res := job.db.Find(&obj, "id = $1", jobID)
+	if res.Error != nil {
+		log.Printf("error occurred while fetching job from DB: %s", res.Error.Error())
+		return model.Job{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		log.Printf("job %d not found", jobID)
+		return model.Job{}, sql.ErrNoRows
+	}
+
+	return obj, nil
+}
+
+// Retry makes a dead job pending again with a fresh set of attempts. model.ErrJobNotDead is returned for jobs which
+// are not dead.
+func (job Job) Retry(ctx context.Context, jobID int, now time.Time) (model.Job, error) {
+	res := job.db.Model(&model.Job{}).Where("id = ? AND status = ?", jobID, model.JobDead).
+		Updates(map[string]interface{}{"status": model.JobPending, "attempts": 0, "run_at": now, "last_error": "", "finished_at": time.Time{}})
+	if res.Error != nil {
+		log.Printf("error occurred while retrying job in DB: %s", res.Error.Error())
+		return model.Job{}, res.Error
+	}
+
+	obj, err := job.GetByID(ctx, jobID)
+	if err != nil {
+		return model.Job{}, err
+	}
+	if res.RowsAffected == 0 {
+		return model.Job{}, model.ErrJobNotDead
+	}
+	return obj, nil
+}
+
+// enqueueJobs saves the jobs within tx, so that they are only run once the transaction commits.
+func enqueueJobs(tx *gorm.DB, jobs []model.Job) error {
+	if len(jobs) == 0 {
+		return nil
+	}
+	return tx.Create(&jobs).Error
+}
+
+// enqueueEventJobs enqueues the jobs following up on the change of the event within tx.
+func enqueueEventJobs(tx *gorm.DB, obj model.Event, jobs model.EventJobs) error {
+	if jobs == nil {
+		return nil
+	}
+	queued, err := jobs(obj)
+	if err != nil {
+		return err
+	}
+	return enqueueJobs(tx, queued)
+}
+
+func NewJob(db *gorm.DB) Job {
+	return Job{db: db}
+}
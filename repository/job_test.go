@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type JobTestSuite struct {
+	suite.Suite
+	repo Job
+	mock sqlmock.Sqlmock
+	now  time.Time
+}
+
+func (suite *JobTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		suite.NoError(err)
+	}
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       mockDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	suite.NoError(err)
+
+	suite.repo = Job{db: db}
+	suite.mock = mock
+	suite.now = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func (suite *JobTestSuite) TestClaimLocksNextDueJobSkippingLockedOnes() {
+	lockedUntil := suite.now.Add(5 * time.Minute)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "jobs" WHERE (status = $1 AND run_at <= $2) OR (status = $3 AND locked_until <= $4) ORDER BY run_at LIMIT 1 FOR UPDATE SKIP LOCKED`)).
+		WithArgs(model.JobPending, suite.now, model.JobRunning, suite.now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "status", "attempts", "max_attempts"}).AddRow(4, "test", model.JobPending, 1, 10))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "jobs" SET "status"=$1,"attempts"=$2,"locked_until"=$3,"updated_at"=$4 WHERE "id" = $5`)).
+		WithArgs(model.JobRunning, 2, lockedUntil, sqlmock.AnyArg(), 4).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	job, err := suite.repo.Claim(context.Background(), suite.now, lockedUntil)
+	suite.NoError(err)
+	suite.Equal(uint(4), job.ID)
+	suite.Equal(model.JobRunning, job.Status)
+	suite.Equal(2, job.Attempts)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *JobTestSuite) TestClaimReturnsErrNoRowsIfNoJobIsDue() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "jobs"`)).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	suite.mock.ExpectRollback()
+
+	_, err := suite.repo.Claim(context.Background(), suite.now, suite.now.Add(5*time.Minute))
+	suite.Equal(sql.ErrNoRows, err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *JobTestSuite) TestFinishReturnsErrNoRowsIfJobWasClaimedAgain() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "jobs" SET "status"=$1,"run_at"=$2,"locked_until"=$3,"last_error"=$4,"finished_at"=$5,"updated_at"=$6 WHERE id = $7 AND status = $8 AND attempts = $9`)).
+		WithArgs(model.JobSucceeded, suite.now, time.Time{}, "", suite.now, sqlmock.AnyArg(), 4, model.JobRunning, 2).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.Finish(context.Background(), model.Job{ID: 4, Status: model.JobSucceeded, Attempts: 2, RunAt: suite.now, FinishedAt: suite.now})
+	suite.Equal(sql.ErrNoRows, err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *JobTestSuite) TestGetAllFiltersByStatusAndKind() {
+	status := int(model.JobDead)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "jobs" WHERE status = $1 AND kind = $2 ORDER BY id DESC LIMIT 100`)).
+		WithArgs(status, "test").WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "status"}).AddRow(4, "test", model.JobDead))
+
+	jobs, err := suite.repo.GetAll(context.Background(), &status, "test", 100)
+	suite.NoError(err)
+	suite.Len(jobs, 1)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *JobTestSuite) TestRetryReturnsErrorIfJobIsNotDead() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "jobs" SET`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectCommit()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "jobs" WHERE id = $1`)).
+		WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"id", "kind", "status"}).AddRow(4, "test", model.JobSucceeded))
+
+	_, err := suite.repo.Retry(context.Background(), 4, suite.now)
+	suite.ErrorIs(err, model.ErrJobNotDead)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *JobTestSuite) TestRetryReturnsErrNoRowsIfJobDoesNotExist() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "jobs" SET`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectCommit()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "jobs" WHERE id = $1`)).
+		WithArgs(4).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := suite.repo.Retry(context.Background(), 4, suite.now)
+	suite.Equal(sql.ErrNoRows, err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestJobTestSuite(t *testing.T) {
+	suite.Run(t, new(JobTestSuite))
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+type Lock struct {
+	db *gorm.DB
+}
+
+// TryLock takes the Postgres advisory lock identified by key without waiting for it. The lock belongs to a
+// dedicated connection which is held until the returned function is called, so only one process can hold it
+// at a time. false is returned when another process holds the lock.
+func (lock Lock) TryLock(ctx context.Context, key int64) (func(), bool, error) {
+	sqlDB, err := lock.db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		log.Printf("error occurred while getting a connection for lock %d: %s", key, err.Error())
+		return nil, false, err
+	}
+
+	var acquired bool
+	err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired)
+	if err != nil || !acquired {
+		conn.Close()
+		if err != nil {
+			log.Printf("error occurred while taking lock %d: %s", key, err.Error())
+		}
+		return nil, false, err
+	}
+
+	unlock := func() {
+		// The lock has to be released even if the context it was taken with is done
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			log.Printf("error occurred while releasing lock %d: %s", key, err.Error())
+		}
+		conn.Close()
+	}
+	return unlock, true, nil
+}
+
+func NewLock(db *gorm.DB) Lock {
+	return Lock{db: db}
+}
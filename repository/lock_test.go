@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type LockTestSuite struct {
+	suite.Suite
+	repo Lock
+	mock sqlmock.Sqlmock
+}
+
+func (suite *LockTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		suite.NoError(err)
+	}
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       mockDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	suite.NoError(err)
+
+	suite.repo = Lock{db: db}
+	suite.mock = mock
+}
+
+func (suite *LockTestSuite) TestTryLockTakesAndReleasesTheLock() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_lock($1)`)).WithArgs(42).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(true))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock($1)`)).WithArgs(42).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	unlock, acquired, err := suite.repo.TryLock(context.Background(), 42)
+	suite.NoError(err)
+	suite.True(acquired)
+
+	unlock()
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *LockTestSuite) TestTryLockReturnsFalseWhenLockIsHeldElsewhere() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT pg_try_advisory_lock($1)`)).WithArgs(42).
+		WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_lock"}).AddRow(false))
+
+	unlock, acquired, err := suite.repo.TryLock(context.Background(), 42)
+	suite.NoError(err)
+	suite.False(acquired)
+	suite.Nil(unlock)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestLockTestSuite(t *testing.T) {
+	suite.Run(t, new(LockTestSuite))
+}
@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"gorm.io/gorm"
+)
+
+type Notification struct {
+	db *gorm.DB
+}
+
+// Create adds the notifications to the outbox.
+func (notification Notification) Create(ctx context.Context, notifications []model.Notification) error {
+	err := notification.db.Omit("Event").Create(&notifications).Error
+	if err != nil {
+		log.Printf("error occurred while saving notifications in DB: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetDue returns the pending notifications which are due at now, oldest first, along with their event.
+func (notification Notification) GetDue(ctx context.Context, now time.Time, limit int) ([]model.Notification, error) {
+	notifications := make([]model.Notification, 0)
+	err := notification.db.Preload("Event").Order("send_at").Limit(limit).
+		Find(&notifications, "status = $1 AND send_at <= $2", model.DeliveryPending, now).Error
+	if err != nil {
+		log.Printf("error occurred while fetching due notifications from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// Update records the outcome of an attempt to send the notification.
+func (notification Notification) Update(ctx context.Context, obj model.Notification) error {
+	err := notification.db.Model(&model.Notification{ID: obj.ID}).
+		Select("send_at", "status", "attempts", "error", "sent_at").
+		Updates(obj).Error
+	if err != nil {
+		log.Printf("error occurred while updating notification in DB: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewNotification(db *gorm.DB) Notification {
+	return Notification{db: db}
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type NotificationTestSuite struct {
+	suite.Suite
+	repo Notification
+	mock sqlmock.Sqlmock
+}
+
+func (suite *NotificationTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		suite.NoError(err)
+	}
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       mockDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	suite.NoError(err)
+
+	suite.repo = Notification{db: db}
+	suite.mock = mock
+}
+
+func (suite *NotificationTestSuite) TestGetDueLoadsTheirEvents() {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "notifications" WHERE status = $1 AND send_at <= $2 ORDER BY send_at LIMIT 100`)).
+		WithArgs(model.DeliveryPending, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "event_id", "kind", "recipient"}).AddRow(1, 2, model.NotificationReminder, model.RecipientInvitee))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "events" WHERE "events"."id" = $1`)).
+		WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "invitee_email"}).AddRow(2, 1, "test@example.xyz"))
+
+	notifications, err := suite.repo.GetDue(context.Background(), now, 100)
+	suite.NoError(err)
+	suite.Len(notifications, 1)
+	suite.Equal("test@example.xyz", notifications[0].Event.InviteeEmail)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *NotificationTestSuite) TestUpdateOnlyUpdatesTheOutcome() {
+	sentAt := time.Date(2023, 6, 1, 0, 1, 0, 0, time.UTC)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "notifications" SET "send_at"=$1,"status"=$2,"attempts"=$3,"error"=$4,"sent_at"=$5,"updated_at"=$6 WHERE "id" = $7`)).
+		WithArgs(sentAt, model.DeliverySucceeded, 1, "", sentAt, sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.Update(context.Background(), model.Notification{ID: 3, EventID: 2, Kind: model.NotificationConfirmation, Status: model.DeliverySucceeded,
+		Attempts: 1, SendAt: sentAt, SentAt: sentAt})
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestNotificationTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationTestSuite))
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"log"
 	"time"
 
@@ -34,10 +35,13 @@ func (slot Slot) Create(ctx context.Context, slots []model.Slot) error {
 
 func (slot Slot) GetByID(ctx context.Context, slotID int) (model.Slot, error) {
 	slotObj := model.Slot{ID: uint(slotID)}
-	err := slot.db.Find(&slotObj).Error
-	if err != nil {
-		log.Printf("error occurred while fetching slot from db: %s", err.Error())
-		return model.Slot{}, err
+	res := slot.db.Find(&slotObj)
+	if res.Error != nil {
+		log.Printf("error occurred while fetching slot from db: %s", res.Error.Error())
+		return model.Slot{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return model.Slot{}, sql.ErrNoRows
 	}
 	return slotObj, nil
 }
@@ -52,11 +56,22 @@ func (slot Slot) DeleteByID(ctx context.Context, slotID int) error {
 	return nil
 }
 
-func (slot Slot) BookSlot(ctx context.Context, slotID int) error {
-	slotObj := model.Slot{ID: uint(slotID)}
-	err := slot.db.Model(&slotObj).Update("status", model.StatusBooked).Error
+// Expire marks the slots which ended before the given time and were never booked as expired.
+func (slot Slot) Expire(ctx context.Context, before time.Time) (int64, error) {
+	res := slot.db.Model(&model.Slot{}).Where("status = ? AND booked_seats = 0 AND end_time <= ?", model.StatusCreated, before).Update("status", model.StatusExpired)
+	if res.Error != nil {
+		log.Printf("error occurred while expiring slots in db: %s", res.Error.Error())
+		return 0, res.Error
+	}
+	return res.RowsAffected, nil
+}
+
+// DeleteUnbooked deletes the slots of a user starting at or after the given time of which no seat is booked.
+func (slot Slot) DeleteUnbooked(ctx context.Context, userID int, after time.Time) error {
+	err := slot.db.Model(&model.Slot{}).Where("user_id = ? AND status = ? AND booked_seats = 0 AND start_time >= ?", userID, model.StatusCreated, after).
+		Updates(model.Slot{Status: model.StatusDeleted, DeletedAt: time.Now()}).Error
 	if err != nil {
-		log.Printf("error occurred while booking slot in db: %s", err.Error())
+		log.Printf("error occurred while deleting unbooked slots from db: %s", err.Error())
 		return err
 	}
 	return nil
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -40,10 +41,10 @@ func (suite *SlotTestSuite) SetupTest() {
 
 func (suite *SlotTestSuite) TestCreateHappyFlow() {
 	suite.mock.ExpectBegin()
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slots" ("user_id","start_time","end_time","status","created_at","updated_at","deleted_at") 
-	VALUES ($1,$2,$3,$4,$5,$6,$7),($8,$9,$10,$11,$12,$13,$14)`)).
-		WithArgs(1, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-			1, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slots" ("user_id","event_type_id","start_time","end_time","status","capacity","booked_seats","created_at","updated_at","deleted_at") 
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10),($11,$12,$13,$14,$15,$16,$17,$18,$19,$20)`)).
+		WithArgs(1, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, 1, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			1, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, 1, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id"}).AddRow(1))
 	suite.mock.ExpectCommit()
@@ -55,12 +56,14 @@ func (suite *SlotTestSuite) TestCreateHappyFlow() {
 			StartTime: now,
 			EndTime:   now.Add(30 * time.Minute),
 			Status:    model.StatusCreated,
+			Capacity:  1,
 		},
 		{
 			UserID:    1,
 			StartTime: now.Add(30 * time.Minute),
 			EndTime:   now.Add(60 * time.Minute),
 			Status:    model.StatusCreated,
+			Capacity:  1,
 		},
 	})
 
@@ -70,10 +73,10 @@ func (suite *SlotTestSuite) TestCreateHappyFlow() {
 
 func (suite *SlotTestSuite) TestCreateReturnsErrorWhenDBFails() {
 	suite.mock.ExpectBegin()
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slots" ("user_id","start_time","end_time","status","created_at","updated_at","deleted_at") 
-	VALUES ($1,$2,$3,$4,$5,$6,$7),($8,$9,$10,$11,$12,$13,$14)`)).
-		WithArgs(1, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
-			1, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "slots" ("user_id","event_type_id","start_time","end_time","status","capacity","booked_seats","created_at","updated_at","deleted_at") 
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10),($11,$12,$13,$14,$15,$16,$17,$18,$19,$20)`)).
+		WithArgs(1, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, 1, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(),
+			1, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), 0, 1, 0, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnError(errors.New("some error"))
 	suite.mock.ExpectRollback()
 
@@ -84,12 +87,14 @@ func (suite *SlotTestSuite) TestCreateReturnsErrorWhenDBFails() {
 			StartTime: now,
 			EndTime:   now.Add(30 * time.Minute),
 			Status:    model.StatusCreated,
+			Capacity:  1,
 		},
 		{
 			UserID:    1,
 			StartTime: now.Add(30 * time.Minute),
 			EndTime:   now.Add(60 * time.Minute),
 			Status:    model.StatusCreated,
+			Capacity:  1,
 		},
 	})
 
@@ -147,6 +152,45 @@ func (suite *SlotTestSuite) TestGetByIDReturnsErrorIfDBReturnsError() {
 	suite.Empty(resp)
 }
 
+func (suite *SlotTestSuite) TestGetByIDReturnsNotFoundIfSlotDoesNotExist() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "slots" WHERE "slots"."id" = $1`)).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	resp, err := suite.repo.GetByID(context.Background(), 1)
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.Empty(resp)
+}
+
+func (suite *SlotTestSuite) TestExpireMarksPastUnbookedSlots() {
+	now := time.Now()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "status"=$1,"updated_at"=$2 WHERE status = $3 AND booked_seats = 0 AND end_time <= $4`)).
+		WithArgs(model.StatusExpired, sqlmock.AnyArg(), model.StatusCreated, now).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	suite.mock.ExpectCommit()
+
+	expired, err := suite.repo.Expire(context.Background(), now)
+
+	suite.NoError(err)
+	suite.Equal(int64(3), expired)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *SlotTestSuite) TestDeleteUnbookedOnlyDeletesFutureCreatedSlots() {
+	now := time.Now()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "slots" SET "status"=$1,"updated_at"=$2,"deleted_at"=$3 WHERE user_id = $4 AND status = $5 AND booked_seats = 0 AND start_time >= $6`)).
+		WithArgs(model.StatusDeleted, sqlmock.AnyArg(), sqlmock.AnyArg(), 1, model.StatusCreated, now).
+		WillReturnResult(sqlmock.NewResult(0, 10))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.DeleteUnbooked(context.Background(), 1, now)
+
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
 func TestSlotTestSuite(t *testing.T) {
 	suite.Run(t, new(SlotTestSuite))
 }
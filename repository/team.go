@@ -0,0 +1,193 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"gorm.io/gorm"
+)
+
+type Team struct {
+	db *gorm.DB
+}
+
+// Create saves the team along with its members.
+func (team Team) Create(ctx context.Context, obj model.Team) (model.Team, error) {
+	err := team.db.Create(&obj).Error
+	if err != nil {
+		log.Printf("error occurred while saving team in DB: %s", err.Error())
+		return model.Team{}, translateUniqueViolation(err, teamMemberUniqueErrors)
+	}
+
+	return obj, nil
+}
+
+// GetAll returns the teams owned by the user along with their members.
+func (team Team) GetAll(ctx context.Context, ownerID int) ([]model.Team, error) {
+	teams := make([]model.Team, 0)
+	err := team.db.Preload("Members", orderMembers).Order("id").Find(&teams, "owner_id = $1", ownerID).Error
+	if err != nil {
+		log.Printf("error occurred while fetching teams from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// GetByID returns the team of the owner along with its members, or sql.ErrNoRows if the owner has no such team.
+func (team Team) GetByID(ctx context.Context, ownerID, teamID int) (model.Team, error) {
+	obj := model.Team{}
+	res := team.db.Preload("Members", orderMembers).Find(&obj, "id = $1 AND owner_id = $2", teamID, ownerID)
+	if res.Error != nil {
+		log.Printf("error occurred while fetching team from DB: %s", res.Error.Error())
+		return model.Team{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return model.Team{}, sql.ErrNoRows
+	}
+
+	return obj, nil
+}
+
+// Delete deletes the team of the owner along with its members and event types. The bookings made through the team
+// stay with the members.
+func (team Team) Delete(ctx context.Context, ownerID, teamID int) error {
+	return team.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&model.Team{}, "id = ? AND owner_id = ?", teamID, ownerID)
+		if res.Error != nil {
+			log.Printf("error occurred while deleting team from DB: %s", res.Error.Error())
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return sql.ErrNoRows
+		}
+
+		if err := tx.Delete(&model.TeamMember{}, "team_id = ?", teamID).Error; err != nil {
+			log.Printf("error occurred while deleting team members from DB: %s", err.Error())
+			return err
+		}
+		if err := tx.Delete(&model.TeamEventType{}, "team_id = ?", teamID).Error; err != nil {
+			log.Printf("error occurred while deleting team event types from DB: %s", err.Error())
+			return err
+		}
+		return nil
+	})
+}
+
+// AddMember adds a user to a team, or model.ErrDuplicateMember if they are a member already.
+func (team Team) AddMember(ctx context.Context, member model.TeamMember) (model.TeamMember, error) {
+	err := team.db.Create(&member).Error
+	if err != nil {
+		log.Printf("error occurred while saving team member in DB: %s", err.Error())
+		return model.TeamMember{}, translateUniqueViolation(err, teamMemberUniqueErrors)
+	}
+
+	return member, nil
+}
+
+// GetMemberships returns the teams the user is a member of, whose members only include the user.
+func (team Team) GetMemberships(ctx context.Context, userID int) ([]model.Team, error) {
+	teams := make([]model.Team, 0)
+	members := team.db.Model(&model.TeamMember{}).Select("team_id").Where("user_id = ?", userID)
+	err := team.db.Preload("Members", "user_id = ?", userID).Order("id").Find(&teams, "id IN (?)", members).Error
+	if err != nil {
+		log.Printf("error occurred while fetching team memberships from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return teams, nil
+}
+
+// AcceptMember records that the user accepts to be booked through the team, or returns sql.ErrNoRows if they are
+// not a member.
+func (team Team) AcceptMember(ctx context.Context, teamID, userID int) error {
+	res := team.db.Model(&model.TeamMember{}).Where("team_id = ? AND user_id = ?", teamID, userID).Update("accepted", true)
+	if res.Error != nil {
+		log.Printf("error occurred while updating team member in DB: %s", res.Error.Error())
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// RemoveMember removes a user from a team, or returns sql.ErrNoRows if they are not a member.
+func (team Team) RemoveMember(ctx context.Context, teamID, userID int) error {
+	res := team.db.Delete(&model.TeamMember{}, "team_id = $1 AND user_id = $2", teamID, userID)
+	if res.Error != nil {
+		log.Printf("error occurred while deleting team member from DB: %s", res.Error.Error())
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+func (team Team) CreateEventType(ctx context.Context, obj model.TeamEventType) (model.TeamEventType, error) {
+	err := team.db.Create(&obj).Error
+	if err != nil {
+		log.Printf("error occurred while saving team event type in DB: %s", err.Error())
+		return model.TeamEventType{}, translateUniqueViolation(err, teamEventTypeUniqueErrors)
+	}
+
+	return obj, nil
+}
+
+func (team Team) GetEventTypes(ctx context.Context, teamID int) ([]model.TeamEventType, error) {
+	eventTypes := make([]model.TeamEventType, 0)
+	err := team.db.Order("id").Find(&eventTypes, "team_id = $1", teamID).Error
+	if err != nil {
+		log.Printf("error occurred while fetching team event types from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return eventTypes, nil
+}
+
+func (team Team) GetEventType(ctx context.Context, teamID, eventTypeID int) (model.TeamEventType, error) {
+	obj := model.TeamEventType{}
+	res := team.db.Find(&obj, "id = $1 AND team_id = $2", eventTypeID, teamID)
+	if res.Error != nil {
+		log.Printf("error occurred while fetching team event type from DB: %s", res.Error.Error())
+		return model.TeamEventType{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return model.TeamEventType{}, sql.ErrNoRows
+	}
+
+	return obj, nil
+}
+
+func (team Team) DeleteEventType(ctx context.Context, teamID, eventTypeID int) error {
+	res := team.db.Delete(&model.TeamEventType{}, "id = $1 AND team_id = $2", eventTypeID, teamID)
+	if res.Error != nil {
+		log.Printf("error occurred while deleting team event type from DB: %s", res.Error.Error())
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// orderMembers lists the members of a team in the order they were added
+func orderMembers(db *gorm.DB) *gorm.DB {
+	return db.Order("created_at, user_id")
+}
+
+func NewTeam(db *gorm.DB) Team {
+	return Team{db: db}
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type TeamTestSuite struct {
+	suite.Suite
+	repo Team
+	mock sqlmock.Sqlmock
+}
+
+func (suite *TeamTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		suite.NoError(err)
+	}
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       mockDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	suite.NoError(err)
+
+	suite.repo = Team{db: db}
+	suite.mock = mock
+}
+
+func (suite *TeamTestSuite) TestCreateSavesTeamWithMembers() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "teams" ("owner_id","name","created_at","updated_at") VALUES ($1,$2,$3,$4) RETURNING "id"`)).
+		WithArgs(1, "sales", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "team_members" ("team_id","user_id","priority","accepted","last_booked_at","bookings","created_at") VALUES ($1,$2,$3,$4,$5,$6,$7) ON CONFLICT ("team_id","user_id") DO UPDATE SET "team_id"="excluded"."team_id"`)).
+		WithArgs(2, 5, 1, false, sqlmock.AnyArg(), 0, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	resp, err := suite.repo.Create(context.Background(), model.Team{OwnerID: 1, Name: "sales", Members: []model.TeamMember{{UserID: 5, Priority: 1}}})
+
+	suite.NoError(err)
+	suite.Equal(2, int(resp.ID))
+	suite.Equal(2, int(resp.Members[0].TeamID))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamTestSuite) TestGetByIDReturnsErrNoRowsIfTeamBelongsToAnotherOwner() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "teams" WHERE id = $1 AND owner_id = $2`)).
+		WithArgs(2, 1).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, err := suite.repo.GetByID(context.Background(), 1, 2)
+	suite.Equal(sql.ErrNoRows, err)
+}
+
+func (suite *TeamTestSuite) TestAddMemberReturnsErrDuplicateMemberIfUserIsAMember() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO "team_members"`)).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "team_members_pkey"})
+	suite.mock.ExpectRollback()
+
+	_, err := suite.repo.AddMember(context.Background(), model.TeamMember{TeamID: 2, UserID: 5})
+	suite.Equal(model.ErrDuplicateMember, err)
+}
+
+func (suite *TeamTestSuite) TestGetMembershipsReturnsTeamsOfUserWithTheirMembershipOnly() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "teams" WHERE id IN (SELECT "team_id" FROM "team_members" WHERE user_id = $1) ORDER BY id`)).
+		WithArgs(5).WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id", "name"}).AddRow(2, 1, "sales"))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "team_members" WHERE "team_members"."team_id" = $1 AND user_id = $2`)).
+		WithArgs(2, 5).WillReturnRows(sqlmock.NewRows([]string{"team_id", "user_id", "accepted"}).AddRow(2, 5, false))
+
+	resp, err := suite.repo.GetMemberships(context.Background(), 5)
+	suite.NoError(err)
+	suite.Len(resp, 1)
+	suite.Equal([]model.TeamMember{{TeamID: 2, UserID: 5}}, resp[0].Members)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamTestSuite) TestAcceptMemberReturnsErrNoRowsIfUserIsNotAMember() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "team_members" SET "accepted"=$1 WHERE team_id = $2 AND user_id = $3`)).
+		WithArgs(true, 2, 5).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.AcceptMember(context.Background(), 2, 5)
+	suite.Equal(sql.ErrNoRows, err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamTestSuite) TestDeleteRemovesMembersAndEventTypes() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "teams" WHERE id = $1 AND owner_id = $2`)).
+		WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "team_members" WHERE team_id = $1`)).
+		WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "team_event_types" WHERE team_id = $1`)).
+		WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.Delete(context.Background(), 1, 2)
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *TeamTestSuite) TestDeleteReturnsErrNoRowsIfTeamDoesNotExist() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "teams"`)).
+		WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+
+	err := suite.repo.Delete(context.Background(), 1, 2)
+	suite.Equal(sql.ErrNoRows, err)
+}
+
+func TestTeamTestSuite(t *testing.T) {
+	suite.Run(t, new(TeamTestSuite))
+}
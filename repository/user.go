@@ -2,13 +2,18 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"log"
+	"strings"
 
 	"github.com/harbor-xyz/coding-project/model"
 
 	"gorm.io/gorm"
 )
 
+// likeEscaper escapes the wildcards of LIKE patterns, so that searched text is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
 type User struct {
 	db *gorm.DB
 }
@@ -17,12 +22,182 @@ func (user User) Create(ctx context.Context, input model.User) (model.User, erro
 	err := user.db.Create(&input).Error
 	if err != nil {
 		log.Printf("error occurred while saving user in DB: %s", err.Error())
-		return model.User{}, err
+		return model.User{}, translateUniqueViolation(err, userUniqueErrors)
 	}
 
 	return input, nil
 }
 
+// GetAll returns a page of the users whose email contains the given text, ordered by ID, along with how many users
+// match in total. An empty email matches every user.
+func (user User) GetAll(ctx context.Context, email string, limit, offset int) ([]model.User, int64, error) {
+	query := user.db.Model(&model.User{})
+	if email != "" {
+		query = query.Where("email ILIKE ?", "%"+likeEscaper.Replace(email)+"%")
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		log.Printf("error occurred while counting users in DB: %s", err.Error())
+		return nil, 0, err
+	}
+
+	users := make([]model.User, 0)
+	if err := query.Order("id").Limit(limit).Offset(offset).Find(&users).Error; err != nil {
+		log.Printf("error occurred while fetching users from DB: %s", err.Error())
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+func (user User) GetByID(ctx context.Context, userID int) (model.User, error) {
+	userObj := model.User{}
+	res := user.db.Find(&userObj, userID)
+	if res.Error != nil {
+		log.Printf("error occurred while getting user from DB: %s", res.Error.Error())
+		return model.User{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		log.Printf("user not found: %d", userID)
+		return model.User{}, sql.ErrNoRows
+	}
+
+	return userObj, nil
+}
+
+// GetByFeedTokenHash returns the user whose calendar feed token has the given hash.
+func (user User) GetByFeedTokenHash(ctx context.Context, hash string) (model.User, error) {
+	userObj := model.User{}
+	res := user.db.Find(&userObj, "feed_token_hash = $1", hash)
+	if res.Error != nil {
+		log.Printf("error occurred while getting user from DB: %s", res.Error.Error())
+		return model.User{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return model.User{}, sql.ErrNoRows
+	}
+
+	return userObj, nil
+}
+
+// GetBySlug returns the user whose public booking page has the given slug.
+func (user User) GetBySlug(ctx context.Context, slug string) (model.User, error) {
+	userObj := model.User{}
+	res := user.db.Find(&userObj, "slug = $1", slug)
+	if res.Error != nil {
+		log.Printf("error occurred while getting user from DB: %s", res.Error.Error())
+		return model.User{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return model.User{}, sql.ErrNoRows
+	}
+
+	return userObj, nil
+}
+
+// SetSlug changes the slug of the user's public booking page, which stops the previous one from working.
+func (user User) SetSlug(ctx context.Context, userID int, slug string) error {
+	res := user.db.Model(&model.User{}).Where("id = ?", userID).Update("slug", slug)
+	if res.Error != nil {
+		log.Printf("error occurred while updating user in DB: %s", res.Error.Error())
+		return translateUniqueViolation(res.Error, userUniqueErrors)
+	}
+
+	if res.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// SetFeedTokenHash replaces the hash of the user's calendar feed token, which invalidates the previous feed URL.
+func (user User) SetFeedTokenHash(ctx context.Context, userID int, hash string) error {
+	res := user.db.Model(&model.User{}).Where("id = ?", userID).Update("feed_token_hash", hash)
+	if res.Error != nil {
+		log.Printf("error occurred while updating user in DB: %s", res.Error.Error())
+		return res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+
+	return nil
+}
+
+// Update saves the name, email, time zone and slug of the user.
+func (user User) Update(ctx context.Context, obj model.User) (model.User, error) {
+	res := user.db.Model(&model.User{ID: obj.ID}).Select("name", "email", "time_zone", "slug").Updates(obj)
+	if res.Error != nil {
+		log.Printf("error occurred while updating user in DB: %s", res.Error.Error())
+		return model.User{}, translateUniqueViolation(res.Error, userUniqueErrors)
+	}
+
+	if res.RowsAffected == 0 {
+		return model.User{}, sql.ErrNoRows
+	}
+
+	return obj, nil
+}
+
+// Delete removes the user along with everything they own: their availability, event types, slots, events and their
+// series, history and notifications, external calendars, webhooks and API keys. Pending jobs about the user, their
+// events or their webhooks are deleted as well, as they would fail once those are gone.
+func (user User) Delete(ctx context.Context, userID int) error {
+	return user.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&model.User{}, "id = ?", userID)
+		if res.Error != nil {
+			log.Printf("error occurred while deleting user from DB: %s", res.Error.Error())
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return sql.ErrNoRows
+		}
+
+		events := tx.Model(&model.Event{}).Select("id").Where("user_id = ?", userID)
+		webhooks := tx.Model(&model.Webhook{}).Select("id").Where("user_id = ?", userID)
+		teams := tx.Model(&model.Team{}).Select("id").Where("owner_id = ?", userID)
+		jobs := tx.Model(&model.Job{}).Select("id").Where("status = ?", model.JobPending).
+			Where("(payload->>'user_id')::bigint = ? OR (payload->>'event_id')::bigint IN (?)", userID, events)
+		deletions := []struct {
+			obj   interface{}
+			query string
+			arg   interface{}
+		}{
+			{&model.Job{}, "id IN (?)", jobs},
+			{&model.Notification{}, "event_id IN (?)", events},
+			{&model.EventChange{}, "event_id IN (?)", events},
+			{&model.WebhookDelivery{}, "webhook_id IN (?)", webhooks},
+			{&model.Event{}, "user_id = ?", userID},
+			{&model.EventSeries{}, "user_id = ?", userID},
+			{&model.Slot{}, "user_id = ?", userID},
+			{&model.EventType{}, "user_id = ?", userID},
+			{&model.UserAvailability{}, "user_id = ?", userID},
+			{&model.AvailabilityOverride{}, "user_id = ?", userID},
+			{&model.BusyBlock{}, "user_id = ?", userID},
+			{&model.ExternalCalendar{}, "user_id = ?", userID},
+			{&model.Webhook{}, "user_id = ?", userID},
+			{&model.APIKey{}, "user_id = ?", userID},
+			{&model.TeamMember{}, "user_id = ?", userID},
+			{&model.TeamMember{}, "team_id IN (?)", teams},
+			{&model.TeamEventType{}, "team_id IN (?)", teams},
+			{&model.Team{}, "owner_id = ?", userID},
+		}
+		for _, deletion := range deletions {
+			if err := tx.Where(deletion.query, deletion.arg).Delete(deletion.obj).Error; err != nil {
+				log.Printf("error occurred while deleting data of user %d from DB: %s", userID, err.Error())
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func NewUser(db *gorm.DB) User {
 	return User{db: db}
 }
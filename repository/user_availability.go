@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"time"
 
 	"github.com/harbor-xyz/coding-project/model"
 
@@ -15,11 +16,20 @@ type UserAvailability struct {
 	db *gorm.DB
 }
 
-func (availability UserAvailability) Set(ctx context.Context, input model.UserAvailability) (model.UserAvailability, error) {
-	err := availability.db.Clauses(clause.OnConflict{
-		Columns:   []clause.Column{{Name: "user_id"}},
-		DoUpdates: clause.Assignments(map[string]interface{}{"availability": input.Availability, "meeting_duration_mins": input.MeetingDurationMins}),
-	}).Create(&input).Error
+// Set saves the availability of the user along with the jobs following up on the change in a single transaction.
+func (availability UserAvailability) Set(ctx context.Context, input model.UserAvailability, jobs []model.Job) (model.UserAvailability, error) {
+	err := availability.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{"availability": input.Availability, "meeting_duration_mins": input.MeetingDurationMins, "time_zone": input.TimeZone,
+				"slot_increment_mins": input.SlotIncrementMins, "before_buffer_mins": input.BeforeBufferMins, "after_buffer_mins": input.AfterBufferMins, "min_notice_mins": input.MinNoticeMins,
+				"max_horizon_days": input.MaxHorizonDays, "max_per_day": input.MaxPerDay, "max_per_week": input.MaxPerWeek, "updated_at": time.Now()}),
+		}).Create(&input).Error
+		if err != nil {
+			return err
+		}
+		return enqueueJobs(tx, jobs)
+	})
 	if err != nil {
 		log.Printf("error occurred while saving user availability in DB: %s", err.Error())
 		return model.UserAvailability{}, err
@@ -44,6 +54,29 @@ func (availability UserAvailability) Get(ctx context.Context, userID int) (model
 	return ua, nil
 }
 
+func (availability UserAvailability) GetAll(ctx context.Context) ([]model.UserAvailability, error) {
+	availabilities := make([]model.UserAvailability, 0)
+	err := availability.db.Order("user_id").Find(&availabilities).Error
+	if err != nil {
+		log.Printf("error occurred while getting user availabilities from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return availabilities, nil
+}
+
+// MarkSlotsSynced records when slots were last generated for the user. updated_at is left untouched as it tracks
+// changes made by the user.
+func (availability UserAvailability) MarkSlotsSynced(ctx context.Context, userID int, syncedAt time.Time) error {
+	err := availability.db.Model(&model.UserAvailability{}).Where("user_id = ?", userID).UpdateColumn("slots_synced_at", syncedAt).Error
+	if err != nil {
+		log.Printf("error occurred while marking slots synced in DB: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
 func NewUserAvailability(db *gorm.DB) UserAvailability {
 	return UserAvailability{db: db}
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/harbor-xyz/coding-project/model"
 
@@ -57,7 +58,7 @@ func (suite *UserAvailabilityTestSuite) SetupTest() {
 			},
 		},
 		MeetingDurationMins: 30,
-	})
+	}, nil)
 
 	suite.Equal(int(resp.UserID), 1)
 	suite.NoError(err)
@@ -82,7 +83,7 @@ func (suite *UserAvailabilityTestSuite) TestCreateReturnsErrorWhenDBFails() {
 			},
 		},
 		MeetingDurationMins: 30,
-	})
+	}, nil)
 
 	suite.Empty(resp)
 	suite.Equal("some error", err.Error())
@@ -115,6 +116,20 @@ func (suite *UserAvailabilityTestSuite) TestGetReturnsErrorIfDBReturnsError() {
 	suite.Empty(resp)
 }
 
+func (suite *UserAvailabilityTestSuite) TestMarkSlotsSyncedLeavesUpdatedAtUntouched() {
+	now := time.Now()
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "user_availabilities" SET "slots_synced_at"=$1 WHERE user_id = $2`)).
+		WithArgs(now, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.MarkSlotsSynced(context.Background(), 1, now)
+
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
 func TestUserAvailabilityTestSuite(t *testing.T) {
 	suite.Run(t, new(UserAvailabilityTestSuite))
 }
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"regexp"
 	"testing"
@@ -9,6 +10,7 @@ import (
 	"github.com/harbor-xyz/coding-project/model"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -39,33 +41,190 @@ func (suite *UserTestSuite) SetupTest() {
 
 func (suite *UserTestSuite) TestCreateHappyFlow() {
 	suite.mock.ExpectBegin()
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users" ("name","email","created_at","updated_at") VALUES ($1,$2,$3,$4) RETURNING "id"`)).
-		WithArgs("test", "test@example.xyz", sqlmock.AnyArg(), sqlmock.AnyArg()).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users" ("name","email","time_zone","slug","feed_token_hash","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WithArgs("test", "test@example.xyz", "UTC", "test", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnRows(
 			sqlmock.NewRows([]string{"id"}).AddRow(1))
 	suite.mock.ExpectCommit()
 
-	resp, err := suite.repo.Create(context.Background(), model.User{Name: "test", Email: "test@example.xyz"})
+	resp, err := suite.repo.Create(context.Background(), model.User{Name: "test", Email: "test@example.xyz", TimeZone: "UTC", Slug: "test"})
 
 	suite.Equal(1, int(resp.ID))
 	suite.NoError(err)
 	suite.NoError(suite.mock.ExpectationsWereMet())
 }
 
+func (suite *UserTestSuite) TestCreateSavesAPIKeysInSameTransaction() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users"`)).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "api_keys" ("user_id","name","prefix","key_hash","last_used_at","created_at") VALUES ($1,$2,$3,$4,$5,$6)`)).
+		WithArgs(1, "default", "cal_abcd", "hash", sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+	suite.mock.ExpectCommit()
+
+	resp, err := suite.repo.Create(context.Background(), model.User{Name: "test", Email: "test@example.xyz", TimeZone: "UTC", Slug: "test",
+		APIKeys: []model.APIKey{{Name: "default", Prefix: "cal_abcd", KeyHash: "hash"}}})
+	suite.NoError(err)
+	suite.Equal(1, int(resp.APIKeys[0].UserID))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
 func (suite *UserTestSuite) TestCreateReturnsErrorWhenDBFails() {
 	suite.mock.ExpectBegin()
-	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users" ("name","email","created_at","updated_at") VALUES ($1,$2,$3,$4) RETURNING "id"`)).
-		WithArgs("test", "test@example.xyz", sqlmock.AnyArg(), sqlmock.AnyArg()).
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users" ("name","email","time_zone","slug","feed_token_hash","created_at","updated_at") VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING "id"`)).
+		WithArgs("test", "test@example.xyz", "UTC", "test", "", sqlmock.AnyArg(), sqlmock.AnyArg()).
 		WillReturnError(errors.New("some error"))
 	suite.mock.ExpectRollback()
 
-	resp, err := suite.repo.Create(context.Background(), model.User{Name: "test", Email: "test@example.xyz"})
+	resp, err := suite.repo.Create(context.Background(), model.User{Name: "test", Email: "test@example.xyz", TimeZone: "UTC", Slug: "test"})
 
 	suite.Empty(resp)
 	suite.Error(err, "some error")
 	suite.NoError(suite.mock.ExpectationsWereMet())
 }
 
+func (suite *UserTestSuite) TestGetByIDReturnsDataIfExists() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE "users"."id" = $1`)).
+		WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "time_zone"}).AddRow(1, "test", "test@example.xyz", "Asia/Kolkata"))
+
+	resp, err := suite.repo.GetByID(context.Background(), 1)
+	suite.NoError(err)
+	suite.Equal(1, int(resp.ID))
+	suite.Equal("Asia/Kolkata", resp.TimeZone)
+}
+
+func (suite *UserTestSuite) TestGetByIDReturnsErrNoRowsIfUserDoesNotExist() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE "users"."id" = $1`)).
+		WithArgs(1).WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "time_zone"}))
+
+	resp, err := suite.repo.GetByID(context.Background(), 1)
+	suite.Equal(sql.ErrNoRows, err)
+	suite.Empty(resp)
+}
+
+func (suite *UserTestSuite) TestGetByFeedTokenHashReturnsUser() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE feed_token_hash = $1`)).
+		WithArgs("hash").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "feed_token_hash"}).AddRow(1, "test", "hash"))
+
+	resp, err := suite.repo.GetByFeedTokenHash(context.Background(), "hash")
+	suite.NoError(err)
+	suite.Equal(1, int(resp.ID))
+}
+
+func (suite *UserTestSuite) TestSetFeedTokenHashReturnsErrNoRowsIfUserDoesNotExist() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "feed_token_hash"=$1,"updated_at"=$2 WHERE id = $3`)).
+		WithArgs("hash", sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.SetFeedTokenHash(context.Background(), 1, "hash")
+	suite.Equal(sql.ErrNoRows, err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserTestSuite) TestGetBySlugReturnsErrNoRowsIfNoUserHasIt() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE slug = $1`)).
+		WithArgs("jane").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "slug"}))
+
+	resp, err := suite.repo.GetBySlug(context.Background(), "jane")
+	suite.Equal(sql.ErrNoRows, err)
+	suite.Empty(resp)
+}
+
+func (suite *UserTestSuite) TestSetSlugUpdatesSlug() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "slug"=$1,"updated_at"=$2 WHERE id = $3`)).
+		WithArgs("jane", sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.SetSlug(context.Background(), 1, "jane")
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserTestSuite) TestCreateReturnsErrDuplicateEmailIfEmailIsTaken() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "users"`)).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_users_email"})
+	suite.mock.ExpectRollback()
+
+	_, err := suite.repo.Create(context.Background(), model.User{Name: "test", Email: "test@example.xyz", TimeZone: "UTC", Slug: "test"})
+	suite.Equal(model.ErrDuplicateEmail, err)
+}
+
+func (suite *UserTestSuite) TestGetAllSearchesEmailsLiterally() {
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT count(*) FROM "users" WHERE email ILIKE $1`)).
+		WithArgs(`%jane\_doe%`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(21))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "users" WHERE email ILIKE $1 ORDER BY id LIMIT 20 OFFSET 20`)).
+		WithArgs(`%jane\_doe%`).WillReturnRows(sqlmock.NewRows([]string{"id", "email"}).AddRow(21, "jane_doe@example.xyz"))
+
+	users, total, err := suite.repo.GetAll(context.Background(), "jane_doe", 20, 20)
+	suite.NoError(err)
+	suite.Equal(int64(21), total)
+	suite.Len(users, 1)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserTestSuite) TestUpdateOnlyUpdatesEditableFields() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users" SET "name"=$1,"email"=$2,"time_zone"=$3,"slug"=$4,"updated_at"=$5 WHERE "id" = $6`)).
+		WithArgs("jane", "jane@example.xyz", "Asia/Kolkata", "jane", sqlmock.AnyArg(), 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	_, err := suite.repo.Update(context.Background(), model.User{ID: 1, Name: "jane", Email: "jane@example.xyz", TimeZone: "Asia/Kolkata", Slug: "jane",
+		FeedTokenHash: "hash"})
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserTestSuite) TestUpdateReturnsErrDuplicateSlugIfSlugIsTaken() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "users"`)).
+		WillReturnError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_users_slug"})
+	suite.mock.ExpectRollback()
+
+	_, err := suite.repo.Update(context.Background(), model.User{ID: 1, Name: "jane", Slug: "jane"})
+	suite.Equal(model.ErrDuplicateSlug, err)
+}
+
+func (suite *UserTestSuite) TestDeleteRemovesEverythingOfUser() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "users" WHERE id = $1`)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	// Pending jobs about the user, their events or webhooks would fail once those are deleted
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "jobs" WHERE id IN (SELECT "id" FROM "jobs" WHERE status = $1 AND ((payload->>'user_id')::bigint = $2 OR (payload->>'event_id')::bigint IN (SELECT "id" FROM "events" WHERE user_id = $3)))`)).
+		WithArgs(model.JobPending, 1, 1).WillReturnResult(sqlmock.NewResult(0, 3))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "notifications" WHERE event_id IN (SELECT "id" FROM "events" WHERE user_id = $1)`)).
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 2))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "event_changes" WHERE event_id IN (SELECT "id" FROM "events" WHERE user_id = $1)`)).
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "webhook_deliveries" WHERE webhook_id IN (SELECT "id" FROM "webhooks" WHERE user_id = $1)`)).
+		WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	for _, table := range []string{"events", "event_series", "slots", "event_types", "user_availabilities", "availability_overrides", "busy_blocks",
+		"external_calendars", "webhooks", "api_keys"} {
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "` + table + `" WHERE user_id = $1`)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "team_members" WHERE user_id = $1`)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	for _, table := range []string{"team_members", "team_event_types"} {
+		suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "` + table + `" WHERE team_id IN (SELECT "id" FROM "teams" WHERE owner_id = $1)`)).
+			WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "teams" WHERE owner_id = $1`)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	suite.NoError(suite.repo.Delete(context.Background(), 1))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *UserTestSuite) TestDeleteReturnsErrNoRowsIfUserDoesNotExist() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "users" WHERE id = $1`)).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+
+	suite.Equal(sql.ErrNoRows, suite.repo.Delete(context.Background(), 1))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
 func TestUserTestSuite(t *testing.T) {
 	suite.Run(t, new(UserTestSuite))
 }
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"gorm.io/gorm"
+)
+
+type Webhook struct {
+	db *gorm.DB
+}
+
+func (webhook Webhook) Create(ctx context.Context, obj model.Webhook) (model.Webhook, error) {
+	err := webhook.db.Create(&obj).Error
+	if err != nil {
+		log.Printf("error occurred while saving webhook in DB: %s", err.Error())
+		return model.Webhook{}, err
+	}
+
+	return obj, nil
+}
+
+func (webhook Webhook) GetAll(ctx context.Context, userID int) ([]model.Webhook, error) {
+	webhooks := make([]model.Webhook, 0)
+	err := webhook.db.Order("id").Find(&webhooks, "user_id = $1", userID).Error
+	if err != nil {
+		log.Printf("error occurred while fetching webhooks from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (webhook Webhook) GetByID(ctx context.Context, userID, webhookID int) (model.Webhook, error) {
+	obj := model.Webhook{}
+	res := webhook.db.Find(&obj, "id = $1 AND user_id = $2", webhookID, userID)
+	if res.Error != nil {
+		log.Printf("error occurred while fetching webhook from DB: %s", res.Error.Error())
+		return model.Webhook{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		log.Printf("webhook %d not found for user: %d", webhookID, userID)
+		return model.Webhook{}, sql.ErrNoRows
+	}
+
+	return obj, nil
+}
+
+// Delete removes the webhook along with its deliveries, including the pending ones.
+func (webhook Webhook) Delete(ctx context.Context, userID, webhookID int) error {
+	return webhook.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Delete(&model.Webhook{}, "id = ? AND user_id = ?", webhookID, userID)
+		if res.Error != nil {
+			log.Printf("error occurred while deleting webhook from DB: %s", res.Error.Error())
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return sql.ErrNoRows
+		}
+
+		if err := tx.Delete(&model.WebhookDelivery{}, "webhook_id = ?", webhookID).Error; err != nil {
+			log.Printf("error occurred while deleting webhook deliveries from DB: %s", err.Error())
+			return err
+		}
+		return nil
+	})
+}
+
+// CreateDeliveries adds the deliveries to the outbox.
+func (webhook Webhook) CreateDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	err := webhook.db.Omit("Webhook").Create(&deliveries).Error
+	if err != nil {
+		log.Printf("error occurred while saving webhook deliveries in DB: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+// GetDeliveries returns the latest deliveries of the webhook, newest first.
+func (webhook Webhook) GetDeliveries(ctx context.Context, webhookID, limit int) ([]model.WebhookDelivery, error) {
+	deliveries := make([]model.WebhookDelivery, 0)
+	err := webhook.db.Order("id DESC").Limit(limit).Find(&deliveries, "webhook_id = $1", webhookID).Error
+	if err != nil {
+		log.Printf("error occurred while fetching webhook deliveries from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// GetDueDeliveries returns the pending deliveries which are due at now, oldest first, along with their webhook.
+func (webhook Webhook) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	deliveries := make([]model.WebhookDelivery, 0)
+	err := webhook.db.Preload("Webhook").Order("next_attempt_at").Limit(limit).
+		Find(&deliveries, "status = $1 AND next_attempt_at <= $2", model.DeliveryPending, now).Error
+	if err != nil {
+		log.Printf("error occurred while fetching due webhook deliveries from DB: %s", err.Error())
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDelivery records the outcome of an attempt of the delivery.
+func (webhook Webhook) UpdateDelivery(ctx context.Context, obj model.WebhookDelivery) error {
+	err := webhook.db.Model(&model.WebhookDelivery{ID: obj.ID}).
+		Select("status", "attempts", "next_attempt_at", "response_status", "error", "delivered_at").
+		Updates(obj).Error
+	if err != nil {
+		log.Printf("error occurred while updating webhook delivery in DB: %s", err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func NewWebhook(db *gorm.DB) Webhook {
+	return Webhook{db: db}
+}
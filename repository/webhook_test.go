@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+	repo Webhook
+	mock sqlmock.Sqlmock
+}
+
+func (suite *WebhookTestSuite) SetupTest() {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		suite.NoError(err)
+	}
+
+	dialector := postgres.New(postgres.Config{
+		Conn:       mockDB,
+		DriverName: "postgres",
+	})
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	suite.NoError(err)
+
+	suite.repo = Webhook{db: db}
+	suite.mock = mock
+}
+
+func (suite *WebhookTestSuite) TestDeleteRemovesDeliveries() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "webhooks" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "webhook_deliveries" WHERE webhook_id = $1`)).
+		WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 3))
+	suite.mock.ExpectCommit()
+
+	suite.NoError(suite.repo.Delete(context.Background(), 1, 2))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *WebhookTestSuite) TestDeleteReturnsErrNoRowsIfWebhookDoesNotExist() {
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM "webhooks" WHERE id = $1 AND user_id = $2`)).
+		WithArgs(2, 1).WillReturnResult(sqlmock.NewResult(0, 0))
+	suite.mock.ExpectRollback()
+
+	suite.Equal(sql.ErrNoRows, suite.repo.Delete(context.Background(), 1, 2))
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *WebhookTestSuite) TestGetDueDeliveriesLoadsTheirWebhooks() {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhook_deliveries" WHERE status = $1 AND next_attempt_at <= $2 ORDER BY next_attempt_at LIMIT 100`)).
+		WithArgs(model.DeliveryPending, now).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "webhook_id", "event"}).AddRow(1, 2, model.WebhookBookingCreated))
+	suite.mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "webhooks" WHERE "webhooks"."id" = $1`)).
+		WithArgs(2).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "url"}).AddRow(2, 1, "https://example.xyz/hook"))
+
+	deliveries, err := suite.repo.GetDueDeliveries(context.Background(), now, 100)
+	suite.NoError(err)
+	suite.Len(deliveries, 1)
+	suite.Equal("https://example.xyz/hook", deliveries[0].Webhook.URL)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func (suite *WebhookTestSuite) TestUpdateDeliveryOnlyUpdatesTheOutcome() {
+	next := time.Date(2023, 6, 1, 0, 1, 0, 0, time.UTC)
+	suite.mock.ExpectBegin()
+	suite.mock.ExpectExec(regexp.QuoteMeta(`UPDATE "webhook_deliveries" SET "status"=$1,"attempts"=$2,"next_attempt_at"=$3,"response_status"=$4,"error"=$5,"delivered_at"=$6,"updated_at"=$7 WHERE "id" = $8`)).
+		WithArgs(model.DeliveryPending, 1, next, 500, "webhook returned status 500", time.Time{}, sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	suite.mock.ExpectCommit()
+
+	err := suite.repo.UpdateDelivery(context.Background(), model.WebhookDelivery{ID: 3, WebhookID: 2, Event: model.WebhookBookingCreated, Attempts: 1,
+		NextAttemptAt: next, ResponseStatus: 500, Error: "webhook returned status 500"})
+	suite.NoError(err)
+	suite.NoError(suite.mock.ExpectationsWereMet())
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
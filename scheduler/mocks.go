@@ -0,0 +1,67 @@
+package scheduler
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockSlotService struct {
+	mock.Mock
+}
+
+func (mock *MockSlotService) Expire(ctx context.Context) (int64, error) {
+	args := mock.Called(ctx)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (mock *MockSlotService) Sync(ctx context.Context, horizonDays int) (int, error) {
+	args := mock.Called(ctx, horizonDays)
+	return args.Int(0), args.Error(1)
+}
+
+type MockExternalCalendarService struct {
+	mock.Mock
+}
+
+func (mock *MockExternalCalendarService) SyncAll(ctx context.Context) (int, error) {
+	args := mock.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+type MockLocker struct {
+	mock.Mock
+}
+
+func (mock *MockLocker) TryLock(ctx context.Context, key int64) (func(), bool, error) {
+	args := mock.Called(ctx, key)
+	unlock, _ := args.Get(0).(func())
+	return unlock, args.Bool(1), args.Error(2)
+}
+
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (mock *MockWebhookService) DeliverDue(ctx context.Context) (int, error) {
+	args := mock.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+type MockNotificationService struct {
+	mock.Mock
+}
+
+func (mock *MockNotificationService) SendDue(ctx context.Context) (int, error) {
+	args := mock.Called(ctx)
+	return args.Int(0), args.Error(1)
+}
+
+type MockJobService struct {
+	mock.Mock
+}
+
+func (mock *MockJobService) RunNext(ctx context.Context) (bool, error) {
+	args := mock.Called(ctx)
+	return args.Bool(0), args.Error(1)
+}
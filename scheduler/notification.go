@@ -0,0 +1,76 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/database"
+	"github.com/harbor-xyz/coding-project/mail"
+	"github.com/harbor-xyz/coding-project/repository"
+	"github.com/harbor-xyz/coding-project/service"
+)
+
+// notificationLockKey identifies the Postgres advisory lock making sure only one instance sends notifications at a
+// time
+const notificationLockKey int64 = 7_310_003
+
+type NotificationService interface {
+	SendDue(context.Context) (int, error)
+}
+
+// NotificationDispatcher works through the outbox of notifications, sending the ones which are due by email.
+type NotificationDispatcher struct {
+	notificationService NotificationService
+	locker              Locker
+	interval            time.Duration
+}
+
+// Run sends the due notifications straight away and then on every interval, until ctx is done.
+func (dispatcher NotificationDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.interval)
+	defer ticker.Stop()
+
+	for {
+		dispatcher.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (dispatcher NotificationDispatcher) runOnce(ctx context.Context) {
+	unlock, acquired, err := dispatcher.locker.TryLock(ctx, notificationLockKey)
+	if err != nil || !acquired {
+		return
+	}
+	defer unlock()
+
+	sent, err := dispatcher.notificationService.SendDue(ctx)
+	if err != nil {
+		log.Printf("error occurred while sending notifications: %s", err.Error())
+	} else if sent > 0 {
+		log.Printf("sent %d notifications", sent)
+	}
+}
+
+func NewNotificationDispatcher(notificationService NotificationService, locker Locker, interval time.Duration) NotificationDispatcher {
+	return NotificationDispatcher{notificationService: notificationService, locker: locker, interval: interval}
+}
+
+// InitNotificationDispatcher wires the notification dispatcher with the database repositories and the SMTP server of
+// the config, or with a notifier logging the emails when there is none.
+func InitNotificationDispatcher(config Config) NotificationDispatcher {
+	db := database.Get()
+	var notifier service.Notifier = mail.Log{}
+	if config.SMTPHost != "" {
+		notifier = mail.NewSMTP(config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword)
+	} else {
+		log.Printf("SMTP_HOST is not set, notifications will not be sent")
+	}
+	notificationService := service.NewNotification(repository.NewNotification(db), repository.NewEvent(db), repository.NewUser(db), repository.NewEventType(db), notifier,
+		config.MailFrom)
+	return NewNotificationDispatcher(notificationService, repository.NewLock(db), config.NotificationInterval)
+}
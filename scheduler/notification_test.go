@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationDispatcherTestSuite struct {
+	suite.Suite
+	mockNotificationService *MockNotificationService
+	mockLocker              *MockLocker
+	dispatcher              NotificationDispatcher
+	ctx                     context.Context
+}
+
+func (suite *NotificationDispatcherTestSuite) SetupTest() {
+	suite.mockNotificationService = &MockNotificationService{}
+	suite.mockLocker = &MockLocker{}
+	suite.dispatcher = NewNotificationDispatcher(suite.mockNotificationService, suite.mockLocker, time.Second)
+	suite.ctx = context.Background()
+}
+
+func (suite *NotificationDispatcherTestSuite) TestRunOnceSendsWhileHoldingTheLock() {
+	unlocked := false
+	suite.mockLocker.On("TryLock", suite.ctx, notificationLockKey).Return(func() { unlocked = true }, true, nil)
+	suite.mockNotificationService.On("SendDue", suite.ctx).Return(2, nil).Run(func(mock.Arguments) {
+		suite.False(unlocked)
+	})
+
+	suite.dispatcher.runOnce(suite.ctx)
+
+	suite.True(unlocked)
+	suite.mockNotificationService.AssertExpectations(suite.T())
+}
+
+func (suite *NotificationDispatcherTestSuite) TestRunOnceSkipsSendingWhenAnotherInstanceHoldsTheLock() {
+	suite.mockLocker.On("TryLock", suite.ctx, notificationLockKey).Return(nil, false, nil)
+
+	suite.dispatcher.runOnce(suite.ctx)
+
+	suite.mockNotificationService.AssertNotCalled(suite.T(), "SendDue", mock.Anything)
+}
+
+func TestNotificationDispatcherTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationDispatcherTestSuite))
+}
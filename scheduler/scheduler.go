@@ -0,0 +1,250 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/mail"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/database"
+	"github.com/harbor-xyz/coding-project/repository"
+	"github.com/harbor-xyz/coding-project/service"
+)
+
+// lockKey identifies the Postgres advisory lock making sure only one instance runs the jobs at a time
+const lockKey int64 = 7_310_001
+
+const (
+	defaultHorizonDays     = 14
+	defaultInterval        = time.Hour
+	defaultWebhookInterval = 10 * time.Second
+	// defaultNotificationInterval is also the delay of reminders at worst
+	defaultNotificationInterval = 30 * time.Second
+	defaultSMTPPort             = 587
+	defaultMailFrom             = "calendly <noreply@localhost>"
+	defaultJobWorkers           = 4
+	// defaultJobPollInterval is how long idle workers wait before looking for due jobs again
+	defaultJobPollInterval = time.Second
+)
+
+// defaultReminders are sent a day and an hour before events
+var defaultReminders = []time.Duration{24 * time.Hour, time.Hour}
+
+type SlotService interface {
+	Expire(context.Context) (int64, error)
+	Sync(context.Context, int) (int, error)
+}
+
+type ExternalCalendarService interface {
+	SyncAll(context.Context) (int, error)
+}
+
+type Locker interface {
+	TryLock(context.Context, int64) (func(), bool, error)
+}
+
+type Config struct {
+	// HorizonDays is how many days ahead slots are kept generated
+	HorizonDays int
+	// Interval is how often the jobs run
+	Interval time.Duration
+	// WebhookInterval is how often due webhook deliveries are attempted
+	WebhookInterval time.Duration
+	// NotificationInterval is how often due notifications are sent
+	NotificationInterval time.Duration
+	// SMTPHost is the SMTP server notifications are sent through. Without it they are logged and dropped.
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	// MailFrom is the sender of notifications
+	MailFrom mail.Address
+	// Reminders are how long before the start of events their host and invitee are reminded of them
+	Reminders []time.Duration
+	// JobWorkers is how many jobs of the queue are run at the same time by this instance
+	JobWorkers int
+	// JobPollInterval is how often idle workers look for due jobs
+	JobPollInterval time.Duration
+	// CalendarNetworks are the networks external calendars may be fetched from although they are not public
+	CalendarNetworks []*net.IPNet
+}
+
+// ConfigFromEnv reads the configuration from SLOT_HORIZON_DAYS, SCHEDULER_INTERVAL, WEBHOOK_INTERVAL and
+// NOTIFICATION_INTERVAL, falling back to 14 days, 1 hour, 10 seconds and 30 seconds respectively. Notifications are
+// sent through SMTP_HOST and SMTP_PORT, 587 by default, authenticating with SMTP_USERNAME and SMTP_PASSWORD if set,
+// from MAIL_FROM. REMINDERS is a comma separated list of durations such as "24h,1h", which is the default, and an
+// empty list turns reminders off. JOB_WORKERS and JOB_POLL_INTERVAL default to 4 workers polling every second.
+// CALENDAR_NETWORKS is a comma separated list of networks, such as "127.0.0.1/32", whose calendars may be fetched.
+func ConfigFromEnv() (Config, error) {
+	from, _ := mail.ParseAddress(defaultMailFrom)
+	config := Config{
+		HorizonDays:          defaultHorizonDays,
+		Interval:             defaultInterval,
+		WebhookInterval:      defaultWebhookInterval,
+		NotificationInterval: defaultNotificationInterval,
+		SMTPHost:             os.Getenv("SMTP_HOST"),
+		SMTPPort:             defaultSMTPPort,
+		SMTPUsername:         os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:         os.Getenv("SMTP_PASSWORD"),
+		MailFrom:             *from,
+		Reminders:            defaultReminders,
+		JobWorkers:           defaultJobWorkers,
+		JobPollInterval:      defaultJobPollInterval,
+	}
+	if value := os.Getenv("SLOT_HORIZON_DAYS"); value != "" {
+		horizonDays, err := strconv.Atoi(value)
+		if err != nil || horizonDays <= 0 {
+			return Config{}, fmt.Errorf("invalid SLOT_HORIZON_DAYS: %s", value)
+		}
+		config.HorizonDays = horizonDays
+	}
+	if value := os.Getenv("SCHEDULER_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return Config{}, fmt.Errorf("invalid SCHEDULER_INTERVAL: %s", value)
+		}
+		config.Interval = interval
+	}
+	if value := os.Getenv("WEBHOOK_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return Config{}, fmt.Errorf("invalid WEBHOOK_INTERVAL: %s", value)
+		}
+		config.WebhookInterval = interval
+	}
+	if value := os.Getenv("NOTIFICATION_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return Config{}, fmt.Errorf("invalid NOTIFICATION_INTERVAL: %s", value)
+		}
+		config.NotificationInterval = interval
+	}
+	if value := os.Getenv("SMTP_PORT"); value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil || port <= 0 || port > 65535 {
+			return Config{}, fmt.Errorf("invalid SMTP_PORT: %s", value)
+		}
+		config.SMTPPort = port
+	}
+	if value := os.Getenv("MAIL_FROM"); value != "" {
+		from, err := mail.ParseAddress(value)
+		if err != nil {
+			return Config{}, fmt.Errorf("invalid MAIL_FROM: %s", value)
+		}
+		config.MailFrom = *from
+	}
+	if value, ok := os.LookupEnv("REMINDERS"); ok {
+		config.Reminders = make([]time.Duration, 0)
+		for _, item := range strings.Split(value, ",") {
+			item = strings.TrimSpace(item)
+			if item == "" {
+				continue
+			}
+			reminder, err := time.ParseDuration(item)
+			if err != nil || reminder <= 0 {
+				return Config{}, fmt.Errorf("invalid REMINDERS: %s", value)
+			}
+			config.Reminders = append(config.Reminders, reminder)
+		}
+	}
+	if value := os.Getenv("JOB_WORKERS"); value != "" {
+		workers, err := strconv.Atoi(value)
+		if err != nil || workers <= 0 {
+			return Config{}, fmt.Errorf("invalid JOB_WORKERS: %s", value)
+		}
+		config.JobWorkers = workers
+	}
+	if value := os.Getenv("JOB_POLL_INTERVAL"); value != "" {
+		interval, err := time.ParseDuration(value)
+		if err != nil || interval <= 0 {
+			return Config{}, fmt.Errorf("invalid JOB_POLL_INTERVAL: %s", value)
+		}
+		config.JobPollInterval = interval
+	}
+	networks, err := service.ParseNetworks(os.Getenv("CALENDAR_NETWORKS"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid CALENDAR_NETWORKS: %s", err.Error())
+	}
+	config.CalendarNetworks = networks
+	return config, nil
+}
+
+// Scheduler periodically expires past slots, rolls slot generation forward and syncs the external calendars which
+// have a URL. Several instances can run at the same time, only the one holding the lock does the work on each tick.
+type Scheduler struct {
+	slotService             SlotService
+	externalCalendarService ExternalCalendarService
+	locker                  Locker
+	config                  Config
+}
+
+// Run runs the jobs straight away and then on every interval, until ctx is done.
+func (scheduler Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(scheduler.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		scheduler.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (scheduler Scheduler) runOnce(ctx context.Context) {
+	unlock, acquired, err := scheduler.locker.TryLock(ctx, lockKey)
+	if err != nil || !acquired {
+		return
+	}
+	defer unlock()
+
+	expired, err := scheduler.slotService.Expire(ctx)
+	if err != nil {
+		log.Printf("error occurred while expiring slots: %s", err.Error())
+	} else if expired > 0 {
+		log.Printf("expired %d slots", expired)
+	}
+
+	created, err := scheduler.slotService.Sync(ctx, scheduler.config.HorizonDays)
+	if err != nil {
+		log.Printf("error occurred while syncing slots: %s", err.Error())
+	} else if created > 0 {
+		log.Printf("created %d slots", created)
+	}
+
+	synced, err := scheduler.externalCalendarService.SyncAll(ctx)
+	if err != nil {
+		log.Printf("error occurred while syncing external calendars: %s", err.Error())
+	} else if synced > 0 {
+		log.Printf("synced %d external calendars", synced)
+	}
+}
+
+func New(slotService SlotService, externalCalendarService ExternalCalendarService, locker Locker, config Config) Scheduler {
+	return Scheduler{slotService: slotService, externalCalendarService: externalCalendarService, locker: locker, config: config}
+}
+
+// Init wires the scheduler with the database repositories.
+func Init(config Config) Scheduler {
+	db := database.Get()
+	availabilityRepository := repository.NewUserAvailability(db)
+	eventRepository := repository.NewEvent(db)
+	slotService := service.NewSlot(
+		repository.NewSlot(db),
+		availabilityRepository,
+		repository.NewAvailabilityOverride(db),
+		repository.NewEventType(db),
+		eventRepository,
+		repository.NewBusyBlock(db),
+	)
+	externalCalendarService := service.NewExternalCalendar(repository.NewExternalCalendar(db), availabilityRepository, eventRepository,
+		service.NewCalendarClient(config.CalendarNetworks))
+	return New(slotService, externalCalendarService, repository.NewLock(db), config)
+}
@@ -0,0 +1,130 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net/mail"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	mockSlotService             *MockSlotService
+	mockExternalCalendarService *MockExternalCalendarService
+	mockLocker                  *MockLocker
+	scheduler                   Scheduler
+	ctx                         context.Context
+}
+
+func (suite *SchedulerTestSuite) SetupTest() {
+	suite.mockSlotService = &MockSlotService{}
+	suite.mockExternalCalendarService = &MockExternalCalendarService{}
+	suite.mockLocker = &MockLocker{}
+	suite.scheduler = New(suite.mockSlotService, suite.mockExternalCalendarService, suite.mockLocker, Config{HorizonDays: 7, Interval: time.Hour})
+	suite.ctx = context.Background()
+}
+
+func (suite *SchedulerTestSuite) TestRunOnceRunsJobsWhileHoldingTheLock() {
+	unlocked := false
+	suite.mockLocker.On("TryLock", suite.ctx, lockKey).Return(func() { unlocked = true }, true, nil)
+	suite.mockSlotService.On("Expire", suite.ctx).Return(int64(2), nil).Run(func(mock.Arguments) {
+		suite.False(unlocked)
+	})
+	suite.mockSlotService.On("Sync", suite.ctx, 7).Return(10, nil)
+	suite.mockExternalCalendarService.On("SyncAll", suite.ctx).Return(1, nil).Run(func(mock.Arguments) {
+		suite.False(unlocked)
+	})
+
+	suite.scheduler.runOnce(suite.ctx)
+
+	suite.True(unlocked)
+	suite.mockSlotService.AssertExpectations(suite.T())
+	suite.mockExternalCalendarService.AssertExpectations(suite.T())
+}
+
+func (suite *SchedulerTestSuite) TestRunOnceCarriesOnWhenAJobFails() {
+	suite.mockLocker.On("TryLock", suite.ctx, lockKey).Return(func() {}, true, nil)
+	suite.mockSlotService.On("Expire", suite.ctx).Return(int64(0), errors.New("some error"))
+	suite.mockSlotService.On("Sync", suite.ctx, 7).Return(0, errors.New("some error"))
+	suite.mockExternalCalendarService.On("SyncAll", suite.ctx).Return(0, nil)
+
+	suite.scheduler.runOnce(suite.ctx)
+
+	suite.mockSlotService.AssertExpectations(suite.T())
+	suite.mockExternalCalendarService.AssertExpectations(suite.T())
+}
+
+func (suite *SchedulerTestSuite) TestRunOnceSkipsJobsWhenAnotherInstanceHoldsTheLock() {
+	suite.mockLocker.On("TryLock", suite.ctx, lockKey).Return(nil, false, nil)
+
+	suite.scheduler.runOnce(suite.ctx)
+
+	suite.mockSlotService.AssertNotCalled(suite.T(), "Expire", mock.Anything)
+	suite.mockSlotService.AssertNotCalled(suite.T(), "Sync", mock.Anything, mock.Anything)
+	suite.mockExternalCalendarService.AssertNotCalled(suite.T(), "SyncAll", mock.Anything)
+}
+
+func (suite *SchedulerTestSuite) TestRunStopsWhenContextIsCancelled() {
+	ctx, cancel := context.WithCancel(suite.ctx)
+	suite.mockLocker.On("TryLock", ctx, lockKey).Return(nil, false, nil).Run(func(mock.Arguments) {
+		cancel()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		suite.scheduler.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		suite.Fail("scheduler did not stop after the context was cancelled")
+	}
+}
+
+func (suite *SchedulerTestSuite) TestConfigFromEnv() {
+	suite.T().Setenv("SLOT_HORIZON_DAYS", "30")
+	suite.T().Setenv("SCHEDULER_INTERVAL", "15m")
+	suite.T().Setenv("WEBHOOK_INTERVAL", "5s")
+	suite.T().Setenv("NOTIFICATION_INTERVAL", "1m")
+	suite.T().Setenv("SMTP_HOST", "smtp.example.xyz")
+	suite.T().Setenv("SMTP_PORT", "2525")
+	suite.T().Setenv("SMTP_USERNAME", "user")
+	suite.T().Setenv("SMTP_PASSWORD", "password")
+	suite.T().Setenv("MAIL_FROM", "Bookings <bookings@example.xyz>")
+	suite.T().Setenv("REMINDERS", "48h, 30m")
+	suite.T().Setenv("JOB_WORKERS", "8")
+	suite.T().Setenv("JOB_POLL_INTERVAL", "500ms")
+
+	config, err := ConfigFromEnv()
+	suite.NoError(err)
+	suite.Equal(Config{HorizonDays: 30, Interval: 15 * time.Minute, WebhookInterval: 5 * time.Second, NotificationInterval: time.Minute,
+		SMTPHost: "smtp.example.xyz", SMTPPort: 2525, SMTPUsername: "user", SMTPPassword: "password",
+		MailFrom: mail.Address{Name: "Bookings", Address: "bookings@example.xyz"}, Reminders: []time.Duration{48 * time.Hour, 30 * time.Minute},
+		JobWorkers: 8, JobPollInterval: 500 * time.Millisecond}, config)
+}
+
+func (suite *SchedulerTestSuite) TestConfigFromEnvTurnsRemindersOffWhenEmpty() {
+	suite.T().Setenv("REMINDERS", "")
+
+	config, err := ConfigFromEnv()
+	suite.NoError(err)
+	suite.Empty(config.Reminders)
+	suite.Equal(defaultJobWorkers, config.JobWorkers)
+}
+
+func (suite *SchedulerTestSuite) TestConfigFromEnvRejectsInvalidValues() {
+	suite.T().Setenv("SLOT_HORIZON_DAYS", "-1")
+
+	_, err := ConfigFromEnv()
+	suite.EqualError(err, "invalid SLOT_HORIZON_DAYS: -1")
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
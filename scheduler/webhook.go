@@ -0,0 +1,66 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/database"
+	"github.com/harbor-xyz/coding-project/repository"
+	"github.com/harbor-xyz/coding-project/service"
+)
+
+// webhookLockKey identifies the Postgres advisory lock making sure only one instance delivers webhooks at a time
+const webhookLockKey int64 = 7_310_002
+
+type WebhookService interface {
+	DeliverDue(context.Context) (int, error)
+}
+
+// WebhookDispatcher works through the outbox of webhook deliveries, attempting the ones which are due. It runs far
+// more often than the Scheduler, so that webhooks are told about bookings within seconds.
+type WebhookDispatcher struct {
+	webhookService WebhookService
+	locker         Locker
+	interval       time.Duration
+}
+
+// Run attempts the due deliveries straight away and then on every interval, until ctx is done.
+func (dispatcher WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.interval)
+	defer ticker.Stop()
+
+	for {
+		dispatcher.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (dispatcher WebhookDispatcher) runOnce(ctx context.Context) {
+	unlock, acquired, err := dispatcher.locker.TryLock(ctx, webhookLockKey)
+	if err != nil || !acquired {
+		return
+	}
+	defer unlock()
+
+	delivered, err := dispatcher.webhookService.DeliverDue(ctx)
+	if err != nil {
+		log.Printf("error occurred while delivering webhooks: %s", err.Error())
+	} else if delivered > 0 {
+		log.Printf("delivered %d webhooks", delivered)
+	}
+}
+
+func NewWebhookDispatcher(webhookService WebhookService, locker Locker, interval time.Duration) WebhookDispatcher {
+	return WebhookDispatcher{webhookService: webhookService, locker: locker, interval: interval}
+}
+
+// InitWebhookDispatcher wires the webhook dispatcher with the database repositories.
+func InitWebhookDispatcher(config Config) WebhookDispatcher {
+	db := database.Get()
+	return NewWebhookDispatcher(service.NewWebhook(repository.NewWebhook(db)), repository.NewLock(db), config.WebhookInterval)
+}
@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookDispatcherTestSuite struct {
+	suite.Suite
+	mockWebhookService *MockWebhookService
+	mockLocker         *MockLocker
+	dispatcher         WebhookDispatcher
+	ctx                context.Context
+}
+
+func (suite *WebhookDispatcherTestSuite) SetupTest() {
+	suite.mockWebhookService = &MockWebhookService{}
+	suite.mockLocker = &MockLocker{}
+	suite.dispatcher = NewWebhookDispatcher(suite.mockWebhookService, suite.mockLocker, time.Second)
+	suite.ctx = context.Background()
+}
+
+func (suite *WebhookDispatcherTestSuite) TestRunOnceDeliversWhileHoldingTheLock() {
+	unlocked := false
+	suite.mockLocker.On("TryLock", suite.ctx, webhookLockKey).Return(func() { unlocked = true }, true, nil)
+	suite.mockWebhookService.On("DeliverDue", suite.ctx).Return(3, nil).Run(func(mock.Arguments) {
+		suite.False(unlocked)
+	})
+
+	suite.dispatcher.runOnce(suite.ctx)
+
+	suite.True(unlocked)
+	suite.mockWebhookService.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookDispatcherTestSuite) TestRunOnceReleasesTheLockWhenDeliveryFails() {
+	unlocked := false
+	suite.mockLocker.On("TryLock", suite.ctx, webhookLockKey).Return(func() { unlocked = true }, true, nil)
+	suite.mockWebhookService.On("DeliverDue", suite.ctx).Return(0, errors.New("some error"))
+
+	suite.dispatcher.runOnce(suite.ctx)
+
+	suite.True(unlocked)
+}
+
+func (suite *WebhookDispatcherTestSuite) TestRunOnceSkipsDeliveryWhenAnotherInstanceHoldsTheLock() {
+	suite.mockLocker.On("TryLock", suite.ctx, webhookLockKey).Return(nil, false, nil)
+
+	suite.dispatcher.runOnce(suite.ctx)
+
+	suite.mockWebhookService.AssertNotCalled(suite.T(), "DeliverDue", mock.Anything)
+}
+
+func TestWebhookDispatcherTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookDispatcherTestSuite))
+}
@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/database"
+	"github.com/harbor-xyz/coding-project/repository"
+	"github.com/harbor-xyz/coding-project/service"
+)
+
+type JobService interface {
+	RunNext(context.Context) (bool, error)
+}
+
+// Worker runs the jobs of the queue with a pool of goroutines. Jobs are claimed with FOR UPDATE SKIP LOCKED, so any
+// number of instances can run workers at the same time without a lock.
+type Worker struct {
+	jobService  JobService
+	concurrency int
+	interval    time.Duration
+}
+
+// Run runs due jobs until ctx is done, and then waits for the jobs already claimed to finish before returning.
+func (worker Worker) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < worker.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker.loop(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// loop runs one job after the other, waiting for the interval whenever none is due.
+func (worker Worker) loop(ctx context.Context) {
+	// Jobs are not cancelled on shutdown, they are left to finish within their lease instead
+	jobCtx := context.WithoutCancel(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+
+		ran, err := worker.jobService.RunNext(jobCtx)
+		if err != nil {
+			log.Printf("error occurred while running job: %s", err.Error())
+		}
+		if ran && err == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(worker.interval):
+		}
+	}
+}
+
+func NewWorker(jobService JobService, concurrency int, interval time.Duration) Worker {
+	return Worker{jobService: jobService, concurrency: concurrency, interval: interval}
+}
+
+// InitWorker wires the worker pool with the database repositories and the handlers of the side effects of bookings
+// and availability changes.
+func InitWorker(config Config) Worker {
+	db := database.Get()
+	eventRepository := repository.NewEvent(db)
+	userRepository := repository.NewUser(db)
+	eventTypeRepository := repository.NewEventType(db)
+	availabilityRepository := repository.NewUserAvailability(db)
+	slotService := service.NewSlot(repository.NewSlot(db), availabilityRepository, repository.NewAvailabilityOverride(db), eventTypeRepository,
+		eventRepository, repository.NewBusyBlock(db))
+	effects := service.NewEffects(eventRepository, userRepository, eventTypeRepository, repository.NewExternalCalendar(db), availabilityRepository,
+		repository.NewWebhook(db), repository.NewNotification(db), slotService, service.NewCalendarClient(config.CalendarNetworks), config.Reminders,
+		config.HorizonDays)
+	jobService := service.NewJob(repository.NewJob(db), effects.Handlers())
+	return NewWorker(jobService, config.JobWorkers, config.JobPollInterval)
+}
@@ -0,0 +1,91 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type WorkerTestSuite struct {
+	suite.Suite
+	mockJobService *MockJobService
+}
+
+func (suite *WorkerTestSuite) SetupTest() {
+	suite.mockJobService = &MockJobService{}
+}
+
+func (suite *WorkerTestSuite) TestRunRunsDueJobsBackToBack() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	suite.mockJobService.On("RunNext", mock.Anything).Return(true, nil).Times(3)
+	suite.mockJobService.On("RunNext", mock.Anything).Return(false, nil).Run(func(mock.Arguments) { cancel() }).Once()
+	worker := NewWorker(suite.mockJobService, 1, time.Hour)
+
+	done := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		suite.Fail("worker waited for the interval although jobs were due")
+	}
+	suite.mockJobService.AssertNumberOfCalls(suite.T(), "RunNext", 4)
+}
+
+func (suite *WorkerTestSuite) TestRunWaitsForTheIntervalAfterErrors() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	suite.mockJobService.On("RunNext", mock.Anything).Return(true, errors.New("some error")).Run(func(mock.Arguments) { cancel() }).Once()
+	worker := NewWorker(suite.mockJobService, 1, time.Hour)
+
+	worker.Run(ctx)
+
+	suite.mockJobService.AssertNumberOfCalls(suite.T(), "RunNext", 1)
+}
+
+func (suite *WorkerTestSuite) TestRunWaitsForRunningJobsBeforeReturning() {
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	suite.mockJobService.On("RunNext", mock.Anything).Return(true, nil).Run(func(args mock.Arguments) {
+		// Jobs keep running on shutdown
+		suite.NoError(args.Get(0).(context.Context).Err())
+		started <- struct{}{}
+		<-release
+	}).Twice()
+	worker := NewWorker(suite.mockJobService, 2, time.Hour)
+
+	done := make(chan struct{})
+	go func() {
+		worker.Run(ctx)
+		close(done)
+	}()
+	<-started
+	<-started
+	cancel()
+
+	select {
+	case <-done:
+		suite.Fail("worker returned before its jobs finished")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		suite.Fail("worker did not stop after the context was cancelled")
+	}
+	suite.mockJobService.AssertNumberOfCalls(suite.T(), "RunNext", 2)
+}
+
+func TestWorkerTestSuite(t *testing.T) {
+	suite.Run(t, new(WorkerTestSuite))
+}
@@ -0,0 +1,48 @@
+package server
+
+import (
+	"crypto/rand"
+	"fmt"
+	"log"
+	"net"
+	"os"
+
+	"github.com/harbor-xyz/coding-project/service"
+)
+
+type Config struct {
+	// BookingTokenSecret signs the tokens invitees use to manage their bookings
+	BookingTokenSecret []byte
+	// AdminToken is the bearer token of the admin APIs, which are disabled without it
+	AdminToken string
+	// JWTSecret verifies the HS256 JWTs users may authenticate with instead of API keys. Only API keys are accepted
+	// without it.
+	JWTSecret []byte
+	// CalendarNetworks are the networks external calendars may be fetched from although they are not public, such as
+	// the one of a local test server
+	CalendarNetworks []*net.IPNet
+}
+
+// ConfigFromEnv reads the configuration from BOOKING_TOKEN_SECRET, ADMIN_TOKEN, JWT_SECRET and CALENDAR_NETWORKS, a
+// comma separated list of networks such as "127.0.0.1/32". Without a secret a random one is used, so the tokens handed
+// out stop working when the server restarts.
+func ConfigFromEnv() (Config, error) {
+	config := Config{BookingTokenSecret: []byte(os.Getenv("BOOKING_TOKEN_SECRET")), AdminToken: os.Getenv("ADMIN_TOKEN"),
+		JWTSecret: []byte(os.Getenv("JWT_SECRET"))}
+	if len(config.BookingTokenSecret) == 0 {
+		log.Printf("BOOKING_TOKEN_SECRET is not set, booking tokens will not survive a restart")
+		config.BookingTokenSecret = make([]byte, 32)
+		if _, err := rand.Read(config.BookingTokenSecret); err != nil {
+			return Config{}, err
+		}
+	}
+	networks, err := service.ParseNetworks(os.Getenv("CALENDAR_NETWORKS"))
+	if err != nil {
+		return Config{}, fmt.Errorf("invalid CALENDAR_NETWORKS: %s", err.Error())
+	}
+	config.CalendarNetworks = networks
+	if config.AdminToken == "" {
+		log.Printf("ADMIN_TOKEN is not set, the admin APIs are disabled")
+	}
+	return config, nil
+}
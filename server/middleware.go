@@ -2,29 +2,86 @@ package server
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/render"
 
 	"github.com/harbor-xyz/coding-project/contract"
 	"github.com/harbor-xyz/coding-project/controller"
+	"github.com/harbor-xyz/coding-project/model"
+	"github.com/harbor-xyz/coding-project/service"
 )
 
 func userIDContext(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		userID := chi.URLParam(r, "userID")
 		if userID == "" {
-			render.Render(w, r, contract.ErrorRenderer(errors.New("user ID is required")))
+			render.Render(w, r, contract.ErrorRendererFor(model.Validation("user_id", "user ID is required")))
 			return
 		}
 		id, err := strconv.Atoi(userID)
 		if err != nil {
-			render.Render(w, r, contract.ErrorRenderer(errors.New("invalid user ID")))
+			render.Render(w, r, contract.ErrorRendererFor(model.Validation("user_id", "invalid user ID")))
+			return
 		}
 		ctx := context.WithValue(r.Context(), controller.ContextUserIDKey, id)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
+
+// authenticate only lets through requests carrying an API key or JWT of a user as a bearer token, and puts the ID of
+// that user in the context.
+func authenticate(auth service.Auth) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			credential, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !found || credential == "" {
+				render.Render(w, r, contract.ErrorRendererFor(model.Unauthorized("an API key or token is required")))
+				return
+			}
+
+			callerID, err := auth.Authenticate(r.Context(), credential)
+			if err != nil {
+				if !errors.Is(err, model.ErrInvalidCredentials) {
+					log.Printf("error occurred while authenticating request: %s", err.Error())
+				}
+				render.Render(w, r, contract.ErrorRendererFor(err))
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), controller.ContextCallerIDKey, callerID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// authorizeUser only lets users through to their own resources. It runs after authenticate and userIDContext.
+func authorizeUser(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if ctx.Value(controller.ContextCallerIDKey) != ctx.Value(controller.ContextUserIDKey) {
+			render.Render(w, r, contract.ErrorRendererFor(model.ErrForbidden))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// adminAuth only lets through requests carrying the admin token as a bearer token.
+func adminAuth(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+				render.Render(w, r, contract.ErrorRendererFor(model.Unauthorized("a valid admin token is required")))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/harbor-xyz/coding-project/model"
+	"github.com/harbor-xyz/coding-project/service"
+)
+
+type MiddlewareTestSuite struct {
+	suite.Suite
+	router               *chi.Mux
+	mockAPIKeyRepository *service.MockAPIKeyRepository
+}
+
+func (suite *MiddlewareTestSuite) SetupTest() {
+	suite.mockAPIKeyRepository = &service.MockAPIKeyRepository{}
+	suite.mockAPIKeyRepository.On("GetByHash", mock.Anything, mock.Anything).Return(model.APIKey{}, sql.ErrNoRows).Maybe()
+	suite.mockAPIKeyRepository.On("SetLastUsedAt", mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	suite.router = chi.NewRouter()
+	suite.router.Route("/users/{userID}", func(r chi.Router) {
+		r.Use(authenticate(service.NewAuth(suite.mockAPIKeyRepository, []byte("secret"))), userIDContext, authorizeUser)
+		r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+	})
+}
+
+// authenticates makes every API key authenticate the user
+func (suite *MiddlewareTestSuite) authenticates(userID uint) {
+	suite.mockAPIKeyRepository.ExpectedCalls = nil
+	suite.mockAPIKeyRepository.On("GetByHash", mock.Anything, mock.Anything).Return(model.APIKey{ID: 2, UserID: userID}, nil)
+	suite.mockAPIKeyRepository.On("SetLastUsedAt", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+}
+
+func (suite *MiddlewareTestSuite) get(target, authorization string) int {
+	req := httptest.NewRequest(http.MethodGet, target, nil).WithContext(context.Background())
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	return w.Result().StatusCode
+}
+
+func (suite *MiddlewareTestSuite) TestUsersCanAccessTheirOwnResources() {
+	suite.authenticates(1)
+
+	suite.Equal(http.StatusOK, suite.get("/users/1/", "Bearer cal_key"))
+}
+
+func (suite *MiddlewareTestSuite) TestUsersCannotAccessResourcesOfOtherUsers() {
+	suite.authenticates(2)
+
+	suite.Equal(http.StatusForbidden, suite.get("/users/1/", "Bearer cal_key"))
+}
+
+func (suite *MiddlewareTestSuite) TestRequestsWithoutValidCredentialsAreUnauthorized() {
+	suite.Equal(http.StatusUnauthorized, suite.get("/users/1/", ""))
+	suite.Equal(http.StatusUnauthorized, suite.get("/users/1/", "cal_key"))
+	suite.Equal(http.StatusUnauthorized, suite.get("/users/1/", "Bearer cal_unknown"))
+}
+
+// jwt returns a JWT of the claims signed with the secret of the router
+func (suite *MiddlewareTestSuite) jwt(claims string) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256"}`)) + "." + base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte(payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (suite *MiddlewareTestSuite) TestJWTsWithoutExpiryAreUnauthorized() {
+	exp := time.Now().Add(time.Hour).Unix()
+
+	suite.Equal(http.StatusOK, suite.get("/users/1/", "Bearer "+suite.jwt(fmt.Sprintf(`{"sub":"1","exp":%d}`, exp))))
+	suite.Equal(http.StatusUnauthorized, suite.get("/users/1/", "Bearer "+suite.jwt(`{"sub":"1"}`)))
+}
+
+func (suite *MiddlewareTestSuite) TestInvalidUserIDsAreRejected() {
+	suite.authenticates(1)
+
+	suite.Equal(http.StatusUnprocessableEntity, suite.get("/users/jane/", "Bearer cal_key"))
+}
+
+func TestMiddlewareTestSuite(t *testing.T) {
+	suite.Run(t, new(MiddlewareTestSuite))
+}
@@ -12,7 +12,7 @@ import (
 	"github.com/harbor-xyz/coding-project/service"
 )
 
-func Init() *chi.Mux {
+func Init(config Config) *chi.Mux {
 	r := chi.NewRouter()
 	r.Use(render.SetContentType(render.ContentTypeJSON))
 	r.Use(middleware.Logger)
@@ -23,21 +23,141 @@ func Init() *chi.Mux {
 	userAvailabilityRepository := repository.NewUserAvailability(db)
 	eventRepository := repository.NewEvent(db)
 	slotRepository := repository.NewSlot(db)
+	overrideRepository := repository.NewAvailabilityOverride(db)
+	eventTypeRepository := repository.NewEventType(db)
+	externalCalendarRepository := repository.NewExternalCalendar(db)
+	busyBlockRepository := repository.NewBusyBlock(db)
+	webhookRepository := repository.NewWebhook(db)
+	apiKeyRepository := repository.NewAPIKey(db)
+	auth := service.NewAuth(apiKeyRepository, config.JWTSecret)
 
-	userController := controller.NewUser(service.NewUser(userRepository, userAvailabilityRepository))
-	eventController := controller.NewEvent(service.NewEvent(eventRepository, slotRepository))
-	slotController := controller.NewSlot(service.NewSlot(slotRepository, userAvailabilityRepository))
+	userController := controller.NewUser(service.NewUser(userRepository, userAvailabilityRepository, overrideRepository, eventRepository, busyBlockRepository))
+	eventService := service.NewEvent(eventRepository, slotRepository, userAvailabilityRepository, overrideRepository, eventTypeRepository,
+		userRepository, busyBlockRepository, service.NewBookingTokens(config.BookingTokenSecret))
+	eventController := controller.NewEvent(eventService)
+	bookingController := controller.NewBooking(eventService)
+	slotService := service.NewSlot(slotRepository, userAvailabilityRepository, overrideRepository, eventTypeRepository, eventRepository,
+		busyBlockRepository)
+	slotController := controller.NewSlot(slotService)
+	publicPageController := controller.NewPublicPage(service.NewPublicPage(userRepository, eventTypeRepository, slotService, eventService))
+	eventTypeController := controller.NewEventType(service.NewEventType(eventTypeRepository))
+	externalCalendarController := controller.NewExternalCalendar(service.NewExternalCalendar(externalCalendarRepository, userAvailabilityRepository,
+		eventRepository, service.NewCalendarClient(config.CalendarNetworks)))
+	teamController := controller.NewTeam(service.NewTeam(repository.NewTeam(db), userRepository, eventService))
+	webhookController := controller.NewWebhook(service.NewWebhook(webhookRepository))
+	apiKeyController := controller.NewAPIKey(service.NewAPIKey(apiKeyRepository))
+	// The admin APIs only inspect and retry jobs, which are run by the worker pool
+	jobController := controller.NewJob(service.NewJob(repository.NewJob(db), nil))
 
+	// Only the APIs of invitees, calendar feeds and user registration are open, everything else requires an API key
+	r.With(authenticate(auth)).Get("/availability_overlap", userController.GetFreeOverlap)
+	r.Get("/calendar_feeds/{token}.ics", eventController.GetCalendarFeed)
+	r.Route("/p/{slug}", func(r chi.Router) {
+		r.Get("/", publicPageController.Get)
+		r.Post("/book", publicPageController.Book)
+		r.Get("/{eventTypeSlug}/slots", publicPageController.GetSlots)
+	})
+	r.Route("/bookings/{token}", func(r chi.Router) {
+		r.Get("/", bookingController.Get)
+		r.Post("/cancel", bookingController.Cancel)
+		r.Post("/reschedule", bookingController.Reschedule)
+	})
+	if config.AdminToken != "" {
+		r.Route("/admin", func(r chi.Router) {
+			r.Use(adminAuth(config.AdminToken))
+			r.Route("/jobs", func(r chi.Router) {
+				r.Get("/", jobController.GetAll)
+				r.Get("/{jobID}", jobController.Get)
+				r.Post("/{jobID}/retry", jobController.Retry)
+			})
+		})
+	}
 	r.Route("/users", func(r chi.Router) {
 		r.Post("/", userController.Create)
+		// Listing users reveals their emails, so it is an admin API
+		if config.AdminToken != "" {
+			r.With(adminAuth(config.AdminToken)).Get("/", userController.GetAll)
+		}
 		r.Route("/{userID}", func(r chi.Router) {
-			r.Use(userIDContext)
-			r.Post("/availability", userController.SetAvailability)
-			r.Get("/availability", userController.GetAvailability)
+			r.Use(authenticate(auth), userIDContext, authorizeUser)
+			r.Get("/", userController.Get)
+			r.Patch("/", userController.Update)
+			r.Delete("/", userController.Delete)
+			r.Route("/availability", func(r chi.Router) {
+				r.Post("/", userController.SetAvailability)
+				r.Get("/", userController.GetAvailability)
+				r.Route("/overrides", func(r chi.Router) {
+					r.Post("/", userController.CreateOverride)
+					r.Get("/", userController.GetOverrides)
+					r.Get("/{overrideID}", userController.GetOverride)
+					r.Put("/{overrideID}", userController.UpdateOverride)
+					r.Delete("/{overrideID}", userController.DeleteOverride)
+				})
+			})
 			r.Get("/availability_overlap", userController.GetAvailabilityOverlap)
+			r.Put("/slug", userController.SetSlug)
+			r.Route("/event_types", func(r chi.Router) {
+				r.Post("/", eventTypeController.Create)
+				r.Get("/", eventTypeController.GetAll)
+				r.Get("/{eventTypeID}", eventTypeController.Get)
+				r.Put("/{eventTypeID}", eventTypeController.Update)
+				r.Delete("/{eventTypeID}", eventTypeController.Delete)
+			})
+			r.Route("/teams", func(r chi.Router) {
+				r.Post("/", teamController.Create)
+				r.Get("/", teamController.GetAll)
+				r.Route("/{teamID}", func(r chi.Router) {
+					r.Get("/", teamController.Get)
+					r.Delete("/", teamController.Delete)
+					r.Post("/members", teamController.AddMember)
+					r.Delete("/members/{memberID}", teamController.RemoveMember)
+					r.Route("/event_types", func(r chi.Router) {
+						r.Post("/", teamController.CreateEventType)
+						r.Get("/", teamController.GetEventTypes)
+						r.Get("/{teamEventTypeID}", teamController.GetEventType)
+						r.Delete("/{teamEventTypeID}", teamController.DeleteEventType)
+						r.Get("/{teamEventTypeID}/slots", teamController.GetSlots)
+					})
+					r.Post("/events", teamController.Book)
+				})
+			})
+			r.Route("/team_memberships", func(r chi.Router) {
+				r.Get("/", teamController.GetMemberships)
+				r.Post("/{teamID}/accept", teamController.AcceptMembership)
+				r.Delete("/{teamID}", teamController.LeaveTeam)
+			})
+			r.Route("/external_calendars", func(r chi.Router) {
+				r.Post("/", externalCalendarController.Create)
+				r.Get("/", externalCalendarController.GetAll)
+				r.Post("/{externalCalendarID}/import", externalCalendarController.Import)
+				r.Post("/{externalCalendarID}/sync", externalCalendarController.Sync)
+				r.Delete("/{externalCalendarID}", externalCalendarController.Delete)
+			})
+			r.Route("/api_keys", func(r chi.Router) {
+				r.Post("/", apiKeyController.Create)
+				r.Get("/", apiKeyController.GetAll)
+				r.Delete("/{apiKeyID}", apiKeyController.Revoke)
+			})
+			r.Route("/webhooks", func(r chi.Router) {
+				r.Post("/", webhookController.Create)
+				r.Get("/", webhookController.GetAll)
+				r.Delete("/{webhookID}", webhookController.Delete)
+				r.Get("/{webhookID}/deliveries", webhookController.GetDeliveries)
+			})
+			r.Get("/events.ics", eventController.ExportCalendar)
+			r.Post("/calendar_feed", eventController.CreateCalendarFeed)
 			r.Route("/events", func(r chi.Router) {
 				r.Post("/", eventController.Create)
 				r.Get("/", eventController.GetAll)
+				r.Post("/{eventID}/cancel", eventController.Cancel)
+				r.Post("/{eventID}/reschedule", eventController.Reschedule)
+				r.Get("/{eventID}/changes", eventController.GetChanges)
+			})
+			r.Route("/event_series", func(r chi.Router) {
+				r.Post("/", eventController.CreateSeries)
+				r.Get("/{seriesID}", eventController.GetSeries)
+				r.Post("/{seriesID}/cancel", eventController.CancelSeries)
+				r.Post("/{seriesID}/reschedule", eventController.RescheduleSeries)
 			})
 			r.Route("/slots", func(r chi.Router) {
 				r.Post("/", slotController.Create)
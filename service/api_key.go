@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+const (
+	// apiKeyPrefix starts every API key, so that keys can be told apart from other tokens and spotted when leaked
+	apiKeyPrefix = "cal_"
+	// apiKeyPrefixLength is how many characters of a key are kept to tell keys apart
+	apiKeyPrefixLength = 12
+	// defaultAPIKeyName names the key created along with a user
+	defaultAPIKeyName = "default"
+)
+
+type APIKey struct {
+	apiKeyRepository APIKeyRepository
+}
+
+// Create generates a new API key for the user. The key is only returned here.
+func (apiKey APIKey) Create(ctx context.Context, userID int, input contract.APIKey) (contract.APIKeyResponse, error) {
+	key, obj, err := newAPIKey(input.Name)
+	if err != nil {
+		return contract.APIKeyResponse{}, err
+	}
+	obj.UserID = uint(userID)
+
+	obj, err = apiKey.apiKeyRepository.Create(ctx, obj)
+	if err != nil {
+		return contract.APIKeyResponse{}, err
+	}
+
+	resp := toAPIKeyContract(obj)
+	resp.Key = key
+	return resp, nil
+}
+
+func (apiKey APIKey) GetAll(ctx context.Context, userID int) (contract.APIKeyList, error) {
+	keys, err := apiKey.apiKeyRepository.GetAll(ctx, userID)
+	if err != nil {
+		return contract.APIKeyList{}, err
+	}
+
+	resp := make([]contract.APIKeyResponse, 0)
+	for _, obj := range keys {
+		resp = append(resp, toAPIKeyContract(obj))
+	}
+
+	return contract.APIKeyList{APIKeys: resp}, nil
+}
+
+// Revoke deletes the API key of the user, which stops authenticating requests straight away.
+func (apiKey APIKey) Revoke(ctx context.Context, userID, apiKeyID int) error {
+	err := apiKey.apiKeyRepository.Delete(ctx, userID, apiKeyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("API key", err)
+	}
+	return err
+}
+
+// newAPIKey generates a random key, and returns it along with the record to store for it.
+func newAPIKey(name string) (string, model.APIKey, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", model.APIKey{}, err
+	}
+	key := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	return key, model.APIKey{Name: name, Prefix: key[:apiKeyPrefixLength], KeyHash: hashAPIKey(key)}, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func isAPIKey(credential string) bool {
+	return strings.HasPrefix(credential, apiKeyPrefix)
+}
+
+func toAPIKeyContract(obj model.APIKey) contract.APIKeyResponse {
+	resp := contract.APIKeyResponse{
+		ID:        obj.ID,
+		Name:      obj.Name,
+		Prefix:    obj.Prefix,
+		CreatedAt: obj.CreatedAt,
+	}
+	if !obj.LastUsedAt.IsZero() {
+		lastUsedAt := obj.LastUsedAt
+		resp.LastUsedAt = &lastUsedAt
+	}
+	return resp
+}
+
+func NewAPIKey(apiKeyRepository APIKeyRepository) APIKey {
+	return APIKey{apiKeyRepository: apiKeyRepository}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type APIKeyTestSuite struct {
+	suite.Suite
+	service              APIKey
+	mockAPIKeyRepository *MockAPIKeyRepository
+	ctx                  context.Context
+}
+
+func (suite *APIKeyTestSuite) SetupTest() {
+	suite.mockAPIKeyRepository = &MockAPIKeyRepository{}
+	suite.service = NewAPIKey(suite.mockAPIKeyRepository)
+	suite.ctx = context.Background()
+}
+
+func (suite *APIKeyTestSuite) TestCreateOnlyStoresHashOfKey() {
+	var stored model.APIKey
+	suite.mockAPIKeyRepository.On("Create", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		stored = args.Get(1).(model.APIKey)
+	}).Return(model.APIKey{ID: 2, UserID: 1, Name: "ci", Prefix: "cal_abcdefgh"}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.APIKey{Name: "ci"})
+	suite.NoError(err)
+	suite.True(strings.HasPrefix(resp.Key, apiKeyPrefix))
+	suite.Equal(uint(1), stored.UserID)
+	suite.Equal("ci", stored.Name)
+	suite.Equal(hashAPIKey(resp.Key), stored.KeyHash)
+	suite.NotContains(stored.KeyHash, resp.Key[len(apiKeyPrefix):])
+	suite.Equal(resp.Key[:apiKeyPrefixLength], stored.Prefix)
+}
+
+func (suite *APIKeyTestSuite) TestGetAllNeverReturnsKeys() {
+	lastUsedAt := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.mockAPIKeyRepository.On("GetAll", suite.ctx, 1).Return([]model.APIKey{
+		{ID: 2, UserID: 1, Name: "ci", Prefix: "cal_abcdefgh", KeyHash: "hash", LastUsedAt: lastUsedAt},
+		{ID: 3, UserID: 1, Name: "unused", Prefix: "cal_ijklmnop", KeyHash: "other hash"},
+	}, nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, 1)
+	suite.NoError(err)
+	suite.Equal(contract.APIKeyList{APIKeys: []contract.APIKeyResponse{
+		{ID: 2, Name: "ci", Prefix: "cal_abcdefgh", LastUsedAt: &lastUsedAt},
+		{ID: 3, Name: "unused", Prefix: "cal_ijklmnop"},
+	}}, resp)
+}
+
+func (suite *APIKeyTestSuite) TestRevokeReturnsNotFoundIfKeyDoesNotExist() {
+	suite.mockAPIKeyRepository.On("Delete", suite.ctx, 1, 2).Return(sql.ErrNoRows)
+
+	err := suite.service.Revoke(suite.ctx, 1, 2)
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.EqualError(err, "API key not found: sql: no rows in result set")
+}
+
+func TestAPIKeyTestSuite(t *testing.T) {
+	suite.Run(t, new(APIKeyTestSuite))
+}
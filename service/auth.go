@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// lastUsedPrecision is how stale the last use of an API key may get, so that not every request writes to it
+const lastUsedPrecision = time.Minute
+
+// maxJWTLifetime is how far ahead JWTs may expire, so that a leaked token cannot be used for longer
+const maxJWTLifetime = 24 * time.Hour
+
+// Auth finds the user on whose behalf a request is made. Requests carry either one of the user's API keys, or a JWT
+// signed with HS256 by a system sharing the JWT secret, whose subject is the user ID.
+type Auth struct {
+	apiKeyRepository APIKeyRepository
+	// jwtSecret verifies JWTs, which are not accepted without it
+	jwtSecret []byte
+	now       func() time.Time
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+}
+
+type jwtClaims struct {
+	Sub string `json:"sub"`
+	// Exp is when the token expires, in seconds since the epoch. Tokens without it are rejected, as are tokens
+	// expiring more than maxJWTLifetime ahead.
+	Exp int64 `json:"exp"`
+}
+
+// Authenticate returns the ID of the user the credential belongs to, or model.ErrInvalidCredentials if it belongs
+// to nobody.
+func (auth Auth) Authenticate(ctx context.Context, credential string) (int, error) {
+	if isAPIKey(credential) {
+		return auth.authenticateAPIKey(ctx, credential)
+	}
+	return auth.authenticateJWT(credential)
+}
+
+func (auth Auth) authenticateAPIKey(ctx context.Context, key string) (int, error) {
+	obj, err := auth.apiKeyRepository.GetByHash(ctx, hashAPIKey(key))
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, model.ErrInvalidCredentials
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	now := auth.now()
+	if now.Sub(obj.LastUsedAt) >= lastUsedPrecision {
+		// The request is let through regardless, the last use is only informational
+		if err := auth.apiKeyRepository.SetLastUsedAt(ctx, int(obj.ID), now); err != nil {
+			log.Printf("unable to record use of API key %d: %s", obj.ID, err.Error())
+		}
+	}
+
+	return int(obj.UserID), nil
+}
+
+func (auth Auth) authenticateJWT(token string) (int, error) {
+	if len(auth.jwtSecret) == 0 {
+		return 0, model.ErrInvalidCredentials
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return 0, model.ErrInvalidCredentials
+	}
+	mac := hmac.New(sha256.New, auth.jwtSecret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, mac.Sum(nil)) {
+		return 0, model.ErrInvalidCredentials
+	}
+
+	header := jwtHeader{}
+	if err := decodeJWTSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return 0, model.ErrInvalidCredentials
+	}
+	claims := jwtClaims{}
+	if err := decodeJWTSegment(parts[1], &claims); err != nil {
+		return 0, model.ErrInvalidCredentials
+	}
+	now := auth.now()
+	if claims.Exp == 0 {
+		return 0, model.ErrInvalidCredentials
+	}
+	if exp := time.Unix(claims.Exp, 0); !now.Before(exp) || exp.Sub(now) > maxJWTLifetime {
+		return 0, model.ErrInvalidCredentials
+	}
+	userID, err := strconv.Atoi(claims.Sub)
+	if err != nil || userID <= 0 {
+		return 0, model.ErrInvalidCredentials
+	}
+
+	return userID, nil
+}
+
+func decodeJWTSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func NewAuth(apiKeyRepository APIKeyRepository, jwtSecret []byte) Auth {
+	return Auth{apiKeyRepository: apiKeyRepository, jwtSecret: jwtSecret, now: time.Now}
+}
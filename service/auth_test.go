@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AuthTestSuite struct {
+	suite.Suite
+	service              Auth
+	mockAPIKeyRepository *MockAPIKeyRepository
+	now                  time.Time
+	ctx                  context.Context
+}
+
+func (suite *AuthTestSuite) SetupTest() {
+	suite.mockAPIKeyRepository = &MockAPIKeyRepository{}
+	suite.service = NewAuth(suite.mockAPIKeyRepository, []byte("secret"))
+	suite.now = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.service.now = func() time.Time { return suite.now }
+	suite.ctx = context.Background()
+}
+
+func (suite *AuthTestSuite) jwt(secret, header, claims string) string {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + base64.RawURLEncoding.EncodeToString([]byte(claims))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (suite *AuthTestSuite) TestAuthenticateReturnsOwnerOfAPIKeyAndRecordsItsUse() {
+	suite.mockAPIKeyRepository.On("GetByHash", suite.ctx, hashAPIKey("cal_key")).Return(model.APIKey{ID: 2, UserID: 1}, nil)
+	suite.mockAPIKeyRepository.On("SetLastUsedAt", suite.ctx, 2, suite.now).Return(nil)
+
+	userID, err := suite.service.Authenticate(suite.ctx, "cal_key")
+	suite.NoError(err)
+	suite.Equal(1, userID)
+	suite.mockAPIKeyRepository.AssertExpectations(suite.T())
+}
+
+func (suite *AuthTestSuite) TestAuthenticateOnlyRecordsUseOfAPIKeyOncePerMinute() {
+	suite.mockAPIKeyRepository.On("GetByHash", suite.ctx, hashAPIKey("cal_key")).Return(model.APIKey{ID: 2, UserID: 1,
+		LastUsedAt: suite.now.Add(-30 * time.Second)}, nil)
+
+	_, err := suite.service.Authenticate(suite.ctx, "cal_key")
+	suite.NoError(err)
+	suite.mockAPIKeyRepository.AssertNotCalled(suite.T(), "SetLastUsedAt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *AuthTestSuite) TestAuthenticateLetsRequestThroughIfUseCannotBeRecorded() {
+	suite.mockAPIKeyRepository.On("GetByHash", suite.ctx, hashAPIKey("cal_key")).Return(model.APIKey{ID: 2, UserID: 1}, nil)
+	suite.mockAPIKeyRepository.On("SetLastUsedAt", suite.ctx, 2, suite.now).Return(errors.New("some error"))
+
+	userID, err := suite.service.Authenticate(suite.ctx, "cal_key")
+	suite.NoError(err)
+	suite.Equal(1, userID)
+}
+
+func (suite *AuthTestSuite) TestAuthenticateRejectsUnknownAPIKey() {
+	suite.mockAPIKeyRepository.On("GetByHash", suite.ctx, hashAPIKey("cal_key")).Return(model.APIKey{}, sql.ErrNoRows)
+
+	_, err := suite.service.Authenticate(suite.ctx, "cal_key")
+	suite.ErrorIs(err, model.ErrInvalidCredentials)
+}
+
+func (suite *AuthTestSuite) TestAuthenticateReturnsSubjectOfJWT() {
+	token := suite.jwt("secret", `{"alg":"HS256","typ":"JWT"}`, `{"sub":"1","exp":1685581260}`)
+
+	userID, err := suite.service.Authenticate(suite.ctx, token)
+	suite.NoError(err)
+	suite.Equal(1, userID)
+	suite.mockAPIKeyRepository.AssertNotCalled(suite.T(), "GetByHash", mock.Anything, mock.Anything)
+}
+
+func (suite *AuthTestSuite) TestAuthenticateRejectsInvalidJWTs() {
+	cases := map[string]string{
+		"signed with another secret": suite.jwt("other secret", `{"alg":"HS256"}`, `{"sub":"1","exp":1685581260}`),
+		"expired":                    suite.jwt("secret", `{"alg":"HS256"}`, `{"sub":"1","exp":1685577600}`),
+		"without expiry":             suite.jwt("secret", `{"alg":"HS256"}`, `{"sub":"1"}`),
+		"expiring after a day":       suite.jwt("secret", `{"alg":"HS256"}`, `{"sub":"1","exp":1685667601}`),
+		"of another algorithm":       suite.jwt("secret", `{"alg":"none"}`, `{"sub":"1","exp":1685581260}`),
+		"without user ID":            suite.jwt("secret", `{"alg":"HS256"}`, `{"sub":"jane","exp":1685581260}`),
+		"malformed":                  "not a token",
+	}
+	for name, token := range cases {
+		_, err := suite.service.Authenticate(suite.ctx, token)
+		suite.ErrorIs(err, model.ErrInvalidCredentials, name)
+	}
+}
+
+func (suite *AuthTestSuite) TestAuthenticateRejectsJWTsWithoutSecret() {
+	suite.service.jwtSecret = nil
+
+	_, err := suite.service.Authenticate(suite.ctx, suite.jwt("", `{"alg":"HS256"}`, `{"sub":"1"}`))
+	suite.ErrorIs(err, model.ErrInvalidCredentials)
+}
+
+func TestAuthTestSuite(t *testing.T) {
+	suite.Run(t, new(AuthTestSuite))
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"sort"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// availableIntervals expands a user's weekly availability into concrete intervals between from and to,
+// replacing the weekly windows of any date covered by one of the given overrides.
+func availableIntervals(availability model.UserAvailability, overrides []model.AvailabilityOverride, from, to time.Time) ([]interval, error) {
+	loc, err := availability.Location()
+	if err != nil {
+		return nil, err
+	}
+	weekly := availability.GetAvailabilityMap()
+
+	intervals := make([]interval, 0)
+	first := from.In(loc)
+	for i := 0; ; i++ {
+		t := time.Date(first.Year(), first.Month(), first.Day()+i, 0, 0, 0, 0, loc)
+		if !t.Before(to) {
+			break
+		}
+		for _, window := range windowsOn(t, weekly, overrides) {
+			start := wallClock(t.Year(), t.Month(), t.Day(), window.StartTime, loc)
+			end := wallClock(t.Year(), t.Month(), t.Day(), window.EndTime, loc)
+			if start.Before(from) {
+				start = from
+			}
+			if end.After(to) {
+				end = to
+			}
+			if start.Before(end) {
+				intervals = append(intervals, interval{start: start, end: end})
+			}
+		}
+	}
+	return intervals, nil
+}
+
+// windowsOn returns the availability windows of the calendar date of t, sorted by their start time.
+func windowsOn(t time.Time, weekly map[model.Day][]model.Availability, overrides []model.AvailabilityOverride) []model.Availability {
+	for _, override := range overrides {
+		if !override.Covers(t) {
+			continue
+		}
+		if override.Unavailable {
+			return nil
+		}
+		windows := make([]model.Availability, len(override.Availability))
+		copy(windows, override.Availability)
+		sort.Slice(windows, func(i, j int) bool { return windows[i].StartTime < windows[j].StartTime })
+		return windows
+	}
+	return weekly[model.GetDayFromInt(int(t.Weekday()))]
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// GetBooking returns the booking the management token was issued for.
+func (event Event) GetBooking(ctx context.Context, token string, loc *time.Location) (contract.Booking, error) {
+	claims, err := event.tokens.verify(token)
+	if err != nil {
+		return contract.Booking{}, err
+	}
+
+	eventObj, err := event.eventRepository.GetByID(ctx, claims.UserID, claims.EventID)
+	if err != nil {
+		return contract.Booking{}, maskMissingEvent(err)
+	}
+	if err := event.tokens.check(claims, eventObj); err != nil {
+		return contract.Booking{}, err
+	}
+
+	return event.toBooking(ctx, eventObj, loc)
+}
+
+// CancelBooking cancels the booking the management token was issued for on behalf of the invitee.
+func (event Event) CancelBooking(ctx context.Context, token string, input contract.CancelEvent) (contract.Booking, error) {
+	claims, err := event.tokens.verify(token)
+	if err != nil {
+		return contract.Booking{}, err
+	}
+
+	eventObj, err := event.eventRepository.GetByID(ctx, claims.UserID, claims.EventID)
+	if err != nil {
+		return contract.Booking{}, maskMissingEvent(err)
+	}
+	if err := event.tokens.check(claims, eventObj); err != nil {
+		return contract.Booking{}, err
+	}
+	if err := event.checkChangeable(eventObj); err != nil {
+		return contract.Booking{}, err
+	}
+	eventObj, err = event.cancel(ctx, eventObj, model.ChangedByInvitee, input)
+	if err != nil {
+		return contract.Booking{}, err
+	}
+
+	return event.toBooking(ctx, eventObj, nil)
+}
+
+// RescheduleBooking moves the booking the management token was issued for on behalf of the invitee.
+func (event Event) RescheduleBooking(ctx context.Context, token string, input contract.RescheduleEvent) (contract.Booking, error) {
+	claims, err := event.tokens.verify(token)
+	if err != nil {
+		return contract.Booking{}, err
+	}
+
+	eventObj, err := event.eventRepository.GetByID(ctx, claims.UserID, claims.EventID)
+	if err != nil {
+		return contract.Booking{}, maskMissingEvent(err)
+	}
+	if err := event.tokens.check(claims, eventObj); err != nil {
+		return contract.Booking{}, err
+	}
+	if err := event.checkChangeable(eventObj); err != nil {
+		return contract.Booking{}, err
+	}
+	eventObj, err = event.reschedule(ctx, eventObj, model.ChangedByInvitee, input)
+	if err != nil {
+		return contract.Booking{}, err
+	}
+
+	return event.toBooking(ctx, eventObj, nil)
+}
+
+// maskMissingEvent reports an event which no longer exists the same way as an invalid token, so that invitees cannot
+// learn anything about the host's other events.
+func maskMissingEvent(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ErrInvalidToken
+	}
+	return err
+}
+
+func (event Event) toBooking(ctx context.Context, eventObj model.Event, loc *time.Location) (contract.Booking, error) {
+	host, err := event.userRepository.GetByID(ctx, int(eventObj.UserID))
+	if err != nil {
+		return contract.Booking{}, err
+	}
+
+	booking := contract.Booking{
+		HostName:        host.Name,
+		InviteeEmail:    eventObj.InviteeEmail,
+		InviteeName:     eventObj.InviteeName,
+		InviteeNotes:    eventObj.InviteeNotes,
+		StartTime:       inLocation(eventObj.StartTime, loc),
+		EndTime:         inLocation(eventObj.EndTime, loc),
+		Status:          eventObj.Status.String(),
+		CancelReason:    eventObj.CancelReason,
+		ManagementToken: event.tokens.sign(eventObj),
+	}
+	if eventObj.EventTypeID != 0 {
+		eventType, err := event.eventTypeRepository.GetByID(ctx, int(eventObj.UserID), int(eventObj.EventTypeID))
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return contract.Booking{}, err
+		}
+		booking.EventTypeName = eventType.Name
+		booking.Location = eventType.Location
+	}
+	return booking, nil
+}
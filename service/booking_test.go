@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type BookingTestSuite struct {
+	suite.Suite
+	service                    Event
+	tokens                     BookingTokens
+	mockEventRepository        *MockEventRepository
+	mockSlotRepository         *MockSlotRepository
+	mockAvailabilityRepository *MockUserAvailabilityRepository
+	mockEventTypeRepository    *MockEventTypeRepository
+	mockUserRepository         *MockUserRepository
+	mockBusyBlockRepository    *MockBusyBlockRepository
+	ctx                        context.Context
+	now                        time.Time
+	start                      time.Time
+}
+
+func (suite *BookingTestSuite) SetupTest() {
+	suite.mockEventRepository = &MockEventRepository{}
+	suite.mockSlotRepository = &MockSlotRepository{}
+	suite.mockEventTypeRepository = &MockEventTypeRepository{}
+	suite.mockUserRepository = &MockUserRepository{}
+	suite.mockBusyBlockRepository = &MockBusyBlockRepository{}
+	// Users have no external calendars unless a test says otherwise
+	suite.mockBusyBlockRepository.On("GetInRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.BusyBlock{}, nil).Maybe()
+	suite.now = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.start = time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	suite.tokens = NewBookingTokens([]byte("secret"))
+	suite.tokens.now = func() time.Time { return suite.now }
+	suite.mockAvailabilityRepository = &MockUserAvailabilityRepository{}
+	suite.service = NewEvent(suite.mockEventRepository, suite.mockSlotRepository, suite.mockAvailabilityRepository, &MockAvailabilityOverrideRepository{},
+		suite.mockEventTypeRepository, suite.mockUserRepository, suite.mockBusyBlockRepository, suite.tokens)
+	suite.service.now = func() time.Time { return suite.now }
+	suite.ctx = context.Background()
+}
+
+func (suite *BookingTestSuite) event() model.Event {
+	return model.Event{ID: 3, UserID: 1, SlotID: 5, EventTypeID: 2, InviteeName: "test", InviteeEmail: "test@example.xyz",
+		StartTime: suite.start, EndTime: suite.start.Add(30 * time.Minute)}
+}
+
+func (suite *BookingTestSuite) TestTokenIsOnlyValidForItsEventUntilItEnds() {
+	claims, err := suite.tokens.verify(suite.tokens.sign(suite.event()))
+	suite.NoError(err)
+	suite.Equal(bookingClaims{UserID: 1, EventID: 3}, claims)
+	suite.NoError(suite.tokens.check(claims, suite.event()))
+
+	suite.now = suite.start.Add(30 * time.Minute)
+	suite.ErrorIs(suite.tokens.check(claims, suite.event()), model.ErrInvalidToken)
+}
+
+func (suite *BookingTestSuite) TestTokenStopsWorkingOnceEventIsRescheduled() {
+	claims, err := suite.tokens.verify(suite.tokens.sign(suite.event()))
+	suite.NoError(err)
+	rescheduled := suite.event()
+	rescheduled.Revision = 1
+
+	suite.ErrorIs(suite.tokens.check(claims, rescheduled), model.ErrInvalidToken)
+}
+
+func (suite *BookingTestSuite) TestTokenIsRejectedIfTamperedWith() {
+	token := suite.tokens.sign(suite.event())
+	other := suite.event()
+	other.ID = 4
+	payload, _, _ := strings.Cut(suite.tokens.sign(other), ".")
+	_, signature, _ := strings.Cut(token, ".")
+
+	_, err := suite.tokens.verify(payload + "." + signature)
+	suite.ErrorIs(err, model.ErrInvalidToken)
+
+	_, err = NewBookingTokens([]byte("other secret")).verify(token)
+	suite.ErrorIs(err, model.ErrInvalidToken)
+
+	_, err = suite.tokens.verify("garbage")
+	suite.ErrorIs(err, model.ErrInvalidToken)
+}
+
+func (suite *BookingTestSuite) TestGetBookingOnlyExposesTheBooking() {
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(suite.event(), nil)
+	suite.mockUserRepository.On("GetByID", suite.ctx, 1).Return(model.User{ID: 1, Name: "host", Email: "host@example.xyz"}, nil)
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 2).Return(model.EventType{ID: 2, Name: "Intro call", Location: "Zoom"}, nil)
+
+	resp, err := suite.service.GetBooking(suite.ctx, suite.tokens.sign(suite.event()), nil)
+	suite.NoError(err)
+	suite.Equal(contract.Booking{
+		HostName:        "host",
+		EventTypeName:   "Intro call",
+		Location:        "Zoom",
+		InviteeEmail:    "test@example.xyz",
+		InviteeName:     "test",
+		StartTime:       suite.start,
+		EndTime:         suite.start.Add(30 * time.Minute),
+		Status:          "confirmed",
+		ManagementToken: suite.tokens.sign(suite.event()),
+	}, resp)
+}
+
+func (suite *BookingTestSuite) TestGetBookingReportsMissingEventAsInvalidToken() {
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(model.Event{}, sql.ErrNoRows)
+
+	resp, err := suite.service.GetBooking(suite.ctx, suite.tokens.sign(suite.event()), nil)
+	suite.ErrorIs(err, model.ErrInvalidToken)
+	suite.Empty(resp)
+}
+
+func (suite *BookingTestSuite) TestGetBookingRejectsTokenIssuedBeforeEventWasMovedEarlier() {
+	token := suite.tokens.sign(suite.event())
+	moved := suite.event()
+	moved.StartTime, moved.EndTime, moved.Revision = suite.start.Add(-2*time.Hour), suite.start.Add(-90*time.Minute), 1
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(moved, nil)
+	suite.now = suite.start.Add(-time.Hour)
+
+	resp, err := suite.service.GetBooking(suite.ctx, token, nil)
+	suite.ErrorIs(err, model.ErrInvalidToken)
+	suite.Empty(resp)
+}
+
+func (suite *BookingTestSuite) TestCancelBookingIsRecordedAsCancelledByInvitee() {
+	cancelled := suite.event()
+	cancelled.Status = model.EventCancelled
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(suite.event(), nil)
+	suite.mockEventRepository.On("Cancel", suite.ctx, suite.event(), model.EventChange{ChangedBy: model.ChangedByInvitee, Reason: "sick"}).
+		Return(cancelled, nil)
+	suite.mockUserRepository.On("GetByID", suite.ctx, 1).Return(model.User{ID: 1, Name: "host"}, nil)
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 2).Return(model.EventType{ID: 2, Name: "Intro call"}, nil)
+
+	resp, err := suite.service.CancelBooking(suite.ctx, suite.tokens.sign(suite.event()), contract.CancelEvent{Reason: "sick"})
+	suite.NoError(err)
+	suite.Equal("cancelled", resp.Status)
+}
+
+func (suite *BookingTestSuite) TestRescheduleBookingReturnsTokenForNewTime() {
+	slot := model.Slot{ID: 6, UserID: 1, EventTypeID: 2, StartTime: suite.start.Add(24 * time.Hour), EndTime: suite.start.Add(24*time.Hour + 30*time.Minute)}
+	moved := suite.event()
+	moved.SlotID, moved.StartTime, moved.EndTime, moved.Revision = slot.ID, slot.StartTime, slot.EndTime, 1
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(suite.event(), nil)
+	suite.mockSlotRepository.On("GetByID", suite.ctx, 6).Return(slot, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1}, nil)
+	suite.mockEventRepository.On("Reschedule", suite.ctx, suite.event(), slot, model.BookingLimits{}, model.EventChange{ChangedBy: model.ChangedByInvitee}).
+		Return(moved, nil)
+	suite.mockUserRepository.On("GetByID", suite.ctx, 1).Return(model.User{ID: 1, Name: "host"}, nil)
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 2).Return(model.EventType{ID: 2, Name: "Intro call"}, nil)
+
+	resp, err := suite.service.RescheduleBooking(suite.ctx, suite.tokens.sign(suite.event()), contract.RescheduleEvent{SlotID: 6})
+	suite.NoError(err)
+	suite.Equal(slot.StartTime, resp.StartTime)
+	suite.Equal(suite.tokens.sign(moved), resp.ManagementToken)
+	suite.NotEqual(suite.tokens.sign(suite.event()), resp.ManagementToken)
+}
+
+func (suite *BookingTestSuite) TestRescheduleBookingRejectsExpiredToken() {
+	token := suite.tokens.sign(suite.event())
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(suite.event(), nil)
+	suite.now = suite.start.Add(time.Hour)
+
+	resp, err := suite.service.RescheduleBooking(suite.ctx, token, contract.RescheduleEvent{SlotID: 6})
+	suite.ErrorIs(err, model.ErrInvalidToken)
+	suite.Empty(resp)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "Reschedule", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBookingTestSuite(t *testing.T) {
+	suite.Run(t, new(BookingTestSuite))
+}
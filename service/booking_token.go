@@ -0,0 +1,73 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// BookingTokens signs and verifies the tokens invitees use to view and manage their booking without an account.
+// A token names the event it was issued for along with its revision, and stops working when the event is rescheduled
+// or ends.
+type BookingTokens struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// bookingClaims are the event a token was issued for and the revision of the event at the time
+type bookingClaims struct {
+	UserID   int
+	EventID  int
+	Revision uint
+}
+
+// sign returns a token for the event, valid until the event is rescheduled or ends.
+func (tokens BookingTokens) sign(eventObj model.Event) string {
+	payload := base64.RawURLEncoding.EncodeToString(
+		[]byte(fmt.Sprintf("%d.%d.%d", eventObj.UserID, eventObj.ID, eventObj.Revision)))
+	return payload + "." + tokens.signature(payload)
+}
+
+// verify returns what the token was issued for, or model.ErrInvalidToken if the token was tampered with. Whether it
+// is still valid for the event is up to check.
+func (tokens BookingTokens) verify(token string) (bookingClaims, error) {
+	payload, signature, found := strings.Cut(token, ".")
+	if !found || !hmac.Equal([]byte(signature), []byte(tokens.signature(payload))) {
+		return bookingClaims{}, model.ErrInvalidToken
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return bookingClaims{}, model.ErrInvalidToken
+	}
+	claims := bookingClaims{}
+	if _, err := fmt.Sscanf(string(decoded), "%d.%d.%d", &claims.UserID, &claims.EventID, &claims.Revision); err != nil {
+		return bookingClaims{}, model.ErrInvalidToken
+	}
+
+	return claims, nil
+}
+
+// check fails with model.ErrInvalidToken unless the claims are about the event as it is now, that is the event was
+// not rescheduled since the token was issued and has not ended yet.
+func (tokens BookingTokens) check(claims bookingClaims, eventObj model.Event) error {
+	if claims.Revision != eventObj.Revision || !tokens.now().Before(eventObj.EndTime) {
+		return model.ErrInvalidToken
+	}
+	return nil
+}
+
+func (tokens BookingTokens) signature(payload string) string {
+	mac := hmac.New(sha256.New, tokens.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func NewBookingTokens(secret []byte) BookingTokens {
+	return BookingTokens{secret: secret, now: time.Now}
+}
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// calendar works out when users are free by combining their weekly availability,
+// date overrides, existing bookings and the busy blocks of their external calendars.
+type calendar struct {
+	availabilityRepository UserAvailabilityRepository
+	overrideRepository     AvailabilityOverrideRepository
+	eventRepository        EventRepository
+	busyBlockRepository    BusyBlockRepository
+}
+
+// availableIntervals returns the intervals between from and to during which the user is available
+// as per their weekly availability and date overrides.
+func (c calendar) availableIntervals(ctx context.Context, availability model.UserAvailability, from, to time.Time) ([]interval, error) {
+	loc, err := availability.Location()
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := c.overrideRepository.GetInRange(ctx, int(availability.UserID), from.In(loc), to.In(loc))
+	if err != nil {
+		return nil, err
+	}
+	return availableIntervals(availability, overrides, from, to)
+}
+
+// busyIntervals returns the intervals between from and to during which the user is already booked or busy as per
+// their external calendars, leaving out the ignored events.
+func (c calendar) busyIntervals(ctx context.Context, userID int, from, to time.Time, ignored []uint) ([]interval, error) {
+	events, err := c.eventRepository.GetInRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	busy := make([]interval, 0)
+	for _, event := range events {
+		if slices.Contains(ignored, event.ID) {
+			continue
+		}
+		busy = append(busy, interval{start: event.StartTime, end: event.EndTime})
+	}
+
+	blocked, err := c.blockedIntervals(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return append(busy, blocked...), nil
+}
+
+// blockedIntervals returns the intervals between from and to during which the user is busy as per their external
+// calendars.
+func (c calendar) blockedIntervals(ctx context.Context, userID int, from, to time.Time) ([]interval, error) {
+	blocks, err := c.busyBlockRepository.GetInRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	blocked := make([]interval, 0, len(blocks))
+	for _, block := range blocks {
+		blocked = append(blocked, interval{start: block.StartTime, end: block.EndTime})
+	}
+	return blocked, nil
+}
+
+// freeIntervals returns the intervals between from and to during which the user is available and not booked.
+func (c calendar) freeIntervals(ctx context.Context, availability model.UserAvailability, from, to time.Time) ([]interval, error) {
+	available, err := c.availableIntervals(ctx, availability, from, to)
+	if err != nil {
+		return nil, err
+	}
+	busy, err := c.busyIntervals(ctx, int(availability.UserID), from, to, nil)
+	if err != nil {
+		return nil, err
+	}
+	return subtractIntervals(available, busy), nil
+}
+
+// slots splits the user's available time between from and to into slots of the meeting duration, leaving out the
+// ones that overlap a booking along with the buffers of the user's scheduling rules. Slots start every slot increment
+// from the start of each availability window whatever range is asked for, so only the slots lying completely between
+// from and to are returned. Slots overlap each other when the increment is shorter than the meeting duration, and all
+// of those overlapping a booking are left out. The ignored events are not treated as bookings.
+func (c calendar) slots(ctx context.Context, availability model.UserAvailability, from, to time.Time, ignored []uint) ([]interval, error) {
+	duration := time.Duration(availability.MeetingDurationMins) * time.Minute
+	if duration <= 0 {
+		return nil, errors.New("meeting duration is not set")
+	}
+	available, err := c.windows(ctx, availability, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return c.free(ctx, availability, slotsBetween(available, duration, availability.SlotIncrement(), from, to), from, to, ignored)
+}
+
+// commonSlots splits the time between from and to during which all the users are available into slots of the given
+// duration, leaving out the ones that overlap a booking of any of them along with the buffers of their scheduling
+// rules, or that fall on a day or in a week where any of them reaches the caps of their scheduling rules. The
+// availability windows of the users are intersected first, the same way as their availability overlap, and slots
+// start every increment from the start of each common window.
+func (c calendar) commonSlots(ctx context.Context, availabilities []model.UserAvailability, duration, increment time.Duration, from, to time.Time) ([]interval, error) {
+	if len(availabilities) == 0 {
+		return nil, nil
+	}
+	var common []interval
+	for i, availability := range availabilities {
+		available, err := c.windows(ctx, availability, from, to)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			common = available
+		} else {
+			common = intersectIntervals(common, available)
+		}
+	}
+
+	slots := slotsBetween(common, duration, increment, from, to)
+	for _, availability := range availabilities {
+		if len(slots) == 0 {
+			break
+		}
+		var err error
+		if slots, err = c.free(ctx, availability, slots, from, to, nil); err != nil {
+			return nil, err
+		}
+		if slots, err = c.withinCaps(ctx, availability, slots, nil); err != nil {
+			return nil, err
+		}
+	}
+	return slots, nil
+}
+
+// windows returns the user's availability windows for the whole days around from and to, in their time zone, so
+// that slots line up with the window start whatever range is asked for.
+func (c calendar) windows(ctx context.Context, availability model.UserAvailability, from, to time.Time) ([]interval, error) {
+	loc, err := availability.Location()
+	if err != nil {
+		return nil, err
+	}
+	first, last := from.In(loc), to.In(loc)
+	return c.availableIntervals(ctx, availability,
+		time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc),
+		time.Date(last.Year(), last.Month(), last.Day()+1, 0, 0, 0, 0, loc))
+}
+
+// free leaves out the slots, lying between from and to, which overlap a booking of the user along with the buffers
+// of their scheduling rules. The ignored events are not treated as bookings.
+func (c calendar) free(ctx context.Context, availability model.UserAvailability, slots []interval, from, to time.Time, ignored []uint) ([]interval, error) {
+	before := time.Duration(availability.BeforeBufferMins) * time.Minute
+	after := time.Duration(availability.AfterBufferMins) * time.Minute
+	busy, err := c.busyIntervals(ctx, int(availability.UserID), from.Add(-before), to.Add(after), ignored)
+	if err != nil {
+		return nil, err
+	}
+	busy = mergeIntervals(busy)
+
+	free := make([]interval, 0, len(slots))
+	for _, slot := range slots {
+		if !overlapsAny(interval{start: slot.start.Add(-before), end: slot.end.Add(after)}, busy) {
+			free = append(free, slot)
+		}
+	}
+	return free, nil
+}
+
+// slotsBetween lays out the slots of each window and keeps the ones lying completely between from and to.
+func slotsBetween(windows []interval, duration, increment time.Duration, from, to time.Time) []interval {
+	slots := make([]interval, 0)
+	for _, window := range windows {
+		for _, slot := range slotsIn(window, duration, increment) {
+			if !slot.start.Before(from) && !slot.end.After(to) {
+				slots = append(slots, slot)
+			}
+		}
+	}
+	return slots
+}
+
+// bookableRange narrows the range from and to down to the slots of the given duration which can be booked at now as
+// per the notice and horizon of the scheduling rules. Slots have to start within the booking window, so they have to
+// end within it too, short of the duration. The range is empty when from is not before to.
+func bookableRange(rules model.SchedulingRules, now time.Time, duration time.Duration, from, to time.Time) (time.Time, time.Time) {
+	earliest, latest := rules.BookingWindow(now)
+	if from.Before(earliest) {
+		from = earliest
+	}
+	if !latest.IsZero() {
+		if last := latest.Add(duration); to.After(last) {
+			to = last
+		}
+	}
+	return from, to
+}
+
+// session is a slot of a group event type which some invitees have booked already.
+type session struct {
+	interval
+	slotID    uint
+	remaining int
+}
+
+// openSessions returns the slots of the group event type lying between from and to which are booked by some invitees
+// but still have seats left, oldest first. The ignored events are not counted.
+func (c calendar) openSessions(ctx context.Context, userID int, eventType model.EventType, from, to time.Time, ignored []uint) ([]session, error) {
+	if eventType.Seats() <= 1 {
+		return nil, nil
+	}
+	events, err := c.eventRepository.GetInRange(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]session, 0)
+	index := make(map[uint]int)
+	for _, event := range events {
+		if event.EventTypeID != eventType.ID || slices.Contains(ignored, event.ID) ||
+			event.StartTime.Before(from) || event.EndTime.After(to) {
+			continue
+		}
+		i, ok := index[event.SlotID]
+		if !ok {
+			i = len(sessions)
+			index[event.SlotID] = i
+			sessions = append(sessions, session{interval: interval{start: event.StartTime, end: event.EndTime}, slotID: event.SlotID, remaining: eventType.Seats()})
+		}
+		sessions[i].remaining--
+	}
+
+	open := make([]session, 0, len(sessions))
+	for _, s := range sessions {
+		if s.remaining > 0 {
+			open = append(open, s)
+		}
+	}
+	return open, nil
+}
+
+// withinCaps leaves out the slots on days or in weeks where the user's bookings already reach the caps of their
+// scheduling rules. The ignored events are not counted.
+func (c calendar) withinCaps(ctx context.Context, availability model.UserAvailability, slots []interval, ignored []uint) ([]interval, error) {
+	if len(slots) == 0 || (availability.MaxPerDay <= 0 && availability.MaxPerWeek <= 0) {
+		return slots, nil
+	}
+	loc, err := availability.Location()
+	if err != nil {
+		return nil, err
+	}
+
+	// Bookings are fetched for every day and week the slots fall in
+	var from, to time.Time
+	for _, s := range slots {
+		for _, limit := range availability.Limits(s.start, loc).Caps {
+			if from.IsZero() || limit.Start.Before(from) {
+				from = limit.Start
+			}
+			if limit.End.After(to) {
+				to = limit.End
+			}
+		}
+	}
+	events, err := c.eventRepository.GetInRange(ctx, int(availability.UserID), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	within := make([]interval, 0, len(slots))
+	for _, s := range slots {
+		if !reachesCap(availability.Limits(s.start, loc).Caps, events, ignored) {
+			within = append(within, s)
+		}
+	}
+	return within, nil
+}
+
+// reachesCap reports whether the events, other than the ignored ones, reach any of the caps. Events booking the same
+// slot of a group event type count once.
+func reachesCap(caps []model.BookingCap, events []model.Event, ignored []uint) bool {
+	for _, limit := range caps {
+		booked := make(map[uint]bool)
+		for _, event := range events {
+			if slices.Contains(ignored, event.ID) {
+				continue
+			}
+			if !event.StartTime.Before(limit.Start) && event.StartTime.Before(limit.End) {
+				booked[event.SlotID] = true
+			}
+		}
+		if len(booked) >= limit.Max {
+			return true
+		}
+	}
+	return false
+}
+
+func overlapsAny(i interval, others []interval) bool {
+	for _, other := range others {
+		if i.overlaps(other) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// NewCalendarClient returns the HTTP client fetching external calendars. Their URLs are given by users, so the client
+// refuses to connect to loopback, private, link-local and unspecified addresses, unless they are in one of the allowed
+// networks. The address is checked when connecting, after the host is resolved, so that a host resolving to another
+// address than the one it resolved to before cannot get around the check.
+func NewCalendarClient(allowed []*net.IPNet) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			return checkCalendarAddress(address, allowed)
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// A proxy would connect to the calendar on our behalf, after the check
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, address)
+	}
+	return &http.Client{Timeout: fetchTimeout, Transport: transport}
+}
+
+func checkCalendarAddress(address string, allowed []*net.IPNet) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("calendar address %s is not an IP address", host)
+	}
+	for _, network := range allowed {
+		if network.Contains(ip) {
+			return nil
+		}
+	}
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("calendar address %s is not public", ip)
+	}
+	return nil
+}
+
+// ParseNetworks parses a comma separated list of networks in CIDR notation, such as "127.0.0.1/32,10.0.0.0/8"
+func ParseNetworks(value string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/ical"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+const icalProdID = "-//harbor-xyz//calendly//EN"
+
+// icalUIDSuffix ends the UIDs of the exported events, so that they are recognised when a calendar they were
+// subscribed from is imported back
+const icalUIDSuffix = "@calendly"
+
+// ExportCalendar returns the events of a user, including cancelled ones, as an iCalendar file.
+func (event Event) ExportCalendar(ctx context.Context, userID int) ([]byte, error) {
+	user, err := event.userRepository.GetByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NotFound("user", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return event.exportCalendar(ctx, user)
+}
+
+// CreateCalendarFeed generates a new secret feed URL for the user, which replaces the previous one.
+func (event Event) CreateCalendarFeed(ctx context.Context, userID int) (contract.CalendarFeed, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return contract.CalendarFeed{}, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	err := event.userRepository.SetFeedTokenHash(ctx, userID, hashFeedToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.CalendarFeed{}, model.NotFound("user", err)
+	}
+	if err != nil {
+		return contract.CalendarFeed{}, err
+	}
+
+	return contract.CalendarFeed{URL: fmt.Sprintf("/calendar_feeds/%s.ics", token)}, nil
+}
+
+// ExportCalendarFeed returns the events of the user the feed token belongs to as an iCalendar file.
+func (event Event) ExportCalendarFeed(ctx context.Context, token string) ([]byte, error) {
+	user, err := event.userRepository.GetByFeedTokenHash(ctx, hashFeedToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, model.NotFound("calendar feed", err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return event.exportCalendar(ctx, user)
+}
+
+func (event Event) exportCalendar(ctx context.Context, user model.User) ([]byte, error) {
+	events, err := event.eventRepository.GetAll(ctx, int(user.ID))
+	if err != nil {
+		return nil, err
+	}
+	eventTypes, err := event.eventTypeRepository.GetAll(ctx, int(user.ID))
+	if err != nil {
+		return nil, err
+	}
+	eventTypesByID := make(map[uint]model.EventType)
+	for _, eventType := range eventTypes {
+		eventTypesByID[eventType.ID] = eventType
+	}
+
+	cal := ical.Calendar{ProdID: icalProdID, Name: fmt.Sprintf("Bookings of %s", user.Name)}
+	for _, eventObj := range events {
+		cal.Events = append(cal.Events, toICalEvent(eventObj, user, eventTypesByID[eventObj.EventTypeID]))
+	}
+	return cal.Bytes()
+}
+
+func toICalEvent(eventObj model.Event, host model.User, eventType model.EventType) ical.Event {
+	summary := fmt.Sprintf("Meeting with %s", eventObj.InviteeName)
+	if eventType.Name != "" {
+		summary = fmt.Sprintf("%s with %s", eventType.Name, eventObj.InviteeName)
+	}
+	status := ical.StatusConfirmed
+	if eventObj.Status == model.EventCancelled {
+		status = ical.StatusCancelled
+	}
+
+	return ical.Event{
+		UID:          icalUID(eventObj),
+		Start:        eventObj.StartTime,
+		End:          eventObj.EndTime,
+		Summary:      summary,
+		Description:  eventObj.InviteeNotes,
+		Location:     eventType.Location,
+		Status:       status,
+		Organizer:    ical.Person{Name: host.Name, Email: host.Email},
+		Attendees:    []ical.Person{{Name: eventObj.InviteeName, Email: eventObj.InviteeEmail}},
+		Created:      eventObj.CreatedAt,
+		LastModified: eventObj.UpdatedAt,
+	}
+}
+
+// hashFeedToken returns the hash a feed token is stored as, so that a leaked database does not leak feed URLs.
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CalendarFeedTestSuite struct {
+	suite.Suite
+	service                 Event
+	mockEventRepository     *MockEventRepository
+	mockEventTypeRepository *MockEventTypeRepository
+	mockUserRepository      *MockUserRepository
+	ctx                     context.Context
+}
+
+func (suite *CalendarFeedTestSuite) SetupTest() {
+	suite.mockEventRepository = &MockEventRepository{}
+	suite.mockEventTypeRepository = &MockEventTypeRepository{}
+	suite.mockUserRepository = &MockUserRepository{}
+	suite.service = NewEvent(suite.mockEventRepository, &MockSlotRepository{}, &MockUserAvailabilityRepository{}, &MockAvailabilityOverrideRepository{},
+		suite.mockEventTypeRepository, suite.mockUserRepository, &MockBusyBlockRepository{}, NewBookingTokens([]byte("secret")))
+	suite.ctx = context.Background()
+}
+
+func (suite *CalendarFeedTestSuite) mockEvents() {
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	suite.mockEventRepository.On("GetAll", suite.ctx, 1).Return([]model.Event{
+		{ID: 1, UserID: 1, EventTypeID: 2, InviteeName: "test", InviteeEmail: "test@example.xyz", StartTime: start, EndTime: start.Add(30 * time.Minute)},
+		{ID: 2, UserID: 1, InviteeName: "other", InviteeEmail: "other@example.xyz", StartTime: start.Add(time.Hour), EndTime: start.Add(90 * time.Minute),
+			Status: model.EventCancelled},
+	}, nil)
+	suite.mockEventTypeRepository.On("GetAll", suite.ctx, 1).Return([]model.EventType{{ID: 2, UserID: 1, Name: "Intro call", Location: "Zoom"}}, nil)
+}
+
+func (suite *CalendarFeedTestSuite) TestExportCalendarRendersEveryEvent() {
+	suite.mockUserRepository.On("GetByID", suite.ctx, 1).Return(model.User{ID: 1, Name: "host", Email: "host@example.xyz"}, nil)
+	suite.mockEvents()
+
+	data, err := suite.service.ExportCalendar(suite.ctx, 1)
+	suite.NoError(err)
+	cal := string(data)
+	suite.Equal(2, strings.Count(cal, "BEGIN:VEVENT"))
+	suite.Contains(cal, "X-WR-CALNAME:Bookings of host\r\n")
+	suite.Contains(cal, "UID:event-1@calendly\r\n")
+	suite.Contains(cal, "DTSTART:20230605T090000Z\r\n")
+	suite.Contains(cal, "SUMMARY:Intro call with test\r\n")
+	suite.Contains(cal, "LOCATION:Zoom\r\n")
+	suite.Contains(cal, "ORGANIZER;CN=host:mailto:host@example.xyz\r\n")
+	suite.Contains(cal, "SUMMARY:Meeting with other\r\n")
+	suite.Contains(cal, "STATUS:CANCELLED\r\n")
+}
+
+func (suite *CalendarFeedTestSuite) TestExportCalendarReturnsErrorIfUserDoesNotExist() {
+	suite.mockUserRepository.On("GetByID", suite.ctx, 1).Return(model.User{}, sql.ErrNoRows)
+
+	data, err := suite.service.ExportCalendar(suite.ctx, 1)
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.Nil(data)
+}
+
+func (suite *CalendarFeedTestSuite) TestCalendarFeedIsServedForTheLatestToken() {
+	var storedHash string
+	suite.mockUserRepository.On("SetFeedTokenHash", suite.ctx, 1, mock.Anything).Run(func(args mock.Arguments) {
+		storedHash = args.String(2)
+	}).Return(nil)
+
+	feed, err := suite.service.CreateCalendarFeed(suite.ctx, 1)
+	suite.NoError(err)
+	suite.True(strings.HasPrefix(feed.URL, "/calendar_feeds/"))
+	token := strings.TrimSuffix(strings.TrimPrefix(feed.URL, "/calendar_feeds/"), ".ics")
+	suite.NotContains(storedHash, token)
+	suite.Equal(hashFeedToken(token), storedHash)
+
+	suite.mockUserRepository.On("GetByFeedTokenHash", suite.ctx, storedHash).Return(model.User{ID: 1, Name: "host", Email: "host@example.xyz"}, nil)
+	suite.mockEvents()
+	data, err := suite.service.ExportCalendarFeed(suite.ctx, token)
+	suite.NoError(err)
+	suite.Equal(2, strings.Count(string(data), "BEGIN:VEVENT"))
+}
+
+func (suite *CalendarFeedTestSuite) TestExportCalendarFeedReturnsErrorForUnknownToken() {
+	suite.mockUserRepository.On("GetByFeedTokenHash", suite.ctx, hashFeedToken("unknown")).Return(model.User{}, sql.ErrNoRows)
+
+	data, err := suite.service.ExportCalendarFeed(suite.ctx, "unknown")
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.Nil(data)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "GetAll", mock.Anything, mock.Anything)
+}
+
+func TestCalendarFeedTestSuite(t *testing.T) {
+	suite.Run(t, new(CalendarFeedTestSuite))
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/caldav"
+	"github.com/harbor-xyz/coding-project/ical"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+var errWriteBackUnsupported = errors.New("calendar does not support writing events")
+
+// CalendarProvider gives access to a calendar kept outside of the app. Errors caused by the calendar itself, such as
+// an unreachable server or invalid data, wrap model.ErrInvalidCalendar.
+type CalendarProvider interface {
+	// Busy returns the times between from and to during which the owner of the calendar is busy
+	Busy(ctx context.Context, from, to time.Time) ([]ical.Period, error)
+	// PutEvent creates or replaces an event in the calendar
+	PutEvent(ctx context.Context, event ical.Event) error
+	// DeleteEvent removes the event with the given UID from the calendar
+	DeleteEvent(ctx context.Context, uid string) error
+}
+
+// calendarProviders returns the provider reading an external calendar. Floating times of the calendar are read in
+// loc.
+type calendarProviders func(obj model.ExternalCalendar, loc *time.Location) (CalendarProvider, error)
+
+func newCalendarProviders(client *http.Client) calendarProviders {
+	return func(obj model.ExternalCalendar, loc *time.Location) (CalendarProvider, error) {
+		switch obj.Provider {
+		case model.ProviderICS, "":
+			return icsProvider{client: client, url: obj.URL, loc: loc}, nil
+		case model.ProviderCalDAV:
+			return caldavProvider{client: caldav.NewClient(client, obj.URL, obj.Username, obj.Password)}, nil
+		}
+		return nil, fmt.Errorf("unknown calendar provider %q", obj.Provider)
+	}
+}
+
+// icsProvider reads a calendar published as an ICS file. webcal URLs, as handed out by calendar apps, are fetched
+// over https.
+type icsProvider struct {
+	client *http.Client
+	url    string
+	loc    *time.Location
+}
+
+func (provider icsProvider) Busy(ctx context.Context, from, to time.Time) ([]ical.Period, error) {
+	if provider.url == "" {
+		return nil, model.ErrInvalidCalendar.Withf("calendar has no URL")
+	}
+	parsed, err := url.Parse(provider.url)
+	if err != nil {
+		return nil, model.ErrInvalidCalendar.Withf("%s", err)
+	}
+	if strings.EqualFold(parsed.Scheme, "webcal") {
+		parsed.Scheme = "https"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, model.ErrInvalidCalendar.Withf("%s", err)
+	}
+	req.Header.Set("Accept", "text/calendar")
+	resp, err := provider.client.Do(req)
+	if err != nil {
+		return nil, model.ErrInvalidCalendar.Withf("fetching calendar failed").Wrap(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, model.ErrInvalidCalendar.Withf("fetching calendar failed with status %d", resp.StatusCode)
+	}
+
+	return busyPeriods(resp.Body, provider.loc, from, to)
+}
+
+func (provider icsProvider) PutEvent(ctx context.Context, event ical.Event) error {
+	return errWriteBackUnsupported
+}
+
+func (provider icsProvider) DeleteEvent(ctx context.Context, uid string) error {
+	return errWriteBackUnsupported
+}
+
+// caldavProvider reads the free/busy time of a CalDAV calendar and writes events to it.
+type caldavProvider struct {
+	client caldav.Client
+}
+
+func (provider caldavProvider) Busy(ctx context.Context, from, to time.Time) ([]ical.Period, error) {
+	periods, err := provider.client.FreeBusy(ctx, from, to)
+	if err != nil {
+		return nil, model.ErrInvalidCalendar.Withf("fetching calendar failed").Wrap(err)
+	}
+	return periods, nil
+}
+
+func (provider caldavProvider) PutEvent(ctx context.Context, event ical.Event) error {
+	return provider.client.PutEvent(ctx, ical.Calendar{ProdID: icalProdID, Events: []ical.Event{event}})
+}
+
+func (provider caldavProvider) DeleteEvent(ctx context.Context, uid string) error {
+	return provider.client.DeleteEvent(ctx, uid)
+}
+
+// busyPeriods reads an ICS file and returns its busy periods between from and to. Floating times are read in loc.
+// Events exported by this app are left out, as they are already known as bookings.
+func busyPeriods(r io.Reader, loc *time.Location, from, to time.Time) ([]ical.Period, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxCalendarBytes+1))
+	if err != nil {
+		return nil, model.ErrInvalidCalendar.Withf("reading calendar failed").Wrap(err)
+	}
+	if len(data) > maxCalendarBytes {
+		return nil, model.ErrInvalidCalendar.Withf("calendar is larger than %d bytes", maxCalendarBytes)
+	}
+	cal, err := ical.Parse(bytes.NewReader(data), loc)
+	if err != nil {
+		return nil, model.ErrInvalidCalendar.Withf("%s", err)
+	}
+
+	external := ical.Calendar{BusyPeriods: cal.BusyPeriods}
+	for _, icalEvent := range cal.Events {
+		if !strings.HasSuffix(icalEvent.UID, icalUIDSuffix) {
+			external.Events = append(external.Events, icalEvent)
+		}
+	}
+	return external.Busy(from, to), nil
+}
@@ -4,16 +4,44 @@ import (
 	"context"
 	"time"
 
+	"github.com/harbor-xyz/coding-project/mail"
 	"github.com/harbor-xyz/coding-project/model"
 )
 
 type UserRepository interface {
 	Create(context.Context, model.User) (model.User, error)
+	GetByID(context.Context, int) (model.User, error)
+	GetAll(context.Context, string, int, int) ([]model.User, int64, error)
+	Update(context.Context, model.User) (model.User, error)
+	Delete(context.Context, int) error
+	GetByFeedTokenHash(context.Context, string) (model.User, error)
+	SetFeedTokenHash(context.Context, int, string) error
+	GetBySlug(context.Context, string) (model.User, error)
+	SetSlug(context.Context, int, string) error
+}
+
+type APIKeyRepository interface {
+	Create(context.Context, model.APIKey) (model.APIKey, error)
+	GetAll(context.Context, int) ([]model.APIKey, error)
+	GetByHash(context.Context, string) (model.APIKey, error)
+	SetLastUsedAt(context.Context, int, time.Time) error
+	Delete(context.Context, int, int) error
 }
 
 type UserAvailabilityRepository interface {
-	Set(context.Context, model.UserAvailability) (model.UserAvailability, error)
+	Set(context.Context, model.UserAvailability, []model.Job) (model.UserAvailability, error)
 	Get(context.Context, int) (model.UserAvailability, error)
+	GetAll(context.Context) ([]model.UserAvailability, error)
+	MarkSlotsSynced(context.Context, int, time.Time) error
+}
+
+type AvailabilityOverrideRepository interface {
+	Create(context.Context, model.AvailabilityOverride) (model.AvailabilityOverride, error)
+	GetAll(context.Context, int) ([]model.AvailabilityOverride, error)
+	GetInRange(context.Context, int, time.Time, time.Time) ([]model.AvailabilityOverride, error)
+	GetByID(context.Context, int, int) (model.AvailabilityOverride, error)
+	Update(context.Context, model.AvailabilityOverride) (model.AvailabilityOverride, error)
+	Delete(context.Context, int, int) error
 }
 
 type SlotRepository interface {
@@ -21,10 +49,90 @@ type SlotRepository interface {
 	Get(context.Context, int, time.Time, time.Time) ([]model.Slot, error)
 	GetByID(context.Context, int) (model.Slot, error)
 	DeleteByID(context.Context, int) error
-	BookSlot(context.Context, int) error
+	Expire(context.Context, time.Time) (int64, error)
+	DeleteUnbooked(context.Context, int, time.Time) error
 }
 
 type EventRepository interface {
 	Create(context.Context, model.Event) (model.Event, error)
 	GetAll(context.Context, int) ([]model.Event, error)
+	GetInRange(context.Context, int, time.Time, time.Time) ([]model.Event, error)
+	BookSlot(context.Context, model.Event, model.BookingLimits, model.EventJobs) (model.Event, error)
+	BookTime(context.Context, model.Event, model.Slot, model.BookingLimits, model.EventJobs) (model.Event, error)
+	GetByID(context.Context, int, int) (model.Event, error)
+	GetChanges(context.Context, int) ([]model.EventChange, error)
+	Cancel(context.Context, model.Event, model.EventChange, model.EventJobs) (model.Event, error)
+	Reschedule(context.Context, model.Event, model.Slot, model.BookingLimits, model.EventChange, model.EventJobs) (model.Event, error)
+	BookTeam(context.Context, uint, []model.Booking, model.EventJobs) ([]model.Event, error)
+	BookSeries(context.Context, model.EventSeries, []model.Booking, model.EventJobs) (model.EventSeries, error)
+	GetSeries(context.Context, int, int) (model.EventSeries, error)
+	CancelSeries(context.Context, []model.Event, model.EventChange, model.EventJobs) ([]model.Event, error)
+	RescheduleSeries(context.Context, []model.Booking, model.EventChange, model.EventJobs) ([]model.Event, error)
+}
+
+type EventTypeRepository interface {
+	Create(context.Context, model.EventType) (model.EventType, error)
+	GetAll(context.Context, int) ([]model.EventType, error)
+	GetByID(context.Context, int, int) (model.EventType, error)
+	GetBySlug(context.Context, int, string) (model.EventType, error)
+	Update(context.Context, model.EventType) (model.EventType, error)
+	Delete(context.Context, int, int) error
+}
+
+type TeamRepository interface {
+	Create(context.Context, model.Team) (model.Team, error)
+	GetAll(context.Context, int) ([]model.Team, error)
+	GetByID(context.Context, int, int) (model.Team, error)
+	Delete(context.Context, int, int) error
+	AddMember(context.Context, model.TeamMember) (model.TeamMember, error)
+	RemoveMember(context.Context, int, int) error
+	GetMemberships(context.Context, int) ([]model.Team, error)
+	AcceptMember(context.Context, int, int) error
+	CreateEventType(context.Context, model.TeamEventType) (model.TeamEventType, error)
+	GetEventTypes(context.Context, int) ([]model.TeamEventType, error)
+	GetEventType(context.Context, int, int) (model.TeamEventType, error)
+	DeleteEventType(context.Context, int, int) error
+}
+
+type ExternalCalendarRepository interface {
+	Create(context.Context, model.ExternalCalendar) (model.ExternalCalendar, error)
+	GetAll(context.Context, int) ([]model.ExternalCalendar, error)
+	GetAllWithURL(context.Context) ([]model.ExternalCalendar, error)
+	GetByID(context.Context, int, int) (model.ExternalCalendar, error)
+	Delete(context.Context, int, int) error
+	ReplaceBusyBlocks(context.Context, model.ExternalCalendar, []model.BusyBlock, time.Time) (model.ExternalCalendar, error)
+}
+
+type BusyBlockRepository interface {
+	GetInRange(context.Context, int, time.Time, time.Time) ([]model.BusyBlock, error)
+}
+
+type WebhookRepository interface {
+	Create(context.Context, model.Webhook) (model.Webhook, error)
+	GetAll(context.Context, int) ([]model.Webhook, error)
+	GetByID(context.Context, int, int) (model.Webhook, error)
+	Delete(context.Context, int, int) error
+	CreateDeliveries(context.Context, []model.WebhookDelivery) error
+	GetDeliveries(context.Context, int, int) ([]model.WebhookDelivery, error)
+	GetDueDeliveries(context.Context, time.Time, int) ([]model.WebhookDelivery, error)
+	UpdateDelivery(context.Context, model.WebhookDelivery) error
+}
+
+type NotificationRepository interface {
+	Create(context.Context, []model.Notification) error
+	GetDue(context.Context, time.Time, int) ([]model.Notification, error)
+	Update(context.Context, model.Notification) error
+}
+
+// Notifier sends emails, see mail.SMTP.
+type Notifier interface {
+	Send(context.Context, mail.Message) error
+}
+
+type JobRepository interface {
+	Claim(context.Context, time.Time, time.Time) (model.Job, error)
+	Finish(context.Context, model.Job) error
+	GetAll(context.Context, *int, string, int) ([]model.Job, error)
+	GetByID(context.Context, int) (model.Job, error)
+	Retry(context.Context, int, time.Time) (model.Job, error)
 }
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// Kinds of the jobs following up on bookings and availability changes
+const (
+	jobWriteBack             = "event.write_back"
+	jobPublishWebhook        = "webhook.publish"
+	jobScheduleNotifications = "notification.schedule"
+	jobSyncSlots             = "slot.sync"
+)
+
+// eventJob is the payload of jobs about the current state of an event.
+type eventJob struct {
+	UserID  uint `json:"user_id"`
+	EventID uint `json:"event_id"`
+}
+
+// webhookJob is the payload of jobs publishing an event to the user's webhooks, as it was when the event occurred.
+type webhookJob struct {
+	UserID     uint            `json:"user_id"`
+	Event      string          `json:"event"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// notificationJob is the payload of jobs queueing the notifications about a change of an event, which carries the
+// event as it was right after the change so that reminders are queued for the right time.
+type notificationJob struct {
+	EventID   uint      `json:"event_id"`
+	Kind      string    `json:"kind"`
+	StartTime time.Time `json:"start_time"`
+	Cancelled bool      `json:"cancelled"`
+}
+
+// userJob is the payload of jobs about a user.
+type userJob struct {
+	UserID uint `json:"user_id"`
+}
+
+// Effects runs the side effects of bookings and availability changes, which are enqueued as jobs in the same
+// transaction as the change.
+type Effects struct {
+	eventRepository        EventRepository
+	userRepository         UserRepository
+	eventTypeRepository    EventTypeRepository
+	calendarRepository     ExternalCalendarRepository
+	availabilityRepository UserAvailabilityRepository
+	providers              calendarProviders
+	webhooks               webhooks
+	notifications          notifications
+	slot                   Slot
+	// horizonDays is how many days ahead slots are generated again after a change of availability
+	horizonDays int
+}
+
+// Handlers returns the handlers of the jobs, keyed by their kind.
+func (effects Effects) Handlers() map[string]JobHandler {
+	return map[string]JobHandler{
+		jobWriteBack:             effects.writeBack,
+		jobPublishWebhook:        effects.publishWebhook,
+		jobScheduleNotifications: effects.scheduleNotifications,
+		jobSyncSlots:             effects.syncSlots,
+	}
+}
+
+// writeBack puts the event as it is now into the user's external calendars with write back enabled, or removes it
+// from them once it is cancelled.
+func (effects Effects) writeBack(ctx context.Context, payload []byte) error {
+	job := eventJob{}
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	eventObj, err := effects.eventRepository.GetByID(ctx, int(job.UserID), int(job.EventID))
+	if errors.Is(err, sql.ErrNoRows) {
+		// The event was deleted along with its user, and so were their calendars
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return effects.writeBackEvent(ctx, eventObj)
+}
+
+func (effects Effects) publishWebhook(ctx context.Context, payload []byte) error {
+	job := webhookJob{}
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	return effects.webhooks.enqueue(ctx, job.UserID, job.Event, job.OccurredAt, job.Data)
+}
+
+func (effects Effects) scheduleNotifications(ctx context.Context, payload []byte) error {
+	job := notificationJob{}
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	eventObj := model.Event{ID: job.EventID, StartTime: job.StartTime, Status: model.EventConfirmed}
+	if job.Cancelled {
+		eventObj.Status = model.EventCancelled
+	}
+	return effects.notifications.enqueue(ctx, eventObj, job.Kind)
+}
+
+// syncSlots generates the slots of the user again, for clients booking by slot ID.
+func (effects Effects) syncSlots(ctx context.Context, payload []byte) error {
+	job := userJob{}
+	if err := json.Unmarshal(payload, &job); err != nil {
+		return err
+	}
+	availability, err := effects.availabilityRepository.Get(ctx, int(job.UserID))
+	if errors.Is(err, sql.ErrNoRows) {
+		// The user was deleted since
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = effects.slot.syncUser(ctx, availability, effects.horizonDays)
+	return err
+}
+
+// NewEffects returns the side effects run by the worker pool. Reminders are sent the given durations before events,
+// and slots are generated horizonDays ahead. External calendars are written to with calendarClient.
+func NewEffects(eventRepository EventRepository, userRepository UserRepository, eventTypeRepository EventTypeRepository, calendarRepository ExternalCalendarRepository, availabilityRepository UserAvailabilityRepository, webhookRepository WebhookRepository, notificationRepository NotificationRepository, slot Slot, calendarClient *http.Client, reminders []time.Duration, horizonDays int) Effects {
+	return Effects{
+		eventRepository:        eventRepository,
+		userRepository:         userRepository,
+		eventTypeRepository:    eventTypeRepository,
+		calendarRepository:     calendarRepository,
+		availabilityRepository: availabilityRepository,
+		providers:              newCalendarProviders(calendarClient),
+		webhooks:               newWebhooks(webhookRepository),
+		notifications:          newNotifications(notificationRepository, reminders),
+		slot:                   slot,
+		horizonDays:            horizonDays,
+	}
+}
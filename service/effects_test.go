@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/caldav/caldavtest"
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type EffectsTestSuite struct {
+	suite.Suite
+	service                    Effects
+	mockEventRepository        *MockEventRepository
+	mockUserRepository         *MockUserRepository
+	mockEventTypeRepository    *MockEventTypeRepository
+	mockCalendarRepository     *MockExternalCalendarRepository
+	mockAvailabilityRepository *MockUserAvailabilityRepository
+	mockWebhookRepository      *MockWebhookRepository
+	mockNotificationRepository *MockNotificationRepository
+	now                        time.Time
+	start                      time.Time
+	ctx                        context.Context
+}
+
+func (suite *EffectsTestSuite) SetupTest() {
+	suite.mockEventRepository = &MockEventRepository{}
+	suite.mockUserRepository = &MockUserRepository{}
+	suite.mockEventTypeRepository = &MockEventTypeRepository{}
+	suite.mockCalendarRepository = &MockExternalCalendarRepository{}
+	suite.mockAvailabilityRepository = &MockUserAvailabilityRepository{}
+	suite.mockWebhookRepository = &MockWebhookRepository{}
+	suite.mockNotificationRepository = &MockNotificationRepository{}
+	suite.now = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.start = time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	slot := NewSlot(&MockSlotRepository{}, suite.mockAvailabilityRepository, &MockAvailabilityOverrideRepository{}, suite.mockEventTypeRepository,
+		suite.mockEventRepository, &MockBusyBlockRepository{})
+	suite.service = NewEffects(suite.mockEventRepository, suite.mockUserRepository, suite.mockEventTypeRepository, suite.mockCalendarRepository,
+		suite.mockAvailabilityRepository, suite.mockWebhookRepository, suite.mockNotificationRepository, slot, http.DefaultClient, []time.Duration{24 * time.Hour, time.Hour}, 14)
+	suite.service.webhooks.now = func() time.Time { return suite.now }
+	suite.service.notifications.now = func() time.Time { return suite.now }
+	suite.ctx = context.Background()
+}
+
+func (suite *EffectsTestSuite) payload(obj interface{}) []byte {
+	data, err := json.Marshal(obj)
+	suite.Require().NoError(err)
+	return data
+}
+
+func (suite *EffectsTestSuite) TestHandlersCoverEveryKindOfJob() {
+	handlers := suite.service.Handlers()
+	for _, kind := range []string{jobWriteBack, jobPublishWebhook, jobScheduleNotifications, jobSyncSlots} {
+		suite.Contains(handlers, kind)
+	}
+}
+
+func (suite *EffectsTestSuite) TestWriteBackPutsEventsIntoCalDAVCalendarsAndRemovesThemOnceCancelled() {
+	server := caldavtest.NewServer()
+	defer server.Close()
+	suite.mockCalendarRepository.On("GetAll", suite.ctx, 1).Return([]model.ExternalCalendar{
+		{ID: 1, UserID: 1, Provider: model.ProviderICS, URL: "https://example.xyz/work.ics"},
+		{ID: 2, UserID: 1, Provider: model.ProviderCalDAV, URL: server.CalendarURL(), Username: server.Username, Password: server.Password, WriteBack: true},
+	}, nil)
+	suite.mockUserRepository.On("GetByID", suite.ctx, 1).Return(model.User{ID: 1, Name: "host", Email: "host@example.xyz"}, nil)
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 2).Return(model.EventType{ID: 2, UserID: 1, Name: "Intro call"}, nil)
+	eventObj := model.Event{ID: 3, UserID: 1, SlotID: 5, EventTypeID: 2, InviteeName: "test", InviteeEmail: "test@example.xyz", StartTime: suite.start,
+		EndTime: suite.start.Add(30 * time.Minute)}
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(eventObj, nil).Once()
+	payload := suite.payload(eventJob{UserID: 1, EventID: 3})
+
+	suite.NoError(suite.service.writeBack(suite.ctx, payload))
+	written, ok := server.Event("event-3@calendly")
+	suite.True(ok)
+	suite.Equal("Intro call with test", written.Summary)
+	suite.True(suite.start.Equal(written.Start))
+
+	// The job writes the event as it is when it runs, so an event cancelled in the meantime is removed
+	cancelled := eventObj
+	cancelled.Status = model.EventCancelled
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(cancelled, nil).Once()
+
+	suite.NoError(suite.service.writeBack(suite.ctx, payload))
+	suite.Equal(0, server.EventCount())
+}
+
+func (suite *EffectsTestSuite) TestWriteBackReturnsErrorIfCalendarIsUnreachable() {
+	suite.mockCalendarRepository.On("GetAll", suite.ctx, 1).Return([]model.ExternalCalendar{
+		{ID: 2, UserID: 1, Provider: model.ProviderCalDAV, URL: "http://127.0.0.1:0/calendars/test/", WriteBack: true},
+	}, nil)
+	suite.mockUserRepository.On("GetByID", suite.ctx, 1).Return(model.User{ID: 1, Name: "host"}, nil)
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(model.Event{ID: 3, UserID: 1, SlotID: 5, StartTime: suite.start,
+		EndTime: suite.start.Add(30 * time.Minute)}, nil)
+
+	err := suite.service.writeBack(suite.ctx, suite.payload(eventJob{UserID: 1, EventID: 3}))
+	suite.ErrorContains(err, "external calendar 2")
+}
+
+func (suite *EffectsTestSuite) TestWriteBackSkipsUsersWithoutWriteBackCalendars() {
+	suite.mockCalendarRepository.On("GetAll", suite.ctx, 1).Return([]model.ExternalCalendar{
+		{ID: 1, UserID: 1, Provider: model.ProviderCalDAV, URL: "http://127.0.0.1:0/calendars/test/"},
+	}, nil)
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(model.Event{ID: 3, UserID: 1}, nil)
+
+	suite.NoError(suite.service.writeBack(suite.ctx, suite.payload(eventJob{UserID: 1, EventID: 3})))
+	suite.mockUserRepository.AssertNotCalled(suite.T(), "GetByID", mock.Anything, mock.Anything)
+}
+
+func (suite *EffectsTestSuite) TestPublishWebhookQueuesDeliveriesAsTheEventOccurred() {
+	suite.mockWebhookRepository.On("GetAll", suite.ctx, 1).Return([]model.Webhook{{ID: 2, UserID: 1, Events: []string{model.WebhookBookingCreated}}}, nil)
+	var queued []model.WebhookDelivery
+	suite.mockWebhookRepository.On("CreateDeliveries", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		queued = args.Get(1).([]model.WebhookDelivery)
+	}).Return(nil)
+	occurredAt := suite.now.Add(-time.Minute)
+	data := suite.payload(contract.EventResponse{ID: 3, InviteeEmail: "test@example.xyz"})
+
+	err := suite.service.publishWebhook(suite.ctx, suite.payload(webhookJob{UserID: 1, Event: model.WebhookBookingCreated, OccurredAt: occurredAt, Data: data}))
+	suite.NoError(err)
+	suite.Len(queued, 1)
+	payload := contract.WebhookPayload{}
+	suite.NoError(json.Unmarshal(queued[0].Payload, &payload))
+	suite.Equal(model.WebhookBookingCreated, payload.Event)
+	suite.True(occurredAt.Equal(payload.OccurredAt))
+	suite.Equal(float64(3), payload.Data.(map[string]interface{})["id"])
+}
+
+func (suite *EffectsTestSuite) TestPublishWebhookReturnsErrorIfDeliveriesCannotBeQueued() {
+	suite.mockWebhookRepository.On("GetAll", suite.ctx, 1).Return([]model.Webhook{{ID: 2, UserID: 1, Events: []string{model.WebhookBookingCreated}}}, nil)
+	suite.mockWebhookRepository.On("CreateDeliveries", suite.ctx, mock.Anything).Return(errors.New("some error"))
+
+	err := suite.service.publishWebhook(suite.ctx, suite.payload(webhookJob{UserID: 1, Event: model.WebhookBookingCreated, Data: []byte("{}")}))
+	suite.Error(err)
+}
+
+func (suite *EffectsTestSuite) TestScheduleNotificationsQueuesConfirmationsAndReminders() {
+	var queued []model.Notification
+	suite.mockNotificationRepository.On("Create", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		queued = args.Get(1).([]model.Notification)
+	}).Return(nil)
+
+	err := suite.service.scheduleNotifications(suite.ctx, suite.payload(notificationJob{EventID: 3, Kind: model.NotificationConfirmation,
+		StartTime: suite.start}))
+	suite.NoError(err)
+	suite.Equal([]model.Notification{
+		{EventID: 3, Kind: model.NotificationConfirmation, Recipient: model.RecipientInvitee, EventStartTime: suite.start, SendAt: suite.now},
+		{EventID: 3, Kind: model.NotificationReminder, Recipient: model.RecipientInvitee, EventStartTime: suite.start, SendAt: suite.start.Add(-24 * time.Hour)},
+		{EventID: 3, Kind: model.NotificationReminder, Recipient: model.RecipientInvitee, EventStartTime: suite.start, SendAt: suite.start.Add(-time.Hour)},
+		{EventID: 3, Kind: model.NotificationConfirmation, Recipient: model.RecipientHost, EventStartTime: suite.start, SendAt: suite.now},
+		{EventID: 3, Kind: model.NotificationReminder, Recipient: model.RecipientHost, EventStartTime: suite.start, SendAt: suite.start.Add(-24 * time.Hour)},
+		{EventID: 3, Kind: model.NotificationReminder, Recipient: model.RecipientHost, EventStartTime: suite.start, SendAt: suite.start.Add(-time.Hour)},
+	}, queued)
+}
+
+func (suite *EffectsTestSuite) TestScheduleNotificationsQueuesNoRemindersOfCancellations() {
+	var queued []model.Notification
+	suite.mockNotificationRepository.On("Create", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		queued = args.Get(1).([]model.Notification)
+	}).Return(nil)
+
+	err := suite.service.scheduleNotifications(suite.ctx, suite.payload(notificationJob{EventID: 3, Kind: model.NotificationCancellation,
+		StartTime: suite.start, Cancelled: true}))
+	suite.NoError(err)
+	suite.Len(queued, 2)
+	for _, obj := range queued {
+		suite.Equal(model.NotificationCancellation, obj.Kind)
+	}
+}
+
+func (suite *EffectsTestSuite) TestSyncSlotsReturnsErrorIfAvailabilityCannotBeFetched() {
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{}, errors.New("some error"))
+
+	err := suite.service.syncSlots(suite.ctx, suite.payload(userJob{UserID: 1}))
+	suite.Error(err)
+}
+
+func (suite *EffectsTestSuite) TestJobsOfDeletedUsersSucceedWithoutDoingAnything() {
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(model.Event{}, sql.ErrNoRows)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{}, sql.ErrNoRows)
+
+	suite.NoError(suite.service.writeBack(suite.ctx, suite.payload(eventJob{UserID: 1, EventID: 3})))
+	suite.NoError(suite.service.syncSlots(suite.ctx, suite.payload(userJob{UserID: 1})))
+	suite.mockCalendarRepository.AssertNotCalled(suite.T(), "GetAll", mock.Anything, mock.Anything)
+}
+
+func (suite *EffectsTestSuite) TestHandlersReturnErrorOnMalformedPayloads() {
+	for kind, handler := range suite.service.Handlers() {
+		suite.Error(handler(suite.ctx, []byte("not json")), kind)
+	}
+}
+
+func TestEffectsTestSuite(t *testing.T) {
+	suite.Run(t, new(EffectsTestSuite))
+}
@@ -2,55 +2,173 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
 
 	"github.com/harbor-xyz/coding-project/contract"
 	"github.com/harbor-xyz/coding-project/model"
 )
 
 type Event struct {
-	eventRepository EventRepository
-	slotRepository  SlotRepository
+	eventRepository        EventRepository
+	slotRepository         SlotRepository
+	availabilityRepository UserAvailabilityRepository
+	eventTypeRepository    EventTypeRepository
+	userRepository         UserRepository
+	calendar               calendar
+	tokens                 BookingTokens
+	now                    func() time.Time
 }
 
+// Create books either the slot given by its ID or the free slot starting at the given start time. Slots of group event
+// types are booked by several invitees, up to the capacity of the event type. Booking a slot which is already fully
+// booked fails with model.ErrSlotAlreadyBooked, and one which belongs to another user with
+// sql.ErrNoRows. Bookings have to follow the scheduling rules of their event type, or of the user: a slot starting too
+// soon, too far ahead or within the buffers of another booking fails with model.ErrSlotUnavailable, and one beyond the
+// daily or weekly caps with model.ErrBookingLimit.
 func (event Event) Create(ctx context.Context, userID int, input contract.Event) (contract.EventResponse, error) {
-	slot, err := event.slotRepository.GetByID(ctx, input.SlotID)
+	eventObj, err := event.create(ctx, userID, input)
 	if err != nil {
 		return contract.EventResponse{}, err
 	}
+
+	return event.toContract(eventObj, nil), nil
+}
+
+func (event Event) create(ctx context.Context, userID int, input contract.Event) (model.Event, error) {
 	eventObj := model.Event{
 		UserID:       uint(userID),
-		SlotID:       uint(input.SlotID),
 		InviteeEmail: input.InviteeEmail,
 		InviteeName:  input.InviteeName,
 		InviteeNotes: input.InviteeNotes,
-		StartTime:    slot.StartTime,
-		EndTime:      slot.EndTime,
 	}
 
-	eventObj, err = event.eventRepository.Create(ctx, eventObj)
+	var err error
+	if input.SlotID != 0 {
+		eventObj, err = event.bookSlot(ctx, eventObj, input.SlotID, input.EventTypeID)
+	} else {
+		eventObj, err = event.bookTime(ctx, eventObj, input.EventTypeID, input.StartTime)
+	}
+	return eventObj, err
+}
+
+func (event Event) bookSlot(ctx context.Context, eventObj model.Event, slotID, eventTypeID int) (model.Event, error) {
+	slot, err := event.ownSlot(ctx, eventObj.UserID, slotID)
 	if err != nil {
-		return contract.EventResponse{}, err
+		return model.Event{}, err
+	}
+	if eventTypeID != 0 && slot.EventTypeID != uint(eventTypeID) {
+		return model.Event{}, model.ErrEventTypeMismatch
+	}
+	limits, err := event.slotLimits(ctx, slot)
+	if err != nil {
+		return model.Event{}, err
 	}
 
-	err = event.slotRepository.BookSlot(ctx, input.SlotID)
+	eventObj.SlotID = slot.ID
+	eventObj, err = event.eventRepository.BookSlot(ctx, eventObj, limits, event.changeJobs(model.WebhookBookingCreated))
+	if errors.Is(err, sql.ErrNoRows) {
+		// The slot was deleted in the meantime
+		return model.Event{}, model.NotFound("slot", err)
+	}
+	return eventObj, err
+}
+
+func (event Event) bookTime(ctx context.Context, eventObj model.Event, eventTypeID int, startTime time.Time) (model.Event, error) {
+	slot, limits, err := event.freeSlot(ctx, int(eventObj.UserID), eventTypeID, startTime, nil)
 	if err != nil {
-		return contract.EventResponse{}, err
+		return model.Event{}, err
 	}
+	return event.eventRepository.BookTime(ctx, eventObj, slot, limits, event.changeJobs(model.WebhookBookingCreated))
+}
 
-	return contract.EventResponse{
-		ID:           int(eventObj.ID),
-		UserID:       int(eventObj.UserID),
-		SlotID:       int(eventObj.SlotID),
-		InviteeEmail: eventObj.InviteeEmail,
-		InviteeName:  eventObj.InviteeName,
-		InviteeNotes: eventObj.InviteeNotes,
-		CreatedAt:    eventObj.CreatedAt,
-		StartTime:    eventObj.StartTime,
-		EndTime:      eventObj.EndTime,
-	}, nil
-}
-
-func (event Event) GetAll(ctx context.Context, userID int) (contract.EventListResponse, error) {
+// freeSlot checks that a free slot starts at startTime, as computed from the user's availability, overrides, existing
+// bookings and scheduling rules, and returns it along with the limits it is to be booked with. For group event types,
+// a slot which other invitees have booked already but which still has seats left is also returned, leaving it to the
+// repository to join them. The ignored events are not treated as bookings, so that events can be moved to times
+// overlapping their current ones.
+func (event Event) freeSlot(ctx context.Context, userID, eventTypeID int, startTime time.Time, ignored []uint) (model.Slot, model.BookingLimits, error) {
+	if startTime.Before(event.now()) {
+		return model.Slot{}, model.BookingLimits{}, model.ErrSlotUnavailable
+	}
+
+	availability, eventType, err := availabilityForEventType(ctx, event.availabilityRepository, event.eventTypeRepository, userID, eventTypeID)
+	if err != nil {
+		return model.Slot{}, model.BookingLimits{}, err
+	}
+	if err := event.checkBookingWindow(availability.SchedulingRules, startTime); err != nil {
+		return model.Slot{}, model.BookingLimits{}, err
+	}
+	endTime := startTime.Add(time.Duration(availability.MeetingDurationMins) * time.Minute)
+	slots, err := event.calendar.slots(ctx, availability, startTime, endTime, ignored)
+	if err != nil {
+		return model.Slot{}, model.BookingLimits{}, err
+	}
+	if len(slots) == 0 || !slots[0].start.Equal(startTime) {
+		sessions, err := event.calendar.openSessions(ctx, userID, eventType, startTime, endTime, ignored)
+		if err != nil {
+			return model.Slot{}, model.BookingLimits{}, err
+		}
+		if len(sessions) == 0 || !sessions[0].start.Equal(startTime) {
+			return model.Slot{}, model.BookingLimits{}, model.ErrSlotUnavailable
+		}
+		slots = []interval{sessions[0].interval}
+	}
+	loc, err := availability.Location()
+	if err != nil {
+		return model.Slot{}, model.BookingLimits{}, err
+	}
+
+	return model.Slot{
+		UserID:      uint(userID),
+		EventTypeID: uint(eventTypeID),
+		StartTime:   slots[0].start,
+		EndTime:     slots[0].end,
+		Capacity:    eventType.Seats(),
+	}, availability.Limits(startTime, loc), nil
+}
+
+// slotLimits checks that the slot can be booked as per the scheduling rules of its event type, or of the user, and
+// returns the limits it is to be booked with.
+func (event Event) slotLimits(ctx context.Context, slot model.Slot) (model.BookingLimits, error) {
+	availability, _, err := availabilityForEventType(ctx, event.availabilityRepository, event.eventTypeRepository, int(slot.UserID), int(slot.EventTypeID))
+	if err != nil {
+		return model.BookingLimits{}, err
+	}
+	if err := event.checkBookingWindow(availability.SchedulingRules, slot.StartTime); err != nil {
+		return model.BookingLimits{}, err
+	}
+	loc, err := availability.Location()
+	if err != nil {
+		return model.BookingLimits{}, err
+	}
+
+	limits := availability.Limits(slot.StartTime, loc)
+	if err := event.checkNotBlocked(ctx, slot, limits); err != nil {
+		return model.BookingLimits{}, err
+	}
+	return limits, nil
+}
+
+// checkBookingWindow fails with model.ErrSlotUnavailable if a booking starting at startTime would be made too late or
+// too far ahead as per the scheduling rules.
+func (event Event) checkBookingWindow(rules model.SchedulingRules, startTime time.Time) error {
+	earliest, latest := rules.BookingWindow(event.now())
+	if startTime.Before(earliest) {
+		if rules.MinNoticeMins > 0 {
+			return model.ErrSlotUnavailable.Withf("bookings have to be made at least %d minutes ahead", rules.MinNoticeMins)
+		}
+		return model.ErrSlotUnavailable
+	}
+	if !latest.IsZero() && startTime.After(latest) {
+		return model.ErrSlotUnavailable.Withf("bookings can only be made up to %d days ahead", rules.MaxHorizonDays)
+	}
+	return nil
+}
+
+func (event Event) GetAll(ctx context.Context, userID int, loc *time.Location) (contract.EventListResponse, error) {
 	events, err := event.eventRepository.GetAll(ctx, userID)
 	if err != nil {
 		return contract.EventListResponse{}, err
@@ -58,22 +176,239 @@ func (event Event) GetAll(ctx context.Context, userID int) (contract.EventListRe
 
 	resp := make([]contract.EventResponse, 0)
 	for _, eventObj := range events {
-		resp = append(resp, contract.EventResponse{
-			ID:           int(eventObj.ID),
-			UserID:       int(eventObj.UserID),
-			SlotID:       int(eventObj.SlotID),
-			InviteeEmail: eventObj.InviteeEmail,
-			InviteeName:  eventObj.InviteeName,
-			InviteeNotes: eventObj.InviteeNotes,
-			CreatedAt:    eventObj.CreatedAt,
-			StartTime:    eventObj.StartTime,
-			EndTime:      eventObj.EndTime,
-		})
+		resp = append(resp, event.toContract(eventObj, loc))
 	}
 
 	return contract.EventListResponse{Events: resp}, nil
 }
 
-func NewEvent(eventRepository EventRepository, slotRepository SlotRepository) Event {
-	return Event{eventRepository: eventRepository, slotRepository: slotRepository}
+// Cancel cancels an event which has not started yet and frees its seat of the slot for booking again.
+func (event Event) Cancel(ctx context.Context, userID, eventID int, changedBy string, input contract.CancelEvent) (contract.EventResponse, error) {
+	eventObj, err := event.changeableEvent(ctx, userID, eventID)
+	if err != nil {
+		return contract.EventResponse{}, err
+	}
+
+	eventObj, err = event.cancel(ctx, eventObj, changedBy, input)
+	if err != nil {
+		return contract.EventResponse{}, err
+	}
+
+	return event.toContract(eventObj, nil), nil
+}
+
+// cancel cancels the event, which is expected to be changeable.
+func (event Event) cancel(ctx context.Context, eventObj model.Event, changedBy string, input contract.CancelEvent) (model.Event, error) {
+	return event.eventRepository.Cancel(ctx, eventObj, model.EventChange{ChangedBy: changedBy, Reason: input.Reason},
+		event.changeJobs(model.WebhookBookingCancelled))
+}
+
+// Reschedule moves an event which has not started yet to either the slot given by its ID or the free slot starting
+// at the given start time. The new slot has to be of the same event type as the event. Bookings of team event types
+// cannot be rescheduled.
+func (event Event) Reschedule(ctx context.Context, userID, eventID int, changedBy string, input contract.RescheduleEvent) (contract.EventResponse, error) {
+	eventObj, err := event.changeableEvent(ctx, userID, eventID)
+	if err != nil {
+		return contract.EventResponse{}, err
+	}
+
+	eventObj, err = event.reschedule(ctx, eventObj, changedBy, input)
+	if err != nil {
+		return contract.EventResponse{}, err
+	}
+
+	return event.toContract(eventObj, nil), nil
+}
+
+// reschedule moves the event, which is expected to be changeable, to the slot asked for.
+func (event Event) reschedule(ctx context.Context, eventObj model.Event, changedBy string, input contract.RescheduleEvent) (model.Event, error) {
+	if eventObj.TeamEventTypeID != 0 {
+		// The other members of a collective booking, or the turns of a round robin one, would not follow
+		return model.Event{}, model.ErrTeamEvent
+	}
+	var slot model.Slot
+	var limits model.BookingLimits
+	var err error
+	if input.SlotID != 0 {
+		slot, err = event.ownSlot(ctx, eventObj.UserID, input.SlotID)
+		if err != nil {
+			return model.Event{}, err
+		}
+		if slot.EventTypeID != eventObj.EventTypeID {
+			return model.Event{}, model.ErrEventTypeMismatch
+		}
+		limits, err = event.slotLimits(ctx, slot)
+		if err != nil {
+			return model.Event{}, err
+		}
+	} else {
+		slot, limits, err = event.freeSlot(ctx, int(eventObj.UserID), int(eventObj.EventTypeID), input.StartTime, []uint{eventObj.ID})
+		if err != nil {
+			return model.Event{}, err
+		}
+	}
+
+	eventObj, err = event.eventRepository.Reschedule(ctx, eventObj, slot, limits, model.EventChange{ChangedBy: changedBy, Reason: input.Reason},
+		event.changeJobs(model.WebhookBookingRescheduled))
+	if errors.Is(err, sql.ErrNoRows) {
+		// The slot was deleted in the meantime
+		return model.Event{}, model.NotFound("slot", err)
+	}
+	return eventObj, err
+}
+
+// notificationKinds are the notifications sent to the host and the invitee along with each webhook event
+var notificationKinds = map[string]string{
+	model.WebhookBookingCreated:     model.NotificationConfirmation,
+	model.WebhookBookingCancelled:   model.NotificationCancellation,
+	model.WebhookBookingRescheduled: model.NotificationReschedule,
+}
+
+// changeJobs returns the jobs letting the user's external calendars and webhooks, as well as the host and the invitee
+// by email, know about a booking, a cancellation or a reschedule of an event. They are enqueued in the same
+// transaction as the change.
+func (event Event) changeJobs(webhookEvent string) model.EventJobs {
+	occurredAt := event.now()
+	return func(eventObj model.Event) ([]model.Job, error) {
+		// Management tokens are only handed out to invitees
+		data := event.toContract(eventObj, nil)
+		data.ManagementToken = ""
+		encoded, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+
+		return newJobs(occurredAt,
+			jobSpec{jobWriteBack, eventJob{UserID: eventObj.UserID, EventID: eventObj.ID}},
+			jobSpec{jobPublishWebhook, webhookJob{UserID: eventObj.UserID, Event: webhookEvent, OccurredAt: occurredAt, Data: encoded}},
+			jobSpec{jobScheduleNotifications, notificationJob{EventID: eventObj.ID, Kind: notificationKinds[webhookEvent], StartTime: eventObj.StartTime,
+				Cancelled: eventObj.Status == model.EventCancelled}},
+		)
+	}
+}
+
+// GetChanges returns the cancellations and reschedules of an event, oldest first.
+func (event Event) GetChanges(ctx context.Context, userID, eventID int, loc *time.Location) (contract.EventChangeList, error) {
+	if _, err := event.getEvent(ctx, userID, eventID); err != nil {
+		return contract.EventChangeList{}, err
+	}
+
+	changes, err := event.eventRepository.GetChanges(ctx, eventID)
+	if err != nil {
+		return contract.EventChangeList{}, err
+	}
+
+	resp := make([]contract.EventChange, 0)
+	for _, change := range changes {
+		item := contract.EventChange{
+			Action:            change.Action,
+			ChangedBy:         change.ChangedBy,
+			Reason:            change.Reason,
+			PreviousSlotID:    int(change.PreviousSlotID),
+			PreviousStartTime: inLocation(change.PreviousStartTime, loc),
+			PreviousEndTime:   inLocation(change.PreviousEndTime, loc),
+			CreatedAt:         change.CreatedAt,
+		}
+		if change.Action == model.ChangeRescheduled {
+			startTime, endTime := inLocation(change.StartTime, loc), inLocation(change.EndTime, loc)
+			item.StartTime, item.EndTime = &startTime, &endTime
+		}
+		resp = append(resp, item)
+	}
+
+	return contract.EventChangeList{Changes: resp}, nil
+}
+
+func (event Event) getEvent(ctx context.Context, userID, eventID int) (model.Event, error) {
+	eventObj, err := event.eventRepository.GetByID(ctx, userID, eventID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Event{}, model.NotFound("event", err)
+	}
+	return eventObj, err
+}
+
+// changeableEvent returns the event if it can still be cancelled or rescheduled.
+func (event Event) changeableEvent(ctx context.Context, userID, eventID int) (model.Event, error) {
+	eventObj, err := event.getEvent(ctx, userID, eventID)
+	if err != nil {
+		return model.Event{}, err
+	}
+	if err := event.checkChangeable(eventObj); err != nil {
+		return model.Event{}, err
+	}
+	return eventObj, nil
+}
+
+// checkChangeable fails unless the event can still be cancelled or rescheduled.
+func (event Event) checkChangeable(eventObj model.Event) error {
+	if eventObj.Status == model.EventCancelled {
+		return model.ErrEventCancelled
+	}
+	if !eventObj.StartTime.After(event.now()) {
+		return model.ErrEventStarted
+	}
+	return nil
+}
+
+// checkNotBlocked fails with model.ErrSlotUnavailable if the slot, along with the buffers of the limits, overlaps a
+// busy block of the user's external calendars, which may have been imported after the slot was generated.
+func (event Event) checkNotBlocked(ctx context.Context, slot model.Slot, limits model.BookingLimits) error {
+	blocked, err := event.calendar.blockedIntervals(ctx, int(slot.UserID), slot.StartTime.Add(-limits.BeforeBuffer), slot.EndTime.Add(limits.AfterBuffer))
+	if err != nil {
+		return err
+	}
+	if len(blocked) > 0 {
+		return model.ErrSlotUnavailable
+	}
+	return nil
+}
+
+// ownSlot returns the slot if it belongs to the user and sql.ErrNoRows otherwise.
+func (event Event) ownSlot(ctx context.Context, userID uint, slotID int) (model.Slot, error) {
+	slot, err := event.slotRepository.GetByID(ctx, slotID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return model.Slot{}, err
+	}
+	if err != nil || slot.UserID != userID {
+		return model.Slot{}, model.NotFound("slot", sql.ErrNoRows)
+	}
+	return slot, nil
+}
+
+func (event Event) toContract(eventObj model.Event, loc *time.Location) contract.EventResponse {
+	return contract.EventResponse{
+		ID:              int(eventObj.ID),
+		UserID:          int(eventObj.UserID),
+		SlotID:          int(eventObj.SlotID),
+		EventTypeID:     int(eventObj.EventTypeID),
+		TeamEventTypeID: int(eventObj.TeamEventTypeID),
+		SeriesID:        int(eventObj.SeriesID),
+		InviteeEmail:    eventObj.InviteeEmail,
+		InviteeName:     eventObj.InviteeName,
+		InviteeNotes:    eventObj.InviteeNotes,
+		CreatedAt:       eventObj.CreatedAt,
+		StartTime:       inLocation(eventObj.StartTime, loc),
+		EndTime:         inLocation(eventObj.EndTime, loc),
+		Status:          eventObj.Status.String(),
+		CancelReason:    eventObj.CancelReason,
+		ManagementToken: event.tokens.sign(eventObj),
+	}
+}
+
+func NewEvent(eventRepository EventRepository, slotRepository SlotRepository, availabilityRepository UserAvailabilityRepository, overrideRepository AvailabilityOverrideRepository, eventTypeRepository EventTypeRepository, userRepository UserRepository, busyBlockRepository BusyBlockRepository, tokens BookingTokens) Event {
+	return Event{
+		eventRepository:        eventRepository,
+		slotRepository:         slotRepository,
+		availabilityRepository: availabilityRepository,
+		eventTypeRepository:    eventTypeRepository,
+		userRepository:         userRepository,
+		tokens:                 tokens,
+		calendar: calendar{
+			availabilityRepository: availabilityRepository,
+			overrideRepository:     overrideRepository,
+			eventRepository:        eventRepository,
+			busyBlockRepository:    busyBlockRepository,
+		},
+		now: time.Now,
+	}
 }
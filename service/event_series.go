@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// CreateSeries books the free slots starting at the start time and repeating at the given frequency, in the user's
+// time zone. Every occurrence is booked, or none of them is: occurrences which cannot be booked fail the series with
+// model.ErrOccurrencesUnavailable, which tells why each of them failed.
+func (event Event) CreateSeries(ctx context.Context, userID int, input contract.EventSeries) (contract.EventSeriesResponse, error) {
+	loc, err := event.userLocation(ctx, userID, input.EventTypeID)
+	if err != nil {
+		return contract.EventSeriesResponse{}, err
+	}
+
+	series := model.EventSeries{
+		UserID:       uint(userID),
+		EventTypeID:  uint(input.EventTypeID),
+		Frequency:    input.Frequency,
+		Count:        input.Count,
+		Until:        input.Until,
+		InviteeEmail: input.InviteeEmail,
+		InviteeName:  input.InviteeName,
+		InviteeNotes: input.InviteeNotes,
+	}
+	starts := series.Occurrences(input.StartTime, loc)
+	if len(starts) > model.MaxOccurrences {
+		return contract.EventSeriesResponse{}, model.Validation("until", fmt.Sprintf("series should have at most %d occurrences", model.MaxOccurrences))
+	}
+	if len(starts) < 2 {
+		return contract.EventSeriesResponse{}, model.Validation("until", "series should have at least 2 occurrences")
+	}
+
+	events := make([]model.Event, len(starts))
+	for i := range events {
+		events[i] = model.Event{
+			UserID:       series.UserID,
+			InviteeEmail: series.InviteeEmail,
+			InviteeName:  series.InviteeName,
+			InviteeNotes: series.InviteeNotes,
+		}
+	}
+	bookings, err := event.occurrenceBookings(ctx, userID, input.EventTypeID, starts, events)
+	if err != nil {
+		return contract.EventSeriesResponse{}, err
+	}
+
+	series, err = event.eventRepository.BookSeries(ctx, series, bookings, event.changeJobs(model.WebhookBookingCreated))
+	if err != nil {
+		return contract.EventSeriesResponse{}, err
+	}
+
+	return event.seriesToContract(series, nil), nil
+}
+
+// GetSeries returns the series along with its occurrences, earliest first.
+func (event Event) GetSeries(ctx context.Context, userID, seriesID int, loc *time.Location) (contract.EventSeriesResponse, error) {
+	series, err := event.getSeries(ctx, userID, seriesID)
+	if err != nil {
+		return contract.EventSeriesResponse{}, err
+	}
+
+	return event.seriesToContract(series, loc), nil
+}
+
+// CancelSeries cancels the occurrences of the series which have not started yet, leaving the past ones and those
+// cancelled already as they are. Single occurrences are cancelled the same way as any other event.
+func (event Event) CancelSeries(ctx context.Context, userID, seriesID int, changedBy string, input contract.CancelEvent) (contract.EventSeriesResponse, error) {
+	series, upcoming, err := event.upcomingOccurrences(ctx, userID, seriesID)
+	if err != nil {
+		return contract.EventSeriesResponse{}, err
+	}
+
+	cancelled, err := event.eventRepository.CancelSeries(ctx, upcoming, model.EventChange{ChangedBy: changedBy, Reason: input.Reason},
+		event.changeJobs(model.WebhookBookingCancelled))
+	if err != nil {
+		return contract.EventSeriesResponse{}, err
+	}
+
+	return event.seriesToContract(withOccurrences(series, cancelled), nil), nil
+}
+
+// RescheduleSeries moves the occurrences of the series which have not started yet to the free slots starting at the
+// start time and repeating at the frequency of the series. Either every occurrence is moved or none of them is, the
+// same way as CreateSeries books them. Single occurrences are rescheduled the same way as any other event.
+func (event Event) RescheduleSeries(ctx context.Context, userID, seriesID int, changedBy string, input contract.RescheduleSeries) (contract.EventSeriesResponse, error) {
+	series, upcoming, err := event.upcomingOccurrences(ctx, userID, seriesID)
+	if err != nil {
+		return contract.EventSeriesResponse{}, err
+	}
+
+	loc, err := event.userLocation(ctx, userID, int(series.EventTypeID))
+	if err != nil {
+		return contract.EventSeriesResponse{}, err
+	}
+	starts := model.EventSeries{Frequency: series.Frequency, Count: len(upcoming)}.Occurrences(input.StartTime, loc)
+	bookings, err := event.occurrenceBookings(ctx, userID, int(series.EventTypeID), starts, upcoming)
+	if err != nil {
+		return contract.EventSeriesResponse{}, err
+	}
+
+	moved, err := event.eventRepository.RescheduleSeries(ctx, bookings, model.EventChange{ChangedBy: changedBy, Reason: input.Reason},
+		event.changeJobs(model.WebhookBookingRescheduled))
+	if err != nil {
+		return contract.EventSeriesResponse{}, err
+	}
+
+	return event.seriesToContract(withOccurrences(series, moved), nil), nil
+}
+
+// occurrenceBookings checks that a free slot starts at each of the start times, as freeSlot does for single bookings,
+// and returns the bookings of the events at them. Events which are saved already are being moved together, so none
+// of them is treated as a booking. Occurrences without a free slot fail together with model.ErrOccurrencesUnavailable,
+// which tells why each of them failed by their start time.
+func (event Event) occurrenceBookings(ctx context.Context, userID, eventTypeID int, starts []time.Time, events []model.Event) ([]model.Booking, error) {
+	moving := make([]uint, 0, len(events))
+	for _, eventObj := range events {
+		if eventObj.ID != 0 {
+			moving = append(moving, eventObj.ID)
+		}
+	}
+
+	bookings := make([]model.Booking, 0, len(starts))
+	failed := make(map[string]string)
+	for i, start := range starts {
+		slot, limits, err := event.freeSlot(ctx, userID, eventTypeID, start, moving)
+		var domainErr *model.Error
+		if errors.As(err, &domainErr) && domainErr.Kind == model.KindConflict {
+			failed[start.Format(time.RFC3339)] = domainErr.Message
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		bookings = append(bookings, model.Booking{Event: events[i], Slot: slot, Limits: limits})
+	}
+
+	if len(failed) > 0 {
+		return nil, model.ErrOccurrencesUnavailable.WithFields(failed)
+	}
+	return bookings, nil
+}
+
+// upcomingOccurrences returns the series along with its confirmed occurrences which have not started yet, failing
+// with model.ErrSeriesOver if there are none.
+func (event Event) upcomingOccurrences(ctx context.Context, userID, seriesID int) (model.EventSeries, []model.Event, error) {
+	series, err := event.getSeries(ctx, userID, seriesID)
+	if err != nil {
+		return model.EventSeries{}, nil, err
+	}
+
+	upcoming := make([]model.Event, 0, len(series.Events))
+	for _, eventObj := range series.Events {
+		if eventObj.Status == model.EventConfirmed && eventObj.StartTime.After(event.now()) {
+			upcoming = append(upcoming, eventObj)
+		}
+	}
+	if len(upcoming) == 0 {
+		return model.EventSeries{}, nil, model.ErrSeriesOver
+	}
+	return series, upcoming, nil
+}
+
+// withOccurrences returns the series whose occurrences are replaced by the given changed ones, matching them by ID.
+func withOccurrences(series model.EventSeries, changed []model.Event) model.EventSeries {
+	byID := make(map[uint]model.Event, len(changed))
+	for _, eventObj := range changed {
+		byID[eventObj.ID] = eventObj
+	}
+
+	events := make([]model.Event, len(series.Events))
+	for i, eventObj := range series.Events {
+		if changedObj, ok := byID[eventObj.ID]; ok {
+			eventObj = changedObj
+		}
+		events[i] = eventObj
+	}
+	series.Events = events
+	return series
+}
+
+func (event Event) getSeries(ctx context.Context, userID, seriesID int) (model.EventSeries, error) {
+	series, err := event.eventRepository.GetSeries(ctx, userID, seriesID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.EventSeries{}, model.NotFound("event series", err)
+	}
+	return series, err
+}
+
+// userLocation returns the time zone of the user's availability, which occurrences repeat in.
+func (event Event) userLocation(ctx context.Context, userID, eventTypeID int) (*time.Location, error) {
+	availability, _, err := availabilityForEventType(ctx, event.availabilityRepository, event.eventTypeRepository, userID, eventTypeID)
+	if err != nil {
+		return nil, err
+	}
+	return availability.Location()
+}
+
+func (event Event) seriesToContract(series model.EventSeries, loc *time.Location) contract.EventSeriesResponse {
+	resp := contract.EventSeriesResponse{
+		ID:           int(series.ID),
+		UserID:       int(series.UserID),
+		EventTypeID:  int(series.EventTypeID),
+		Frequency:    series.Frequency,
+		Count:        series.Count,
+		InviteeEmail: series.InviteeEmail,
+		InviteeName:  series.InviteeName,
+		InviteeNotes: series.InviteeNotes,
+		CreatedAt:    series.CreatedAt,
+		Events:       make([]contract.EventResponse, 0, len(series.Events)),
+	}
+	if !series.Until.IsZero() {
+		until := inLocation(series.Until, loc)
+		resp.Until = &until
+	}
+	for _, eventObj := range series.Events {
+		resp.Events = append(resp.Events, event.toContract(eventObj, loc))
+	}
+	return resp
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/mock"
+	"gorm.io/datatypes"
+)
+
+func (suite *EventTestSuite) mondayMornings() {
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+}
+
+func (suite *EventTestSuite) TestCreateSeriesBooksEveryOccurrence() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.mondayMornings()
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Event{}, nil)
+	invitee := model.Event{UserID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"}
+	bookings := make([]model.Booking, 0)
+	booked := make([]model.Event, 0)
+	for i, occurrence := range []time.Time{start, start.AddDate(0, 0, 14), start.AddDate(0, 0, 28)} {
+		bookings = append(bookings, model.Booking{Event: invitee,
+			Slot: model.Slot{UserID: 1, StartTime: occurrence, EndTime: occurrence.Add(30 * time.Minute), Capacity: 1}})
+		booked = append(booked, model.Event{ID: uint(i + 1), UserID: 1, SlotID: uint(i + 7), SeriesID: 4,
+			StartTime: occurrence, EndTime: occurrence.Add(30 * time.Minute)})
+	}
+	series := model.EventSeries{UserID: 1, Frequency: model.FrequencyBiweekly, Count: 3, InviteeName: "test", InviteeEmail: "test@example.xyz"}
+	bookedSeries := series
+	bookedSeries.ID, bookedSeries.Events = 4, booked
+	suite.mockEventRepository.On("BookSeries", suite.ctx, series, bookings).Return(bookedSeries, nil)
+
+	resp, err := suite.service.CreateSeries(suite.ctx, 1, contract.EventSeries{StartTime: start, Frequency: model.FrequencyBiweekly, Count: 3,
+		InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.NoError(err)
+	suite.Equal(4, resp.ID)
+	suite.Len(resp.Events, 3)
+	suite.Equal(4, resp.Events[2].SeriesID)
+	suite.Equal(start.AddDate(0, 0, 28), resp.Events[2].StartTime)
+	suite.Len(suite.mockEventRepository.Jobs, 9)
+}
+
+func (suite *EventTestSuite) TestCreateSeriesReportsOccurrencesWhichAreNotFree() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.mondayMornings()
+	busy := start.AddDate(0, 0, 7)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Event{
+		{ID: 9, UserID: 1, StartTime: busy, EndTime: busy.Add(30 * time.Minute)},
+	}, nil)
+
+	resp, err := suite.service.CreateSeries(suite.ctx, 1, contract.EventSeries{StartTime: start, Frequency: model.FrequencyWeekly,
+		Until: start.AddDate(0, 0, 21), InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, model.ErrOccurrencesUnavailable)
+	suite.Empty(resp)
+	var domainErr *model.Error
+	suite.ErrorAs(err, &domainErr)
+	suite.Equal(map[string]string{"2023-06-12T10:00:00Z": "requested time is not available"}, domainErr.Fields)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "BookSeries", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestCreateSeriesReturnsErrorIfItHasTooManyOccurrences() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC)
+	suite.mondayMornings()
+
+	_, err := suite.service.CreateSeries(suite.ctx, 1, contract.EventSeries{StartTime: start, Frequency: model.FrequencyWeekly,
+		Until: start.AddDate(2, 0, 0), InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	var domainErr *model.Error
+	suite.ErrorAs(err, &domainErr)
+	suite.Equal(model.KindValidation, domainErr.Kind)
+	suite.Contains(domainErr.Fields, "until")
+}
+
+func (suite *EventTestSuite) TestCancelSeriesCancelsUpcomingOccurrencesOnly() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 10, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC)
+	past := model.Event{ID: 1, UserID: 1, SeriesID: 4, StartTime: start, Status: model.EventConfirmed}
+	cancelled := model.Event{ID: 2, UserID: 1, SeriesID: 4, StartTime: start.AddDate(0, 0, 7), Status: model.EventCancelled}
+	upcoming := model.Event{ID: 3, UserID: 1, SeriesID: 4, StartTime: start.AddDate(0, 0, 14), Status: model.EventConfirmed}
+	suite.mockEventRepository.On("GetSeries", suite.ctx, 1, 4).Return(model.EventSeries{ID: 4, UserID: 1,
+		Events: []model.Event{past, cancelled, upcoming}}, nil)
+	change := model.EventChange{ChangedBy: model.ChangedByHost, Reason: "moving away"}
+	suite.mockEventRepository.On("CancelSeries", suite.ctx, []model.Event{upcoming}, change).Return([]model.Event{
+		{ID: 3, UserID: 1, SeriesID: 4, StartTime: upcoming.StartTime, Status: model.EventCancelled, CancelReason: "moving away"},
+	}, nil)
+
+	resp, err := suite.service.CancelSeries(suite.ctx, 1, 4, model.ChangedByHost, contract.CancelEvent{Reason: "moving away"})
+	suite.NoError(err)
+	suite.Equal([]string{"confirmed", "cancelled", "cancelled"},
+		[]string{resp.Events[0].Status, resp.Events[1].Status, resp.Events[2].Status})
+	suite.Equal("moving away", resp.Events[2].CancelReason)
+}
+
+func (suite *EventTestSuite) TestCancelSeriesReturnsErrorIfNoOccurrenceIsUpcoming() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 10, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC)
+	suite.mockEventRepository.On("GetSeries", suite.ctx, 1, 4).Return(model.EventSeries{ID: 4, UserID: 1,
+		Events: []model.Event{{ID: 1, UserID: 1, SeriesID: 4, StartTime: start, Status: model.EventConfirmed}}}, nil)
+
+	_, err := suite.service.CancelSeries(suite.ctx, 1, 4, model.ChangedByHost, contract.CancelEvent{})
+	suite.ErrorIs(err, model.ErrSeriesOver)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "CancelSeries", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestRescheduleSeriesMovesUpcomingOccurrencesIgnoringThemselves() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.mondayMornings()
+	occurrences := []model.Event{
+		{ID: 1, UserID: 1, SlotID: 7, SeriesID: 4, StartTime: start, EndTime: start.Add(30 * time.Minute), Status: model.EventConfirmed},
+		{ID: 2, UserID: 1, SlotID: 8, SeriesID: 4, StartTime: start.AddDate(0, 0, 7), EndTime: start.AddDate(0, 0, 7).Add(30 * time.Minute),
+			Status: model.EventConfirmed},
+	}
+	suite.mockEventRepository.On("GetSeries", suite.ctx, 1, 4).Return(model.EventSeries{ID: 4, UserID: 1, Frequency: model.FrequencyWeekly,
+		Count: 2, Events: occurrences}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return(occurrences, nil)
+	newStart := start.Add(30 * time.Minute)
+	bookings := make([]model.Booking, 0)
+	moved := make([]model.Event, 0)
+	for i, occurrence := range []time.Time{newStart, newStart.AddDate(0, 0, 7)} {
+		bookings = append(bookings, model.Booking{Event: occurrences[i],
+			Slot: model.Slot{UserID: 1, StartTime: occurrence, EndTime: occurrence.Add(30 * time.Minute), Capacity: 1}})
+		moved = append(moved, model.Event{ID: occurrences[i].ID, UserID: 1, SlotID: uint(i + 9), SeriesID: 4,
+			StartTime: occurrence, EndTime: occurrence.Add(30 * time.Minute), Status: model.EventConfirmed})
+	}
+	suite.mockEventRepository.On("RescheduleSeries", suite.ctx, bookings, model.EventChange{ChangedBy: model.ChangedByHost}).Return(moved, nil)
+
+	resp, err := suite.service.RescheduleSeries(suite.ctx, 1, 4, model.ChangedByHost, contract.RescheduleSeries{StartTime: newStart})
+	suite.NoError(err)
+	suite.Equal(newStart.AddDate(0, 0, 7), resp.Events[1].StartTime)
+	suite.Equal(10, resp.Events[1].SlotID)
+}
+
+func (suite *EventTestSuite) TestRescheduleSeriesMovesOccurrencesAWeekLaterOntoEachOther() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.mondayMornings()
+	occurrences := []model.Event{
+		{ID: 1, UserID: 1, SlotID: 7, SeriesID: 4, StartTime: start, EndTime: start.Add(30 * time.Minute), Status: model.EventConfirmed},
+		{ID: 2, UserID: 1, SlotID: 8, SeriesID: 4, StartTime: start.AddDate(0, 0, 7), EndTime: start.AddDate(0, 0, 7).Add(30 * time.Minute),
+			Status: model.EventConfirmed},
+	}
+	suite.mockEventRepository.On("GetSeries", suite.ctx, 1, 4).Return(model.EventSeries{ID: 4, UserID: 1, Frequency: model.FrequencyWeekly,
+		Count: 2, Events: occurrences}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return(occurrences, nil)
+	// The first occurrence moves to the time of the second one, which moves along with it
+	newStart := start.AddDate(0, 0, 7)
+	bookings := make([]model.Booking, 0)
+	moved := make([]model.Event, 0)
+	for i, occurrence := range []time.Time{newStart, newStart.AddDate(0, 0, 7)} {
+		bookings = append(bookings, model.Booking{Event: occurrences[i],
+			Slot: model.Slot{UserID: 1, StartTime: occurrence, EndTime: occurrence.Add(30 * time.Minute), Capacity: 1}})
+		moved = append(moved, model.Event{ID: occurrences[i].ID, UserID: 1, SlotID: uint(i + 8), SeriesID: 4,
+			StartTime: occurrence, EndTime: occurrence.Add(30 * time.Minute), Status: model.EventConfirmed})
+	}
+	suite.mockEventRepository.On("RescheduleSeries", suite.ctx, bookings, model.EventChange{ChangedBy: model.ChangedByHost}).Return(moved, nil)
+
+	resp, err := suite.service.RescheduleSeries(suite.ctx, 1, 4, model.ChangedByHost, contract.RescheduleSeries{StartTime: newStart})
+	suite.NoError(err)
+	suite.Equal(newStart, resp.Events[0].StartTime)
+	suite.Equal(newStart.AddDate(0, 0, 7), resp.Events[1].StartTime)
+}
+
+func (suite *EventTestSuite) TestGetSeriesReturnsNotFoundIfSeriesDoesNotExist() {
+	suite.mockEventRepository.On("GetSeries", suite.ctx, 1, 4).Return(model.EventSeries{}, sql.ErrNoRows)
+
+	_, err := suite.service.GetSeries(suite.ctx, 1, 4, nil)
+	var domainErr *model.Error
+	suite.ErrorAs(err, &domainErr)
+	suite.Equal(model.KindNotFound, domainErr.Kind)
+}
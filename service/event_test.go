@@ -2,6 +2,8 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -9,26 +11,41 @@ import (
 	"github.com/harbor-xyz/coding-project/contract"
 	"github.com/harbor-xyz/coding-project/model"
 
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/datatypes"
 )
 
 type EventTestSuite struct {
 	suite.Suite
-	service             Event
-	mockEventRepository *MockEventRepository
-	mockSlotRepository  *MockSlotRepository
-	ctx                 context.Context
+	service                    Event
+	mockEventRepository        *MockEventRepository
+	mockSlotRepository         *MockSlotRepository
+	mockAvailabilityRepository *MockUserAvailabilityRepository
+	mockOverrideRepository     *MockAvailabilityOverrideRepository
+	mockEventTypeRepository    *MockEventTypeRepository
+	mockUserRepository         *MockUserRepository
+	mockBusyBlockRepository    *MockBusyBlockRepository
+	ctx                        context.Context
 }
 
 func (suite *EventTestSuite) SetupTest() {
 	suite.mockEventRepository = &MockEventRepository{}
 	suite.mockSlotRepository = &MockSlotRepository{}
-	suite.service = NewEvent(suite.mockEventRepository, suite.mockSlotRepository)
+	suite.mockAvailabilityRepository = &MockUserAvailabilityRepository{}
+	suite.mockOverrideRepository = &MockAvailabilityOverrideRepository{}
+	suite.mockEventTypeRepository = &MockEventTypeRepository{}
+	suite.mockUserRepository = &MockUserRepository{}
+	suite.mockBusyBlockRepository = &MockBusyBlockRepository{}
+	// Users have no external calendars unless a test says otherwise
+	suite.mockBusyBlockRepository.On("GetInRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.BusyBlock{}, nil).Maybe()
+	suite.service = NewEvent(suite.mockEventRepository, suite.mockSlotRepository, suite.mockAvailabilityRepository, suite.mockOverrideRepository, suite.mockEventTypeRepository,
+		suite.mockUserRepository, suite.mockBusyBlockRepository, NewBookingTokens([]byte("secret")))
 	suite.ctx = context.Background()
 }
 
 func (suite *EventTestSuite) TestCreateHappyFlow() {
-	now := time.Now()
+	now := time.Now().Add(time.Hour)
 	input := contract.Event{
 		SlotID:       1,
 		InviteeName:  "test",
@@ -52,9 +69,9 @@ func (suite *EventTestSuite) TestCreateHappyFlow() {
 		EndTime:   now.Add(30 * time.Minute),
 		Status:    model.StatusCreated,
 	}, nil)
-	suite.mockEventRepository.On("Create", suite.ctx, model.Event{UserID: 1, SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz", StartTime: now, EndTime: now.Add(30 * time.Minute)}).
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1}, nil)
+	suite.mockEventRepository.On("BookSlot", suite.ctx, model.Event{UserID: 1, SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"}, model.BookingLimits{}).
 		Return(expectedResp, nil)
-	suite.mockSlotRepository.On("BookSlot", suite.ctx, 1).Return(nil)
 
 	resp, err := suite.service.Create(suite.ctx, 1, input)
 	suite.Nil(err)
@@ -65,7 +82,7 @@ func (suite *EventTestSuite) TestCreateHappyFlow() {
 }
 
 func (suite *EventTestSuite) TestCreateShouldReturnErrorIfRepositoryReturnsError() {
-	now := time.Now()
+	now := time.Now().Add(time.Hour)
 	input := contract.Event{
 		SlotID:       1,
 		InviteeName:  "test",
@@ -78,7 +95,8 @@ func (suite *EventTestSuite) TestCreateShouldReturnErrorIfRepositoryReturnsError
 		EndTime:   now.Add(30 * time.Minute),
 		Status:    model.StatusCreated,
 	}, nil)
-	suite.mockEventRepository.On("Create", suite.ctx, model.Event{UserID: 1, SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz", StartTime: now, EndTime: now.Add(30 * time.Minute)}).
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1}, nil)
+	suite.mockEventRepository.On("BookSlot", suite.ctx, model.Event{UserID: 1, SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"}, model.BookingLimits{}).
 		Return(model.Event{}, errors.New("some error"))
 
 	resp, err := suite.service.Create(suite.ctx, 1, input)
@@ -86,6 +104,258 @@ func (suite *EventTestSuite) TestCreateShouldReturnErrorIfRepositoryReturnsError
 	suite.Empty(resp)
 }
 
+func (suite *EventTestSuite) TestCreateShouldReturnErrorIfSlotBelongsToAnotherEventType() {
+	input := contract.Event{
+		SlotID:       1,
+		EventTypeID:  3,
+		InviteeName:  "test",
+		InviteeEmail: "test@example.xyz",
+	}
+	suite.mockSlotRepository.On("GetByID", suite.ctx, 1).Return(model.Slot{
+		ID:          1,
+		UserID:      1,
+		EventTypeID: 2,
+		Status:      model.StatusCreated,
+	}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, input)
+	suite.ErrorIs(err, model.ErrEventTypeMismatch)
+	suite.Empty(resp)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "BookSlot", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestCreateShouldReturnNotFoundIfSlotBelongsToAnotherUser() {
+	suite.mockSlotRepository.On("GetByID", suite.ctx, 1).Return(model.Slot{ID: 1, UserID: 2, Status: model.StatusCreated}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.Event{SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.Equal("slot not found: sql: no rows in result set", err.Error())
+	suite.Empty(resp)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "BookSlot", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestCreateShouldReturnConflictIfSlotIsAlreadyBooked() {
+	start := time.Now().Add(time.Hour)
+	suite.mockSlotRepository.On("GetByID", suite.ctx, 1).Return(model.Slot{ID: 1, UserID: 1, StartTime: start, EndTime: start.Add(30 * time.Minute), Status: model.StatusBooked}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1}, nil)
+	suite.mockEventRepository.On("BookSlot", suite.ctx, model.Event{UserID: 1, SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"}, model.BookingLimits{}).
+		Return(model.Event{}, model.ErrSlotAlreadyBooked)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.Event{SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, model.ErrSlotAlreadyBooked)
+	suite.Empty(resp)
+}
+
+func (suite *EventTestSuite) TestCreateShouldReturnErrorIfSlotIsBlockedByExternalCalendar() {
+	now := time.Now()
+	slot := model.Slot{ID: 1, UserID: 1, StartTime: now.Add(time.Hour), EndTime: now.Add(90 * time.Minute), Status: model.StatusCreated}
+	suite.mockSlotRepository.On("GetByID", suite.ctx, 1).Return(slot, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1}, nil)
+	suite.mockBusyBlockRepository.ExpectedCalls = nil
+	suite.mockBusyBlockRepository.On("GetInRange", suite.ctx, 1, slot.StartTime, slot.EndTime).Return([]model.BusyBlock{
+		{UserID: 1, StartTime: now.Add(80 * time.Minute), EndTime: now.Add(2 * time.Hour)},
+	}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.Event{SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+	suite.Empty(resp)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "BookSlot", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestCreateByStartTimeBooksComputedSlot() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	input := contract.Event{
+		StartTime:    start,
+		EventTypeID:  2,
+		InviteeName:  "test",
+		InviteeEmail: "test@example.xyz",
+	}
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 2).Return(model.EventType{ID: 2, UserID: 1, DurationMins: 60}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, start, start.Add(time.Hour)).Return([]model.Event{}, nil)
+	suite.mockEventRepository.On("BookTime", suite.ctx,
+		model.Event{UserID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"},
+		model.Slot{UserID: 1, EventTypeID: 2, StartTime: start, EndTime: start.Add(time.Hour), Capacity: 1},
+		model.BookingLimits{},
+	).Return(model.Event{ID: 1, UserID: 1, SlotID: 7, EventTypeID: 2, StartTime: start, EndTime: start.Add(time.Hour)}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, input)
+	suite.Nil(err)
+	suite.Equal(7, resp.SlotID)
+	suite.Equal(2, resp.EventTypeID)
+	suite.Equal(start.Add(time.Hour), resp.EndTime)
+}
+
+func (suite *EventTestSuite) TestCreateByStartTimeJoinsSlotOfGroupEventTypeWithSeatsLeft() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 2).Return(model.EventType{ID: 2, UserID: 1, DurationMins: 60, Capacity: 2}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, start, start.Add(time.Hour)).Return([]model.Event{
+		{ID: 1, UserID: 1, SlotID: 7, EventTypeID: 2, StartTime: start, EndTime: start.Add(time.Hour)},
+	}, nil)
+	suite.mockEventRepository.On("BookTime", suite.ctx,
+		model.Event{UserID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"},
+		model.Slot{UserID: 1, EventTypeID: 2, StartTime: start, EndTime: start.Add(time.Hour), Capacity: 2},
+		model.BookingLimits{},
+	).Return(model.Event{ID: 2, UserID: 1, SlotID: 7, EventTypeID: 2, StartTime: start, EndTime: start.Add(time.Hour)}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.Event{StartTime: start, EventTypeID: 2, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.Nil(err)
+	suite.Equal(7, resp.SlotID)
+}
+
+func (suite *EventTestSuite) TestCreateByStartTimeReturnsErrorIfSlotOfGroupEventTypeIsFull() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 2).Return(model.EventType{ID: 2, UserID: 1, DurationMins: 60, Capacity: 2}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, start, start.Add(time.Hour)).Return([]model.Event{
+		{ID: 1, UserID: 1, SlotID: 7, EventTypeID: 2, StartTime: start, EndTime: start.Add(time.Hour)},
+		{ID: 2, UserID: 1, SlotID: 7, EventTypeID: 2, StartTime: start, EndTime: start.Add(time.Hour)},
+	}, nil)
+
+	_, err := suite.service.Create(suite.ctx, 1, contract.Event{StartTime: start, EventTypeID: 2, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "BookTime", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestCreateByStartTimeUsesSchedulingRulesOfEventType() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+		SchedulingRules:     model.SchedulingRules{MinNoticeMins: 60},
+	}, nil)
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 2).Return(model.EventType{ID: 2, UserID: 1, DurationMins: 30,
+		SchedulingRules: &model.SchedulingRules{AfterBufferMins: 15, MaxPerDay: 3}}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, start, start.Add(45*time.Minute)).Return([]model.Event{}, nil)
+	suite.mockEventRepository.On("BookTime", suite.ctx,
+		model.Event{UserID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"},
+		model.Slot{UserID: 1, EventTypeID: 2, StartTime: start, EndTime: start.Add(30 * time.Minute), Capacity: 1},
+		model.BookingLimits{AfterBuffer: 15 * time.Minute, Caps: []model.BookingCap{
+			{Start: time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC), End: time.Date(2023, 6, 6, 0, 0, 0, 0, time.UTC), Max: 3},
+		}},
+	).Return(model.Event{ID: 1, UserID: 1, SlotID: 7, EventTypeID: 2, StartTime: start, EndTime: start.Add(30 * time.Minute)}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.Event{StartTime: start, EventTypeID: 2, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.Nil(err)
+	suite.Equal(7, resp.SlotID)
+}
+
+func (suite *EventTestSuite) TestCreateByStartTimeReturnsErrorIfNoticeIsTooShort() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 5, 9, 30, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+		SchedulingRules:     model.SchedulingRules{MinNoticeMins: 60},
+	}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.Event{StartTime: start, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+	suite.EqualError(err, "requested time is not available: bookings have to be made at least 60 minutes ahead")
+	suite.Empty(resp)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "BookTime", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestCreateReturnsErrorIfSlotIsBeyondHorizon() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 15, 10, 0, 0, 0, time.UTC)
+	suite.mockSlotRepository.On("GetByID", suite.ctx, 1).Return(model.Slot{ID: 1, UserID: 1, StartTime: start, EndTime: start.Add(30 * time.Minute), Status: model.StatusCreated}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1, SchedulingRules: model.SchedulingRules{MaxHorizonDays: 7}}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.Event{SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+	suite.Empty(resp)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "BookSlot", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestCreateReturnsErrorIfBookingLimitIsReached() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 7, 10, 0, 0, 0, time.UTC) // wednesday
+	suite.mockSlotRepository.On("GetByID", suite.ctx, 1).Return(model.Slot{ID: 1, UserID: 1, StartTime: start, EndTime: start.Add(30 * time.Minute), Status: model.StatusCreated}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1, SchedulingRules: model.SchedulingRules{MaxPerWeek: 5}}, nil)
+	// Weeks start on Monday
+	suite.mockEventRepository.On("BookSlot", suite.ctx, model.Event{UserID: 1, SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"}, model.BookingLimits{Caps: []model.BookingCap{
+		{Start: time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC), End: time.Date(2023, 6, 12, 0, 0, 0, 0, time.UTC), Max: 5},
+	}}).Return(model.Event{}, model.ErrBookingLimit)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.Event{SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, model.ErrBookingLimit)
+	suite.Empty(resp)
+}
+
+func (suite *EventTestSuite) TestCreateByStartTimeReturnsErrorIfTimeIsNotFree() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC) // monday
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Event{
+		{UserID: 1, StartTime: start, EndTime: start.Add(30 * time.Minute)},
+	}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.Event{StartTime: start, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+	suite.Empty(resp)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "BookTime", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestCreateByStartTimeReturnsErrorIfTimeIsNotAligned() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 9, 10, 0, 0, time.UTC) // monday
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Event{}, nil)
+
+	_, err := suite.service.Create(suite.ctx, 1, contract.Event{StartTime: start, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+}
+
 func (suite *EventTestSuite) TestGetAllHappyFlow() {
 	now := time.Now()
 	suite.mockEventRepository.On("GetAll", suite.ctx, 1).Return([]model.Event{
@@ -111,19 +381,239 @@ func (suite *EventTestSuite) TestGetAllHappyFlow() {
 		},
 	}, nil)
 
-	resp, err := suite.service.GetAll(suite.ctx, 1)
+	resp, err := suite.service.GetAll(suite.ctx, 1, nil)
 	suite.NoError(err)
 	suite.Equal(2, len(resp.Events))
 }
 
+func (suite *EventTestSuite) TestGetAllRendersTimesInRequestedTimeZone() {
+	start := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	suite.mockEventRepository.On("GetAll", suite.ctx, 1).Return([]model.Event{
+		{
+			ID:           1,
+			UserID:       1,
+			SlotID:       1,
+			InviteeEmail: "test@example.xyz",
+			InviteeName:  "test",
+			StartTime:    start,
+			EndTime:      start.Add(30 * time.Minute),
+			CreatedAt:    start,
+		},
+	}, nil)
+	loc, _ := time.LoadLocation("Asia/Kolkata")
+
+	resp, err := suite.service.GetAll(suite.ctx, 1, loc)
+	suite.NoError(err)
+	suite.Equal("2023-06-01T15:30:00+05:30", resp.Events[0].StartTime.Format(time.RFC3339))
+	suite.Equal("2023-06-01T16:00:00+05:30", resp.Events[0].EndTime.Format(time.RFC3339))
+}
+
 func (suite *EventTestSuite) TestGetAllReturnsErrorIfRepositoryReturnsError() {
 	suite.mockEventRepository.On("GetAll", suite.ctx, 1).Return([]model.Event{}, errors.New("some error"))
 
-	resp, err := suite.service.GetAll(suite.ctx, 1)
+	resp, err := suite.service.GetAll(suite.ctx, 1, nil)
 	suite.Equal("some error", err.Error())
 	suite.Empty(resp)
 }
 
+func (suite *EventTestSuite) TestCancelCancelsEventAndRecordsWhoCancelled() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	eventObj := model.Event{ID: 3, UserID: 1, SlotID: 5, StartTime: start, EndTime: start.Add(30 * time.Minute)}
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(eventObj, nil)
+	cancelled := eventObj
+	cancelled.Status = model.EventCancelled
+	cancelled.CancelReason = "sick"
+	suite.mockEventRepository.On("Cancel", suite.ctx, eventObj, model.EventChange{ChangedBy: model.ChangedByHost, Reason: "sick"}).
+		Return(cancelled, nil)
+
+	resp, err := suite.service.Cancel(suite.ctx, 1, 3, model.ChangedByHost, contract.CancelEvent{Reason: "sick"})
+	suite.NoError(err)
+	suite.Equal("cancelled", resp.Status)
+	suite.Equal("sick", resp.CancelReason)
+}
+
+func (suite *EventTestSuite) TestCreateEnqueuesFollowUpsOfBookingWithoutManagementToken() {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.service.now = func() time.Time { return now }
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	suite.mockSlotRepository.On("GetByID", suite.ctx, 5).Return(model.Slot{ID: 5, UserID: 1, StartTime: start, EndTime: start.Add(30 * time.Minute)}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1}, nil)
+	suite.mockEventRepository.On("BookSlot", suite.ctx, mock.Anything, mock.Anything).Return(model.Event{ID: 3, UserID: 1, SlotID: 5, InviteeName: "test",
+		InviteeEmail: "test@example.xyz", StartTime: start, EndTime: start.Add(30 * time.Minute)}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.Event{SlotID: 5, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.NoError(err)
+	suite.NotEmpty(resp.ManagementToken)
+	jobs := suite.mockEventRepository.Jobs
+	suite.Len(jobs, 3)
+	suite.Equal(jobWriteBack, jobs[0].Kind)
+	suite.JSONEq(`{"user_id":1,"event_id":3}`, string(jobs[0].Payload))
+	suite.Equal(jobPublishWebhook, jobs[1].Kind)
+	published := webhookJob{}
+	suite.NoError(json.Unmarshal(jobs[1].Payload, &published))
+	suite.Equal(model.WebhookBookingCreated, published.Event)
+	suite.True(now.Equal(published.OccurredAt))
+	suite.Contains(string(published.Data), `"invitee_email":"test@example.xyz"`)
+	suite.NotContains(string(published.Data), "management_token")
+	suite.Equal(jobScheduleNotifications, jobs[2].Kind)
+	suite.JSONEq(`{"event_id":3,"kind":"confirmation","start_time":"2023-06-05T09:00:00Z","cancelled":false}`, string(jobs[2].Payload))
+	for _, job := range jobs {
+		suite.Equal(model.JobPending, job.Status)
+		suite.Equal(now, job.RunAt)
+	}
+}
+
+func (suite *EventTestSuite) TestCancelEnqueuesFollowUpsOfCancellation() {
+	eventObj := model.Event{ID: 3, UserID: 1, SlotID: 5, StartTime: time.Now().Add(time.Hour), EndTime: time.Now().Add(90 * time.Minute)}
+	cancelled := eventObj
+	cancelled.Status = model.EventCancelled
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(eventObj, nil)
+	suite.mockEventRepository.On("Cancel", suite.ctx, eventObj, mock.Anything).Return(cancelled, nil)
+
+	_, err := suite.service.Cancel(suite.ctx, 1, 3, model.ChangedByHost, contract.CancelEvent{})
+	suite.NoError(err)
+	jobs := suite.mockEventRepository.Jobs
+	suite.Len(jobs, 3)
+	published := webhookJob{}
+	suite.NoError(json.Unmarshal(jobs[1].Payload, &published))
+	suite.Equal(model.WebhookBookingCancelled, published.Event)
+	scheduled := notificationJob{}
+	suite.NoError(json.Unmarshal(jobs[2].Payload, &scheduled))
+	suite.Equal(model.NotificationCancellation, scheduled.Kind)
+	suite.True(scheduled.Cancelled)
+}
+
+func (suite *EventTestSuite) TestCreateEnqueuesNothingIfBookingFails() {
+	now := time.Now()
+	suite.mockSlotRepository.On("GetByID", suite.ctx, 1).Return(model.Slot{ID: 1, UserID: 1, StartTime: now.Add(time.Hour), EndTime: now.Add(90 * time.Minute)}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1}, nil)
+	suite.mockEventRepository.On("BookSlot", suite.ctx, mock.Anything, mock.Anything).Return(model.Event{}, model.ErrSlotAlreadyBooked)
+
+	_, err := suite.service.Create(suite.ctx, 1, contract.Event{SlotID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, model.ErrSlotAlreadyBooked)
+	suite.Empty(suite.mockEventRepository.Jobs)
+}
+
+func (suite *EventTestSuite) TestCancelReturnsNotFoundIfEventDoesNotExist() {
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(model.Event{}, sql.ErrNoRows)
+
+	resp, err := suite.service.Cancel(suite.ctx, 1, 3, model.ChangedByHost, contract.CancelEvent{})
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.Equal("event not found: sql: no rows in result set", err.Error())
+	suite.Empty(resp)
+}
+
+func (suite *EventTestSuite) TestCancelReturnsErrorIfEventIsAlreadyCancelled() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(model.Event{
+		ID: 3, UserID: 1, StartTime: time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC), Status: model.EventCancelled,
+	}, nil)
+
+	resp, err := suite.service.Cancel(suite.ctx, 1, 3, model.ChangedByHost, contract.CancelEvent{})
+	suite.ErrorIs(err, model.ErrEventCancelled)
+	suite.Empty(resp)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "Cancel", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestCancelReturnsErrorIfEventHasStarted() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 5, 9, 10, 0, 0, time.UTC) }
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(model.Event{
+		ID: 3, UserID: 1, StartTime: time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC),
+	}, nil)
+
+	resp, err := suite.service.Cancel(suite.ctx, 1, 3, model.ChangedByHost, contract.CancelEvent{})
+	suite.ErrorIs(err, model.ErrEventStarted)
+	suite.Empty(resp)
+}
+
+func (suite *EventTestSuite) TestRescheduleMovesEventToGivenSlot() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	eventObj := model.Event{ID: 3, UserID: 1, SlotID: 5, EventTypeID: 2, StartTime: start, EndTime: start.Add(30 * time.Minute)}
+	slot := model.Slot{ID: 6, UserID: 1, EventTypeID: 2, StartTime: start.Add(time.Hour), EndTime: start.Add(90 * time.Minute)}
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(eventObj, nil)
+	suite.mockSlotRepository.On("GetByID", suite.ctx, 6).Return(slot, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1}, nil)
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 2).Return(model.EventType{ID: 2, UserID: 1, DurationMins: 30}, nil)
+	suite.mockEventRepository.On("Reschedule", suite.ctx, eventObj, slot, model.BookingLimits{}, model.EventChange{ChangedBy: model.ChangedByHost, Reason: "clash"}).
+		Return(model.Event{ID: 3, UserID: 1, SlotID: 6, EventTypeID: 2, StartTime: slot.StartTime, EndTime: slot.EndTime}, nil)
+
+	resp, err := suite.service.Reschedule(suite.ctx, 1, 3, model.ChangedByHost, contract.RescheduleEvent{SlotID: 6, Reason: "clash"})
+	suite.NoError(err)
+	suite.Equal(6, resp.SlotID)
+	suite.Equal(slot.StartTime, resp.StartTime)
+}
+
+func (suite *EventTestSuite) TestRescheduleReturnsErrorIfSlotBelongsToAnotherEventType() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(model.Event{ID: 3, UserID: 1, SlotID: 5, EventTypeID: 2, StartTime: start}, nil)
+	suite.mockSlotRepository.On("GetByID", suite.ctx, 6).Return(model.Slot{ID: 6, UserID: 1, StartTime: start.Add(time.Hour)}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1}, nil)
+
+	resp, err := suite.service.Reschedule(suite.ctx, 1, 3, model.ChangedByHost, contract.RescheduleEvent{SlotID: 6})
+	suite.ErrorIs(err, model.ErrEventTypeMismatch)
+	suite.Empty(resp)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "Reschedule", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestRescheduleReturnsErrorIfEventIsATeamBooking() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(model.Event{ID: 3, UserID: 1, SlotID: 5, TeamEventTypeID: 4, StartTime: start}, nil)
+
+	_, err := suite.service.Reschedule(suite.ctx, 1, 3, model.ChangedByHost, contract.RescheduleEvent{StartTime: start.Add(time.Hour)})
+	suite.ErrorIs(err, model.ErrTeamEvent)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "Reschedule", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *EventTestSuite) TestRescheduleByStartTimeIgnoresTheEventBeingMoved() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC) // monday
+	eventObj := model.Event{ID: 3, UserID: 1, SlotID: 5, StartTime: start, EndTime: start.Add(time.Hour)}
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(eventObj, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Event{eventObj}, nil)
+	newStart := start.Add(30 * time.Minute)
+	suite.mockEventRepository.On("Reschedule", suite.ctx, eventObj,
+		model.Slot{UserID: 1, StartTime: newStart, EndTime: newStart.Add(30 * time.Minute), Capacity: 1},
+		model.BookingLimits{},
+		model.EventChange{ChangedBy: model.ChangedByInvitee},
+	).Return(model.Event{ID: 3, UserID: 1, SlotID: 8, StartTime: newStart, EndTime: newStart.Add(30 * time.Minute)}, nil)
+
+	resp, err := suite.service.Reschedule(suite.ctx, 1, 3, model.ChangedByInvitee, contract.RescheduleEvent{StartTime: newStart})
+	suite.NoError(err)
+	suite.Equal(8, resp.SlotID)
+	suite.Equal(newStart, resp.StartTime)
+}
+
+func (suite *EventTestSuite) TestGetChangesReturnsHistoryOfEvent() {
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	suite.mockEventRepository.On("GetByID", suite.ctx, 1, 3).Return(model.Event{ID: 3, UserID: 1}, nil)
+	suite.mockEventRepository.On("GetChanges", suite.ctx, 3).Return([]model.EventChange{
+		{EventID: 3, Action: model.ChangeRescheduled, ChangedBy: model.ChangedByHost, PreviousSlotID: 5,
+			PreviousStartTime: start, PreviousEndTime: start.Add(time.Hour), StartTime: start.Add(24 * time.Hour), EndTime: start.Add(25 * time.Hour)},
+		{EventID: 3, Action: model.ChangeCancelled, ChangedBy: model.ChangedByInvitee, Reason: "sick", PreviousSlotID: 6,
+			PreviousStartTime: start.Add(24 * time.Hour), PreviousEndTime: start.Add(25 * time.Hour)},
+	}, nil)
+
+	resp, err := suite.service.GetChanges(suite.ctx, 1, 3, nil)
+	suite.NoError(err)
+	suite.Equal(2, len(resp.Changes))
+	suite.Equal(start.Add(24*time.Hour), *resp.Changes[0].StartTime)
+	suite.Equal(start, resp.Changes[0].PreviousStartTime)
+	suite.Nil(resp.Changes[1].StartTime)
+	suite.Equal("sick", resp.Changes[1].Reason)
+}
+
 func TestEventTestSuite(t *testing.T) {
 	suite.Run(t, new(EventTestSuite))
 }
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+type EventType struct {
+	eventTypeRepository EventTypeRepository
+}
+
+func (eventType EventType) Create(ctx context.Context, userID int, input contract.EventType) (contract.EventTypeResponse, error) {
+	obj := eventTypeFromContract(input)
+	obj.UserID = uint(userID)
+	if err := eventType.checkSlug(ctx, obj); err != nil {
+		return contract.EventTypeResponse{}, err
+	}
+
+	obj, err := eventType.eventTypeRepository.Create(ctx, obj)
+	if err != nil {
+		return contract.EventTypeResponse{}, err
+	}
+
+	return eventTypeToContract(obj), nil
+}
+
+func (eventType EventType) GetAll(ctx context.Context, userID int) (contract.EventTypeList, error) {
+	eventTypes, err := eventType.eventTypeRepository.GetAll(ctx, userID)
+	if err != nil {
+		return contract.EventTypeList{}, err
+	}
+
+	resp := make([]contract.EventTypeResponse, 0)
+	for _, obj := range eventTypes {
+		resp = append(resp, eventTypeToContract(obj))
+	}
+
+	return contract.EventTypeList{EventTypes: resp}, nil
+}
+
+func (eventType EventType) Get(ctx context.Context, userID, eventTypeID int) (contract.EventTypeResponse, error) {
+	obj, err := eventType.eventTypeRepository.GetByID(ctx, userID, eventTypeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.EventTypeResponse{}, model.NotFound("event type", err)
+	}
+	if err != nil {
+		return contract.EventTypeResponse{}, err
+	}
+
+	return eventTypeToContract(obj), nil
+}
+
+func (eventType EventType) Update(ctx context.Context, userID, eventTypeID int, input contract.EventType) (contract.EventTypeResponse, error) {
+	obj, err := eventType.eventTypeRepository.GetByID(ctx, userID, eventTypeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.EventTypeResponse{}, model.NotFound("event type", err)
+	}
+	if err != nil {
+		return contract.EventTypeResponse{}, err
+	}
+
+	updated := eventTypeFromContract(input)
+	updated.ID = obj.ID
+	updated.UserID = obj.UserID
+	updated.CreatedAt = obj.CreatedAt
+	if err := eventType.checkSlug(ctx, updated); err != nil {
+		return contract.EventTypeResponse{}, err
+	}
+
+	updated, err = eventType.eventTypeRepository.Update(ctx, updated)
+	if err != nil {
+		return contract.EventTypeResponse{}, err
+	}
+
+	return eventTypeToContract(updated), nil
+}
+
+func (eventType EventType) Delete(ctx context.Context, userID, eventTypeID int) error {
+	err := eventType.eventTypeRepository.Delete(ctx, userID, eventTypeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("event type", err)
+	}
+	return err
+}
+
+// checkSlug makes sure that no other event type of the user has the same slug
+func (eventType EventType) checkSlug(ctx context.Context, obj model.EventType) error {
+	existing, err := eventType.eventTypeRepository.GetBySlug(ctx, int(obj.UserID), obj.Slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if existing.ID != obj.ID {
+		return model.ErrDuplicateSlug
+	}
+	return nil
+}
+
+// availabilityForEventType returns the user's availability adjusted for the given event type, along with the event
+// type. An eventTypeID of 0 returns the user's availability as is along with an empty event type.
+func availabilityForEventType(ctx context.Context, availabilityRepository UserAvailabilityRepository, eventTypeRepository EventTypeRepository, userID, eventTypeID int) (model.UserAvailability, model.EventType, error) {
+	availability, err := availabilityRepository.Get(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.UserAvailability{}, model.EventType{}, model.NotFound("availability", err)
+		}
+		return model.UserAvailability{}, model.EventType{}, err
+	}
+	if eventTypeID == 0 {
+		return availability, model.EventType{}, nil
+	}
+
+	eventType, err := eventTypeRepository.GetByID(ctx, userID, eventTypeID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return model.UserAvailability{}, model.EventType{}, model.NotFound("event type", err)
+		}
+		return model.UserAvailability{}, model.EventType{}, err
+	}
+	return eventType.ApplyTo(availability), eventType, nil
+}
+
+func eventTypeFromContract(input contract.EventType) model.EventType {
+	return model.EventType{
+		Name:              input.Name,
+		Slug:              input.Slug,
+		DurationMins:      input.DurationMins,
+		SlotIncrementMins: input.SlotIncrementMins,
+		Capacity:          input.Capacity,
+		Description:       input.Description,
+		Location:          input.Location,
+		Availability:      input.Availability,
+		SchedulingRules:   input.SchedulingRules,
+	}
+}
+
+func eventTypeToContract(eventType model.EventType) contract.EventTypeResponse {
+	availability := eventType.Availability
+	if availability == nil {
+		availability = make([]model.DayAvailability, 0)
+	}
+	return contract.EventTypeResponse{
+		ID:                eventType.ID,
+		UserID:            eventType.UserID,
+		Name:              eventType.Name,
+		Slug:              eventType.Slug,
+		DurationMins:      eventType.DurationMins,
+		SlotIncrementMins: eventType.SlotIncrementMins,
+		Capacity:          eventType.Capacity,
+		Description:       eventType.Description,
+		Location:          eventType.Location,
+		Availability:      availability,
+		SchedulingRules:   eventType.SchedulingRules,
+		CreatedAt:         eventType.CreatedAt,
+	}
+}
+
+func NewEventType(eventTypeRepository EventTypeRepository) EventType {
+	return EventType{eventTypeRepository: eventTypeRepository}
+}
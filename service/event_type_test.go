@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/suite"
+	"gorm.io/datatypes"
+)
+
+type EventTypeTestSuite struct {
+	suite.Suite
+	mockEventTypeRepository *MockEventTypeRepository
+	service                 EventType
+	ctx                     context.Context
+}
+
+func (suite *EventTypeTestSuite) SetupTest() {
+	suite.mockEventTypeRepository = &MockEventTypeRepository{}
+	suite.service = NewEventType(suite.mockEventTypeRepository)
+	suite.ctx = context.Background()
+}
+
+func (suite *EventTypeTestSuite) TestCreateHappyFlow() {
+	now := time.Now()
+	input := contract.EventType{
+		Name:         "Intro call",
+		Slug:         "intro-call",
+		DurationMins: 15,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+	}
+	obj := model.EventType{
+		UserID:       1,
+		Name:         "Intro call",
+		Slug:         "intro-call",
+		DurationMins: 15,
+		Availability: input.Availability,
+	}
+	suite.mockEventTypeRepository.On("GetBySlug", suite.ctx, 1, "intro-call").Return(model.EventType{}, sql.ErrNoRows)
+	created := obj
+	created.ID = 1
+	created.CreatedAt = now
+	suite.mockEventTypeRepository.On("Create", suite.ctx, obj).Return(created, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, input)
+	suite.Nil(err)
+	suite.Equal(contract.EventTypeResponse{
+		ID:           1,
+		UserID:       1,
+		Name:         "Intro call",
+		Slug:         "intro-call",
+		DurationMins: 15,
+		Availability: input.Availability,
+		CreatedAt:    now,
+	}, resp)
+}
+
+func (suite *EventTypeTestSuite) TestCreateReturnsErrorIfSlugIsTaken() {
+	suite.mockEventTypeRepository.On("GetBySlug", suite.ctx, 1, "intro-call").Return(model.EventType{ID: 4, UserID: 1, Slug: "intro-call"}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.EventType{Name: "Intro call", Slug: "intro-call", DurationMins: 15})
+	suite.ErrorIs(err, model.ErrDuplicateSlug)
+	suite.Empty(resp)
+}
+
+func (suite *EventTypeTestSuite) TestUpdateKeepingSlug() {
+	now := time.Now()
+	existing := model.EventType{ID: 4, UserID: 1, Name: "Intro call", Slug: "intro-call", DurationMins: 15, CreatedAt: now}
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 4).Return(existing, nil)
+	suite.mockEventTypeRepository.On("GetBySlug", suite.ctx, 1, "intro-call").Return(existing, nil)
+	updated := model.EventType{ID: 4, UserID: 1, Name: "Intro call", Slug: "intro-call", DurationMins: 30, CreatedAt: now}
+	suite.mockEventTypeRepository.On("Update", suite.ctx, updated).Return(updated, nil)
+
+	resp, err := suite.service.Update(suite.ctx, 1, 4, contract.EventType{Name: "Intro call", Slug: "intro-call", DurationMins: 30})
+	suite.Nil(err)
+	suite.Equal(30, resp.DurationMins)
+	suite.Equal(make([]model.DayAvailability, 0), resp.Availability)
+}
+
+func (suite *EventTypeTestSuite) TestUpdateReturnsErrorIfNotFound() {
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 4).Return(model.EventType{}, sql.ErrNoRows)
+
+	resp, err := suite.service.Update(suite.ctx, 1, 4, contract.EventType{Name: "Intro call", Slug: "intro-call", DurationMins: 30})
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.Empty(resp)
+}
+
+func (suite *EventTypeTestSuite) TestGetAllHappyFlow() {
+	suite.mockEventTypeRepository.On("GetAll", suite.ctx, 1).Return([]model.EventType{
+		{ID: 1, UserID: 1, Name: "Intro call", Slug: "intro-call", DurationMins: 15},
+		{ID: 2, UserID: 1, Name: "Deep dive", Slug: "deep-dive", DurationMins: 60},
+	}, nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, 1)
+	suite.Nil(err)
+	suite.Len(resp.EventTypes, 2)
+	suite.Equal("deep-dive", resp.EventTypes[1].Slug)
+}
+
+func TestEventTypeTestSuite(t *testing.T) {
+	suite.Run(t, new(EventTypeTestSuite))
+}
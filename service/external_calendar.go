@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/ical"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+const (
+	// maxCalendarBytes is the largest ICS file which is imported
+	maxCalendarBytes = 5 << 20
+	// importHorizon is how far ahead the busy times of an external calendar are turned into busy blocks. Calendars
+	// with a URL are synced regularly so that the horizon rolls forward.
+	importHorizon = 180 * 24 * time.Hour
+	// maxBusyBlocks bounds the busy blocks of a single external calendar
+	maxBusyBlocks = 10000
+	fetchTimeout  = 30 * time.Second
+)
+
+type ExternalCalendar struct {
+	calendarRepository     ExternalCalendarRepository
+	availabilityRepository UserAvailabilityRepository
+	eventRepository        EventRepository
+	providers              calendarProviders
+	now                    func() time.Time
+}
+
+// Create adds an external calendar to the user. A calendar with a URL is read straight away and only saved if it can
+// be imported.
+func (calendar ExternalCalendar) Create(ctx context.Context, userID int, input contract.ExternalCalendar) (contract.ExternalCalendarResponse, error) {
+	obj := model.ExternalCalendar{
+		UserID:    uint(userID),
+		Name:      input.Name,
+		Provider:  input.Provider,
+		URL:       input.URL,
+		Username:  input.Username,
+		Password:  input.Password,
+		WriteBack: input.WriteBack,
+	}
+	var periods []ical.Period
+	if obj.URL != "" {
+		var err error
+		periods, err = calendar.busy(ctx, obj)
+		if err != nil {
+			return contract.ExternalCalendarResponse{}, err
+		}
+	}
+
+	obj, err := calendar.calendarRepository.Create(ctx, obj)
+	if err != nil {
+		return contract.ExternalCalendarResponse{}, err
+	}
+	if obj.URL == "" {
+		return toExternalCalendarContract(obj, nil), nil
+	}
+	return calendar.replaceBusyBlocks(ctx, obj, periods)
+}
+
+func (calendar ExternalCalendar) GetAll(ctx context.Context, userID int) (contract.ExternalCalendarList, error) {
+	calendars, err := calendar.calendarRepository.GetAll(ctx, userID)
+	if err != nil {
+		return contract.ExternalCalendarList{}, err
+	}
+
+	resp := make([]contract.ExternalCalendarResponse, 0)
+	for _, obj := range calendars {
+		resp = append(resp, toExternalCalendarContract(obj, nil))
+	}
+
+	return contract.ExternalCalendarList{ExternalCalendars: resp}, nil
+}
+
+// Import replaces the busy blocks of an ICS calendar with the events of the uploaded ICS file.
+func (calendar ExternalCalendar) Import(ctx context.Context, userID, calendarID int, r io.Reader) (contract.ExternalCalendarResponse, error) {
+	obj, err := calendar.getCalendar(ctx, userID, calendarID)
+	if err != nil {
+		return contract.ExternalCalendarResponse{}, err
+	}
+	if obj.Provider != model.ProviderICS {
+		return contract.ExternalCalendarResponse{}, model.ErrInvalidCalendar.Withf("only ics calendars can be imported")
+	}
+
+	loc, err := calendar.userLocation(ctx, userID)
+	if err != nil {
+		return contract.ExternalCalendarResponse{}, err
+	}
+	now := calendar.now()
+	periods, err := busyPeriods(r, loc, now, now.Add(importHorizon))
+	if err != nil {
+		return contract.ExternalCalendarResponse{}, err
+	}
+	return calendar.replaceBusyBlocks(ctx, obj, periods)
+}
+
+// Sync reads the external calendar from its URL again and replaces its busy blocks.
+func (calendar ExternalCalendar) Sync(ctx context.Context, userID, calendarID int) (contract.ExternalCalendarResponse, error) {
+	obj, err := calendar.getCalendar(ctx, userID, calendarID)
+	if err != nil {
+		return contract.ExternalCalendarResponse{}, err
+	}
+
+	return calendar.sync(ctx, obj)
+}
+
+// SyncAll syncs every external calendar with a URL and returns the number of calendars synced.
+func (calendar ExternalCalendar) SyncAll(ctx context.Context) (int, error) {
+	calendars, err := calendar.calendarRepository.GetAllWithURL(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	synced := 0
+	for _, obj := range calendars {
+		if _, err := calendar.sync(ctx, obj); err != nil {
+			// An unreachable calendar should not hold back the others, its previous busy blocks are kept
+			log.Printf("error occurred while syncing external calendar %d: %s", obj.ID, err.Error())
+			continue
+		}
+		synced++
+	}
+	return synced, nil
+}
+
+func (calendar ExternalCalendar) Delete(ctx context.Context, userID, calendarID int) error {
+	err := calendar.calendarRepository.Delete(ctx, userID, calendarID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("external calendar", err)
+	}
+	return err
+}
+
+func (calendar ExternalCalendar) sync(ctx context.Context, obj model.ExternalCalendar) (contract.ExternalCalendarResponse, error) {
+	periods, err := calendar.busy(ctx, obj)
+	if err != nil {
+		return contract.ExternalCalendarResponse{}, err
+	}
+	return calendar.replaceBusyBlocks(ctx, obj, periods)
+}
+
+// busy reads the busy times of the external calendar from now until the import horizon through its provider. The
+// user's bookings are taken out of the busy times of calendars they are written back to, so that a booking does not
+// block itself once it shows up there.
+func (calendar ExternalCalendar) busy(ctx context.Context, obj model.ExternalCalendar) ([]ical.Period, error) {
+	loc, err := calendar.userLocation(ctx, int(obj.UserID))
+	if err != nil {
+		return nil, err
+	}
+	provider, err := calendar.providers(obj, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	now := calendar.now()
+	from, to := now, now.Add(importHorizon)
+	periods, err := provider.Busy(ctx, from, to)
+	if err != nil || !obj.WriteBack {
+		return periods, err
+	}
+
+	events, err := calendar.eventRepository.GetInRange(ctx, int(obj.UserID), from, to)
+	if err != nil {
+		return nil, err
+	}
+	busy := make([]interval, 0, len(periods))
+	for _, period := range periods {
+		busy = append(busy, interval{start: period.Start, end: period.End})
+	}
+	booked := make([]interval, 0, len(events))
+	for _, eventObj := range events {
+		booked = append(booked, interval{start: eventObj.StartTime, end: eventObj.EndTime})
+	}
+
+	periods = make([]ical.Period, 0)
+	for _, i := range subtractIntervals(mergeIntervals(busy), booked) {
+		periods = append(periods, ical.Period{Start: i.start, End: i.end})
+	}
+	return periods, nil
+}
+
+func (calendar ExternalCalendar) replaceBusyBlocks(ctx context.Context, obj model.ExternalCalendar, periods []ical.Period) (contract.ExternalCalendarResponse, error) {
+	if len(periods) > maxBusyBlocks {
+		return contract.ExternalCalendarResponse{}, model.ErrInvalidCalendar.Withf("calendar has more than %d events in the next %d days",
+			maxBusyBlocks, int(importHorizon.Hours()/24))
+	}
+	blocks := make([]model.BusyBlock, 0, len(periods))
+	for _, period := range periods {
+		blocks = append(blocks, model.BusyBlock{
+			UserID:             obj.UserID,
+			ExternalCalendarID: obj.ID,
+			StartTime:          period.Start.UTC(),
+			EndTime:            period.End.UTC(),
+		})
+	}
+
+	synced, err := calendar.calendarRepository.ReplaceBusyBlocks(ctx, obj, blocks, calendar.now())
+	if errors.Is(err, sql.ErrNoRows) {
+		// The calendar was deleted in the meantime
+		return contract.ExternalCalendarResponse{}, model.NotFound("external calendar", err)
+	}
+	if err != nil {
+		return contract.ExternalCalendarResponse{}, err
+	}
+
+	count := len(blocks)
+	return toExternalCalendarContract(synced, &count), nil
+}
+
+func (calendar ExternalCalendar) getCalendar(ctx context.Context, userID, calendarID int) (model.ExternalCalendar, error) {
+	obj, err := calendar.calendarRepository.GetByID(ctx, userID, calendarID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.ExternalCalendar{}, model.NotFound("external calendar", err)
+	}
+	return obj, err
+}
+
+// userLocation returns the time zone of the user's availability, or UTC if they have not set it yet.
+func (calendar ExternalCalendar) userLocation(ctx context.Context, userID int) (*time.Location, error) {
+	availability, err := calendar.availabilityRepository.Get(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.UTC, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return availability.Location()
+}
+
+func toExternalCalendarContract(obj model.ExternalCalendar, busyBlocks *int) contract.ExternalCalendarResponse {
+	resp := contract.ExternalCalendarResponse{
+		ID:         obj.ID,
+		UserID:     obj.UserID,
+		Name:       obj.Name,
+		Provider:   obj.Provider,
+		URL:        obj.URL,
+		Username:   obj.Username,
+		WriteBack:  obj.WriteBack,
+		BusyBlocks: busyBlocks,
+	}
+	if !obj.SyncedAt.IsZero() {
+		syncedAt := obj.SyncedAt
+		resp.SyncedAt = &syncedAt
+	}
+	return resp
+}
+
+// NewExternalCalendar returns the service of external calendars, which are fetched with client, see NewCalendarClient.
+func NewExternalCalendar(calendarRepository ExternalCalendarRepository, availabilityRepository UserAvailabilityRepository, eventRepository EventRepository, client *http.Client) ExternalCalendar {
+	return ExternalCalendar{
+		calendarRepository:     calendarRepository,
+		availabilityRepository: availabilityRepository,
+		eventRepository:        eventRepository,
+		providers:              newCalendarProviders(client),
+		now:                    time.Now,
+	}
+}
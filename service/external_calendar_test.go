@@ -0,0 +1,245 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/caldav/caldavtest"
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/ical"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const testICS = "BEGIN:VCALENDAR\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:standup@example.xyz\r\n" +
+	"DTSTART:20230605T090000\r\n" +
+	"DTEND:20230605T091500\r\n" +
+	"RRULE:FREQ=DAILY;COUNT=3\r\n" +
+	"EXDATE:20230606T090000\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"UID:event-7@calendly\r\n" +
+	"DTSTART:20230605T100000Z\r\n" +
+	"DTEND:20230605T103000Z\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+type ExternalCalendarTestSuite struct {
+	suite.Suite
+	service                    ExternalCalendar
+	mockCalendarRepository     *MockExternalCalendarRepository
+	mockAvailabilityRepository *MockUserAvailabilityRepository
+	mockEventRepository        *MockEventRepository
+	server                     *httptest.Server
+	ctx                        context.Context
+	now                        time.Time
+}
+
+func (suite *ExternalCalendarTestSuite) SetupTest() {
+	suite.mockCalendarRepository = &MockExternalCalendarRepository{}
+	suite.mockAvailabilityRepository = &MockUserAvailabilityRepository{}
+	suite.mockAvailabilityRepository.On("Get", mock.Anything, 1).Return(model.UserAvailability{UserID: 1, TimeZone: "Asia/Kolkata"}, nil)
+	suite.mockEventRepository = &MockEventRepository{}
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/work.ics" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/calendar")
+		w.Write([]byte(testICS))
+	}))
+	suite.now = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.service = NewExternalCalendar(suite.mockCalendarRepository, suite.mockAvailabilityRepository, suite.mockEventRepository,
+		suite.server.Client())
+	suite.service.now = func() time.Time { return suite.now }
+	suite.ctx = context.Background()
+}
+
+func (suite *ExternalCalendarTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// expectedBlocks are the busy blocks of testICS, whose floating times are in the user's time zone
+func (suite *ExternalCalendarTestSuite) expectedBlocks(calendarID uint) []model.BusyBlock {
+	return []model.BusyBlock{
+		{UserID: 1, ExternalCalendarID: calendarID, StartTime: time.Date(2023, 6, 5, 3, 30, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 5, 3, 45, 0, 0, time.UTC)},
+		{UserID: 1, ExternalCalendarID: calendarID, StartTime: time.Date(2023, 6, 7, 3, 30, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 7, 3, 45, 0, 0, time.UTC)},
+	}
+}
+
+func (suite *ExternalCalendarTestSuite) TestCreateFetchesCalendarFromURL() {
+	url := suite.server.URL + "/work.ics"
+	suite.mockCalendarRepository.On("Create", suite.ctx, model.ExternalCalendar{UserID: 1, Name: "Work", Provider: model.ProviderICS, URL: url}).
+		Return(model.ExternalCalendar{ID: 2, UserID: 1, Name: "Work", Provider: model.ProviderICS, URL: url}, nil)
+	suite.mockCalendarRepository.On("ReplaceBusyBlocks", suite.ctx, model.ExternalCalendar{ID: 2, UserID: 1, Name: "Work", Provider: model.ProviderICS, URL: url}, suite.expectedBlocks(2), suite.now).
+		Return(model.ExternalCalendar{ID: 2, UserID: 1, Name: "Work", Provider: model.ProviderICS, URL: url, SyncedAt: suite.now}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.ExternalCalendar{Name: "Work", Provider: model.ProviderICS, URL: url})
+	suite.NoError(err)
+	suite.Equal(uint(2), resp.ID)
+	suite.Equal(suite.now, *resp.SyncedAt)
+	suite.Equal(2, *resp.BusyBlocks)
+}
+
+func (suite *ExternalCalendarTestSuite) TestCreateDoesNotSaveCalendarWhichCannotBeFetched() {
+	_, err := suite.service.Create(suite.ctx, 1, contract.ExternalCalendar{Name: "Work", Provider: model.ProviderICS, URL: suite.server.URL + "/missing.ics"})
+	suite.ErrorIs(err, model.ErrInvalidCalendar)
+	suite.ErrorContains(err, "status 404")
+	suite.mockCalendarRepository.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *ExternalCalendarTestSuite) TestCreateRefusesCalendarOnInternalAddress() {
+	suite.service = NewExternalCalendar(suite.mockCalendarRepository, suite.mockAvailabilityRepository, suite.mockEventRepository,
+		NewCalendarClient(nil))
+
+	_, err := suite.service.Create(suite.ctx, 1, contract.ExternalCalendar{Name: "Work", Provider: model.ProviderICS, URL: suite.server.URL + "/work.ics"})
+	suite.ErrorIs(err, model.ErrInvalidCalendar)
+	suite.ErrorContains(err, "is not public")
+	suite.mockCalendarRepository.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *ExternalCalendarTestSuite) TestCreateFetchesCalendarOnAllowedNetwork() {
+	networks, err := ParseNetworks("127.0.0.0/8")
+	suite.Require().NoError(err)
+	suite.service = NewExternalCalendar(suite.mockCalendarRepository, suite.mockAvailabilityRepository, suite.mockEventRepository,
+		NewCalendarClient(networks))
+	suite.service.now = func() time.Time { return suite.now }
+	url := suite.server.URL + "/work.ics"
+	suite.mockCalendarRepository.On("Create", suite.ctx, mock.Anything).Return(model.ExternalCalendar{ID: 2, UserID: 1, URL: url}, nil)
+	suite.mockCalendarRepository.On("ReplaceBusyBlocks", suite.ctx, mock.Anything, suite.expectedBlocks(2), suite.now).
+		Return(model.ExternalCalendar{ID: 2, UserID: 1, URL: url, SyncedAt: suite.now}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.ExternalCalendar{Name: "Work", Provider: model.ProviderICS, URL: url})
+	suite.NoError(err)
+	suite.Equal(2, *resp.BusyBlocks)
+}
+
+func (suite *ExternalCalendarTestSuite) TestCreateWithoutURLOnlySavesCalendar() {
+	suite.mockCalendarRepository.On("Create", suite.ctx, model.ExternalCalendar{UserID: 1, Name: "Work", Provider: model.ProviderICS}).
+		Return(model.ExternalCalendar{ID: 2, UserID: 1, Name: "Work", Provider: model.ProviderICS}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.ExternalCalendar{Name: "Work", Provider: model.ProviderICS})
+	suite.NoError(err)
+	suite.Equal(contract.ExternalCalendarResponse{ID: 2, UserID: 1, Name: "Work", Provider: model.ProviderICS}, resp)
+}
+
+func (suite *ExternalCalendarTestSuite) TestImportReplacesBusyBlocksWithUploadedEvents() {
+	obj := model.ExternalCalendar{ID: 2, UserID: 1, Name: "Work", Provider: model.ProviderICS}
+	suite.mockCalendarRepository.On("GetByID", suite.ctx, 1, 2).Return(obj, nil)
+	suite.mockCalendarRepository.On("ReplaceBusyBlocks", suite.ctx, obj, suite.expectedBlocks(2), suite.now).Return(obj, nil)
+
+	resp, err := suite.service.Import(suite.ctx, 1, 2, strings.NewReader(testICS))
+	suite.NoError(err)
+	suite.Equal(2, *resp.BusyBlocks)
+}
+
+func (suite *ExternalCalendarTestSuite) TestImportOnlyKeepsEventsWithinTheHorizon() {
+	obj := model.ExternalCalendar{ID: 2, UserID: 1, Name: "Work", Provider: model.ProviderICS}
+	suite.now = time.Date(2023, 6, 6, 0, 0, 0, 0, time.UTC)
+	suite.mockCalendarRepository.On("GetByID", suite.ctx, 1, 2).Return(obj, nil)
+	suite.mockCalendarRepository.On("ReplaceBusyBlocks", suite.ctx, obj, suite.expectedBlocks(2)[1:], suite.now).Return(obj, nil)
+
+	resp, err := suite.service.Import(suite.ctx, 1, 2, strings.NewReader(testICS))
+	suite.NoError(err)
+	suite.Equal(1, *resp.BusyBlocks)
+}
+
+func (suite *ExternalCalendarTestSuite) TestImportReturnsErrorIfFileIsNotACalendar() {
+	suite.mockCalendarRepository.On("GetByID", suite.ctx, 1, 2).Return(model.ExternalCalendar{ID: 2, UserID: 1, Provider: model.ProviderICS}, nil)
+
+	_, err := suite.service.Import(suite.ctx, 1, 2, strings.NewReader("name,start\nstandup,09:00\n"))
+	suite.ErrorIs(err, model.ErrInvalidCalendar)
+	suite.mockCalendarRepository.AssertNotCalled(suite.T(), "ReplaceBusyBlocks", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ExternalCalendarTestSuite) TestImportReturnsNotFoundIfCalendarDoesNotExist() {
+	suite.mockCalendarRepository.On("GetByID", suite.ctx, 1, 2).Return(model.ExternalCalendar{}, sql.ErrNoRows)
+
+	_, err := suite.service.Import(suite.ctx, 1, 2, strings.NewReader(testICS))
+	suite.ErrorIs(err, sql.ErrNoRows)
+}
+
+func (suite *ExternalCalendarTestSuite) TestImportReturnsErrorForCalDAVCalendar() {
+	suite.mockCalendarRepository.On("GetByID", suite.ctx, 1, 2).Return(model.ExternalCalendar{ID: 2, UserID: 1, Provider: model.ProviderCalDAV}, nil)
+
+	_, err := suite.service.Import(suite.ctx, 1, 2, strings.NewReader(testICS))
+	suite.ErrorIs(err, model.ErrInvalidCalendar)
+	suite.ErrorContains(err, "only ics calendars can be imported")
+}
+
+func (suite *ExternalCalendarTestSuite) TestSyncReadsFreeBusyOfCalDAVCalendar() {
+	server := caldavtest.NewServer()
+	defer server.Close()
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	server.AddBusy(ical.Period{Start: start, End: start.Add(time.Hour)}, ical.Period{Start: start.Add(24 * time.Hour), End: start.Add(25 * time.Hour)})
+	obj := model.ExternalCalendar{ID: 2, UserID: 1, Provider: model.ProviderCalDAV, URL: server.CalendarURL(), Username: server.Username, Password: server.Password}
+	suite.mockCalendarRepository.On("GetByID", suite.ctx, 1, 2).Return(obj, nil)
+	suite.mockCalendarRepository.On("ReplaceBusyBlocks", suite.ctx, obj, []model.BusyBlock{
+		{UserID: 1, ExternalCalendarID: 2, StartTime: start, EndTime: start.Add(time.Hour)},
+		{UserID: 1, ExternalCalendarID: 2, StartTime: start.Add(24 * time.Hour), EndTime: start.Add(25 * time.Hour)},
+	}, suite.now).Return(obj, nil)
+
+	resp, err := suite.service.Sync(suite.ctx, 1, 2)
+	suite.NoError(err)
+	suite.Equal(2, *resp.BusyBlocks)
+	suite.Equal(model.ProviderCalDAV, resp.Provider)
+}
+
+func (suite *ExternalCalendarTestSuite) TestSyncLeavesOutBookingsWrittenBackToCalDAVCalendar() {
+	server := caldavtest.NewServer()
+	defer server.Close()
+	start := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	server.AddBusy(ical.Period{Start: start, End: start.Add(time.Hour)})
+	obj := model.ExternalCalendar{ID: 2, UserID: 1, Provider: model.ProviderCalDAV, URL: server.CalendarURL(), Username: server.Username, Password: server.Password,
+		WriteBack: true}
+	suite.mockCalendarRepository.On("GetByID", suite.ctx, 1, 2).Return(obj, nil)
+	// The booking from 9:30 shows up in the free/busy time of the calendar as it was written back
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, suite.now, suite.now.Add(importHorizon)).
+		Return([]model.Event{{ID: 7, UserID: 1, StartTime: start.Add(30 * time.Minute), EndTime: start.Add(time.Hour)}}, nil)
+	suite.mockCalendarRepository.On("ReplaceBusyBlocks", suite.ctx, obj, []model.BusyBlock{
+		{UserID: 1, ExternalCalendarID: 2, StartTime: start, EndTime: start.Add(30 * time.Minute)},
+	}, suite.now).Return(obj, nil)
+
+	resp, err := suite.service.Sync(suite.ctx, 1, 2)
+	suite.NoError(err)
+	suite.Equal(1, *resp.BusyBlocks)
+}
+
+func (suite *ExternalCalendarTestSuite) TestSyncReturnsErrorIfCalendarHasNoURL() {
+	suite.mockCalendarRepository.On("GetByID", suite.ctx, 1, 2).Return(model.ExternalCalendar{ID: 2, UserID: 1, Provider: model.ProviderICS}, nil)
+
+	_, err := suite.service.Sync(suite.ctx, 1, 2)
+	suite.ErrorIs(err, model.ErrInvalidCalendar)
+}
+
+func (suite *ExternalCalendarTestSuite) TestSyncAllCarriesOnPastFailingCalendars() {
+	broken := model.ExternalCalendar{ID: 1, UserID: 1, Provider: model.ProviderICS, URL: suite.server.URL + "/missing.ics"}
+	working := model.ExternalCalendar{ID: 2, UserID: 1, Provider: model.ProviderICS, URL: suite.server.URL + "/work.ics"}
+	suite.mockCalendarRepository.On("GetAllWithURL", suite.ctx).Return([]model.ExternalCalendar{broken, working}, nil)
+	suite.mockCalendarRepository.On("ReplaceBusyBlocks", suite.ctx, working, suite.expectedBlocks(2), suite.now).Return(working, nil)
+
+	synced, err := suite.service.SyncAll(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(1, synced)
+}
+
+func (suite *ExternalCalendarTestSuite) TestDeleteReturnsNotFoundIfCalendarDoesNotExist() {
+	suite.mockCalendarRepository.On("Delete", suite.ctx, 1, 2).Return(sql.ErrNoRows)
+
+	err := suite.service.Delete(suite.ctx, 1, 2)
+	suite.True(errors.Is(err, sql.ErrNoRows))
+}
+
+func TestExternalCalendarTestSuite(t *testing.T) {
+	suite.Run(t, new(ExternalCalendarTestSuite))
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"sort"
+	"time"
+)
+
+// interval is a half open range of time [start, end).
+type interval struct {
+	start time.Time
+	end   time.Time
+}
+
+// overlaps reports whether the two intervals share any instant.
+func (i interval) overlaps(other interval) bool {
+	return i.start.Before(other.end) && other.start.Before(i.end)
+}
+
+// intersectIntervals returns the intervals common to both lists. Both lists need to be sorted and non-overlapping.
+func intersectIntervals(a, b []interval) []interval {
+	result := make([]interval, 0)
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		start := a[i].start
+		if b[j].start.After(start) {
+			start = b[j].start
+		}
+		end := a[i].end
+		if b[j].end.Before(end) {
+			end = b[j].end
+		}
+		if start.Before(end) {
+			result = append(result, interval{start: start, end: end})
+		}
+
+		// Move past whichever interval finishes first
+		if a[i].end.Before(b[j].end) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return result
+}
+
+// mergeIntervals sorts the intervals and merges the ones that overlap or touch.
+func mergeIntervals(intervals []interval) []interval {
+	sorted := make([]interval, len(intervals))
+	copy(sorted, intervals)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].start.Before(sorted[j].start) })
+
+	result := make([]interval, 0)
+	for _, i := range sorted {
+		if n := len(result); n > 0 && !i.start.After(result[n-1].end) {
+			if i.end.After(result[n-1].end) {
+				result[n-1].end = i.end
+			}
+			continue
+		}
+		result = append(result, i)
+	}
+	return result
+}
+
+// subtractIntervals removes the busy intervals from the available ones. available needs to be sorted and non-overlapping.
+func subtractIntervals(available, busy []interval) []interval {
+	busy = mergeIntervals(busy)
+	result := make([]interval, 0)
+	j := 0
+	for _, a := range available {
+		start := a.start
+		// Skip busy intervals which end before this one starts
+		for j < len(busy) && !busy[j].end.After(start) {
+			j++
+		}
+		for k := j; k < len(busy) && busy[k].start.Before(a.end); k++ {
+			if busy[k].start.After(start) {
+				result = append(result, interval{start: start, end: busy[k].start})
+			}
+			if busy[k].end.After(start) {
+				start = busy[k].end
+			}
+		}
+		if start.Before(a.end) {
+			result = append(result, interval{start: start, end: a.end})
+		}
+	}
+	return result
+}
+
+// slotsIn lays out slots of the given duration starting every increment from the start of the window, leaving out
+// the ones which would not end within it. Consecutive slots overlap when the increment is shorter than the duration.
+func slotsIn(window interval, duration, increment time.Duration) []interval {
+	slots := make([]interval, 0)
+	if duration <= 0 || increment <= 0 {
+		return slots
+	}
+	for start := window.start; !start.Add(duration).After(window.end); start = start.Add(increment) {
+		slots = append(slots, interval{start: start, end: start.Add(duration)})
+	}
+	return slots
+}
+
+// filterIntervals drops the intervals shorter than the given duration.
+func filterIntervals(intervals []interval, minDuration time.Duration) []interval {
+	result := make([]interval, 0)
+	for _, i := range intervals {
+		if i.end.Sub(i.start) >= minDuration {
+			result = append(result, i)
+		}
+	}
+	return result
+}
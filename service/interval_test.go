@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type IntervalTestSuite struct {
+	suite.Suite
+}
+
+func at(hour, min int) time.Time {
+	return time.Date(2023, 6, 5, hour, min, 0, 0, time.UTC)
+}
+
+func (suite *IntervalTestSuite) TestIntersectIntervals() {
+	a := []interval{{start: at(9, 0), end: at(12, 0)}, {start: at(14, 0), end: at(18, 0)}}
+	b := []interval{{start: at(11, 0), end: at(15, 0)}, {start: at(17, 0), end: at(19, 0)}}
+
+	suite.Equal([]interval{
+		{start: at(11, 0), end: at(12, 0)},
+		{start: at(14, 0), end: at(15, 0)},
+		{start: at(17, 0), end: at(18, 0)},
+	}, intersectIntervals(a, b))
+}
+
+func (suite *IntervalTestSuite) TestMergeIntervals() {
+	intervals := []interval{{start: at(11, 0), end: at(12, 0)}, {start: at(9, 0), end: at(10, 0)}, {start: at(10, 0), end: at(10, 30)}, {start: at(11, 30), end: at(11, 45)}}
+
+	suite.Equal([]interval{
+		{start: at(9, 0), end: at(10, 30)},
+		{start: at(11, 0), end: at(12, 0)},
+	}, mergeIntervals(intervals))
+}
+
+func (suite *IntervalTestSuite) TestSubtractIntervals() {
+	available := []interval{{start: at(9, 0), end: at(12, 0)}, {start: at(13, 0), end: at(17, 0)}}
+	busy := []interval{{start: at(8, 0), end: at(9, 30)}, {start: at(10, 0), end: at(10, 30)}, {start: at(11, 30), end: at(13, 30)}, {start: at(16, 0), end: at(18, 0)}}
+
+	suite.Equal([]interval{
+		{start: at(9, 30), end: at(10, 0)},
+		{start: at(10, 30), end: at(11, 30)},
+		{start: at(13, 30), end: at(16, 0)},
+	}, subtractIntervals(available, busy))
+}
+
+func (suite *IntervalTestSuite) TestFilterIntervals() {
+	intervals := []interval{{start: at(9, 0), end: at(9, 15)}, {start: at(10, 0), end: at(10, 30)}}
+
+	suite.Equal([]interval{{start: at(10, 0), end: at(10, 30)}}, filterIntervals(intervals, 30*time.Minute))
+}
+
+func (suite *IntervalTestSuite) TestSlotsInStartEveryIncrementAndEndWithinWindow() {
+	window := interval{start: at(9, 0), end: at(10, 50)}
+
+	suite.Equal([]interval{
+		{start: at(9, 0), end: at(10, 0)},
+		{start: at(9, 15), end: at(10, 15)},
+		{start: at(9, 30), end: at(10, 30)},
+		{start: at(9, 45), end: at(10, 45)},
+	}, slotsIn(window, time.Hour, 15*time.Minute))
+	suite.Equal([]interval{{start: at(9, 0), end: at(10, 0)}}, slotsIn(window, time.Hour, time.Hour))
+}
+
+func TestIntervalTestSuite(t *testing.T) {
+	suite.Run(t, new(IntervalTestSuite))
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	// maxJobAttempts is how often a job is run before it is dead. With the delay doubling after every attempt, the
+	// last one is made about 1.5 hours after the first.
+	maxJobAttempts = 10
+	jobRetryDelay  = 10 * time.Second
+	// jobLease is how long a job may run before it is given up on and claimed again by another worker
+	jobLease = 5 * time.Minute
+	// jobListSize is how many of the latest jobs are returned
+	jobListSize = 100
+)
+
+// JobHandler runs a job with the given payload. Jobs are retried when their handler fails, or when their worker dies
+// half way through, so handlers should be safe to run more than once.
+type JobHandler func(ctx context.Context, payload []byte) error
+
+// Job runs the jobs of the queue with the handlers of their kind, and lets them be inspected and retried.
+type Job struct {
+	jobRepository JobRepository
+	handlers      map[string]JobHandler
+	now           func() time.Time
+}
+
+// RunNext claims the next due job, runs it and records the outcome. It reports whether there was a job to run.
+// Failed jobs are retried with exponential backoff until they reach their maximum attempts, after which they are
+// dead and only run again when retried through Retry.
+func (job Job) RunNext(ctx context.Context) (bool, error) {
+	now := job.now()
+	obj, err := job.jobRepository.Claim(ctx, now, now.Add(jobLease))
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	obj = job.run(ctx, obj)
+	if err := job.jobRepository.Finish(ctx, obj); err != nil {
+		// The job is claimed again once its lock expires
+		return true, err
+	}
+	return true, nil
+}
+
+func (job Job) run(ctx context.Context, obj model.Job) model.Job {
+	err := job.handle(ctx, obj)
+	now := job.now()
+	obj.LockedUntil = time.Time{}
+	if err == nil {
+		obj.Status = model.JobSucceeded
+		obj.LastError = ""
+		obj.FinishedAt = now
+		return obj
+	}
+
+	obj.LastError = err.Error()
+	if obj.Attempts >= obj.MaxAttempts {
+		obj.Status = model.JobDead
+		obj.FinishedAt = now
+		log.Printf("job %d of kind %s is dead after %d attempts: %s", obj.ID, obj.Kind, obj.Attempts, obj.LastError)
+		return obj
+	}
+	obj.Status = model.JobPending
+	obj.RunAt = now.Add(jobRetryDelay << (obj.Attempts - 1))
+	return obj
+}
+
+// handle runs the handler of the job within its lease, turning panics into errors so that a single bad job does
+// not take its worker down.
+func (job Job) handle(ctx context.Context, obj model.Job) (err error) {
+	handler, ok := job.handlers[obj.Kind]
+	if !ok {
+		return fmt.Errorf("no handler for jobs of kind %s", obj.Kind)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, jobLease)
+	defer cancel()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, obj.Payload)
+}
+
+// GetAll returns the latest jobs, newest first, optionally narrowed down to a status and a kind.
+func (job Job) GetAll(ctx context.Context, status, kind string) (contract.JobList, error) {
+	var statusFilter *int
+	if status != "" {
+		s, ok := model.JobStatuses[status]
+		if !ok {
+			return contract.JobList{}, model.Validation("status", fmt.Sprintf("unknown job status %s", status))
+		}
+		value := int(s)
+		statusFilter = &value
+	}
+
+	jobs, err := job.jobRepository.GetAll(ctx, statusFilter, kind, jobListSize)
+	if err != nil {
+		return contract.JobList{}, err
+	}
+
+	resp := make([]contract.JobResponse, 0)
+	for _, obj := range jobs {
+		resp = append(resp, toJobContract(obj))
+	}
+	return contract.JobList{Jobs: resp}, nil
+}
+
+func (job Job) Get(ctx context.Context, jobID int) (contract.JobResponse, error) {
+	obj, err := job.jobRepository.GetByID(ctx, jobID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.JobResponse{}, model.NotFound("job", err)
+	}
+	if err != nil {
+		return contract.JobResponse{}, err
+	}
+	return toJobContract(obj), nil
+}
+
+// Retry runs a dead job again as soon as possible, with a fresh set of attempts.
+func (job Job) Retry(ctx context.Context, jobID int) (contract.JobResponse, error) {
+	obj, err := job.jobRepository.Retry(ctx, jobID, job.now())
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.JobResponse{}, model.NotFound("job", err)
+	}
+	if err != nil {
+		return contract.JobResponse{}, err
+	}
+	return toJobContract(obj), nil
+}
+
+// newJob returns a job of the given kind, due straight away, whose payload is the JSON encoding of payload.
+func newJob(kind string, payload interface{}, now time.Time) (model.Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return model.Job{}, err
+	}
+	return model.Job{
+		Kind:        kind,
+		Payload:     datatypes.JSON(data),
+		Status:      model.JobPending,
+		MaxAttempts: maxJobAttempts,
+		RunAt:       now,
+	}, nil
+}
+
+// jobSpec is the kind and the payload of a job to enqueue.
+type jobSpec struct {
+	kind    string
+	payload interface{}
+}
+
+// newJobs returns a job for each of the specs, due straight away.
+func newJobs(now time.Time, specs ...jobSpec) ([]model.Job, error) {
+	jobs := make([]model.Job, 0, len(specs))
+	for _, spec := range specs {
+		job, err := newJob(spec.kind, spec.payload, now)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+func toJobContract(obj model.Job) contract.JobResponse {
+	resp := contract.JobResponse{
+		ID:          obj.ID,
+		Kind:        obj.Kind,
+		Status:      obj.Status.String(),
+		Attempts:    obj.Attempts,
+		MaxAttempts: obj.MaxAttempts,
+		LastError:   obj.LastError,
+		CreatedAt:   obj.CreatedAt,
+		Payload:     json.RawMessage(obj.Payload),
+	}
+	if obj.Status == model.JobPending {
+		runAt := obj.RunAt
+		resp.RunAt = &runAt
+	}
+	if obj.Status == model.JobSucceeded || obj.Status == model.JobDead {
+		finishedAt := obj.FinishedAt
+		resp.FinishedAt = &finishedAt
+	}
+	return resp
+}
+
+// NewJob returns a service running jobs with the given handlers, keyed by the kind of job they run. Services which
+// only inspect and retry jobs need no handlers.
+func NewJob(jobRepository JobRepository, handlers map[string]JobHandler) Job {
+	return Job{jobRepository: jobRepository, handlers: handlers, now: time.Now}
+}
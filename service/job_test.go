@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/datatypes"
+)
+
+type JobTestSuite struct {
+	suite.Suite
+	service           Job
+	mockJobRepository *MockJobRepository
+	handled           [][]byte
+	handlerErr        error
+	now               time.Time
+	ctx               context.Context
+}
+
+func (suite *JobTestSuite) SetupTest() {
+	suite.mockJobRepository = &MockJobRepository{}
+	suite.handled = nil
+	suite.handlerErr = nil
+	suite.now = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.service = NewJob(suite.mockJobRepository, map[string]JobHandler{
+		"test": func(ctx context.Context, payload []byte) error {
+			suite.handled = append(suite.handled, payload)
+			return suite.handlerErr
+		},
+		"panic": func(ctx context.Context, payload []byte) error {
+			panic("boom")
+		},
+	})
+	suite.service.now = func() time.Time { return suite.now }
+	suite.ctx = context.Background()
+}
+
+func (suite *JobTestSuite) claim(obj model.Job) {
+	suite.mockJobRepository.On("Claim", suite.ctx, suite.now, suite.now.Add(jobLease)).Return(obj, nil)
+}
+
+func (suite *JobTestSuite) TestRunNextRunsDueJobAndMarksItSucceeded() {
+	suite.claim(model.Job{ID: 1, Kind: "test", Payload: datatypes.JSON(`{"id":1}`), Status: model.JobRunning, Attempts: 1, MaxAttempts: 3,
+		LockedUntil: suite.now.Add(jobLease), LastError: "previous error"})
+	suite.mockJobRepository.On("Finish", suite.ctx, model.Job{ID: 1, Kind: "test", Payload: datatypes.JSON(`{"id":1}`), Status: model.JobSucceeded,
+		Attempts: 1, MaxAttempts: 3, FinishedAt: suite.now}).Return(nil)
+
+	ran, err := suite.service.RunNext(suite.ctx)
+	suite.NoError(err)
+	suite.True(ran)
+	suite.Equal([][]byte{[]byte(`{"id":1}`)}, suite.handled)
+	suite.mockJobRepository.AssertExpectations(suite.T())
+}
+
+func (suite *JobTestSuite) TestRunNextRetriesFailedJobWithBackoff() {
+	suite.handlerErr = errors.New("some error")
+	suite.claim(model.Job{ID: 1, Kind: "test", Status: model.JobRunning, Attempts: 3, MaxAttempts: 10, LockedUntil: suite.now.Add(jobLease)})
+	suite.mockJobRepository.On("Finish", suite.ctx, model.Job{ID: 1, Kind: "test", Status: model.JobPending, Attempts: 3, MaxAttempts: 10,
+		RunAt: suite.now.Add(4 * jobRetryDelay), LastError: "some error"}).Return(nil)
+
+	ran, err := suite.service.RunNext(suite.ctx)
+	suite.NoError(err)
+	suite.True(ran)
+	suite.mockJobRepository.AssertExpectations(suite.T())
+}
+
+func (suite *JobTestSuite) TestRunNextMarksJobDeadAfterMaxAttempts() {
+	suite.handlerErr = errors.New("some error")
+	suite.claim(model.Job{ID: 1, Kind: "test", Status: model.JobRunning, Attempts: 10, MaxAttempts: 10})
+	suite.mockJobRepository.On("Finish", suite.ctx, mock.MatchedBy(func(obj model.Job) bool {
+		return obj.Status == model.JobDead && obj.LastError == "some error" && obj.FinishedAt.Equal(suite.now)
+	})).Return(nil)
+
+	ran, err := suite.service.RunNext(suite.ctx)
+	suite.NoError(err)
+	suite.True(ran)
+	suite.mockJobRepository.AssertExpectations(suite.T())
+}
+
+func (suite *JobTestSuite) TestRunNextFailsJobsWithoutHandler() {
+	suite.claim(model.Job{ID: 1, Kind: "unknown", Status: model.JobRunning, Attempts: 1, MaxAttempts: 10})
+	suite.mockJobRepository.On("Finish", suite.ctx, mock.MatchedBy(func(obj model.Job) bool {
+		return obj.Status == model.JobPending && obj.LastError == "no handler for jobs of kind unknown"
+	})).Return(nil)
+
+	_, err := suite.service.RunNext(suite.ctx)
+	suite.NoError(err)
+	suite.mockJobRepository.AssertExpectations(suite.T())
+}
+
+func (suite *JobTestSuite) TestRunNextRecoversFromPanickingJobs() {
+	suite.claim(model.Job{ID: 1, Kind: "panic", Status: model.JobRunning, Attempts: 1, MaxAttempts: 10})
+	suite.mockJobRepository.On("Finish", suite.ctx, mock.MatchedBy(func(obj model.Job) bool {
+		return obj.Status == model.JobPending && obj.LastError == "job panicked: boom"
+	})).Return(nil)
+
+	_, err := suite.service.RunNext(suite.ctx)
+	suite.NoError(err)
+	suite.mockJobRepository.AssertExpectations(suite.T())
+}
+
+func (suite *JobTestSuite) TestRunNextReportsThatNothingIsDue() {
+	suite.mockJobRepository.On("Claim", suite.ctx, suite.now, suite.now.Add(jobLease)).Return(model.Job{}, sql.ErrNoRows)
+
+	ran, err := suite.service.RunNext(suite.ctx)
+	suite.NoError(err)
+	suite.False(ran)
+	suite.Empty(suite.handled)
+}
+
+func (suite *JobTestSuite) TestRunNextReturnsErrorIfOutcomeCannotBeRecorded() {
+	suite.claim(model.Job{ID: 1, Kind: "test", Status: model.JobRunning, Attempts: 1, MaxAttempts: 10})
+	suite.mockJobRepository.On("Finish", suite.ctx, mock.Anything).Return(sql.ErrNoRows)
+
+	ran, err := suite.service.RunNext(suite.ctx)
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.True(ran)
+}
+
+func (suite *JobTestSuite) TestGetAllFiltersByStatusAndKind() {
+	dead := int(model.JobDead)
+	suite.mockJobRepository.On("GetAll", suite.ctx, &dead, "test", jobListSize).Return([]model.Job{
+		{ID: 2, Kind: "test", Status: model.JobDead, Attempts: 10, MaxAttempts: 10, LastError: "some error", FinishedAt: suite.now},
+	}, nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, "dead", "test")
+	suite.NoError(err)
+	suite.Len(resp.Jobs, 1)
+	suite.Equal("dead", resp.Jobs[0].Status)
+	suite.Equal(suite.now, *resp.Jobs[0].FinishedAt)
+	suite.Nil(resp.Jobs[0].RunAt)
+}
+
+func (suite *JobTestSuite) TestGetAllReturnsErrorOnUnknownStatus() {
+	_, err := suite.service.GetAll(suite.ctx, "stuck", "")
+	suite.Error(err)
+	suite.mockJobRepository.AssertNotCalled(suite.T(), "GetAll", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *JobTestSuite) TestGetReturnsNotFoundIfJobDoesNotExist() {
+	suite.mockJobRepository.On("GetByID", suite.ctx, 2).Return(model.Job{}, sql.ErrNoRows)
+
+	_, err := suite.service.Get(suite.ctx, 2)
+	suite.ErrorIs(err, sql.ErrNoRows)
+}
+
+func (suite *JobTestSuite) TestRetryRunsDeadJobAgain() {
+	suite.mockJobRepository.On("Retry", suite.ctx, 2, suite.now).Return(model.Job{ID: 2, Kind: "test", Status: model.JobPending, MaxAttempts: 10,
+		RunAt: suite.now}, nil)
+
+	resp, err := suite.service.Retry(suite.ctx, 2)
+	suite.NoError(err)
+	suite.Equal("pending", resp.Status)
+	suite.Equal(suite.now, *resp.RunAt)
+}
+
+func (suite *JobTestSuite) TestRetryReturnsErrorIfJobIsNotDead() {
+	suite.mockJobRepository.On("Retry", suite.ctx, 2, suite.now).Return(model.Job{}, model.ErrJobNotDead)
+
+	_, err := suite.service.Retry(suite.ctx, 2)
+	suite.ErrorIs(err, model.ErrJobNotDead)
+}
+
+func (suite *JobTestSuite) TestRetryReturnsNotFoundIfJobDoesNotExist() {
+	suite.mockJobRepository.On("Retry", suite.ctx, 2, suite.now).Return(model.Job{}, sql.ErrNoRows)
+
+	_, err := suite.service.Retry(suite.ctx, 2)
+	suite.ErrorIs(err, sql.ErrNoRows)
+}
+
+func TestJobTestSuite(t *testing.T) {
+	suite.Run(t, new(JobTestSuite))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/harbor-xyz/coding-project/mail"
 	"github.com/harbor-xyz/coding-project/model"
 
 	"github.com/stretchr/testify/mock"
@@ -18,12 +19,57 @@ func (mock *MockUserRepository) Create(ctx context.Context, user model.User) (mo
 	return args.Get(0).(model.User), args.Error(1)
 }
 
+func (mock *MockUserRepository) GetByID(ctx context.Context, userID int) (model.User, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (mock *MockUserRepository) GetAll(ctx context.Context, email string, limit, offset int) ([]model.User, int64, error) {
+	args := mock.Called(ctx, email, limit, offset)
+	return args.Get(0).([]model.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (mock *MockUserRepository) Update(ctx context.Context, userObj model.User) (model.User, error) {
+	args := mock.Called(ctx, userObj)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (mock *MockUserRepository) Delete(ctx context.Context, userID int) error {
+	args := mock.Called(ctx, userID)
+	return args.Error(0)
+}
+
+func (mock *MockUserRepository) GetByFeedTokenHash(ctx context.Context, hash string) (model.User, error) {
+	args := mock.Called(ctx, hash)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (mock *MockUserRepository) SetFeedTokenHash(ctx context.Context, userID int, hash string) error {
+	args := mock.Called(ctx, userID, hash)
+	return args.Error(0)
+}
+
+func (mock *MockUserRepository) GetBySlug(ctx context.Context, slug string) (model.User, error) {
+	args := mock.Called(ctx, slug)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (mock *MockUserRepository) SetSlug(ctx context.Context, userID int, slug string) error {
+	args := mock.Called(ctx, userID, slug)
+	return args.Error(0)
+}
+
 type MockUserAvailabilityRepository struct {
 	mock.Mock
+	// Jobs are the jobs enqueued along with successful writes
+	Jobs []model.Job
 }
 
-func (mock *MockUserAvailabilityRepository) Set(ctx context.Context, ua model.UserAvailability) (model.UserAvailability, error) {
+func (mock *MockUserAvailabilityRepository) Set(ctx context.Context, ua model.UserAvailability, jobs []model.Job) (model.UserAvailability, error) {
 	args := mock.Called(ctx, ua)
+	if args.Error(1) == nil {
+		mock.Jobs = append(mock.Jobs, jobs...)
+	}
 	return args.Get(0).(model.UserAvailability), args.Error(1)
 }
 
@@ -32,8 +78,67 @@ func (mock *MockUserAvailabilityRepository) Get(ctx context.Context, userID int)
 	return args.Get(0).(model.UserAvailability), args.Error(1)
 }
 
+func (mock *MockUserAvailabilityRepository) GetAll(ctx context.Context) ([]model.UserAvailability, error) {
+	args := mock.Called(ctx)
+	return args.Get(0).([]model.UserAvailability), args.Error(1)
+}
+
+func (mock *MockUserAvailabilityRepository) MarkSlotsSynced(ctx context.Context, userID int, syncedAt time.Time) error {
+	args := mock.Called(ctx, userID, syncedAt)
+	return args.Error(0)
+}
+
+type MockAvailabilityOverrideRepository struct {
+	mock.Mock
+}
+
+func (mock *MockAvailabilityOverrideRepository) Create(ctx context.Context, override model.AvailabilityOverride) (model.AvailabilityOverride, error) {
+	args := mock.Called(ctx, override)
+	return args.Get(0).(model.AvailabilityOverride), args.Error(1)
+}
+
+func (mock *MockAvailabilityOverrideRepository) GetAll(ctx context.Context, userID int) ([]model.AvailabilityOverride, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).([]model.AvailabilityOverride), args.Error(1)
+}
+
+func (mock *MockAvailabilityOverrideRepository) GetInRange(ctx context.Context, userID int, from, to time.Time) ([]model.AvailabilityOverride, error) {
+	args := mock.Called(ctx, userID, from, to)
+	return args.Get(0).([]model.AvailabilityOverride), args.Error(1)
+}
+
+func (mock *MockAvailabilityOverrideRepository) GetByID(ctx context.Context, userID, overrideID int) (model.AvailabilityOverride, error) {
+	args := mock.Called(ctx, userID, overrideID)
+	return args.Get(0).(model.AvailabilityOverride), args.Error(1)
+}
+
+func (mock *MockAvailabilityOverrideRepository) Update(ctx context.Context, override model.AvailabilityOverride) (model.AvailabilityOverride, error) {
+	args := mock.Called(ctx, override)
+	return args.Get(0).(model.AvailabilityOverride), args.Error(1)
+}
+
+func (mock *MockAvailabilityOverrideRepository) Delete(ctx context.Context, userID, overrideID int) error {
+	args := mock.Called(ctx, userID, overrideID)
+	return args.Error(0)
+}
+
 type MockEventRepository struct {
 	mock.Mock
+	// Jobs are the jobs enqueued along with successful writes
+	Jobs []model.Job
+}
+
+// enqueue builds the jobs of a successful write of event, the way the repository does within its transaction.
+func (mock *MockEventRepository) enqueue(event model.Event, err error, jobs model.EventJobs) (model.Event, error) {
+	if err != nil || jobs == nil {
+		return event, err
+	}
+	queued, err := jobs(event)
+	if err != nil {
+		return model.Event{}, err
+	}
+	mock.Jobs = append(mock.Jobs, queued...)
+	return event, nil
 }
 
 func (mock *MockEventRepository) Create(ctx context.Context, event model.Event) (model.Event, error) {
@@ -46,6 +151,84 @@ func (mock *MockEventRepository) GetAll(ctx context.Context, userID int) ([]mode
 	return args.Get(0).([]model.Event), args.Error(1)
 }
 
+func (mock *MockEventRepository) GetInRange(ctx context.Context, userID int, from, to time.Time) ([]model.Event, error) {
+	args := mock.Called(ctx, userID, from, to)
+	return args.Get(0).([]model.Event), args.Error(1)
+}
+
+func (mock *MockEventRepository) BookSlot(ctx context.Context, event model.Event, limits model.BookingLimits, jobs model.EventJobs) (model.Event, error) {
+	args := mock.Called(ctx, event, limits)
+	return mock.enqueue(args.Get(0).(model.Event), args.Error(1), jobs)
+}
+
+func (mock *MockEventRepository) BookTime(ctx context.Context, event model.Event, slot model.Slot, limits model.BookingLimits, jobs model.EventJobs) (model.Event, error) {
+	args := mock.Called(ctx, event, slot, limits)
+	return mock.enqueue(args.Get(0).(model.Event), args.Error(1), jobs)
+}
+
+func (mock *MockEventRepository) BookTeam(ctx context.Context, teamID uint, bookings []model.Booking, jobs model.EventJobs) ([]model.Event, error) {
+	args := mock.Called(ctx, teamID, bookings)
+	return mock.enqueueAll(args.Get(0).([]model.Event), args.Error(1), jobs)
+}
+
+// enqueueAll builds the jobs of each event of a successful write of several events.
+func (mock *MockEventRepository) enqueueAll(events []model.Event, err error, jobs model.EventJobs) ([]model.Event, error) {
+	if err != nil {
+		return nil, err
+	}
+	for i := range events {
+		if events[i], err = mock.enqueue(events[i], nil, jobs); err != nil {
+			return nil, err
+		}
+	}
+	return events, nil
+}
+
+func (mock *MockEventRepository) BookSeries(ctx context.Context, series model.EventSeries, bookings []model.Booking, jobs model.EventJobs) (model.EventSeries, error) {
+	args := mock.Called(ctx, series, bookings)
+	series, err := args.Get(0).(model.EventSeries), args.Error(1)
+	if err != nil {
+		return model.EventSeries{}, err
+	}
+	series.Events, err = mock.enqueueAll(series.Events, nil, jobs)
+	return series, err
+}
+
+func (mock *MockEventRepository) GetSeries(ctx context.Context, userID, seriesID int) (model.EventSeries, error) {
+	args := mock.Called(ctx, userID, seriesID)
+	return args.Get(0).(model.EventSeries), args.Error(1)
+}
+
+func (mock *MockEventRepository) CancelSeries(ctx context.Context, events []model.Event, change model.EventChange, jobs model.EventJobs) ([]model.Event, error) {
+	args := mock.Called(ctx, events, change)
+	return mock.enqueueAll(args.Get(0).([]model.Event), args.Error(1), jobs)
+}
+
+func (mock *MockEventRepository) RescheduleSeries(ctx context.Context, bookings []model.Booking, change model.EventChange, jobs model.EventJobs) ([]model.Event, error) {
+	args := mock.Called(ctx, bookings, change)
+	return mock.enqueueAll(args.Get(0).([]model.Event), args.Error(1), jobs)
+}
+
+func (mock *MockEventRepository) GetByID(ctx context.Context, userID, eventID int) (model.Event, error) {
+	args := mock.Called(ctx, userID, eventID)
+	return args.Get(0).(model.Event), args.Error(1)
+}
+
+func (mock *MockEventRepository) GetChanges(ctx context.Context, eventID int) ([]model.EventChange, error) {
+	args := mock.Called(ctx, eventID)
+	return args.Get(0).([]model.EventChange), args.Error(1)
+}
+
+func (mock *MockEventRepository) Cancel(ctx context.Context, event model.Event, change model.EventChange, jobs model.EventJobs) (model.Event, error) {
+	args := mock.Called(ctx, event, change)
+	return mock.enqueue(args.Get(0).(model.Event), args.Error(1), jobs)
+}
+
+func (mock *MockEventRepository) Reschedule(ctx context.Context, event model.Event, slot model.Slot, limits model.BookingLimits, change model.EventChange, jobs model.EventJobs) (model.Event, error) {
+	args := mock.Called(ctx, event, slot, limits, change)
+	return mock.enqueue(args.Get(0).(model.Event), args.Error(1), jobs)
+}
+
 type MockSlotRepository struct {
 	mock.Mock
 }
@@ -70,7 +253,283 @@ func (mock *MockSlotRepository) DeleteByID(ctx context.Context, slotID int) erro
 	return args.Error(0)
 }
 
-func (mock *MockSlotRepository) BookSlot(ctx context.Context, slotID int) error {
-	args := mock.Called(ctx, slotID)
+func (mock *MockSlotRepository) Expire(ctx context.Context, before time.Time) (int64, error) {
+	args := mock.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (mock *MockSlotRepository) DeleteUnbooked(ctx context.Context, userID int, after time.Time) error {
+	args := mock.Called(ctx, userID, after)
+	return args.Error(0)
+}
+
+type MockEventTypeRepository struct {
+	mock.Mock
+}
+
+func (mock *MockEventTypeRepository) Create(ctx context.Context, eventType model.EventType) (model.EventType, error) {
+	args := mock.Called(ctx, eventType)
+	return args.Get(0).(model.EventType), args.Error(1)
+}
+
+func (mock *MockEventTypeRepository) GetAll(ctx context.Context, userID int) ([]model.EventType, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).([]model.EventType), args.Error(1)
+}
+
+func (mock *MockEventTypeRepository) GetByID(ctx context.Context, userID, eventTypeID int) (model.EventType, error) {
+	args := mock.Called(ctx, userID, eventTypeID)
+	return args.Get(0).(model.EventType), args.Error(1)
+}
+
+func (mock *MockEventTypeRepository) GetBySlug(ctx context.Context, userID int, slug string) (model.EventType, error) {
+	args := mock.Called(ctx, userID, slug)
+	return args.Get(0).(model.EventType), args.Error(1)
+}
+
+func (mock *MockEventTypeRepository) Update(ctx context.Context, eventType model.EventType) (model.EventType, error) {
+	args := mock.Called(ctx, eventType)
+	return args.Get(0).(model.EventType), args.Error(1)
+}
+
+func (mock *MockEventTypeRepository) Delete(ctx context.Context, userID, eventTypeID int) error {
+	args := mock.Called(ctx, userID, eventTypeID)
+	return args.Error(0)
+}
+
+type MockTeamRepository struct {
+	mock.Mock
+}
+
+func (mock *MockTeamRepository) Create(ctx context.Context, team model.Team) (model.Team, error) {
+	args := mock.Called(ctx, team)
+	return args.Get(0).(model.Team), args.Error(1)
+}
+
+func (mock *MockTeamRepository) GetAll(ctx context.Context, ownerID int) ([]model.Team, error) {
+	args := mock.Called(ctx, ownerID)
+	return args.Get(0).([]model.Team), args.Error(1)
+}
+
+func (mock *MockTeamRepository) GetByID(ctx context.Context, ownerID, teamID int) (model.Team, error) {
+	args := mock.Called(ctx, ownerID, teamID)
+	return args.Get(0).(model.Team), args.Error(1)
+}
+
+func (mock *MockTeamRepository) Delete(ctx context.Context, ownerID, teamID int) error {
+	args := mock.Called(ctx, ownerID, teamID)
+	return args.Error(0)
+}
+
+func (mock *MockTeamRepository) AddMember(ctx context.Context, member model.TeamMember) (model.TeamMember, error) {
+	args := mock.Called(ctx, member)
+	return args.Get(0).(model.TeamMember), args.Error(1)
+}
+
+func (mock *MockTeamRepository) RemoveMember(ctx context.Context, teamID, userID int) error {
+	args := mock.Called(ctx, teamID, userID)
+	return args.Error(0)
+}
+
+func (mock *MockTeamRepository) GetMemberships(ctx context.Context, userID int) ([]model.Team, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).([]model.Team), args.Error(1)
+}
+
+func (mock *MockTeamRepository) AcceptMember(ctx context.Context, teamID, userID int) error {
+	args := mock.Called(ctx, teamID, userID)
+	return args.Error(0)
+}
+
+func (mock *MockTeamRepository) CreateEventType(ctx context.Context, eventType model.TeamEventType) (model.TeamEventType, error) {
+	args := mock.Called(ctx, eventType)
+	return args.Get(0).(model.TeamEventType), args.Error(1)
+}
+
+func (mock *MockTeamRepository) GetEventTypes(ctx context.Context, teamID int) ([]model.TeamEventType, error) {
+	args := mock.Called(ctx, teamID)
+	return args.Get(0).([]model.TeamEventType), args.Error(1)
+}
+
+func (mock *MockTeamRepository) GetEventType(ctx context.Context, teamID, eventTypeID int) (model.TeamEventType, error) {
+	args := mock.Called(ctx, teamID, eventTypeID)
+	return args.Get(0).(model.TeamEventType), args.Error(1)
+}
+
+func (mock *MockTeamRepository) DeleteEventType(ctx context.Context, teamID, eventTypeID int) error {
+	args := mock.Called(ctx, teamID, eventTypeID)
+	return args.Error(0)
+}
+
+type MockExternalCalendarRepository struct {
+	mock.Mock
+}
+
+func (mock *MockExternalCalendarRepository) Create(ctx context.Context, calendar model.ExternalCalendar) (model.ExternalCalendar, error) {
+	args := mock.Called(ctx, calendar)
+	return args.Get(0).(model.ExternalCalendar), args.Error(1)
+}
+
+func (mock *MockExternalCalendarRepository) GetAll(ctx context.Context, userID int) ([]model.ExternalCalendar, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).([]model.ExternalCalendar), args.Error(1)
+}
+
+func (mock *MockExternalCalendarRepository) GetAllWithURL(ctx context.Context) ([]model.ExternalCalendar, error) {
+	args := mock.Called(ctx)
+	return args.Get(0).([]model.ExternalCalendar), args.Error(1)
+}
+
+func (mock *MockExternalCalendarRepository) GetByID(ctx context.Context, userID, calendarID int) (model.ExternalCalendar, error) {
+	args := mock.Called(ctx, userID, calendarID)
+	return args.Get(0).(model.ExternalCalendar), args.Error(1)
+}
+
+func (mock *MockExternalCalendarRepository) Delete(ctx context.Context, userID, calendarID int) error {
+	args := mock.Called(ctx, userID, calendarID)
+	return args.Error(0)
+}
+
+func (mock *MockExternalCalendarRepository) ReplaceBusyBlocks(ctx context.Context, calendar model.ExternalCalendar, blocks []model.BusyBlock, syncedAt time.Time) (model.ExternalCalendar, error) {
+	args := mock.Called(ctx, calendar, blocks, syncedAt)
+	return args.Get(0).(model.ExternalCalendar), args.Error(1)
+}
+
+type MockBusyBlockRepository struct {
+	mock.Mock
+}
+
+func (mock *MockBusyBlockRepository) GetInRange(ctx context.Context, userID int, from, to time.Time) ([]model.BusyBlock, error) {
+	args := mock.Called(ctx, userID, from, to)
+	return args.Get(0).([]model.BusyBlock), args.Error(1)
+}
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (mock *MockWebhookRepository) Create(ctx context.Context, webhook model.Webhook) (model.Webhook, error) {
+	args := mock.Called(ctx, webhook)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (mock *MockWebhookRepository) GetAll(ctx context.Context, userID int) ([]model.Webhook, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).([]model.Webhook), args.Error(1)
+}
+
+func (mock *MockWebhookRepository) GetByID(ctx context.Context, userID, webhookID int) (model.Webhook, error) {
+	args := mock.Called(ctx, userID, webhookID)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (mock *MockWebhookRepository) Delete(ctx context.Context, userID, webhookID int) error {
+	args := mock.Called(ctx, userID, webhookID)
+	return args.Error(0)
+}
+
+func (mock *MockWebhookRepository) CreateDeliveries(ctx context.Context, deliveries []model.WebhookDelivery) error {
+	args := mock.Called(ctx, deliveries)
+	return args.Error(0)
+}
+
+func (mock *MockWebhookRepository) GetDeliveries(ctx context.Context, webhookID, limit int) ([]model.WebhookDelivery, error) {
+	args := mock.Called(ctx, webhookID, limit)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (mock *MockWebhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	args := mock.Called(ctx, now, limit)
+	return args.Get(0).([]model.WebhookDelivery), args.Error(1)
+}
+
+func (mock *MockWebhookRepository) UpdateDelivery(ctx context.Context, delivery model.WebhookDelivery) error {
+	args := mock.Called(ctx, delivery)
+	return args.Error(0)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (mock *MockNotificationRepository) Create(ctx context.Context, notifications []model.Notification) error {
+	args := mock.Called(ctx, notifications)
+	return args.Error(0)
+}
+
+func (mock *MockNotificationRepository) GetDue(ctx context.Context, now time.Time, limit int) ([]model.Notification, error) {
+	args := mock.Called(ctx, now, limit)
+	return args.Get(0).([]model.Notification), args.Error(1)
+}
+
+func (mock *MockNotificationRepository) Update(ctx context.Context, notification model.Notification) error {
+	args := mock.Called(ctx, notification)
+	return args.Error(0)
+}
+
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (mock *MockNotifier) Send(ctx context.Context, msg mail.Message) error {
+	args := mock.Called(ctx, msg)
+	return args.Error(0)
+}
+
+type MockJobRepository struct {
+	mock.Mock
+}
+
+func (mock *MockJobRepository) Claim(ctx context.Context, now, lockedUntil time.Time) (model.Job, error) {
+	args := mock.Called(ctx, now, lockedUntil)
+	return args.Get(0).(model.Job), args.Error(1)
+}
+
+func (mock *MockJobRepository) Finish(ctx context.Context, job model.Job) error {
+	args := mock.Called(ctx, job)
+	return args.Error(0)
+}
+
+func (mock *MockJobRepository) GetAll(ctx context.Context, status *int, kind string, limit int) ([]model.Job, error) {
+	args := mock.Called(ctx, status, kind, limit)
+	return args.Get(0).([]model.Job), args.Error(1)
+}
+
+func (mock *MockJobRepository) GetByID(ctx context.Context, jobID int) (model.Job, error) {
+	args := mock.Called(ctx, jobID)
+	return args.Get(0).(model.Job), args.Error(1)
+}
+
+func (mock *MockJobRepository) Retry(ctx context.Context, jobID int, now time.Time) (model.Job, error) {
+	args := mock.Called(ctx, jobID, now)
+	return args.Get(0).(model.Job), args.Error(1)
+}
+
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (mock *MockAPIKeyRepository) Create(ctx context.Context, apiKey model.APIKey) (model.APIKey, error) {
+	args := mock.Called(ctx, apiKey)
+	return args.Get(0).(model.APIKey), args.Error(1)
+}
+
+func (mock *MockAPIKeyRepository) GetAll(ctx context.Context, userID int) ([]model.APIKey, error) {
+	args := mock.Called(ctx, userID)
+	return args.Get(0).([]model.APIKey), args.Error(1)
+}
+
+func (mock *MockAPIKeyRepository) GetByHash(ctx context.Context, hash string) (model.APIKey, error) {
+	args := mock.Called(ctx, hash)
+	return args.Get(0).(model.APIKey), args.Error(1)
+}
+
+func (mock *MockAPIKeyRepository) SetLastUsedAt(ctx context.Context, apiKeyID int, usedAt time.Time) error {
+	args := mock.Called(ctx, apiKeyID, usedAt)
+	return args.Error(0)
+}
+
+func (mock *MockAPIKeyRepository) Delete(ctx context.Context, userID, apiKeyID int) error {
+	args := mock.Called(ctx, userID, apiKeyID)
 	return args.Error(0)
 }
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	netmail "net/mail"
+	"strings"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/ical"
+	"github.com/harbor-xyz/coding-project/mail"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+const (
+	// maxNotificationAttempts is how often a notification is attempted before it is marked as failed
+	maxNotificationAttempts = 5
+	notificationRetryDelay  = time.Minute
+	// notificationBatchSize bounds the notifications sent on each run of the dispatcher
+	notificationBatchSize = 100
+)
+
+const notificationTimeLayout = "Monday, 2 January 2006 15:04"
+
+// Notification sends the notifications queued about bookings by email.
+type Notification struct {
+	notificationRepository NotificationRepository
+	eventRepository        EventRepository
+	userRepository         UserRepository
+	eventTypeRepository    EventTypeRepository
+	notifier               Notifier
+	from                   netmail.Address
+	now                    func() time.Time
+}
+
+// SendDue sends the pending notifications which are due and returns the number of them which were sent. Failed
+// attempts are retried with exponential backoff until maxNotificationAttempts is reached.
+func (notification Notification) SendDue(ctx context.Context) (int, error) {
+	notifications, err := notification.notificationRepository.GetDue(ctx, notification.now(), notificationBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, obj := range notifications {
+		obj = notification.attempt(ctx, obj)
+		if err := notification.notificationRepository.Update(ctx, obj); err != nil {
+			// The notification stays due, so it is attempted again on the next run
+			continue
+		}
+		if obj.Status == model.DeliverySucceeded {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// attempt sends the notification and records the outcome on it. Reminders of events which have been cancelled or
+// rescheduled since they were queued are skipped, as the reschedule queued reminders of its own.
+func (notification Notification) attempt(ctx context.Context, obj model.Notification) model.Notification {
+	if obj.Kind == model.NotificationReminder &&
+		(obj.Event.Status == model.EventCancelled || !obj.Event.StartTime.Equal(obj.EventStartTime)) {
+		obj.Status = model.DeliverySkipped
+		return obj
+	}
+
+	now := notification.now()
+	obj.Attempts++
+	obj.Error = ""
+
+	err := notification.send(ctx, obj)
+	if err == nil {
+		obj.Status = model.DeliverySucceeded
+		obj.SentAt = now
+		return obj
+	}
+
+	obj.Error = err.Error()
+	if obj.Attempts >= maxNotificationAttempts {
+		obj.Status = model.DeliveryFailed
+		log.Printf("giving up on notification %d after %d attempts: %s", obj.ID, obj.Attempts, obj.Error)
+		return obj
+	}
+	obj.SendAt = now.Add(notificationRetryDelay << (obj.Attempts - 1))
+	return obj
+}
+
+func (notification Notification) send(ctx context.Context, obj model.Notification) error {
+	host, err := notification.userRepository.GetByID(ctx, int(obj.Event.UserID))
+	if err != nil {
+		return err
+	}
+	var eventType model.EventType
+	if obj.Event.EventTypeID != 0 {
+		eventType, err = notification.eventTypeRepository.GetByID(ctx, int(obj.Event.UserID), int(obj.Event.EventTypeID))
+		// The event is still worth telling about once its event type is deleted
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	}
+
+	// Every cancellation and reschedule of the event is a revision of its invitation
+	changes, err := notification.eventRepository.GetChanges(ctx, int(obj.Event.ID))
+	if err != nil {
+		return err
+	}
+
+	msg, err := notification.message(obj, host, eventType, len(changes))
+	if err != nil {
+		return err
+	}
+	return notification.notifier.Send(ctx, msg)
+}
+
+// message renders the notification in the host's time zone. All but reminders carry the event as an iCalendar
+// invitation at the given revision, so that mail clients offer to add it to the recipient's calendar, to update it or
+// to remove it.
+func (notification Notification) message(obj model.Notification, host model.User, eventType model.EventType, sequence int) (mail.Message, error) {
+	eventObj := obj.Event
+	loc, err := host.Location()
+	if err != nil {
+		loc = time.UTC
+	}
+
+	title := eventType.Name
+	if title == "" {
+		title = "Meeting"
+	}
+	to := netmail.Address{Name: eventObj.InviteeName, Address: eventObj.InviteeEmail}
+	counterpart := host.Name
+	if obj.Recipient == model.RecipientHost {
+		to = netmail.Address{Name: host.Name, Address: host.Email}
+		counterpart = eventObj.InviteeName
+	}
+
+	var subject, lead string
+	switch obj.Kind {
+	case model.NotificationConfirmation:
+		subject, lead = "Confirmed", fmt.Sprintf("Your meeting with %s is confirmed.", counterpart)
+		if obj.Recipient == model.RecipientHost {
+			subject, lead = "New booking", fmt.Sprintf("%s booked a meeting with you.", counterpart)
+		}
+	case model.NotificationCancellation:
+		subject, lead = "Cancelled", fmt.Sprintf("Your meeting with %s has been cancelled.", counterpart)
+	case model.NotificationReschedule:
+		subject, lead = "Rescheduled", fmt.Sprintf("Your meeting with %s has been moved to a new time.", counterpart)
+	case model.NotificationReminder:
+		subject, lead = "Reminder", fmt.Sprintf("Your meeting with %s is coming up.", counterpart)
+	default:
+		return mail.Message{}, fmt.Errorf("unknown notification kind %q", obj.Kind)
+	}
+
+	start, end := eventObj.StartTime.In(loc), eventObj.EndTime.In(loc)
+	var body strings.Builder
+	fmt.Fprintf(&body, "Hi %s,\n\n%s\n\n", to.Name, lead)
+	fmt.Fprintf(&body, "What: %s\n", title)
+	fmt.Fprintf(&body, "When: %s - %s (%s)\n", start.Format(notificationTimeLayout), end.Format("15:04"), loc.String())
+	fmt.Fprintf(&body, "Who: %s <%s>, %s <%s>\n", host.Name, host.Email, eventObj.InviteeName, eventObj.InviteeEmail)
+	if eventType.Location != "" {
+		fmt.Fprintf(&body, "Where: %s\n", eventType.Location)
+	}
+	if eventObj.InviteeNotes != "" {
+		fmt.Fprintf(&body, "Notes: %s\n", eventObj.InviteeNotes)
+	}
+	if obj.Kind == model.NotificationCancellation && eventObj.CancelReason != "" {
+		fmt.Fprintf(&body, "Reason: %s\n", eventObj.CancelReason)
+	}
+
+	msg := mail.Message{
+		From:    notification.from,
+		To:      []netmail.Address{to},
+		Subject: fmt.Sprintf("%s: %s with %s on %s", subject, title, counterpart, start.Format(notificationTimeLayout)),
+		Body:    body.String(),
+		Date:    notification.now(),
+	}
+	if obj.Kind == model.NotificationReminder {
+		return msg, nil
+	}
+
+	method := ical.MethodRequest
+	if obj.Kind == model.NotificationCancellation {
+		method = ical.MethodCancel
+	}
+	icalEvent := toICalEvent(eventObj, host, eventType)
+	icalEvent.Sequence = sequence
+	cal := ical.Calendar{ProdID: icalProdID, Method: method, Stamp: msg.Date, Events: []ical.Event{icalEvent}}
+	data, err := cal.Bytes()
+	if err != nil {
+		return mail.Message{}, err
+	}
+	msg.Attachments = []mail.Attachment{{
+		Filename:    "invite.ics",
+		ContentType: fmt.Sprintf("text/calendar; charset=utf-8; method=%s", method),
+		Data:        data,
+	}}
+	return msg, nil
+}
+
+// notifications queues the emails about a booking, a cancellation or a reschedule of an event.
+type notifications struct {
+	notificationRepository NotificationRepository
+	// reminders are how long before the start of events their reminders are sent
+	reminders []time.Duration
+	now       func() time.Time
+}
+
+// enqueue queues a notification of the given kind to both the host and the invitee of the event, along with the
+// reminders of confirmed events which are still ahead.
+func (notifications notifications) enqueue(ctx context.Context, eventObj model.Event, kind string) error {
+	now := notifications.now()
+	queued := make([]model.Notification, 0)
+	for _, recipient := range []string{model.RecipientInvitee, model.RecipientHost} {
+		queued = append(queued, model.Notification{
+			EventID:        eventObj.ID,
+			Kind:           kind,
+			Recipient:      recipient,
+			EventStartTime: eventObj.StartTime,
+			SendAt:         now,
+			Status:         model.DeliveryPending,
+		})
+		if eventObj.Status == model.EventCancelled {
+			continue
+		}
+		for _, before := range notifications.reminders {
+			sendAt := eventObj.StartTime.Add(-before)
+			if !sendAt.After(now) {
+				continue
+			}
+			queued = append(queued, model.Notification{
+				EventID:        eventObj.ID,
+				Kind:           model.NotificationReminder,
+				Recipient:      recipient,
+				EventStartTime: eventObj.StartTime,
+				SendAt:         sendAt,
+				Status:         model.DeliveryPending,
+			})
+		}
+	}
+
+	return notifications.notificationRepository.Create(ctx, queued)
+}
+
+func newNotifications(notificationRepository NotificationRepository, reminders []time.Duration) notifications {
+	return notifications{notificationRepository: notificationRepository, reminders: reminders, now: time.Now}
+}
+
+// NewNotification returns a service sending notifications through notifier, from the given address.
+func NewNotification(notificationRepository NotificationRepository, eventRepository EventRepository, userRepository UserRepository,
+	eventTypeRepository EventTypeRepository, notifier Notifier, from netmail.Address) Notification {
+	return Notification{
+		notificationRepository: notificationRepository,
+		eventRepository:        eventRepository,
+		userRepository:         userRepository,
+		eventTypeRepository:    eventTypeRepository,
+		notifier:               notifier,
+		from:                   from,
+		now:                    time.Now,
+	}
+}
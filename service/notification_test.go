@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	netmail "net/mail"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/mail"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type NotificationTestSuite struct {
+	suite.Suite
+	service                    Notification
+	mockNotificationRepository *MockNotificationRepository
+	mockEventRepository        *MockEventRepository
+	mockUserRepository         *MockUserRepository
+	mockEventTypeRepository    *MockEventTypeRepository
+	mockNotifier               *MockNotifier
+	ctx                        context.Context
+	now                        time.Time
+	start                      time.Time
+}
+
+func (suite *NotificationTestSuite) SetupTest() {
+	suite.mockNotificationRepository = &MockNotificationRepository{}
+	suite.mockEventRepository = &MockEventRepository{}
+	suite.mockEventRepository.On("GetChanges", mock.Anything, 3).Return([]model.EventChange{}, nil).Maybe()
+	suite.mockUserRepository = &MockUserRepository{}
+	suite.mockUserRepository.On("GetByID", mock.Anything, 1).Return(model.User{ID: 1, Name: "host", Email: "host@example.xyz",
+		TimeZone: "Asia/Kolkata"}, nil).Maybe()
+	suite.mockEventTypeRepository = &MockEventTypeRepository{}
+	suite.mockEventTypeRepository.On("GetByID", mock.Anything, 1, 4).Return(model.EventType{ID: 4, UserID: 1, Name: "Intro call",
+		Location: "https://meet.example.xyz/intro"}, nil).Maybe()
+	suite.mockNotifier = &MockNotifier{}
+	suite.now = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.start = time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	suite.service = NewNotification(suite.mockNotificationRepository, suite.mockEventRepository, suite.mockUserRepository, suite.mockEventTypeRepository, suite.mockNotifier,
+		netmail.Address{Name: "calendly", Address: "noreply@example.xyz"})
+	suite.service.now = func() time.Time { return suite.now }
+	suite.ctx = context.Background()
+}
+
+func (suite *NotificationTestSuite) notification(kind, recipient string, attempts int) model.Notification {
+	return model.Notification{
+		ID: 7, EventID: 3, Kind: kind, Recipient: recipient, EventStartTime: suite.start, SendAt: suite.now, Attempts: attempts,
+		Event: model.Event{ID: 3, UserID: 1, EventTypeID: 4, InviteeName: "test", InviteeEmail: "test@example.xyz", StartTime: suite.start,
+			EndTime: suite.start.Add(30 * time.Minute)},
+	}
+}
+
+// send makes the notifier accept every message and returns the messages sent.
+func (suite *NotificationTestSuite) send() *[]mail.Message {
+	sent := make([]mail.Message, 0)
+	suite.mockNotifier.On("Send", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		sent = append(sent, args.Get(1).(mail.Message))
+	}).Return(nil)
+	return &sent
+}
+
+func (suite *NotificationTestSuite) TestSendDueSendsConfirmationWithInvitation() {
+	obj := suite.notification(model.NotificationConfirmation, model.RecipientInvitee, 0)
+	suite.mockNotificationRepository.On("GetDue", suite.ctx, suite.now, notificationBatchSize).Return([]model.Notification{obj}, nil)
+	sent := suite.send()
+	suite.mockNotificationRepository.On("Update", suite.ctx, mock.MatchedBy(func(updated model.Notification) bool {
+		return updated.ID == 7 && updated.Status == model.DeliverySucceeded && updated.Attempts == 1 && updated.SentAt.Equal(suite.now)
+	})).Return(nil)
+
+	count, err := suite.service.SendDue(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(1, count)
+	suite.Len(*sent, 1)
+	msg := (*sent)[0]
+	suite.Equal([]netmail.Address{{Name: "test", Address: "test@example.xyz"}}, msg.To)
+	suite.Equal("noreply@example.xyz", msg.From.Address)
+	// Times are shown in the host's time zone
+	suite.Equal("Confirmed: Intro call with host on Monday, 5 June 2023 14:30", msg.Subject)
+	suite.Contains(msg.Body, "When: Monday, 5 June 2023 14:30 - 15:00 (Asia/Kolkata)\n")
+	suite.Contains(msg.Body, "Where: https://meet.example.xyz/intro\n")
+	suite.Len(msg.Attachments, 1)
+	suite.Equal("text/calendar; charset=utf-8; method=REQUEST", msg.Attachments[0].ContentType)
+	suite.Contains(string(msg.Attachments[0].Data), "METHOD:REQUEST\r\n")
+	suite.Contains(string(msg.Attachments[0].Data), "UID:event-3")
+	suite.Contains(string(msg.Attachments[0].Data), "DTSTAMP:20230601T000000Z\r\nSEQUENCE:0\r\n")
+	suite.mockNotificationRepository.AssertExpectations(suite.T())
+}
+
+func (suite *NotificationTestSuite) TestSendDueTellsHostAboutNewBooking() {
+	obj := suite.notification(model.NotificationConfirmation, model.RecipientHost, 0)
+	suite.mockNotificationRepository.On("GetDue", suite.ctx, suite.now, notificationBatchSize).Return([]model.Notification{obj}, nil)
+	sent := suite.send()
+	suite.mockNotificationRepository.On("Update", suite.ctx, mock.Anything).Return(nil)
+
+	_, err := suite.service.SendDue(suite.ctx)
+	suite.NoError(err)
+	suite.Len(*sent, 1)
+	suite.Equal([]netmail.Address{{Name: "host", Address: "host@example.xyz"}}, (*sent)[0].To)
+	suite.Equal("New booking: Intro call with test on Monday, 5 June 2023 14:30", (*sent)[0].Subject)
+	suite.Contains((*sent)[0].Body, "test booked a meeting with you.")
+}
+
+func (suite *NotificationTestSuite) TestSendDueSendsCancellationWithCancelledInvitation() {
+	obj := suite.notification(model.NotificationCancellation, model.RecipientInvitee, 0)
+	obj.Event.Status, obj.Event.CancelReason = model.EventCancelled, "something came up"
+	suite.mockNotificationRepository.On("GetDue", suite.ctx, suite.now, notificationBatchSize).Return([]model.Notification{obj}, nil)
+	sent := suite.send()
+	suite.mockNotificationRepository.On("Update", suite.ctx, mock.Anything).Return(nil)
+
+	_, err := suite.service.SendDue(suite.ctx)
+	suite.NoError(err)
+	suite.Len(*sent, 1)
+	suite.Contains((*sent)[0].Body, "Reason: something came up\n")
+	suite.Equal("text/calendar; charset=utf-8; method=CANCEL", (*sent)[0].Attachments[0].ContentType)
+	suite.Contains(string((*sent)[0].Attachments[0].Data), "METHOD:CANCEL\r\n")
+	suite.Contains(string((*sent)[0].Attachments[0].Data), "STATUS:CANCELLED\r\n")
+}
+
+func (suite *NotificationTestSuite) TestSendDueSendsRescheduleAsLaterRevisionOfInvitation() {
+	obj := suite.notification(model.NotificationReschedule, model.RecipientInvitee, 0)
+	suite.mockEventRepository.ExpectedCalls = nil
+	suite.mockEventRepository.On("GetChanges", suite.ctx, 3).Return([]model.EventChange{
+		{ID: 1, EventID: 3, Action: model.ChangeRescheduled}, {ID: 2, EventID: 3, Action: model.ChangeRescheduled},
+	}, nil)
+	suite.mockNotificationRepository.On("GetDue", suite.ctx, suite.now, notificationBatchSize).Return([]model.Notification{obj}, nil)
+	sent := suite.send()
+	suite.mockNotificationRepository.On("Update", suite.ctx, mock.Anything).Return(nil)
+
+	_, err := suite.service.SendDue(suite.ctx)
+	suite.NoError(err)
+	suite.Len(*sent, 1)
+	suite.Contains(string((*sent)[0].Attachments[0].Data), "METHOD:REQUEST\r\n")
+	suite.Contains(string((*sent)[0].Attachments[0].Data), "DTSTAMP:20230601T000000Z\r\nSEQUENCE:2\r\n")
+}
+
+func (suite *NotificationTestSuite) TestSendDueSendsRemindersWithoutInvitation() {
+	obj := suite.notification(model.NotificationReminder, model.RecipientInvitee, 0)
+	suite.mockNotificationRepository.On("GetDue", suite.ctx, suite.now, notificationBatchSize).Return([]model.Notification{obj}, nil)
+	sent := suite.send()
+	suite.mockNotificationRepository.On("Update", suite.ctx, mock.Anything).Return(nil)
+
+	count, err := suite.service.SendDue(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(1, count)
+	suite.Equal("Reminder: Intro call with host on Monday, 5 June 2023 14:30", (*sent)[0].Subject)
+	suite.Empty((*sent)[0].Attachments)
+}
+
+func (suite *NotificationTestSuite) TestSendDueSkipsRemindersOfCancelledAndRescheduledEvents() {
+	cancelled := suite.notification(model.NotificationReminder, model.RecipientInvitee, 0)
+	cancelled.Event.Status = model.EventCancelled
+	rescheduled := suite.notification(model.NotificationReminder, model.RecipientHost, 0)
+	rescheduled.ID = 8
+	rescheduled.Event.StartTime = suite.start.Add(24 * time.Hour)
+	suite.mockNotificationRepository.On("GetDue", suite.ctx, suite.now, notificationBatchSize).Return([]model.Notification{cancelled, rescheduled}, nil)
+	suite.mockNotificationRepository.On("Update", suite.ctx, mock.MatchedBy(func(updated model.Notification) bool {
+		return updated.Status == model.DeliverySkipped && updated.Attempts == 0
+	})).Return(nil).Twice()
+
+	count, err := suite.service.SendDue(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(0, count)
+	suite.mockNotifier.AssertNotCalled(suite.T(), "Send", mock.Anything, mock.Anything)
+	suite.mockNotificationRepository.AssertExpectations(suite.T())
+}
+
+func (suite *NotificationTestSuite) TestSendDueRetriesFailedNotificationsWithBackoff() {
+	obj := suite.notification(model.NotificationConfirmation, model.RecipientInvitee, 2)
+	suite.mockNotificationRepository.On("GetDue", suite.ctx, suite.now, notificationBatchSize).Return([]model.Notification{obj}, nil)
+	suite.mockNotifier.On("Send", suite.ctx, mock.Anything).Return(errors.New("connection refused"))
+	suite.mockNotificationRepository.On("Update", suite.ctx, mock.MatchedBy(func(updated model.Notification) bool {
+		return updated.Status == model.DeliveryPending && updated.Attempts == 3 && updated.Error == "connection refused" &&
+			updated.SendAt.Equal(suite.now.Add(4*time.Minute))
+	})).Return(nil)
+
+	count, err := suite.service.SendDue(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(0, count)
+	suite.mockNotificationRepository.AssertExpectations(suite.T())
+}
+
+func (suite *NotificationTestSuite) TestSendDueGivesUpAfterMaxAttempts() {
+	obj := suite.notification(model.NotificationConfirmation, model.RecipientInvitee, maxNotificationAttempts-1)
+	suite.mockNotificationRepository.On("GetDue", suite.ctx, suite.now, notificationBatchSize).Return([]model.Notification{obj}, nil)
+	suite.mockNotifier.On("Send", suite.ctx, mock.Anything).Return(errors.New("connection refused"))
+	suite.mockNotificationRepository.On("Update", suite.ctx, mock.MatchedBy(func(updated model.Notification) bool {
+		return updated.Status == model.DeliveryFailed && updated.Attempts == maxNotificationAttempts
+	})).Return(nil)
+
+	_, err := suite.service.SendDue(suite.ctx)
+	suite.NoError(err)
+	suite.mockNotificationRepository.AssertExpectations(suite.T())
+}
+
+func (suite *NotificationTestSuite) TestSendDueReturnsErrorIfDueNotificationsCannotBeFetched() {
+	suite.mockNotificationRepository.On("GetDue", suite.ctx, suite.now, notificationBatchSize).Return([]model.Notification(nil), errors.New("some error"))
+
+	count, err := suite.service.SendDue(suite.ctx)
+	suite.Error(err)
+	suite.Equal(0, count)
+}
+
+func (suite *NotificationTestSuite) TestEnqueueQueuesNoRemindersOfCancellationsNorPastReminders() {
+	queued := make([][]model.Notification, 0)
+	suite.mockNotificationRepository.On("Create", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		queued = append(queued, args.Get(1).([]model.Notification))
+	}).Return(nil)
+	notifications := newNotifications(suite.mockNotificationRepository, []time.Duration{24 * time.Hour, time.Hour})
+	notifications.now = func() time.Time { return suite.now }
+
+	cancelled := model.Event{ID: 3, StartTime: suite.start, Status: model.EventCancelled}
+	suite.NoError(notifications.enqueue(suite.ctx, cancelled, model.NotificationCancellation))
+	soon := model.Event{ID: 4, StartTime: suite.now.Add(2 * time.Hour)}
+	suite.NoError(notifications.enqueue(suite.ctx, soon, model.NotificationReschedule))
+
+	suite.Len(queued, 2)
+	suite.Equal([]model.Notification{
+		{EventID: 3, Kind: model.NotificationCancellation, Recipient: model.RecipientInvitee, EventStartTime: suite.start, SendAt: suite.now},
+		{EventID: 3, Kind: model.NotificationCancellation, Recipient: model.RecipientHost, EventStartTime: suite.start, SendAt: suite.now},
+	}, queued[0])
+	suite.Equal([]model.Notification{
+		{EventID: 4, Kind: model.NotificationReschedule, Recipient: model.RecipientInvitee, EventStartTime: soon.StartTime, SendAt: suite.now},
+		{EventID: 4, Kind: model.NotificationReminder, Recipient: model.RecipientInvitee, EventStartTime: soon.StartTime, SendAt: suite.now.Add(time.Hour)},
+		{EventID: 4, Kind: model.NotificationReschedule, Recipient: model.RecipientHost, EventStartTime: soon.StartTime, SendAt: suite.now},
+		{EventID: 4, Kind: model.NotificationReminder, Recipient: model.RecipientHost, EventStartTime: soon.StartTime, SendAt: suite.now.Add(time.Hour)},
+	}, queued[1])
+}
+
+func TestNotificationTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationTestSuite))
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// PublicPage serves the public booking pages of users, found by their slug. Invitees only get to see the host's
+// name, their event types and the times which can be booked, never the IDs behind them.
+type PublicPage struct {
+	userRepository      UserRepository
+	eventTypeRepository EventTypeRepository
+	slot                Slot
+	event               Event
+}
+
+func (page PublicPage) Get(ctx context.Context, slug string) (contract.PublicPage, error) {
+	host, err := page.host(ctx, slug)
+	if err != nil {
+		return contract.PublicPage{}, err
+	}
+	eventTypes, err := page.eventTypeRepository.GetAll(ctx, int(host.ID))
+	if err != nil {
+		return contract.PublicPage{}, err
+	}
+
+	resp := contract.PublicPage{HostName: host.Name, TimeZone: host.TimeZone, EventTypes: make([]contract.PublicEventType, 0)}
+	for _, obj := range eventTypes {
+		resp.EventTypes = append(resp.EventTypes, contract.PublicEventType{
+			Name:         obj.Name,
+			Slug:         obj.Slug,
+			DurationMins: obj.DurationMins,
+			Description:  obj.Description,
+			Location:     obj.Location,
+		})
+	}
+	return resp, nil
+}
+
+// GetSlots returns the free slots of the event type between from and to, the next 14 days without them.
+func (page PublicPage) GetSlots(ctx context.Context, slug, eventTypeSlug string, from, to time.Time, loc *time.Location) (contract.PublicSlotList, error) {
+	host, eventType, err := page.eventType(ctx, slug, eventTypeSlug)
+	if err != nil {
+		return contract.PublicSlotList{}, err
+	}
+
+	slots, err := page.slot.GetAll(ctx, int(host.ID), int(eventType.ID), from, to, loc)
+	if err != nil {
+		return contract.PublicSlotList{}, maskMissingPage(err)
+	}
+
+	resp := make([]contract.PublicSlot, 0)
+	for _, s := range slots.Slots {
+		resp = append(resp, contract.PublicSlot{StartTime: s.StartTime, EndTime: s.EndTime})
+	}
+	return contract.PublicSlotList{Slots: resp}, nil
+}
+
+// Book books the free slot of the event type starting at the given time, and returns the booking as the invitee
+// sees it along with its management token.
+func (page PublicPage) Book(ctx context.Context, slug string, input contract.PublicBooking) (contract.Booking, error) {
+	host, eventType, err := page.eventType(ctx, slug, input.EventTypeSlug)
+	if err != nil {
+		return contract.Booking{}, err
+	}
+
+	eventObj, err := page.event.create(ctx, int(host.ID), contract.Event{
+		StartTime:    input.StartTime,
+		EventTypeID:  int(eventType.ID),
+		InviteeEmail: input.InviteeEmail,
+		InviteeName:  input.InviteeName,
+		InviteeNotes: input.InviteeNotes,
+	})
+	if err != nil {
+		return contract.Booking{}, maskMissingPage(err)
+	}
+
+	return page.event.toBooking(ctx, eventObj, nil)
+}
+
+func (page PublicPage) host(ctx context.Context, slug string) (model.User, error) {
+	host, err := page.userRepository.GetBySlug(ctx, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, model.NotFound("page", err)
+	}
+	return host, err
+}
+
+func (page PublicPage) eventType(ctx context.Context, slug, eventTypeSlug string) (model.User, model.EventType, error) {
+	host, err := page.host(ctx, slug)
+	if err != nil {
+		return model.User{}, model.EventType{}, err
+	}
+	eventType, err := page.eventTypeRepository.GetBySlug(ctx, int(host.ID), eventTypeSlug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.User{}, model.EventType{}, model.NotFound("event type", err)
+	}
+	if err != nil {
+		return model.User{}, model.EventType{}, err
+	}
+	return host, eventType, nil
+}
+
+// maskMissingPage hides which record was missing, such as the host's availability, since the messages carry IDs
+// invitees should not see.
+func maskMissingPage(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("page", sql.ErrNoRows)
+	}
+	return err
+}
+
+func NewPublicPage(userRepository UserRepository, eventTypeRepository EventTypeRepository, slot Slot, event Event) PublicPage {
+	return PublicPage{userRepository: userRepository, eventTypeRepository: eventTypeRepository, slot: slot, event: event}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/datatypes"
+)
+
+type PublicPageTestSuite struct {
+	suite.Suite
+	service                    PublicPage
+	mockUserRepository         *MockUserRepository
+	mockEventTypeRepository    *MockEventTypeRepository
+	mockAvailabilityRepository *MockUserAvailabilityRepository
+	mockOverrideRepository     *MockAvailabilityOverrideRepository
+	mockEventRepository        *MockEventRepository
+	now                        time.Time
+	start                      time.Time
+	ctx                        context.Context
+}
+
+func (suite *PublicPageTestSuite) SetupTest() {
+	suite.mockUserRepository = &MockUserRepository{}
+	suite.mockEventTypeRepository = &MockEventTypeRepository{}
+	suite.mockAvailabilityRepository = &MockUserAvailabilityRepository{}
+	suite.mockOverrideRepository = &MockAvailabilityOverrideRepository{}
+	suite.mockEventRepository = &MockEventRepository{}
+	busyBlockRepository := &MockBusyBlockRepository{}
+	busyBlockRepository.On("GetInRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.BusyBlock{}, nil).Maybe()
+	suite.now = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.start = time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC) // monday
+
+	slot := NewSlot(&MockSlotRepository{}, suite.mockAvailabilityRepository, suite.mockOverrideRepository, suite.mockEventTypeRepository,
+		suite.mockEventRepository, busyBlockRepository)
+	slot.now = func() time.Time { return suite.now }
+	event := NewEvent(suite.mockEventRepository, &MockSlotRepository{}, suite.mockAvailabilityRepository, suite.mockOverrideRepository,
+		suite.mockEventTypeRepository, suite.mockUserRepository, busyBlockRepository, NewBookingTokens([]byte("secret")))
+	event.now = func() time.Time { return suite.now }
+	suite.service = NewPublicPage(suite.mockUserRepository, suite.mockEventTypeRepository, slot, event)
+	suite.ctx = context.Background()
+
+	suite.mockUserRepository.On("GetBySlug", suite.ctx, "jane").Return(model.User{ID: 1, Name: "Jane", Email: "jane@example.xyz", TimeZone: "UTC",
+		Slug: "jane"}, nil).Maybe()
+	suite.mockUserRepository.On("GetByID", suite.ctx, 1).Return(model.User{ID: 1, Name: "Jane", Email: "jane@example.xyz", TimeZone: "UTC"}, nil).Maybe()
+	suite.mockUserRepository.On("GetBySlug", suite.ctx, mock.Anything).Return(model.User{}, sql.ErrNoRows).Maybe()
+	suite.mockEventTypeRepository.On("GetBySlug", suite.ctx, 1, "intro").Return(suite.intro(), nil).Maybe()
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 2).Return(suite.intro(), nil).Maybe()
+	suite.mockEventTypeRepository.On("GetBySlug", suite.ctx, 1, mock.Anything).Return(model.EventType{}, sql.ErrNoRows).Maybe()
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID:              1,
+		Availability:        []model.DayAvailability{{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(10, 0, 0, 0)}},
+		MeetingDurationMins: 60,
+	}, nil).Maybe()
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil).Maybe()
+}
+
+func (suite *PublicPageTestSuite) intro() model.EventType {
+	return model.EventType{ID: 2, UserID: 1, Name: "Intro call", Slug: "intro", DurationMins: 30, Location: "Zoom"}
+}
+
+func (suite *PublicPageTestSuite) TestGetOnlyExposesHostNameAndEventTypes() {
+	suite.mockEventTypeRepository.On("GetAll", suite.ctx, 1).Return([]model.EventType{suite.intro()}, nil)
+
+	resp, err := suite.service.Get(suite.ctx, "jane")
+	suite.NoError(err)
+	suite.Equal(contract.PublicPage{HostName: "Jane", TimeZone: "UTC", EventTypes: []contract.PublicEventType{
+		{Name: "Intro call", Slug: "intro", DurationMins: 30, Location: "Zoom"},
+	}}, resp)
+}
+
+func (suite *PublicPageTestSuite) TestGetReturnsNotFoundForUnknownSlug() {
+	_, err := suite.service.Get(suite.ctx, "john")
+	suite.ErrorIs(err, sql.ErrNoRows)
+}
+
+func (suite *PublicPageTestSuite) TestGetSlotsReturnsFreeTimesOfEventType() {
+	end := suite.start.Add(time.Hour)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, suite.start, end).Return([]model.Event{
+		{UserID: 1, StartTime: suite.start, EndTime: suite.start.Add(30 * time.Minute)},
+	}, nil)
+
+	resp, err := suite.service.GetSlots(suite.ctx, "jane", "intro", suite.start, end, nil)
+	suite.NoError(err)
+	suite.Equal(contract.PublicSlotList{Slots: []contract.PublicSlot{
+		{StartTime: suite.start.Add(30 * time.Minute), EndTime: end},
+	}}, resp)
+}
+
+func (suite *PublicPageTestSuite) TestGetSlotsReturnsNotFoundForUnknownEventType() {
+	_, err := suite.service.GetSlots(suite.ctx, "jane", "demo", suite.start, suite.start.Add(time.Hour), nil)
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.ErrorContains(err, "event type not found")
+}
+
+func (suite *PublicPageTestSuite) TestGetSlotsHidesWhichRecordIsMissing() {
+	suite.mockAvailabilityRepository.ExpectedCalls = nil
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{}, sql.ErrNoRows)
+
+	_, err := suite.service.GetSlots(suite.ctx, "jane", "intro", suite.start, suite.start.Add(time.Hour), nil)
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.EqualError(err, "page not found: sql: no rows in result set")
+}
+
+func (suite *PublicPageTestSuite) TestBookBooksFreeSlotOfEventType() {
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, suite.start, suite.start.Add(30*time.Minute)).Return([]model.Event{}, nil)
+	booked := model.Event{ID: 3, UserID: 1, SlotID: 7, EventTypeID: 2, InviteeName: "test", InviteeEmail: "test@example.xyz", StartTime: suite.start,
+		EndTime: suite.start.Add(30 * time.Minute)}
+	suite.mockEventRepository.On("BookTime", suite.ctx,
+		model.Event{UserID: 1, InviteeName: "test", InviteeEmail: "test@example.xyz"},
+		model.Slot{UserID: 1, EventTypeID: 2, StartTime: suite.start, EndTime: suite.start.Add(30 * time.Minute), Capacity: 1},
+		model.BookingLimits{},
+	).Return(booked, nil)
+
+	resp, err := suite.service.Book(suite.ctx, "jane", contract.PublicBooking{EventTypeSlug: "intro", StartTime: suite.start, InviteeName: "test",
+		InviteeEmail: "test@example.xyz"})
+	suite.NoError(err)
+	suite.Equal("Jane", resp.HostName)
+	suite.Equal("Intro call", resp.EventTypeName)
+	suite.Equal(suite.start, resp.StartTime)
+	suite.Equal(suite.service.event.tokens.sign(booked), resp.ManagementToken)
+	suite.Len(suite.mockEventRepository.Jobs, 3)
+}
+
+func (suite *PublicPageTestSuite) TestBookReturnsErrorIfTimeIsNotFree() {
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, suite.start, suite.start.Add(30*time.Minute)).Return([]model.Event{
+		{UserID: 1, StartTime: suite.start, EndTime: suite.start.Add(30 * time.Minute)},
+	}, nil)
+
+	_, err := suite.service.Book(suite.ctx, "jane", contract.PublicBooking{EventTypeSlug: "intro", StartTime: suite.start, InviteeName: "test",
+		InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "BookTime", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestPublicPageTestSuite(t *testing.T) {
+	suite.Run(t, new(PublicPageTestSuite))
+}
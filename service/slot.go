@@ -2,7 +2,8 @@ package service
 
 import (
 	"context"
-	"errors"
+	"log"
+	"sort"
 	"time"
 
 	"github.com/harbor-xyz/coding-project/contract"
@@ -12,79 +13,149 @@ import (
 type Slot struct {
 	slotRepository         SlotRepository
 	availabilityRepository UserAvailabilityRepository
+	overrideRepository     AvailabilityOverrideRepository
+	eventTypeRepository    EventTypeRepository
+	calendar               calendar
+	now                    func() time.Time
 }
 
-func (slot Slot) Create(ctx context.Context, userID, numDays int) (int, error) {
-	now := time.Now()
-	// Check if slots already exist in the given time period
+// Create generates slots for the given number of days. When eventTypeID is set, the slots are generated with the
+// event type's duration and availability, otherwise with the user's meeting duration.
+func (slot Slot) Create(ctx context.Context, userID, eventTypeID, numDays int) (int, error) {
+	now := slot.now()
+	// Check if slots of this event type already exist in the given time period
 	slots, err := slot.slotRepository.Get(ctx, userID, now, now.AddDate(0, 0, numDays))
 	if err != nil {
 		return -1, err
 	}
 
-	if len(slots) > 0 {
-		return -1, errors.New("slots already exist")
+	if len(filterByEventType(slots, eventTypeID)) > 0 {
+		return -1, model.ErrSlotsExist
 	}
 
-	// Get availability for the user
-	availability, err := slot.availabilityRepository.Get(ctx, userID)
+	// Get availability for the user, adjusted for the event type
+	availability, eventType, err := availabilityForEventType(ctx, slot.availabilityRepository, slot.eventTypeRepository, userID, eventTypeID)
 	if err != nil {
 		return -1, err
 	}
-	availabilityMap := availability.GetAvailabilityMap()
-	meetingDuration := availability.MeetingDurationMins
-
-	// Prepare slots based on days and hours of availability and meeting duration
-	slots = make([]model.Slot, 0)
-	for i := 0; i < numDays; i++ {
-		t := now.AddDate(0, 0, i)
-		day := model.GetDayFromInt(int(t.Weekday()))
-		// If availability exists for this day
-		if availability, exists := availabilityMap[day]; exists {
-			s, _ := time.Parse("15:04:05", availability.StartTime.String())
-			e, _ := time.Parse("15:04:05", availability.EndTime.String())
-
-			startTime := time.Date(t.Year(), t.Month(), t.Day(), s.Hour(), s.Minute(), s.Second(), s.Nanosecond(), t.Location())
-			endTime := time.Date(t.Year(), t.Month(), t.Day(), e.Hour(), e.Minute(), e.Second(), e.Nanosecond(), t.Location())
-			for startTime.Before(endTime) {
-				end := startTime.Add(time.Minute * time.Duration(meetingDuration))
-				slots = append(slots, model.Slot{
-					UserID:    uint(userID),
-					StartTime: startTime,
-					EndTime:   end,
-					Status:    model.StatusCreated,
-				})
-				startTime = end
-			}
-		}
+	slots, err = slot.generate(ctx, availability, eventType, numDays)
+	if err != nil {
+		return -1, err
 	}
+
 	// Insert slots
 	err = slot.slotRepository.Create(ctx, slots)
 	if err != nil {
-		return -1, nil
+		return -1, err
 	}
 	return len(slots), nil
 }
 
-func (slot Slot) GetAll(ctx context.Context, userID int) (contract.SlotList, error) {
-	slots, err := slot.slotRepository.Get(ctx, userID, time.Now(), time.Now().AddDate(0, 0, 14))
+// generate prepares the slots of the given availability for numDays days starting today, without saving them. The
+// slots belong to the event type, which is empty for slots generated without one.
+func (slot Slot) generate(ctx context.Context, availability model.UserAvailability, eventType model.EventType, numDays int) ([]model.Slot, error) {
+	loc, err := availability.Location()
 	if err != nil {
-		return contract.SlotList{}, err
+		return nil, err
+	}
+
+	// Days are walked in the user's time zone so that the weekly template maps to the right instants
+	today := slot.now().In(loc)
+	from := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, loc)
+	to := time.Date(today.Year(), today.Month(), today.Day()+numDays, 0, 0, 0, 0, loc)
+	overrides, err := slot.overrideRepository.GetInRange(ctx, int(availability.UserID), from, to)
+	if err != nil {
+		return nil, err
+	}
+	intervals, err := availableIntervals(availability, overrides, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Prepare slots based on the available intervals, meeting duration and slot increment
+	slots := make([]model.Slot, 0)
+	for _, available := range intervals {
+		for _, s := range slotsIn(available, time.Duration(availability.MeetingDurationMins)*time.Minute, availability.SlotIncrement()) {
+			slots = append(slots, model.Slot{
+				UserID:      availability.UserID,
+				EventTypeID: eventType.ID,
+				StartTime:   s.start,
+				EndTime:     s.end,
+				Status:      model.StatusCreated,
+				Capacity:    eventType.Seats(),
+			})
+		}
+	}
+	return slots, nil
+}
+
+// GetAll computes the free slots of the user between from and to from their availability, overrides and existing
+// bookings. The next 14 days are used when no range is given. A non zero eventTypeID computes the slots with the
+// event type's duration, availability and scheduling rules. Only the slots which can be booked right now, as per the
+// notice, horizon and caps of the scheduling rules, are returned. Slots of a group event type which some invitees have
+// booked already are returned along with the free ones for as long as they have seats left.
+func (slot Slot) GetAll(ctx context.Context, userID, eventTypeID int, from, to time.Time, loc *time.Location) (contract.SlotList, error) {
+	now := slot.now()
+	if from.IsZero() {
+		from, to = now, now.AddDate(0, 0, 14)
+	}
+	if from.Before(now) {
+		from = now
 	}
 
 	resp := make([]contract.Slot, 0)
+	if !from.Before(to) {
+		return contract.SlotList{Slots: resp}, nil
+	}
+
+	availability, eventType, err := availabilityForEventType(ctx, slot.availabilityRepository, slot.eventTypeRepository, userID, eventTypeID)
+	if err != nil {
+		return contract.SlotList{}, err
+	}
+
+	from, to = bookableRange(availability.SchedulingRules, now, time.Duration(availability.MeetingDurationMins)*time.Minute, from, to)
+	if !from.Before(to) {
+		return contract.SlotList{Slots: resp}, nil
+	}
+
+	slots, err := slot.calendar.slots(ctx, availability, from, to, nil)
+	if err != nil {
+		return contract.SlotList{}, err
+	}
+	slots, err = slot.calendar.withinCaps(ctx, availability, slots, nil)
+	if err != nil {
+		return contract.SlotList{}, err
+	}
+
+	sessions, err := slot.calendar.openSessions(ctx, userID, eventType, from, to, nil)
+	if err != nil {
+		return contract.SlotList{}, err
+	}
+
 	for _, s := range slots {
-		if s.EndTime.Before(time.Now()) {
-			s.Status = model.StatusExpired
-		}
 		resp = append(resp, contract.Slot{
-			ID:        int(s.ID),
-			UserID:    s.UserID,
-			StartTime: s.StartTime,
-			EndTime:   s.EndTime,
-			Status:    s.Status.String(),
+			UserID:         uint(userID),
+			EventTypeID:    uint(eventTypeID),
+			StartTime:      inLocation(s.start, loc),
+			EndTime:        inLocation(s.end, loc),
+			Status:         model.StatusCreated.String(),
+			RemainingSeats: eventType.Seats(),
+		})
+	}
+	for _, s := range sessions {
+		resp = append(resp, contract.Slot{
+			ID:             int(s.slotID),
+			UserID:         uint(userID),
+			EventTypeID:    uint(eventTypeID),
+			StartTime:      inLocation(s.start, loc),
+			EndTime:        inLocation(s.end, loc),
+			Status:         model.StatusCreated.String(),
+			RemainingSeats: s.remaining,
 		})
 	}
+	sort.SliceStable(resp, func(i, j int) bool {
+		return resp[i].StartTime.Before(resp[j].StartTime)
+	})
 
 	return contract.SlotList{Slots: resp}, nil
 }
@@ -94,6 +165,124 @@ func (slot Slot) DeleteByID(ctx context.Context, slotID int) error {
 
 }
 
-func NewSlot(slotRepository SlotRepository, availabilityRepository UserAvailabilityRepository) Slot {
-	return Slot{slotRepository: slotRepository, availabilityRepository: availabilityRepository}
+// Expire marks the slots which are over without having been booked as expired.
+func (slot Slot) Expire(ctx context.Context) (int64, error) {
+	return slot.slotRepository.Expire(ctx, slot.now())
+}
+
+// Sync keeps the slots of every user generated for the next horizonDays days, for their default meeting duration
+// and for each of their event types. The unbooked slots of users whose availability changed since the last sync
+// are generated again. It returns the number of slots created.
+func (slot Slot) Sync(ctx context.Context, horizonDays int) (int, error) {
+	availabilities, err := slot.availabilityRepository.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, availability := range availabilities {
+		n, err := slot.syncUser(ctx, availability, horizonDays)
+		if err != nil {
+			// A single user's failure should not hold back the others
+			log.Printf("error occurred while syncing slots for user %d: %s", availability.UserID, err.Error())
+			continue
+		}
+		created += n
+	}
+	return created, nil
+}
+
+func (slot Slot) syncUser(ctx context.Context, availability model.UserAvailability, horizonDays int) (int, error) {
+	userID := int(availability.UserID)
+	now := slot.now()
+	if availability.UpdatedAt.After(availability.SlotsSyncedAt) {
+		if err := slot.slotRepository.DeleteUnbooked(ctx, userID, now); err != nil {
+			return 0, err
+		}
+	}
+
+	existing, err := slot.slotRepository.Get(ctx, userID, now, now.AddDate(0, 0, horizonDays+1))
+	if err != nil {
+		return 0, err
+	}
+	eventTypes, err := slot.eventTypeRepository.GetAll(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	variants := []model.UserAvailability{availability}
+	eventTypes = append([]model.EventType{{}}, eventTypes...)
+	for _, eventType := range eventTypes[1:] {
+		variants = append(variants, eventType.ApplyTo(availability))
+	}
+
+	slots := make([]model.Slot, 0)
+	for i, variant := range variants {
+		generated, err := slot.generate(ctx, variant, eventTypes[i], horizonDays)
+		if err != nil {
+			return 0, err
+		}
+		for _, s := range generated {
+			if s.StartTime.Before(now) || clashes(s, existing) {
+				continue
+			}
+			slots = append(slots, s)
+		}
+	}
+
+	if len(slots) > 0 {
+		if err := slot.slotRepository.Create(ctx, slots); err != nil {
+			return 0, err
+		}
+	}
+	if err := slot.availabilityRepository.MarkSlotsSynced(ctx, userID, now); err != nil {
+		return 0, err
+	}
+	return len(slots), nil
+}
+
+// clashes reports whether s overlaps a live slot of the same event type, or a slot of any event type with booked
+// seats.
+func clashes(s model.Slot, existing []model.Slot) bool {
+	candidate := interval{start: s.StartTime, end: s.EndTime}
+	for _, e := range existing {
+		if e.Status == model.StatusDeleted || e.Status == model.StatusExpired {
+			continue
+		}
+		if e.EventTypeID != s.EventTypeID && e.Status != model.StatusBooked && e.BookedSeats == 0 {
+			continue
+		}
+		if candidate.overlaps(interval{start: e.StartTime, end: e.EndTime}) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterByEventType returns the slots belonging to the given event type. Slots generated without an event type have
+// an eventTypeID of 0.
+func filterByEventType(slots []model.Slot, eventTypeID int) []model.Slot {
+	filtered := make([]model.Slot, 0)
+	for _, s := range slots {
+		if s.EventTypeID == uint(eventTypeID) {
+			filtered = append(filtered, s)
+		}
+	}
+	return filtered
+}
+
+func NewSlot(slotRepository SlotRepository, availabilityRepository UserAvailabilityRepository, overrideRepository AvailabilityOverrideRepository, eventTypeRepository EventTypeRepository, eventRepository EventRepository, busyBlockRepository BusyBlockRepository) Slot {
+	return Slot{
+		slotRepository:         slotRepository,
+		availabilityRepository: availabilityRepository,
+		overrideRepository:     overrideRepository,
+		eventTypeRepository:    eventTypeRepository,
+		calendar: calendar{
+			availabilityRepository: availabilityRepository,
+			overrideRepository:     overrideRepository,
+			eventRepository:        eventRepository,
+			busyBlockRepository:    busyBlockRepository,
+		},
+		now: time.Now,
+	}
 }
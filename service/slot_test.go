@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/harbor-xyz/coding-project/model"
 
@@ -15,6 +17,10 @@ type SlotTestSuite struct {
 	suite.Suite
 	mockSlotRepository         *MockSlotRepository
 	mockAvailabilityRepository *MockUserAvailabilityRepository
+	mockOverrideRepository     *MockAvailabilityOverrideRepository
+	mockEventTypeRepository    *MockEventTypeRepository
+	mockEventRepository        *MockEventRepository
+	mockBusyBlockRepository    *MockBusyBlockRepository
 	service                    Slot
 	ctx                        context.Context
 }
@@ -22,12 +28,20 @@ type SlotTestSuite struct {
 func (suite *SlotTestSuite) SetupTest() {
 	suite.mockSlotRepository = &MockSlotRepository{}
 	suite.mockAvailabilityRepository = &MockUserAvailabilityRepository{}
-	suite.service = NewSlot(suite.mockSlotRepository, suite.mockAvailabilityRepository)
+	suite.mockOverrideRepository = &MockAvailabilityOverrideRepository{}
+	suite.mockEventTypeRepository = &MockEventTypeRepository{}
+	suite.mockEventRepository = &MockEventRepository{}
+	suite.mockBusyBlockRepository = &MockBusyBlockRepository{}
+	// Users have no external calendars unless a test says otherwise
+	suite.mockBusyBlockRepository.On("GetInRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.BusyBlock{}, nil).Maybe()
+	suite.service = NewSlot(suite.mockSlotRepository, suite.mockAvailabilityRepository, suite.mockOverrideRepository, suite.mockEventTypeRepository, suite.mockEventRepository,
+		suite.mockBusyBlockRepository)
 	suite.ctx = context.Background()
 }
 
 func (suite *SlotTestSuite) TestCreateHappyFlow() {
 	suite.mockSlotRepository.On("Get", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Slot{}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
 	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
 		UserID: 1,
 		Availability: []model.DayAvailability{
@@ -46,11 +60,518 @@ func (suite *SlotTestSuite) TestCreateHappyFlow() {
 	}, nil)
 	suite.mockSlotRepository.On("Create", suite.ctx, mock.Anything).Return(nil)
 
-	numSlots, err := suite.service.Create(suite.ctx, 1, 14)
+	numSlots, err := suite.service.Create(suite.ctx, 1, 0, 14)
 	suite.Equal(60, numSlots)
 	suite.Nil(err)
 }
 
+func (suite *SlotTestSuite) TestCreateHonoursMultipleWindowsPerDay() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC) } // monday
+	suite.mockSlotRepository.On("Get", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Slot{}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{
+				Day:       "monday",
+				StartTime: datatypes.NewTime(14, 0, 0, 0),
+				EndTime:   datatypes.NewTime(15, 0, 0, 0),
+			},
+			{
+				Day:       "monday",
+				StartTime: datatypes.NewTime(9, 0, 0, 0),
+				EndTime:   datatypes.NewTime(10, 0, 0, 0),
+			},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	var created []model.Slot
+	suite.mockSlotRepository.On("Create", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).([]model.Slot)
+	}).Return(nil)
+
+	numSlots, err := suite.service.Create(suite.ctx, 1, 0, 1)
+	suite.Nil(err)
+	suite.Equal(4, numSlots)
+	suite.Equal(time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC), created[0].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 9, 30, 0, 0, time.UTC), created[1].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 14, 0, 0, 0, time.UTC), created[2].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 14, 30, 0, 0, time.UTC), created[3].StartTime.UTC())
+}
+
+func (suite *SlotTestSuite) TestCreateRespectsAvailabilityOverrides() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC) } // monday
+	suite.mockSlotRepository.On("Get", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Slot{}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{
+				Day:       "monday",
+				StartTime: datatypes.NewTime(9, 0, 0, 0),
+				EndTime:   datatypes.NewTime(10, 0, 0, 0),
+			},
+			{
+				Day:       "tuesday",
+				StartTime: datatypes.NewTime(9, 0, 0, 0),
+				EndTime:   datatypes.NewTime(10, 0, 0, 0),
+			},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{
+		{
+			UserID:      1,
+			StartDate:   datatypes.Date(time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)),
+			EndDate:     datatypes.Date(time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)),
+			Unavailable: true,
+		},
+		{
+			UserID:    1,
+			StartDate: datatypes.Date(time.Date(2023, 6, 10, 0, 0, 0, 0, time.UTC)),
+			EndDate:   datatypes.Date(time.Date(2023, 6, 10, 0, 0, 0, 0, time.UTC)),
+			Availability: []model.Availability{
+				{StartTime: datatypes.NewTime(11, 0, 0, 0), EndTime: datatypes.NewTime(11, 30, 0, 0)},
+			},
+		},
+	}, nil)
+	var created []model.Slot
+	suite.mockSlotRepository.On("Create", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).([]model.Slot)
+	}).Return(nil)
+
+	numSlots, err := suite.service.Create(suite.ctx, 1, 0, 7)
+	suite.Nil(err)
+	// Monday is blacked out, tuesday follows the weekly template and saturday gets a one-off window
+	suite.Equal(3, numSlots)
+	suite.Equal(time.Date(2023, 6, 6, 9, 0, 0, 0, time.UTC), created[0].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 6, 9, 30, 0, 0, time.UTC), created[1].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 10, 11, 0, 0, 0, time.UTC), created[2].StartTime.UTC())
+}
+
+func (suite *SlotTestSuite) TestCreateUsesEventTypeDurationAndAvailability() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC) } // monday
+	suite.mockSlotRepository.On("Get", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Slot{
+		{ID: 1, UserID: 1, StartTime: time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 5, 9, 30, 0, 0, time.UTC)},
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(17, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 2).Return(model.EventType{
+		ID:           2,
+		UserID:       1,
+		DurationMins: 60,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(14, 0, 0, 0), EndTime: datatypes.NewTime(16, 0, 0, 0)},
+		},
+	}, nil)
+	var created []model.Slot
+	suite.mockSlotRepository.On("Create", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).([]model.Slot)
+	}).Return(nil)
+
+	// The existing slot belongs to the default event type so it does not block generation
+	numSlots, err := suite.service.Create(suite.ctx, 1, 2, 1)
+	suite.Nil(err)
+	suite.Equal(2, numSlots)
+	suite.Equal(uint(2), created[0].EventTypeID)
+	suite.Equal(time.Date(2023, 6, 5, 14, 0, 0, 0, time.UTC), created[0].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 15, 0, 0, 0, time.UTC), created[0].EndTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 15, 0, 0, 0, time.UTC), created[1].StartTime.UTC())
+}
+
+func (suite *SlotTestSuite) TestCreateStartsSlotsEveryIncrementWithinWindows() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC) } // monday
+	suite.mockSlotRepository.On("Get", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Slot{}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(10, 30, 0, 0)},
+		},
+		MeetingDurationMins: 60,
+		SlotIncrementMins:   20,
+	}, nil)
+	var created []model.Slot
+	suite.mockSlotRepository.On("Create", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).([]model.Slot)
+	}).Return(nil)
+
+	numSlots, err := suite.service.Create(suite.ctx, 1, 0, 1)
+	suite.Nil(err)
+	// The slot starting at 9:40 would end after the window
+	suite.Equal(2, numSlots)
+	suite.Equal(time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC), created[0].StartTime)
+	suite.Equal(time.Date(2023, 6, 5, 9, 20, 0, 0, time.UTC), created[1].StartTime)
+	suite.Equal(time.Date(2023, 6, 5, 10, 20, 0, 0, time.UTC), created[1].EndTime)
+}
+
+func (suite *SlotTestSuite) TestCreateReturnsErrorIfSlotsOfEventTypeExist() {
+	suite.mockSlotRepository.On("Get", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Slot{
+		{ID: 1, UserID: 1, EventTypeID: 2},
+	}, nil)
+
+	numSlots, err := suite.service.Create(suite.ctx, 1, 2, 1)
+	suite.Equal(-1, numSlots)
+	suite.ErrorIs(err, model.ErrSlotsExist)
+}
+
+func (suite *SlotTestSuite) TestCreateReturnsErrorIfSlotsCannotBeSaved() {
+	suite.mockSlotRepository.On("Get", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Slot{}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{
+				Day:       "monday",
+				StartTime: datatypes.NewTime(10, 0, 0, 0),
+				EndTime:   datatypes.NewTime(17, 0, 0, 0),
+			},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockSlotRepository.On("Create", suite.ctx, mock.Anything).Return(errors.New("some error"))
+
+	numSlots, err := suite.service.Create(suite.ctx, 1, 0, 14)
+	suite.Equal(-1, numSlots)
+	suite.EqualError(err, "some error")
+}
+
+func (suite *SlotTestSuite) TestCreateExpandsAvailabilityInUserTimeZoneAcrossSpringForward() {
+	loc, _ := time.LoadLocation("America/New_York")
+	suite.service.now = func() time.Time { return time.Date(2023, 3, 11, 12, 0, 0, 0, loc) }
+	suite.mockSlotRepository.On("Get", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Slot{}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{
+				Day:       "sunday",
+				StartTime: datatypes.NewTime(1, 0, 0, 0),
+				EndTime:   datatypes.NewTime(4, 0, 0, 0),
+			},
+		},
+		MeetingDurationMins: 30,
+		TimeZone:            "America/New_York",
+	}, nil)
+	var created []model.Slot
+	suite.mockSlotRepository.On("Create", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).([]model.Slot)
+	}).Return(nil)
+
+	numSlots, err := suite.service.Create(suite.ctx, 1, 0, 2)
+	suite.Nil(err)
+	// 01:00 EST to 04:00 EDT is only two hours long on the day clocks jump forward
+	suite.Equal(4, numSlots)
+	suite.Equal(time.Date(2023, 3, 12, 6, 0, 0, 0, time.UTC), created[0].StartTime.UTC())
+	suite.Equal(time.Date(2023, 3, 12, 8, 0, 0, 0, time.UTC), created[3].EndTime.UTC())
+}
+
+func (suite *SlotTestSuite) TestCreateExpandsAvailabilityInUserTimeZoneAcrossFallBack() {
+	loc, _ := time.LoadLocation("America/New_York")
+	suite.service.now = func() time.Time { return time.Date(2023, 11, 5, 0, 0, 0, 0, loc) }
+	suite.mockSlotRepository.On("Get", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Slot{}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{
+				Day:       "sunday",
+				StartTime: datatypes.NewTime(1, 30, 0, 0),
+				EndTime:   datatypes.NewTime(3, 0, 0, 0),
+			},
+		},
+		MeetingDurationMins: 30,
+		TimeZone:            "America/New_York",
+	}, nil)
+	var created []model.Slot
+	suite.mockSlotRepository.On("Create", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).([]model.Slot)
+	}).Return(nil)
+
+	numSlots, err := suite.service.Create(suite.ctx, 1, 0, 1)
+	suite.Nil(err)
+	// 01:30 is ambiguous and resolves to its first (EDT) occurrence, making the window two and a half hours long
+	suite.Equal(5, numSlots)
+	suite.Equal(time.Date(2023, 11, 5, 5, 30, 0, 0, time.UTC), created[0].StartTime.UTC())
+	suite.Equal(time.Date(2023, 11, 5, 8, 0, 0, 0, time.UTC), created[4].EndTime.UTC())
+}
+
+func (suite *SlotTestSuite) TestGetAllComputesFreeSlotsAroundBookings() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC) // monday
+	to := time.Date(2023, 6, 6, 0, 0, 0, 0, time.UTC)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(11, 15, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, from, to).Return([]model.Event{
+		{UserID: 1, StartTime: time.Date(2023, 6, 5, 9, 30, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC)},
+	}, nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, 1, 0, from, to, nil)
+	suite.Nil(err)
+	// The booked slot is left out and the window's last 15 minutes are too short for a slot
+	suite.Len(resp.Slots, 3)
+	suite.Equal(time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC), resp.Slots[0].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC), resp.Slots[1].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 10, 30, 0, 0, time.UTC), resp.Slots[2].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 11, 0, 0, 0, time.UTC), resp.Slots[2].EndTime.UTC())
+	suite.Equal("created", resp.Slots[0].Status)
+}
+
+func (suite *SlotTestSuite) TestGetAllLeavesOutEveryOverlappingSlotOfABooking() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC) // monday
+	to := time.Date(2023, 6, 6, 0, 0, 0, 0, time.UTC)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 60,
+		SlotIncrementMins:   30,
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, from, to).Return([]model.Event{
+		{UserID: 1, StartTime: time.Date(2023, 6, 5, 9, 30, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 5, 10, 30, 0, 0, time.UTC)},
+	}, nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, 1, 0, from, to, nil)
+	suite.Nil(err)
+	// 9:00, 9:30 and 10:00 overlap the booking
+	suite.Len(resp.Slots, 2)
+	suite.Equal(time.Date(2023, 6, 5, 10, 30, 0, 0, time.UTC), resp.Slots[0].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 11, 0, 0, 0, time.UTC), resp.Slots[1].StartTime.UTC())
+}
+
+func (suite *SlotTestSuite) TestGetAllLeavesOutSlotsBlockedByExternalCalendars() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC) // monday
+	to := time.Date(2023, 6, 6, 0, 0, 0, 0, time.UTC)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(11, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, from, to).Return([]model.Event{}, nil)
+	suite.mockBusyBlockRepository.ExpectedCalls = nil
+	suite.mockBusyBlockRepository.On("GetInRange", suite.ctx, 1, from, to).Return([]model.BusyBlock{
+		{UserID: 1, StartTime: time.Date(2023, 6, 5, 9, 15, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC)},
+	}, nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, 1, 0, from, to, nil)
+	suite.Nil(err)
+	suite.Len(resp.Slots, 2)
+	suite.Equal(time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC), resp.Slots[0].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 10, 30, 0, 0, time.UTC), resp.Slots[1].StartTime.UTC())
+}
+
+func (suite *SlotTestSuite) TestGetAllDoesNotReturnPastSlots() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 5, 9, 10, 0, 0, time.UTC) }
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(10, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Event{}, nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, 1, 0, time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC), time.Date(2023, 6, 6, 0, 0, 0, 0, time.UTC), nil)
+	suite.Nil(err)
+	// Slots stay aligned to the window start, so the one already under way is dropped
+	suite.Len(resp.Slots, 1)
+	suite.Equal(time.Date(2023, 6, 5, 9, 30, 0, 0, time.UTC), resp.Slots[0].StartTime.UTC())
+}
+
+func (suite *SlotTestSuite) TestGetAllOnlyReturnsSlotsWithinNoticeAndHorizon() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 4, 9, 0, 0, 0, time.UTC) } // sunday
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "sunday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 60,
+		SchedulingRules:     model.SchedulingRules{MinNoticeMins: 120, MaxHorizonDays: 1},
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Event{}, nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, 1, 0, time.Date(2023, 6, 4, 0, 0, 0, 0, time.UTC), time.Date(2023, 6, 6, 0, 0, 0, 0, time.UTC), nil)
+	suite.Nil(err)
+	// Sunday's slots need 2 hours notice and Monday's can only start up to a day ahead
+	suite.Len(resp.Slots, 2)
+	suite.Equal(time.Date(2023, 6, 4, 11, 0, 0, 0, time.UTC), resp.Slots[0].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC), resp.Slots[1].StartTime.UTC())
+}
+
+func (suite *SlotTestSuite) TestGetAllLeavesOutSlotsWithinBuffersOfBookings() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC) // monday
+	to := time.Date(2023, 6, 6, 0, 0, 0, 0, time.UTC)
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+		SchedulingRules:     model.SchedulingRules{BeforeBufferMins: 15, AfterBufferMins: 30},
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, from.Add(-15*time.Minute), to.Add(30*time.Minute)).Return([]model.Event{
+		{UserID: 1, StartTime: time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 5, 10, 30, 0, 0, time.UTC)},
+	}, nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, 1, 0, from, to, nil)
+	suite.Nil(err)
+	// 9:30 ends within the 30 minutes kept free before the booking, 10:30 starts within the 15 minutes after it
+	suite.Len(resp.Slots, 3)
+	suite.Equal(time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC), resp.Slots[0].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 11, 0, 0, 0, time.UTC), resp.Slots[1].StartTime.UTC())
+	suite.Equal(time.Date(2023, 6, 5, 11, 30, 0, 0, time.UTC), resp.Slots[2].StartTime.UTC())
+}
+
+func (suite *SlotTestSuite) TestGetAllLeavesOutDaysAndWeeksWhereCapsAreReached() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(11, 0, 0, 0)},
+			{Day: "tuesday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(11, 0, 0, 0)},
+			{Day: "wednesday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(11, 0, 0, 0)},
+		},
+		MeetingDurationMins: 60,
+		SchedulingRules:     model.SchedulingRules{MaxPerDay: 1, MaxPerWeek: 2},
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	monday := model.Event{ID: 1, UserID: 1, SlotID: 1, StartTime: time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC)}
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC), time.Date(2023, 6, 7, 0, 0, 0, 0, time.UTC)).
+		Return([]model.Event{monday}, nil)
+	// The caps are counted over the whole week
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC), time.Date(2023, 6, 12, 0, 0, 0, 0, time.UTC)).
+		Return([]model.Event{monday, {ID: 2, UserID: 1, SlotID: 2, StartTime: time.Date(2023, 6, 7, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 7, 10, 0, 0, 0, time.UTC)}}, nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, 1, 0, time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC), time.Date(2023, 6, 7, 0, 0, 0, 0, time.UTC), nil)
+	suite.Nil(err)
+	// Monday has reached its daily cap and Monday's and Wednesday's bookings reach the weekly cap
+	suite.Empty(resp.Slots)
+}
+
+func (suite *SlotTestSuite) TestGetAllKeepsSlotsOfGroupEventTypesOpenUntilFull() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC) // monday
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{
+		UserID: 1,
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+		MeetingDurationMins: 30,
+	}, nil)
+	suite.mockEventTypeRepository.On("GetByID", suite.ctx, 1, 2).Return(model.EventType{ID: 2, UserID: 1, DurationMins: 60, Capacity: 3}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	nine, ten := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC), time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.Event{
+		{ID: 1, UserID: 1, SlotID: 7, EventTypeID: 2, StartTime: nine, EndTime: ten},
+		{ID: 2, UserID: 1, SlotID: 7, EventTypeID: 2, StartTime: nine, EndTime: ten},
+		{ID: 3, UserID: 1, SlotID: 8, EventTypeID: 2, StartTime: ten, EndTime: ten.Add(time.Hour)},
+		{ID: 4, UserID: 1, SlotID: 8, EventTypeID: 2, StartTime: ten, EndTime: ten.Add(time.Hour)},
+		{ID: 5, UserID: 1, SlotID: 8, EventTypeID: 2, StartTime: ten, EndTime: ten.Add(time.Hour)},
+	}, nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, 1, 2, from, from.AddDate(0, 0, 1), nil)
+	suite.Nil(err)
+	// 9:00 has a seat left, 10:00 is full and 11:00 is free
+	suite.Len(resp.Slots, 2)
+	suite.Equal(7, resp.Slots[0].ID)
+	suite.Equal(nine, resp.Slots[0].StartTime.UTC())
+	suite.Equal(1, resp.Slots[0].RemainingSeats)
+	suite.Equal(0, resp.Slots[1].ID)
+	suite.Equal(time.Date(2023, 6, 5, 11, 0, 0, 0, time.UTC), resp.Slots[1].StartTime.UTC())
+	suite.Equal(3, resp.Slots[1].RemainingSeats)
+}
+
+func (suite *SlotTestSuite) TestSyncRegeneratesUnbookedSlotsWhenAvailabilityChanged() {
+	now := time.Date(2023, 6, 5, 9, 45, 0, 0, time.UTC) // monday
+	suite.service.now = func() time.Time { return now }
+	suite.mockAvailabilityRepository.On("GetAll", suite.ctx).Return([]model.UserAvailability{
+		{
+			UserID: 1,
+			Availability: []model.DayAvailability{
+				{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+			},
+			MeetingDurationMins: 30,
+			UpdatedAt:           now.Add(-time.Hour),
+			SlotsSyncedAt:       now.Add(-2 * time.Hour),
+		},
+	}, nil)
+	suite.mockSlotRepository.On("DeleteUnbooked", suite.ctx, 1, now).Return(nil)
+	suite.mockSlotRepository.On("Get", suite.ctx, 1, now, now.AddDate(0, 0, 2)).Return([]model.Slot{
+		{ID: 1, UserID: 1, StartTime: time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 5, 10, 30, 0, 0, time.UTC), Status: model.StatusDeleted},
+		{ID: 2, UserID: 1, StartTime: time.Date(2023, 6, 5, 11, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 5, 11, 30, 0, 0, time.UTC), Status: model.StatusBooked},
+	}, nil)
+	suite.mockEventTypeRepository.On("GetAll", suite.ctx, 1).Return([]model.EventType{{ID: 3, UserID: 1, DurationMins: 60}}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	var created []model.Slot
+	suite.mockSlotRepository.On("Create", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).([]model.Slot)
+	}).Return(nil)
+	suite.mockAvailabilityRepository.On("MarkSlotsSynced", suite.ctx, 1, now).Return(nil)
+
+	numSlots, err := suite.service.Sync(suite.ctx, 1)
+	suite.Nil(err)
+	// Past slots and slots overlapping the booking are skipped, the deleted slot is generated again
+	suite.Equal(4, numSlots)
+	suite.Equal(time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC), created[0].StartTime)
+	suite.Equal(time.Date(2023, 6, 5, 10, 30, 0, 0, time.UTC), created[1].StartTime)
+	suite.Equal(time.Date(2023, 6, 5, 11, 30, 0, 0, time.UTC), created[2].StartTime)
+	suite.Equal(uint(3), created[3].EventTypeID)
+	suite.Equal(time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC), created[3].StartTime)
+	suite.mockAvailabilityRepository.AssertExpectations(suite.T())
+}
+
+func (suite *SlotTestSuite) TestSyncOnlyFillsGapsWhenAvailabilityIsUnchanged() {
+	now := time.Date(2023, 6, 5, 8, 0, 0, 0, time.UTC) // monday
+	suite.service.now = func() time.Time { return now }
+	suite.mockAvailabilityRepository.On("GetAll", suite.ctx).Return([]model.UserAvailability{
+		{
+			UserID: 1,
+			Availability: []model.DayAvailability{
+				{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(10, 0, 0, 0)},
+			},
+			MeetingDurationMins: 30,
+			UpdatedAt:           now.Add(-2 * time.Hour),
+			SlotsSyncedAt:       now.Add(-time.Hour),
+		},
+	}, nil)
+	suite.mockSlotRepository.On("Get", suite.ctx, 1, now, now.AddDate(0, 0, 2)).Return([]model.Slot{
+		{ID: 1, UserID: 1, StartTime: time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 5, 9, 30, 0, 0, time.UTC), Status: model.StatusCreated},
+		{ID: 2, UserID: 1, StartTime: time.Date(2023, 6, 5, 9, 30, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC), Status: model.StatusCreated},
+	}, nil)
+	suite.mockEventTypeRepository.On("GetAll", suite.ctx, 1).Return([]model.EventType{}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockAvailabilityRepository.On("MarkSlotsSynced", suite.ctx, 1, now).Return(nil)
+
+	numSlots, err := suite.service.Sync(suite.ctx, 1)
+	suite.Nil(err)
+	suite.Equal(0, numSlots)
+	suite.mockSlotRepository.AssertNotCalled(suite.T(), "DeleteUnbooked", mock.Anything, mock.Anything, mock.Anything)
+	suite.mockSlotRepository.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
 func TestSlotTestSuite(t *testing.T) {
 	suite.Run(t, new(SlotTestSuite))
 }
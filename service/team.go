@@ -0,0 +1,525 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// Team manages the teams of a user and books their members through the team's event types, either all of them at
+// once (collective) or one of them in turns (round robin).
+type Team struct {
+	teamRepository TeamRepository
+	userRepository UserRepository
+	event          Event
+	now            func() time.Time
+}
+
+// Create creates a team of the owner along with its first members. Members other than the owner are only booked
+// through the team once they accept.
+func (team Team) Create(ctx context.Context, ownerID int, input contract.Team) (contract.TeamResponse, error) {
+	obj := model.Team{OwnerID: uint(ownerID), Name: input.Name}
+	for _, member := range input.Members {
+		if err := team.checkUser(ctx, member.UserID); err != nil {
+			return contract.TeamResponse{}, err
+		}
+		obj.Members = append(obj.Members, model.TeamMember{UserID: uint(member.UserID), Priority: member.Priority,
+			Accepted: member.UserID == ownerID})
+	}
+
+	obj, err := team.teamRepository.Create(ctx, obj)
+	if err != nil {
+		return contract.TeamResponse{}, err
+	}
+
+	return teamToContract(obj), nil
+}
+
+func (team Team) GetAll(ctx context.Context, ownerID int) (contract.TeamList, error) {
+	teams, err := team.teamRepository.GetAll(ctx, ownerID)
+	if err != nil {
+		return contract.TeamList{}, err
+	}
+
+	resp := make([]contract.TeamResponse, 0)
+	for _, obj := range teams {
+		resp = append(resp, teamToContract(obj))
+	}
+
+	return contract.TeamList{Teams: resp}, nil
+}
+
+func (team Team) Get(ctx context.Context, ownerID, teamID int) (contract.TeamResponse, error) {
+	obj, err := team.getTeam(ctx, ownerID, teamID)
+	if err != nil {
+		return contract.TeamResponse{}, err
+	}
+
+	return teamToContract(obj), nil
+}
+
+// Delete deletes a team along with its event types. The bookings made through the team stay with its members.
+func (team Team) Delete(ctx context.Context, ownerID, teamID int) error {
+	err := team.teamRepository.Delete(ctx, ownerID, teamID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("team", err)
+	}
+	return err
+}
+
+// AddMember adds a user to a team, who is only booked through the team once they accept, unless they are its owner.
+// Adding a user who is a member already fails with model.ErrDuplicateMember.
+func (team Team) AddMember(ctx context.Context, ownerID, teamID int, input contract.TeamMember) (contract.TeamMemberResponse, error) {
+	if _, err := team.getTeam(ctx, ownerID, teamID); err != nil {
+		return contract.TeamMemberResponse{}, err
+	}
+	if err := team.checkUser(ctx, input.UserID); err != nil {
+		return contract.TeamMemberResponse{}, err
+	}
+
+	member, err := team.teamRepository.AddMember(ctx, model.TeamMember{TeamID: uint(teamID), UserID: uint(input.UserID), Priority: input.Priority,
+		Accepted: input.UserID == ownerID})
+	if err != nil {
+		return contract.TeamMemberResponse{}, err
+	}
+
+	return teamMemberToContract(member), nil
+}
+
+func (team Team) RemoveMember(ctx context.Context, ownerID, teamID, userID int) error {
+	if _, err := team.getTeam(ctx, ownerID, teamID); err != nil {
+		return err
+	}
+
+	err := team.teamRepository.RemoveMember(ctx, teamID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("team member", err)
+	}
+	return err
+}
+
+// GetMemberships returns the teams the user has been added to, along with whether they accepted to be booked
+// through them.
+func (team Team) GetMemberships(ctx context.Context, userID int) (contract.TeamMembershipList, error) {
+	teams, err := team.teamRepository.GetMemberships(ctx, userID)
+	if err != nil {
+		return contract.TeamMembershipList{}, err
+	}
+
+	resp := make([]contract.TeamMembership, 0, len(teams))
+	for _, obj := range teams {
+		for _, member := range obj.Members {
+			resp = append(resp, contract.TeamMembership{TeamID: obj.ID, TeamName: obj.Name, OwnerID: obj.OwnerID,
+				Priority: member.Priority, Accepted: member.Accepted})
+		}
+	}
+
+	return contract.TeamMembershipList{Memberships: resp}, nil
+}
+
+// AcceptMembership lets the team the user has been added to read their calendar and book them.
+func (team Team) AcceptMembership(ctx context.Context, userID, teamID int) error {
+	err := team.teamRepository.AcceptMember(ctx, teamID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("team membership", err)
+	}
+	return err
+}
+
+// LeaveTeam removes the user from a team they have been added to, whether they accepted it or not. Their bookings
+// made through the team stay with them.
+func (team Team) LeaveTeam(ctx context.Context, userID, teamID int) error {
+	err := team.teamRepository.RemoveMember(ctx, teamID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("team membership", err)
+	}
+	return err
+}
+
+func (team Team) CreateEventType(ctx context.Context, ownerID, teamID int, input contract.TeamEventType) (contract.TeamEventTypeResponse, error) {
+	if _, err := team.getTeam(ctx, ownerID, teamID); err != nil {
+		return contract.TeamEventTypeResponse{}, err
+	}
+
+	obj, err := team.teamRepository.CreateEventType(ctx, model.TeamEventType{
+		TeamID:            uint(teamID),
+		Name:              input.Name,
+		Slug:              input.Slug,
+		SchedulingType:    input.SchedulingType,
+		DurationMins:      input.DurationMins,
+		SlotIncrementMins: input.SlotIncrementMins,
+		Description:       input.Description,
+		Location:          input.Location,
+	})
+	if err != nil {
+		return contract.TeamEventTypeResponse{}, err
+	}
+
+	return teamEventTypeToContract(obj), nil
+}
+
+func (team Team) GetEventTypes(ctx context.Context, ownerID, teamID int) (contract.TeamEventTypeList, error) {
+	if _, err := team.getTeam(ctx, ownerID, teamID); err != nil {
+		return contract.TeamEventTypeList{}, err
+	}
+
+	eventTypes, err := team.teamRepository.GetEventTypes(ctx, teamID)
+	if err != nil {
+		return contract.TeamEventTypeList{}, err
+	}
+
+	resp := make([]contract.TeamEventTypeResponse, 0)
+	for _, obj := range eventTypes {
+		resp = append(resp, teamEventTypeToContract(obj))
+	}
+
+	return contract.TeamEventTypeList{EventTypes: resp}, nil
+}
+
+func (team Team) GetEventType(ctx context.Context, ownerID, teamID, eventTypeID int) (contract.TeamEventTypeResponse, error) {
+	_, obj, err := team.teamEventType(ctx, ownerID, teamID, eventTypeID)
+	if err != nil {
+		return contract.TeamEventTypeResponse{}, err
+	}
+
+	return teamEventTypeToContract(obj), nil
+}
+
+func (team Team) DeleteEventType(ctx context.Context, ownerID, teamID, eventTypeID int) error {
+	if _, err := team.getTeam(ctx, ownerID, teamID); err != nil {
+		return err
+	}
+
+	err := team.teamRepository.DeleteEventType(ctx, teamID, eventTypeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("team event type", err)
+	}
+	return err
+}
+
+// GetSlots computes the free slots of a team event type between from and to, the next 14 days when no range is
+// given. Collective event types offer the times when every member is free, and round robin ones the times when any
+// member is, along with the members who can be booked then. Only the members who accepted are taken into account.
+// Each member's availability, bookings and scheduling rules are taken into account, with the duration and slot
+// increment of the event type.
+func (team Team) GetSlots(ctx context.Context, ownerID, teamID, eventTypeID int, from, to time.Time, loc *time.Location) (contract.TeamSlotList, error) {
+	now := team.now()
+	if from.IsZero() {
+		from, to = now, now.AddDate(0, 0, 14)
+	}
+	if from.Before(now) {
+		from = now
+	}
+
+	teamObj, eventType, err := team.teamEventType(ctx, ownerID, teamID, eventTypeID)
+	if err != nil {
+		return contract.TeamSlotList{}, err
+	}
+	hosts, err := team.hosts(ctx, teamObj, eventType)
+	if err != nil {
+		return contract.TeamSlotList{}, err
+	}
+
+	resp := make([]contract.TeamSlot, 0)
+	if !from.Before(to) {
+		return contract.TeamSlotList{Slots: resp}, nil
+	}
+	slots, err := team.slots(ctx, teamObj, eventType, hosts, from, to)
+	if err != nil {
+		return contract.TeamSlotList{}, err
+	}
+
+	for _, s := range slots {
+		userIDs := make([]uint, 0, len(s.hosts))
+		for _, h := range s.hosts {
+			userIDs = append(userIDs, h.member.UserID)
+		}
+		resp = append(resp, contract.TeamSlot{StartTime: inLocation(s.start, loc), EndTime: inLocation(s.end, loc), UserIDs: userIDs})
+	}
+
+	return contract.TeamSlotList{Slots: resp}, nil
+}
+
+// Book books the free time of a team event type starting at the given start time. Collective event types book every
+// member, either all of them or none. Round robin ones book one of the free members: the ones of the highest priority
+// first and, among them, the one booked least recently, moving on to the next one if a member turns out to be taken
+// in the meantime. A time which is not free fails with model.ErrSlotUnavailable.
+func (team Team) Book(ctx context.Context, ownerID, teamID int, input contract.TeamEvent) (contract.EventListResponse, error) {
+	if input.StartTime.Before(team.now()) {
+		return contract.EventListResponse{}, model.ErrSlotUnavailable
+	}
+
+	teamObj, eventType, err := team.teamEventType(ctx, ownerID, teamID, input.TeamEventTypeID)
+	if err != nil {
+		return contract.EventListResponse{}, err
+	}
+	hosts, err := team.hosts(ctx, teamObj, eventType)
+	if err != nil {
+		return contract.EventListResponse{}, err
+	}
+	endTime := input.StartTime.Add(time.Duration(eventType.DurationMins) * time.Minute)
+	slots, err := team.slots(ctx, teamObj, eventType, hosts, input.StartTime, endTime)
+	if err != nil {
+		return contract.EventListResponse{}, err
+	}
+	if len(slots) == 0 || !slots[0].start.Equal(input.StartTime) {
+		return contract.EventListResponse{}, model.ErrSlotUnavailable
+	}
+
+	eventObj := model.Event{
+		TeamEventTypeID: eventType.ID,
+		InviteeEmail:    input.InviteeEmail,
+		InviteeName:     input.InviteeName,
+		InviteeNotes:    input.InviteeNotes,
+	}
+	var events []model.Event
+	if eventType.SchedulingType == model.SchedulingCollective {
+		events, err = team.book(ctx, teamObj.ID, eventObj, slots[0].interval, slots[0].hosts...)
+	} else {
+		events, err = team.bookInTurn(ctx, teamObj.ID, eventObj, slots[0])
+	}
+	if err != nil {
+		return contract.EventListResponse{}, err
+	}
+
+	resp := make([]contract.EventResponse, 0, len(events))
+	for _, obj := range events {
+		resp = append(resp, team.event.toContract(obj, nil))
+	}
+	return contract.EventListResponse{Events: resp}, nil
+}
+
+// bookInTurn books the first free member of the slot in round robin order.
+func (team Team) bookInTurn(ctx context.Context, teamID uint, eventObj model.Event, slot teamSlot) ([]model.Event, error) {
+	candidates := append([]host(nil), slot.hosts...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i].member, candidates[j].member
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if !a.LastBookedAt.Equal(b.LastBookedAt) {
+			return a.LastBookedAt.Before(b.LastBookedAt)
+		}
+		return a.UserID < b.UserID
+	})
+
+	for _, candidate := range candidates {
+		events, err := team.book(ctx, teamID, eventObj, slot.interval, candidate)
+		// The member may have been booked since the slots were computed, in which case the next one gets the booking
+		if errors.Is(err, model.ErrSlotUnavailable) || errors.Is(err, model.ErrSlotAlreadyBooked) || errors.Is(err, model.ErrBookingLimit) {
+			continue
+		}
+		return events, err
+	}
+	return nil, model.ErrSlotUnavailable
+}
+
+// book books the hosts for the slot in a single transaction, each of them with the limits of their scheduling rules.
+func (team Team) book(ctx context.Context, teamID uint, eventObj model.Event, slot interval, hosts ...host) ([]model.Event, error) {
+	bookings := make([]model.Booking, 0, len(hosts))
+	for _, h := range hosts {
+		loc, err := h.availability.Location()
+		if err != nil {
+			return nil, err
+		}
+		booking := model.Booking{
+			Event:  eventObj,
+			Slot:   model.Slot{UserID: h.member.UserID, StartTime: slot.start, EndTime: slot.end, Capacity: 1},
+			Limits: h.availability.Limits(slot.start, loc),
+		}
+		booking.Event.UserID = h.member.UserID
+		bookings = append(bookings, booking)
+	}
+
+	return team.event.eventRepository.BookTeam(ctx, teamID, bookings, team.event.changeJobs(model.WebhookBookingCreated))
+}
+
+// host is a member of a team along with their availability, adjusted for a team event type.
+type host struct {
+	member       model.TeamMember
+	availability model.UserAvailability
+}
+
+// teamSlot is a free slot of a team event type along with the members who can be booked for it.
+type teamSlot struct {
+	interval
+	hosts []host
+}
+
+// hosts returns the members of the team with their availability adjusted for the event type. Members who have not
+// accepted yet are left out, and so are members who have not set their availability yet, as they are never free.
+func (team Team) hosts(ctx context.Context, teamObj model.Team, eventType model.TeamEventType) ([]host, error) {
+	hosts := make([]host, 0, len(teamObj.Members))
+	for _, member := range teamObj.Members {
+		if !member.Accepted {
+			continue
+		}
+		availability, err := team.event.availabilityRepository.Get(ctx, int(member.UserID))
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, host{member: member, availability: eventType.ApplyTo(availability)})
+	}
+	return hosts, nil
+}
+
+// slots computes the free slots of the event type between from and to for the given hosts, as per the scheduling
+// type. A collective event type has no free slots unless every member of its team who accepted is a host.
+func (team Team) slots(ctx context.Context, teamObj model.Team, eventType model.TeamEventType, hosts []host, from, to time.Time) ([]teamSlot, error) {
+	now := team.now()
+	duration := time.Duration(eventType.DurationMins) * time.Minute
+
+	if eventType.SchedulingType == model.SchedulingCollective {
+		if len(hosts) == 0 || len(hosts) < acceptedMembers(teamObj) {
+			return nil, nil
+		}
+		// Slots have to be bookable as per the notice and horizon of every member
+		availabilities := make([]model.UserAvailability, 0, len(hosts))
+		for _, h := range hosts {
+			from, to = bookableRange(h.availability.SchedulingRules, now, duration, from, to)
+			availabilities = append(availabilities, h.availability)
+		}
+		if !from.Before(to) {
+			return nil, nil
+		}
+		// The members' own slot increments may differ, so the slots follow the event type's or its duration
+		increment := duration
+		if eventType.SlotIncrementMins > 0 {
+			increment = time.Duration(eventType.SlotIncrementMins) * time.Minute
+		}
+		common, err := team.event.calendar.commonSlots(ctx, availabilities, duration, increment, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		slots := make([]teamSlot, 0, len(common))
+		for _, s := range common {
+			slots = append(slots, teamSlot{interval: s, hosts: hosts})
+		}
+		return slots, nil
+	}
+
+	slots := make([]teamSlot, 0)
+	index := make(map[int64]int)
+	for _, h := range hosts {
+		hostFrom, hostTo := bookableRange(h.availability.SchedulingRules, now, duration, from, to)
+		if !hostFrom.Before(hostTo) {
+			continue
+		}
+		free, err := team.event.calendar.slots(ctx, h.availability, hostFrom, hostTo, nil)
+		if err != nil {
+			return nil, err
+		}
+		free, err = team.event.calendar.withinCaps(ctx, h.availability, free, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, s := range free {
+			i, ok := index[s.start.UnixNano()]
+			if !ok {
+				i = len(slots)
+				index[s.start.UnixNano()] = i
+				slots = append(slots, teamSlot{interval: s})
+			}
+			slots[i].hosts = append(slots[i].hosts, h)
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool { return slots[i].start.Before(slots[j].start) })
+	return slots, nil
+}
+
+// acceptedMembers counts the members of the team who accepted to be booked through it.
+func acceptedMembers(teamObj model.Team) int {
+	accepted := 0
+	for _, member := range teamObj.Members {
+		if member.Accepted {
+			accepted++
+		}
+	}
+	return accepted
+}
+
+// getTeam returns the team of the owner, or model.NotFound if the owner has no such team.
+func (team Team) getTeam(ctx context.Context, ownerID, teamID int) (model.Team, error) {
+	obj, err := team.teamRepository.GetByID(ctx, ownerID, teamID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Team{}, model.NotFound("team", err)
+	}
+	return obj, err
+}
+
+// teamEventType returns the team of the owner along with one of its event types.
+func (team Team) teamEventType(ctx context.Context, ownerID, teamID, eventTypeID int) (model.Team, model.TeamEventType, error) {
+	teamObj, err := team.getTeam(ctx, ownerID, teamID)
+	if err != nil {
+		return model.Team{}, model.TeamEventType{}, err
+	}
+	eventType, err := team.teamRepository.GetEventType(ctx, teamID, eventTypeID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.Team{}, model.TeamEventType{}, model.NotFound("team event type", err)
+	}
+	if err != nil {
+		return model.Team{}, model.TeamEventType{}, err
+	}
+	return teamObj, eventType, nil
+}
+
+// checkUser fails with model.NotFound if the user to be added to a team does not exist.
+func (team Team) checkUser(ctx context.Context, userID int) error {
+	_, err := team.userRepository.GetByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("user", err)
+	}
+	return err
+}
+
+func teamToContract(obj model.Team) contract.TeamResponse {
+	members := make([]contract.TeamMemberResponse, 0, len(obj.Members))
+	for _, member := range obj.Members {
+		members = append(members, teamMemberToContract(member))
+	}
+	return contract.TeamResponse{
+		ID:        obj.ID,
+		OwnerID:   obj.OwnerID,
+		Name:      obj.Name,
+		Members:   members,
+		CreatedAt: obj.CreatedAt,
+	}
+}
+
+func teamMemberToContract(member model.TeamMember) contract.TeamMemberResponse {
+	resp := contract.TeamMemberResponse{UserID: member.UserID, Priority: member.Priority, Accepted: member.Accepted, Bookings: member.Bookings}
+	if !member.LastBookedAt.IsZero() {
+		lastBookedAt := member.LastBookedAt
+		resp.LastBookedAt = &lastBookedAt
+	}
+	return resp
+}
+
+func teamEventTypeToContract(eventType model.TeamEventType) contract.TeamEventTypeResponse {
+	return contract.TeamEventTypeResponse{
+		ID:                eventType.ID,
+		TeamID:            eventType.TeamID,
+		Name:              eventType.Name,
+		Slug:              eventType.Slug,
+		SchedulingType:    eventType.SchedulingType,
+		DurationMins:      eventType.DurationMins,
+		SlotIncrementMins: eventType.SlotIncrementMins,
+		Description:       eventType.Description,
+		Location:          eventType.Location,
+		CreatedAt:         eventType.CreatedAt,
+	}
+}
+
+func NewTeam(teamRepository TeamRepository, userRepository UserRepository, event Event) Team {
+	return Team{teamRepository: teamRepository, userRepository: userRepository, event: event, now: time.Now}
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/datatypes"
+)
+
+type TeamTestSuite struct {
+	suite.Suite
+	service                    Team
+	mockTeamRepository         *MockTeamRepository
+	mockUserRepository         *MockUserRepository
+	mockEventRepository        *MockEventRepository
+	mockAvailabilityRepository *MockUserAvailabilityRepository
+	mockOverrideRepository     *MockAvailabilityOverrideRepository
+	ctx                        context.Context
+}
+
+func (suite *TeamTestSuite) SetupTest() {
+	suite.mockTeamRepository = &MockTeamRepository{}
+	suite.mockUserRepository = &MockUserRepository{}
+	suite.mockEventRepository = &MockEventRepository{}
+	suite.mockAvailabilityRepository = &MockUserAvailabilityRepository{}
+	suite.mockOverrideRepository = &MockAvailabilityOverrideRepository{}
+	busyBlockRepository := &MockBusyBlockRepository{}
+	busyBlockRepository.On("GetInRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.BusyBlock{}, nil).Maybe()
+	suite.mockOverrideRepository.On("GetInRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil).Maybe()
+	event := NewEvent(suite.mockEventRepository, &MockSlotRepository{}, suite.mockAvailabilityRepository, suite.mockOverrideRepository, &MockEventTypeRepository{},
+		suite.mockUserRepository, busyBlockRepository, NewBookingTokens([]byte("secret")))
+	suite.service = NewTeam(suite.mockTeamRepository, suite.mockUserRepository, event)
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC) }
+	suite.ctx = context.Background()
+}
+
+// available makes the user available on mondays between the given hours.
+func (suite *TeamTestSuite) available(userID, fromHour, toHour int, rules model.SchedulingRules) {
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, userID).Return(model.UserAvailability{
+		UserID: uint(userID),
+		Availability: []model.DayAvailability{
+			{Day: "monday", StartTime: datatypes.NewTime(fromHour, 0, 0, 0), EndTime: datatypes.NewTime(toHour, 0, 0, 0)},
+		},
+		MeetingDurationMins: 45,
+		SchedulingRules:     rules,
+	}, nil)
+}
+
+func (suite *TeamTestSuite) team(eventType model.TeamEventType, members ...model.TeamMember) {
+	suite.mockTeamRepository.On("GetByID", suite.ctx, 1, 2).Return(model.Team{ID: 2, OwnerID: 1, Members: members}, nil)
+	suite.mockTeamRepository.On("GetEventType", suite.ctx, 2, 3).Return(eventType, nil)
+}
+
+func (suite *TeamTestSuite) TestCreateReturnsNotFoundIfMemberDoesNotExist() {
+	suite.mockUserRepository.On("GetByID", suite.ctx, 5).Return(model.User{}, sql.ErrNoRows)
+
+	_, err := suite.service.Create(suite.ctx, 1, contract.Team{Name: "sales", Members: []contract.TeamMember{{UserID: 5}}})
+	suite.ErrorIs(err, model.NotFound("user", nil))
+	suite.mockTeamRepository.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *TeamTestSuite) TestGetSlotsOfCollectiveEventTypeOnlyOffersTimesWhenEveryMemberIsFree() {
+	monday := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	suite.team(model.TeamEventType{ID: 3, TeamID: 2, SchedulingType: model.SchedulingCollective, DurationMins: 30},
+		model.TeamMember{TeamID: 2, UserID: 5, Accepted: true}, model.TeamMember{TeamID: 2, UserID: 6, Accepted: true})
+	suite.available(5, 9, 12, model.SchedulingRules{})
+	suite.available(6, 10, 13, model.SchedulingRules{})
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 5, mock.Anything, mock.Anything).Return([]model.Event{}, nil)
+	// The second member is booked at 11
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 6, mock.Anything, mock.Anything).Return([]model.Event{
+		{UserID: 6, StartTime: monday.Add(11 * time.Hour), EndTime: monday.Add(11*time.Hour + 30*time.Minute)},
+	}, nil)
+
+	resp, err := suite.service.GetSlots(suite.ctx, 1, 2, 3, monday, monday.AddDate(0, 0, 1), nil)
+	suite.Nil(err)
+	suite.Equal([]contract.TeamSlot{
+		{StartTime: monday.Add(10 * time.Hour), EndTime: monday.Add(10*time.Hour + 30*time.Minute), UserIDs: []uint{5, 6}},
+		{StartTime: monday.Add(10*time.Hour + 30*time.Minute), EndTime: monday.Add(11 * time.Hour), UserIDs: []uint{5, 6}},
+		{StartTime: monday.Add(11*time.Hour + 30*time.Minute), EndTime: monday.Add(12 * time.Hour), UserIDs: []uint{5, 6}},
+	}, resp.Slots)
+}
+
+func (suite *TeamTestSuite) TestGetSlotsOfCollectiveEventTypeIsEmptyIfAMemberHasNoAvailability() {
+	monday := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	suite.team(model.TeamEventType{ID: 3, TeamID: 2, SchedulingType: model.SchedulingCollective, DurationMins: 30},
+		model.TeamMember{TeamID: 2, UserID: 5, Accepted: true}, model.TeamMember{TeamID: 2, UserID: 6, Accepted: true})
+	suite.available(5, 9, 12, model.SchedulingRules{})
+	suite.mockAvailabilityRepository.On("Get", suite.ctx, 6).Return(model.UserAvailability{}, sql.ErrNoRows)
+
+	resp, err := suite.service.GetSlots(suite.ctx, 1, 2, 3, monday, monday.AddDate(0, 0, 1), nil)
+	suite.Nil(err)
+	suite.Empty(resp.Slots)
+}
+
+func (suite *TeamTestSuite) TestGetSlotsOfRoundRobinEventTypeOffersTimesWhenAnyMemberIsFree() {
+	monday := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	suite.team(model.TeamEventType{ID: 3, TeamID: 2, SchedulingType: model.SchedulingRoundRobin, DurationMins: 60},
+		model.TeamMember{TeamID: 2, UserID: 5, Accepted: true}, model.TeamMember{TeamID: 2, UserID: 6, Accepted: true})
+	suite.available(5, 9, 11, model.SchedulingRules{})
+	suite.available(6, 10, 12, model.SchedulingRules{})
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 5, mock.Anything, mock.Anything).Return([]model.Event{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 6, mock.Anything, mock.Anything).Return([]model.Event{}, nil)
+
+	resp, err := suite.service.GetSlots(suite.ctx, 1, 2, 3, monday, monday.AddDate(0, 0, 1), nil)
+	suite.Nil(err)
+	suite.Equal([]contract.TeamSlot{
+		{StartTime: monday.Add(9 * time.Hour), EndTime: monday.Add(10 * time.Hour), UserIDs: []uint{5}},
+		{StartTime: monday.Add(10 * time.Hour), EndTime: monday.Add(11 * time.Hour), UserIDs: []uint{5, 6}},
+		{StartTime: monday.Add(11 * time.Hour), EndTime: monday.Add(12 * time.Hour), UserIDs: []uint{6}},
+	}, resp.Slots)
+}
+
+func (suite *TeamTestSuite) TestGetSlotsLeavesOutMembersWhoHaveNotAccepted() {
+	monday := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	suite.team(model.TeamEventType{ID: 3, TeamID: 2, SchedulingType: model.SchedulingCollective, DurationMins: 60},
+		model.TeamMember{TeamID: 2, UserID: 5, Accepted: true}, model.TeamMember{TeamID: 2, UserID: 6})
+	suite.available(5, 9, 11, model.SchedulingRules{})
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 5, mock.Anything, mock.Anything).Return([]model.Event{}, nil)
+
+	resp, err := suite.service.GetSlots(suite.ctx, 1, 2, 3, monday, monday.AddDate(0, 0, 1), nil)
+	suite.Nil(err)
+	suite.Equal([]contract.TeamSlot{
+		{StartTime: monday.Add(9 * time.Hour), EndTime: monday.Add(10 * time.Hour), UserIDs: []uint{5}},
+		{StartTime: monday.Add(10 * time.Hour), EndTime: monday.Add(11 * time.Hour), UserIDs: []uint{5}},
+	}, resp.Slots)
+	// The calendar of the member who has not accepted is never read
+	suite.mockAvailabilityRepository.AssertNotCalled(suite.T(), "Get", suite.ctx, 6)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "GetInRange", suite.ctx, 6, mock.Anything, mock.Anything)
+}
+
+func (suite *TeamTestSuite) TestBookDoesNotBookMembersWhoHaveNotAccepted() {
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.team(model.TeamEventType{ID: 3, TeamID: 2, SchedulingType: model.SchedulingRoundRobin, DurationMins: 30},
+		model.TeamMember{TeamID: 2, UserID: 5})
+
+	_, err := suite.service.Book(suite.ctx, 1, 2, contract.TeamEvent{TeamEventTypeID: 3, StartTime: start, InviteeName: "test", InviteeEmail: "test@example.xyz"})
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "BookTeam", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TeamTestSuite) TestAddMemberOnlyAcceptsForTheOwner() {
+	suite.team(model.TeamEventType{})
+	for _, userID := range []int{1, 5} {
+		suite.mockUserRepository.On("GetByID", suite.ctx, userID).Return(model.User{ID: uint(userID)}, nil)
+		member := model.TeamMember{TeamID: 2, UserID: uint(userID), Accepted: userID == 1}
+		suite.mockTeamRepository.On("AddMember", suite.ctx, member).Return(member, nil)
+	}
+
+	owner, err := suite.service.AddMember(suite.ctx, 1, 2, contract.TeamMember{UserID: 1})
+	suite.NoError(err)
+	suite.True(owner.Accepted)
+	invited, err := suite.service.AddMember(suite.ctx, 1, 2, contract.TeamMember{UserID: 5})
+	suite.NoError(err)
+	suite.False(invited.Accepted)
+}
+
+func (suite *TeamTestSuite) TestAcceptMembershipReturnsNotFoundIfUserIsNotAMember() {
+	suite.mockTeamRepository.On("AcceptMember", suite.ctx, 2, 5).Return(sql.ErrNoRows)
+
+	err := suite.service.AcceptMembership(suite.ctx, 5, 2)
+	suite.ErrorIs(err, model.NotFound("team membership", nil))
+}
+
+func (suite *TeamTestSuite) TestBookCollectiveEventTypeBooksEveryMember() {
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.team(model.TeamEventType{ID: 3, TeamID: 2, SchedulingType: model.SchedulingCollective, DurationMins: 30},
+		model.TeamMember{TeamID: 2, UserID: 5, Accepted: true}, model.TeamMember{TeamID: 2, UserID: 6, Accepted: true})
+	suite.available(5, 9, 12, model.SchedulingRules{})
+	suite.available(6, 10, 13, model.SchedulingRules{})
+	suite.mockEventRepository.On("GetInRange", suite.ctx, mock.Anything, mock.Anything, mock.Anything).Return([]model.Event{}, nil)
+	event := model.Event{TeamEventTypeID: 3, InviteeEmail: "test@example.xyz", InviteeName: "test"}
+	slot := model.Slot{StartTime: start, EndTime: start.Add(30 * time.Minute), Capacity: 1}
+	booking := func(userID uint) model.Booking {
+		booking := model.Booking{Event: event, Slot: slot}
+		booking.Event.UserID, booking.Slot.UserID = userID, userID
+		return booking
+	}
+	suite.mockEventRepository.On("BookTeam", suite.ctx, uint(2), []model.Booking{booking(5), booking(6)}).Return([]model.Event{
+		{ID: 1, UserID: 5, TeamEventTypeID: 3, StartTime: start, EndTime: start.Add(30 * time.Minute)},
+		{ID: 2, UserID: 6, TeamEventTypeID: 3, StartTime: start, EndTime: start.Add(30 * time.Minute)},
+	}, nil)
+
+	resp, err := suite.service.Book(suite.ctx, 1, 2, contract.TeamEvent{TeamEventTypeID: 3, StartTime: start, InviteeEmail: "test@example.xyz", InviteeName: "test"})
+	suite.Nil(err)
+	suite.Len(resp.Events, 2)
+	suite.Equal(3, resp.Events[0].TeamEventTypeID)
+	// Each member's calendars, webhooks and notifications follow up on their booking
+	suite.Len(suite.mockEventRepository.Jobs, 6)
+}
+
+func (suite *TeamTestSuite) TestBookCollectiveEventTypeReturnsErrorIfAMemberIsBusy() {
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.team(model.TeamEventType{ID: 3, TeamID: 2, SchedulingType: model.SchedulingCollective, DurationMins: 30},
+		model.TeamMember{TeamID: 2, UserID: 5, Accepted: true}, model.TeamMember{TeamID: 2, UserID: 6, Accepted: true})
+	suite.available(5, 9, 12, model.SchedulingRules{})
+	suite.available(6, 10, 13, model.SchedulingRules{})
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 5, mock.Anything, mock.Anything).Return([]model.Event{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 6, mock.Anything, mock.Anything).Return([]model.Event{
+		{UserID: 6, StartTime: start, EndTime: start.Add(time.Hour)},
+	}, nil)
+
+	_, err := suite.service.Book(suite.ctx, 1, 2, contract.TeamEvent{TeamEventTypeID: 3, StartTime: start, InviteeEmail: "test@example.xyz", InviteeName: "test"})
+	suite.ErrorIs(err, model.ErrSlotUnavailable)
+	suite.mockEventRepository.AssertNotCalled(suite.T(), "BookTeam", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TeamTestSuite) TestBookRoundRobinEventTypeAssignsMemberBookedLeastRecentlyOfHighestPriority() {
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.team(model.TeamEventType{ID: 3, TeamID: 2, SchedulingType: model.SchedulingRoundRobin, DurationMins: 30},
+		model.TeamMember{TeamID: 2, UserID: 5, Accepted: true, Priority: 0},
+		model.TeamMember{TeamID: 2, UserID: 6, Accepted: true, Priority: 1, LastBookedAt: start.AddDate(0, 0, -1)},
+		model.TeamMember{TeamID: 2, UserID: 7, Accepted: true, Priority: 1, LastBookedAt: start.AddDate(0, 0, -2)})
+	for _, userID := range []int{5, 6, 7} {
+		suite.available(userID, 9, 12, model.SchedulingRules{})
+	}
+	suite.mockEventRepository.On("GetInRange", suite.ctx, mock.Anything, mock.Anything, mock.Anything).Return([]model.Event{}, nil)
+	suite.mockEventRepository.On("BookTeam", suite.ctx, uint(2), mock.Anything).Return([]model.Event{
+		{ID: 1, UserID: 7, TeamEventTypeID: 3, StartTime: start, EndTime: start.Add(30 * time.Minute)},
+	}, nil)
+
+	resp, err := suite.service.Book(suite.ctx, 1, 2, contract.TeamEvent{TeamEventTypeID: 3, StartTime: start, InviteeEmail: "test@example.xyz", InviteeName: "test"})
+	suite.Nil(err)
+	suite.Equal(7, resp.Events[0].UserID)
+	bookings := suite.mockEventRepository.Calls[len(suite.mockEventRepository.Calls)-1].Arguments.Get(2).([]model.Booking)
+	suite.Len(bookings, 1)
+	suite.Equal(uint(7), bookings[0].Event.UserID)
+}
+
+func (suite *TeamTestSuite) TestBookRoundRobinEventTypeMovesOnToNextMemberIfOneIsTaken() {
+	start := time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC) // monday
+	suite.team(model.TeamEventType{ID: 3, TeamID: 2, SchedulingType: model.SchedulingRoundRobin, DurationMins: 30},
+		model.TeamMember{TeamID: 2, UserID: 5, Accepted: true, LastBookedAt: start.AddDate(0, 0, -2)},
+		model.TeamMember{TeamID: 2, UserID: 6, Accepted: true, LastBookedAt: start.AddDate(0, 0, -1)})
+	suite.available(5, 9, 12, model.SchedulingRules{MaxPerDay: 1})
+	suite.available(6, 9, 12, model.SchedulingRules{})
+	suite.mockEventRepository.On("GetInRange", suite.ctx, mock.Anything, mock.Anything, mock.Anything).Return([]model.Event{}, nil)
+	// The first member is booked by someone else in the meantime
+	suite.mockEventRepository.On("BookTeam", suite.ctx, uint(2), mock.MatchedBy(func(bookings []model.Booking) bool {
+		return bookings[0].Event.UserID == 5
+	})).Return([]model.Event(nil), model.ErrBookingLimit)
+	suite.mockEventRepository.On("BookTeam", suite.ctx, uint(2), mock.MatchedBy(func(bookings []model.Booking) bool {
+		return bookings[0].Event.UserID == 6
+	})).Return([]model.Event{{ID: 1, UserID: 6, TeamEventTypeID: 3, StartTime: start, EndTime: start.Add(30 * time.Minute)}}, nil)
+
+	resp, err := suite.service.Book(suite.ctx, 1, 2, contract.TeamEvent{TeamEventTypeID: 3, StartTime: start, InviteeEmail: "test@example.xyz", InviteeName: "test"})
+	suite.Nil(err)
+	suite.Equal(6, resp.Events[0].UserID)
+	suite.mockEventRepository.AssertNumberOfCalls(suite.T(), "BookTeam", 2)
+}
+
+func (suite *TeamTestSuite) TestBookReturnsNotFoundIfEventTypeBelongsToAnotherTeam() {
+	suite.mockTeamRepository.On("GetByID", suite.ctx, 1, 2).Return(model.Team{ID: 2, OwnerID: 1}, nil)
+	suite.mockTeamRepository.On("GetEventType", suite.ctx, 2, 3).Return(model.TeamEventType{}, sql.ErrNoRows)
+
+	_, err := suite.service.Book(suite.ctx, 1, 2, contract.TeamEvent{TeamEventTypeID: 3, StartTime: time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC),
+		InviteeEmail: "test@example.xyz", InviteeName: "test"})
+	suite.ErrorIs(err, model.NotFound("team event type", nil))
+}
+
+func TestTeamTestSuite(t *testing.T) {
+	suite.Run(t, new(TeamTestSuite))
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// wallClock resolves a wall clock time on the given calendar date in loc.
+//
+// Daylight saving transitions make this ambiguous in two cases, which are
+// resolved the same way RFC 5545 does:
+//   - a time falling in a gap (clocks jump forward) is interpreted with the
+//     offset in effect before the gap, i.e. it moves forward by the gap length.
+//   - a time that occurs twice (clocks fall back) resolves to its first occurrence.
+func wallClock(year int, month time.Month, day int, clock datatypes.Time, loc *time.Location) time.Time {
+	naive := time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Add(time.Duration(clock))
+
+	_, offsetBefore := naive.Add(-24 * time.Hour).In(loc).Zone()
+	_, offsetAfter := naive.Add(24 * time.Hour).In(loc).Zone()
+
+	var resolved time.Time
+	for _, offset := range []int{offsetBefore, offsetAfter} {
+		candidate := naive.Add(-time.Duration(offset) * time.Second).In(loc)
+		if !sameWallClock(candidate, naive) {
+			continue
+		}
+		if resolved.IsZero() || candidate.Before(resolved) {
+			resolved = candidate
+		}
+	}
+
+	if resolved.IsZero() {
+		// The wall clock time does not exist on this date.
+		resolved = naive.Add(-time.Duration(offsetBefore) * time.Second).In(loc)
+	}
+	return resolved
+}
+
+func sameWallClock(t, naive time.Time) bool {
+	y1, m1, d1 := t.Date()
+	y2, m2, d2 := naive.Date()
+	return y1 == y2 && m1 == m2 && d1 == d2 &&
+		t.Hour() == naive.Hour() && t.Minute() == naive.Minute() && t.Second() == naive.Second()
+}
+
+// inLocation converts the given time to loc. A nil loc leaves the time untouched.
+func inLocation(t time.Time, loc *time.Location) time.Time {
+	if loc == nil {
+		return t
+	}
+	return t.In(loc)
+}
@@ -2,28 +2,211 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/harbor-xyz/coding-project/contract"
 	"github.com/harbor-xyz/coding-project/model"
+
+	"gorm.io/datatypes"
 )
 
+// maxSlugSuffix bounds how many numbered variants of the slug derived from a name are tried
+const maxSlugSuffix = 100
+
+var nonSlugCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
 type User struct {
 	userRepository         UserRepository
 	availabilityRepository UserAvailabilityRepository
+	overrideRepository     AvailabilityOverrideRepository
+	calendar               calendar
+	now                    func() time.Time
 }
 
 func (user User) Create(ctx context.Context, input contract.User) (contract.UserResponse, error) {
 	userObj := model.User{
-		Name:  input.Name,
-		Email: input.Email,
+		Name:     input.Name,
+		Email:    input.Email,
+		TimeZone: input.TimeZone,
+	}
+	if userObj.TimeZone == "" {
+		userObj.TimeZone = "UTC"
+	}
+	slug, err := user.slugFor(ctx, input)
+	if err != nil {
+		return contract.UserResponse{}, err
+	}
+	userObj.Slug = slug
+	// The user gets their first API key right away, as every other API of the user requires one
+	key, keyObj, err := newAPIKey(defaultAPIKeyName)
+	if err != nil {
+		return contract.UserResponse{}, err
+	}
+	userObj.APIKeys = []model.APIKey{keyObj}
+
+	userObj, err = user.userRepository.Create(ctx, userObj)
+	if err != nil {
+		return contract.UserResponse{}, err
+	}
+
+	resp := toUserContract(userObj)
+	resp.APIKey = key
+	return resp, nil
+}
+
+func (user User) Get(ctx context.Context, userID int) (contract.UserResponse, error) {
+	userObj, err := user.userRepository.GetByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.UserResponse{}, model.NotFound("user", err)
+	}
+	if err != nil {
+		return contract.UserResponse{}, err
+	}
+
+	return toUserContract(userObj), nil
+}
+
+// GetAll returns a page of the users whose email contains the given text.
+func (user User) GetAll(ctx context.Context, email string, limit, offset int) (contract.UserList, error) {
+	users, total, err := user.userRepository.GetAll(ctx, email, limit, offset)
+	if err != nil {
+		return contract.UserList{}, err
+	}
+
+	resp := make([]contract.UserResponse, 0)
+	for _, userObj := range users {
+		resp = append(resp, toUserContract(userObj))
+	}
+
+	return contract.UserList{Users: resp, Total: total}, nil
+}
+
+// Update changes the given fields of the user. A new time zone only applies to availability set afterwards, which
+// keeps the time zone it was set in.
+func (user User) Update(ctx context.Context, userID int, input contract.UserUpdate) (contract.UserResponse, error) {
+	userObj, err := user.userRepository.GetByID(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.UserResponse{}, model.NotFound("user", err)
+	}
+	if err != nil {
+		return contract.UserResponse{}, err
 	}
 
-	userObj, err := user.userRepository.Create(ctx, userObj)
+	if input.Name != nil {
+		userObj.Name = *input.Name
+	}
+	if input.Email != nil {
+		userObj.Email = *input.Email
+	}
+	if input.TimeZone != nil {
+		userObj.TimeZone = *input.TimeZone
+	}
+	if input.Slug != nil {
+		if err := user.checkSlug(ctx, userID, *input.Slug); err != nil {
+			return contract.UserResponse{}, err
+		}
+		userObj.Slug = *input.Slug
+	}
+
+	userObj, err = user.userRepository.Update(ctx, userObj)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.UserResponse{}, model.NotFound("user", err)
+	}
 	if err != nil {
 		return contract.UserResponse{}, err
 	}
 
-	return contract.UserResponse{ID: userObj.ID}, nil
+	return toUserContract(userObj), nil
+}
+
+// Delete removes the user along with everything they own. Invitees of their upcoming events are not notified.
+func (user User) Delete(ctx context.Context, userID int) error {
+	err := user.userRepository.Delete(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("user", err)
+	}
+	return err
+}
+
+// SetSlug changes the path of the user's public booking page. The previous path stops working straight away.
+func (user User) SetSlug(ctx context.Context, userID int, input contract.UserSlug) (contract.UserSlug, error) {
+	if err := user.checkSlug(ctx, userID, input.Slug); err != nil {
+		return contract.UserSlug{}, err
+	}
+
+	err := user.userRepository.SetSlug(ctx, userID, input.Slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.UserSlug{}, model.NotFound("user", err)
+	}
+	if err != nil {
+		return contract.UserSlug{}, err
+	}
+
+	return input, nil
+}
+
+// slugFor returns the slug given for a new user, or derives one from their name. Derived slugs are numbered when
+// other users already have them, while a slug given which is taken fails with model.ErrDuplicateSlug.
+func (user User) slugFor(ctx context.Context, input contract.User) (string, error) {
+	if input.Slug != "" {
+		return input.Slug, user.checkSlug(ctx, 0, input.Slug)
+	}
+
+	base := slugify(input.Name)
+	for i := 1; i <= maxSlugSuffix; i++ {
+		slug := base
+		if i > 1 {
+			slug = fmt.Sprintf("%s-%d", base, i)
+		}
+		err := user.checkSlug(ctx, 0, slug)
+		if errors.Is(err, model.ErrDuplicateSlug) {
+			continue
+		}
+		return slug, err
+	}
+	return "", model.ErrDuplicateSlug
+}
+
+// checkSlug makes sure that no user other than the one with userID has the slug
+func (user User) checkSlug(ctx context.Context, userID int, slug string) error {
+	existing, err := user.userRepository.GetBySlug(ctx, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if int(existing.ID) != userID {
+		return model.ErrDuplicateSlug
+	}
+	return nil
+}
+
+func toUserContract(userObj model.User) contract.UserResponse {
+	return contract.UserResponse{
+		ID:        userObj.ID,
+		Name:      userObj.Name,
+		Email:     userObj.Email,
+		TimeZone:  userObj.TimeZone,
+		Slug:      userObj.Slug,
+		CreatedAt: userObj.CreatedAt,
+	}
+}
+
+// slugify turns a name into a slug, such as "jane-doe" for "Jane Doe". Names without any latin letter or digit get
+// "user".
+func slugify(name string) string {
+	slug := strings.Trim(nonSlugCharacters.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		return "user"
+	}
+	return slug
 }
 
 func (user User) SetAvailability(ctx context.Context, userID int, input contract.UserAvailability) (model.UserAvailability, error) {
@@ -31,13 +214,53 @@ func (user User) SetAvailability(ctx context.Context, userID int, input contract
 		UserID:              uint(userID),
 		Availability:        input.Availability,
 		MeetingDurationMins: input.MeetingDurationMins,
+		SlotIncrementMins:   input.SlotIncrementMins,
+		TimeZone:            input.TimeZone,
+		SchedulingRules:     input.SchedulingRules,
 	}
 
-	return user.availabilityRepository.Set(ctx, availabilityObj)
+	// Availability is expressed in the user's own time zone unless stated otherwise
+	if availabilityObj.TimeZone == "" {
+		userObj, err := user.userRepository.GetByID(ctx, userID)
+		if err != nil {
+			return model.UserAvailability{}, err
+		}
+		availabilityObj.TimeZone = userObj.TimeZone
+	}
+
+	jobs, err := user.availabilityJobs(availabilityObj)
+	if err != nil {
+		return model.UserAvailability{}, err
+	}
+	return user.availabilityRepository.Set(ctx, availabilityObj, jobs)
+}
+
+// availabilityJobs returns the jobs generating the user's slots again and letting the user's webhooks know about the
+// new availability. They are enqueued in the same transaction as the change.
+func (user User) availabilityJobs(availabilityObj model.UserAvailability) ([]model.Job, error) {
+	now := user.now()
+	data, err := json.Marshal(contract.UserAvailability{
+		Availability:        availabilityObj.Availability,
+		MeetingDurationMins: availabilityObj.MeetingDurationMins,
+		SlotIncrementMins:   availabilityObj.SlotIncrementMins,
+		TimeZone:            availabilityObj.TimeZone,
+		SchedulingRules:     availabilityObj.SchedulingRules,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return newJobs(now,
+		jobSpec{jobSyncSlots, userJob{UserID: availabilityObj.UserID}},
+		jobSpec{jobPublishWebhook, webhookJob{UserID: availabilityObj.UserID, Event: model.WebhookAvailabilityUpdated, OccurredAt: now, Data: data}},
+	)
 }
 
 func (user User) GetAvailability(ctx context.Context, userID int) (contract.UserAvailability, error) {
 	availability, err := user.availabilityRepository.Get(ctx, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.UserAvailability{}, model.NotFound("availability", err)
+	}
 	if err != nil {
 		return contract.UserAvailability{}, err
 	}
@@ -45,54 +268,264 @@ func (user User) GetAvailability(ctx context.Context, userID int) (contract.User
 	return contract.UserAvailability{
 		Availability:        availability.Availability,
 		MeetingDurationMins: availability.MeetingDurationMins,
+		SlotIncrementMins:   availability.SlotIncrementMins,
+		TimeZone:            availability.TimeZone,
+		SchedulingRules:     availability.SchedulingRules,
 	}, nil
 }
 
-func (user User) GetAvailabilityOverlap(ctx context.Context, user1ID, user2ID int) (contract.UserAvailabilityOverlap, error) {
+// GetAvailabilityOverlap returns the overlap of the weekly availability of 2 users, in the time zone of the first one.
+// If a time range is given, the concrete intervals within it during which both users are available
+// are returned as well, taking their date overrides into account.
+func (user User) GetAvailabilityOverlap(ctx context.Context, user1ID, user2ID int, from, to time.Time) (contract.UserAvailabilityOverlap, error) {
 	availability1, err := user.availabilityRepository.Get(ctx, user1ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.UserAvailabilityOverlap{}, model.NotFound("availability", err)
+	}
 	if err != nil {
 		return contract.UserAvailabilityOverlap{}, err
 	}
 	availability2, err := user.availabilityRepository.Get(ctx, user2ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.UserAvailabilityOverlap{}, model.NotFound("availability", err)
+	}
 	if err != nil {
 		return contract.UserAvailabilityOverlap{}, err
 	}
 
-	overlap := make([]model.DayAvailability, 0)
+	overlap, err := user.weeklyOverlap(availability1, availability2)
+	if err != nil {
+		return contract.UserAvailabilityOverlap{}, err
+	}
+
+	resp := contract.UserAvailabilityOverlap{
+		Overlap: overlap,
+	}
+	if from.IsZero() || to.IsZero() {
+		return resp, nil
+	}
+
+	intervals1, err := user.calendar.availableIntervals(ctx, availability1, from, to)
+	if err != nil {
+		return contract.UserAvailabilityOverlap{}, err
+	}
+	intervals2, err := user.calendar.availableIntervals(ctx, availability2, from, to)
+	if err != nil {
+		return contract.UserAvailabilityOverlap{}, err
+	}
+	resp.Intervals = make([]contract.Interval, 0)
+	for _, i := range intersectIntervals(intervals1, intervals2) {
+		resp.Intervals = append(resp.Intervals, contract.Interval{StartTime: i.start, EndTime: i.end})
+	}
 
-	av1Map := availability1.GetAvailabilityMap()
-	av2Map := availability2.GetAvailabilityMap()
-
-	for day, availability := range av1Map {
-		if a, exists := av2Map[day]; exists {
-			// If there is an overlap between the 2 availabilities
-			if availability.StartTime <= a.EndTime && availability.EndTime >= a.StartTime {
-				overlappingAvailability := model.DayAvailability{Day: day}
-
-				// Start time will be the greater of the 2 starts
-				if availability.StartTime <= a.StartTime {
-					overlappingAvailability.StartTime = a.StartTime
-				} else {
-					overlappingAvailability.StartTime = availability.StartTime
-				}
-
-				// End time will be the lower of the 2 ends
-				if availability.EndTime <= a.EndTime {
-					overlappingAvailability.EndTime = availability.EndTime
-				} else {
-					overlappingAvailability.EndTime = a.EndTime
-				}
-
-				overlap = append(overlap, overlappingAvailability)
+	return resp, nil
+}
+
+// GetFreeOverlap returns the intervals between from and to during which all the given users are available
+// and not booked, leaving out intervals shorter than minDuration.
+func (user User) GetFreeOverlap(ctx context.Context, userIDs []int, from, to time.Time, minDuration time.Duration) (contract.AvailabilityOverlap, error) {
+	var common []interval
+	for i, userID := range userIDs {
+		availability, err := user.availabilityRepository.Get(ctx, userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return contract.AvailabilityOverlap{}, model.NotFound("availability", err)
 			}
+			return contract.AvailabilityOverlap{}, err
+		}
+		free, err := user.calendar.freeIntervals(ctx, availability, from, to)
+		if err != nil {
+			return contract.AvailabilityOverlap{}, err
+		}
+
+		if i == 0 {
+			common = free
+		} else {
+			common = intersectIntervals(common, free)
+		}
+		if len(common) == 0 {
+			break
 		}
 	}
 
-	return contract.UserAvailabilityOverlap{
-		Overlap: overlap,
-	}, nil
+	resp := contract.AvailabilityOverlap{
+		UserIDs:      userIDs,
+		From:         from,
+		To:           to,
+		DurationMins: int(minDuration / time.Minute),
+		Intervals:    make([]contract.Interval, 0),
+	}
+	for _, i := range filterIntervals(common, minDuration) {
+		resp.Intervals = append(resp.Intervals, contract.Interval{StartTime: i.start, EndTime: i.end})
+	}
+	return resp, nil
+}
+
+func (user User) CreateOverride(ctx context.Context, userID int, input contract.AvailabilityOverride) (contract.AvailabilityOverrideResponse, error) {
+	override := overrideFromContract(input)
+	override.UserID = uint(userID)
+	if err := user.checkOverrideOverlap(ctx, override); err != nil {
+		return contract.AvailabilityOverrideResponse{}, err
+	}
+
+	override, err := user.overrideRepository.Create(ctx, override)
+	if err != nil {
+		return contract.AvailabilityOverrideResponse{}, err
+	}
+
+	return overrideToContract(override), nil
+}
+
+func (user User) GetOverrides(ctx context.Context, userID int) (contract.AvailabilityOverrideList, error) {
+	overrides, err := user.overrideRepository.GetAll(ctx, userID)
+	if err != nil {
+		return contract.AvailabilityOverrideList{}, err
+	}
+
+	resp := make([]contract.AvailabilityOverrideResponse, 0)
+	for _, override := range overrides {
+		resp = append(resp, overrideToContract(override))
+	}
+
+	return contract.AvailabilityOverrideList{Overrides: resp}, nil
+}
+
+func (user User) GetOverride(ctx context.Context, userID, overrideID int) (contract.AvailabilityOverrideResponse, error) {
+	override, err := user.overrideRepository.GetByID(ctx, userID, overrideID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.AvailabilityOverrideResponse{}, model.NotFound("override", err)
+	}
+	if err != nil {
+		return contract.AvailabilityOverrideResponse{}, err
+	}
+
+	return overrideToContract(override), nil
+}
+
+func (user User) UpdateOverride(ctx context.Context, userID, overrideID int, input contract.AvailabilityOverride) (contract.AvailabilityOverrideResponse, error) {
+	override, err := user.overrideRepository.GetByID(ctx, userID, overrideID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.AvailabilityOverrideResponse{}, model.NotFound("override", err)
+	}
+	if err != nil {
+		return contract.AvailabilityOverrideResponse{}, err
+	}
+
+	updated := overrideFromContract(input)
+	updated.ID = override.ID
+	updated.UserID = override.UserID
+	updated.CreatedAt = override.CreatedAt
+	if err := user.checkOverrideOverlap(ctx, updated); err != nil {
+		return contract.AvailabilityOverrideResponse{}, err
+	}
+
+	updated, err = user.overrideRepository.Update(ctx, updated)
+	if err != nil {
+		return contract.AvailabilityOverrideResponse{}, err
+	}
+
+	return overrideToContract(updated), nil
+}
+
+func (user User) DeleteOverride(ctx context.Context, userID, overrideID int) error {
+	err := user.overrideRepository.Delete(ctx, userID, overrideID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("override", err)
+	}
+	return err
 }
 
-func NewUser(userRepository UserRepository, availabilityRepository UserAvailabilityRepository) User {
-	return User{userRepository: userRepository, availabilityRepository: availabilityRepository}
+// checkOverrideOverlap makes sure that a date is covered by at most one override
+func (user User) checkOverrideOverlap(ctx context.Context, override model.AvailabilityOverride) error {
+	existing, err := user.overrideRepository.GetInRange(ctx, int(override.UserID), time.Time(override.StartDate), time.Time(override.EndDate))
+	if err != nil {
+		return err
+	}
+	for _, e := range existing {
+		if e.ID != override.ID {
+			return model.ErrOverlappingOverride
+		}
+	}
+	return nil
+}
+
+func overrideFromContract(input contract.AvailabilityOverride) model.AvailabilityOverride {
+	// Dates are validated while binding the request
+	startDate, _ := time.Parse(contract.DateLayout, input.StartDate)
+	endDate, _ := time.Parse(contract.DateLayout, input.EndDate)
+	return model.AvailabilityOverride{
+		StartDate:    datatypes.Date(startDate),
+		EndDate:      datatypes.Date(endDate),
+		Unavailable:  input.Unavailable,
+		Availability: input.Availability,
+	}
+}
+
+func overrideToContract(override model.AvailabilityOverride) contract.AvailabilityOverrideResponse {
+	availability := override.Availability
+	if availability == nil {
+		availability = make([]model.Availability, 0)
+	}
+	return contract.AvailabilityOverrideResponse{
+		ID:           override.ID,
+		StartDate:    time.Time(override.StartDate).Format(contract.DateLayout),
+		EndDate:      time.Time(override.EndDate).Format(contract.DateLayout),
+		Unavailable:  override.Unavailable,
+		Availability: availability,
+	}
+}
+
+// weeklyOverlap returns the windows of the week during which both users are available as per their weekly
+// availability, in the time zone of the first one. The windows of both users are laid out on the dates of the current
+// week in their own time zones, so that users in different time zones only overlap when they are available at the
+// same instants.
+func (user User) weeklyOverlap(availability1, availability2 model.UserAvailability) ([]model.DayAvailability, error) {
+	loc, err := availability1.Location()
+	if err != nil {
+		return nil, err
+	}
+	now := user.now().In(loc)
+	// Weeks start on Monday
+	weekStart := time.Date(now.Year(), now.Month(), now.Day()-(int(now.Weekday())+6)%7, 0, 0, 0, 0, loc)
+	weekEnd := weekStart.AddDate(0, 0, 7)
+
+	intervals1, err := availableIntervals(availability1, nil, weekStart, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+	intervals2, err := availableIntervals(availability2, nil, weekStart, weekEnd)
+	if err != nil {
+		return nil, err
+	}
+
+	overlap := make([]model.DayAvailability, 0)
+	for _, i := range intersectIntervals(intervals1, intervals2) {
+		start, end := i.start.In(loc), i.end.In(loc)
+		window := model.DayAvailability{
+			Day:       model.GetDayFromInt(int(start.Weekday())),
+			StartTime: datatypes.NewTime(start.Hour(), start.Minute(), start.Second(), 0),
+			EndTime:   datatypes.NewTime(end.Hour(), end.Minute(), end.Second(), 0),
+		}
+		if end.Day() != start.Day() {
+			// The overlap lasts until the end of the day
+			window.EndTime = datatypes.NewTime(24, 0, 0, 0)
+		}
+		overlap = append(overlap, window)
+	}
+	return overlap, nil
+}
+
+func NewUser(userRepository UserRepository, availabilityRepository UserAvailabilityRepository, overrideRepository AvailabilityOverrideRepository, eventRepository EventRepository, busyBlockRepository BusyBlockRepository) User {
+	return User{
+		userRepository:         userRepository,
+		availabilityRepository: availabilityRepository,
+		overrideRepository:     overrideRepository,
+		calendar: calendar{
+			availabilityRepository: availabilityRepository,
+			overrideRepository:     overrideRepository,
+			eventRepository:        eventRepository,
+			busyBlockRepository:    busyBlockRepository,
+		},
+		now: time.Now,
+	}
 }
@@ -2,13 +2,18 @@ package service
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/harbor-xyz/coding-project/contract"
 	"github.com/harbor-xyz/coding-project/model"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/datatypes"
 )
@@ -18,16 +23,36 @@ type UserTestSuite struct {
 	service                        User
 	mockUserRepository             *MockUserRepository
 	mockUserAvailabilityRepository *MockUserAvailabilityRepository
+	mockOverrideRepository         *MockAvailabilityOverrideRepository
+	mockEventRepository            *MockEventRepository
+	mockBusyBlockRepository        *MockBusyBlockRepository
 	ctx                            context.Context
 }
 
 func (suite *UserTestSuite) SetupTest() {
 	suite.mockUserRepository = &MockUserRepository{}
 	suite.mockUserAvailabilityRepository = &MockUserAvailabilityRepository{}
-	suite.service = NewUser(suite.mockUserRepository, suite.mockUserAvailabilityRepository)
+	suite.mockOverrideRepository = &MockAvailabilityOverrideRepository{}
+	suite.mockEventRepository = &MockEventRepository{}
+	suite.mockBusyBlockRepository = &MockBusyBlockRepository{}
+	// Users have no external calendars unless a test says otherwise
+	suite.mockBusyBlockRepository.On("GetInRange", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return([]model.BusyBlock{}, nil).Maybe()
+	suite.service = NewUser(suite.mockUserRepository, suite.mockUserAvailabilityRepository, suite.mockOverrideRepository, suite.mockEventRepository,
+		suite.mockBusyBlockRepository)
 	suite.ctx = context.Background()
 }
 
+// newUser matches the user created for expected, which comes with a freshly generated API key
+func (suite *UserTestSuite) newUser(expected model.User) interface{} {
+	return mock.MatchedBy(func(obj model.User) bool {
+		if len(obj.APIKeys) != 1 || obj.APIKeys[0].Name != defaultAPIKeyName || obj.APIKeys[0].KeyHash == "" {
+			return false
+		}
+		obj.APIKeys = nil
+		return assert.ObjectsAreEqual(expected, obj)
+	})
+}
+
 func (suite *UserTestSuite) TestCreateHappyFlow() {
 	input := contract.User{
 		Name:  "test",
@@ -40,11 +65,123 @@ func (suite *UserTestSuite) TestCreateHappyFlow() {
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
-	suite.mockUserRepository.On("Create", suite.ctx, model.User{Name: "test", Email: "test@example.xyz"}).Return(expectedResp, nil)
+	expectedResp.Slug = "test"
+	suite.mockUserRepository.On("GetBySlug", suite.ctx, "test").Return(model.User{}, sql.ErrNoRows)
+	var created model.User
+	suite.mockUserRepository.On("Create", suite.ctx, suite.newUser(model.User{Name: "test", Email: "test@example.xyz", TimeZone: "UTC", Slug: "test"})).
+		Run(func(args mock.Arguments) { created = args.Get(1).(model.User) }).Return(expectedResp, nil)
 
 	resp, err := suite.service.Create(suite.ctx, input)
 	suite.Nil(err)
-	suite.Equal(contract.UserResponse{ID: 1}, resp)
+	suite.Equal(uint(1), resp.ID)
+	suite.Equal("test", resp.Slug)
+	suite.True(strings.HasPrefix(resp.APIKey, apiKeyPrefix))
+	suite.Equal(hashAPIKey(resp.APIKey), created.APIKeys[0].KeyHash)
+	suite.Equal(resp.APIKey[:apiKeyPrefixLength], created.APIKeys[0].Prefix)
+}
+
+func (suite *UserTestSuite) TestCreateNumbersSlugDerivedFromNameIfTaken() {
+	suite.mockUserRepository.On("GetBySlug", suite.ctx, "jane-doe").Return(model.User{ID: 2}, nil)
+	suite.mockUserRepository.On("GetBySlug", suite.ctx, "jane-doe-2").Return(model.User{ID: 3}, nil)
+	suite.mockUserRepository.On("GetBySlug", suite.ctx, "jane-doe-3").Return(model.User{}, sql.ErrNoRows)
+	suite.mockUserRepository.On("Create", suite.ctx, suite.newUser(model.User{Name: "Jane Doe!", Email: "jane@example.xyz", TimeZone: "UTC",
+		Slug: "jane-doe-3"})).Return(model.User{ID: 4, Slug: "jane-doe-3"}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, contract.User{Name: "Jane Doe!", Email: "jane@example.xyz"})
+	suite.NoError(err)
+	suite.Equal("jane-doe-3", resp.Slug)
+}
+
+func (suite *UserTestSuite) TestCreateReturnsErrorIfGivenSlugIsTaken() {
+	suite.mockUserRepository.On("GetBySlug", suite.ctx, "jane").Return(model.User{ID: 2}, nil)
+
+	_, err := suite.service.Create(suite.ctx, contract.User{Name: "Jane", Email: "jane@example.xyz", Slug: "jane"})
+	suite.ErrorIs(err, model.ErrDuplicateSlug)
+	suite.mockUserRepository.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *UserTestSuite) TestGetReturnsNotFoundIfUserDoesNotExist() {
+	suite.mockUserRepository.On("GetByID", suite.ctx, 2).Return(model.User{}, sql.ErrNoRows)
+
+	_, err := suite.service.Get(suite.ctx, 2)
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.EqualError(err, "user not found: sql: no rows in result set")
+}
+
+func (suite *UserTestSuite) TestGetAllReturnsPageOfUsers() {
+	createdAt := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.mockUserRepository.On("GetAll", suite.ctx, "example", 20, 0).Return([]model.User{
+		{ID: 1, Name: "Jane", Email: "jane@example.xyz", TimeZone: "UTC", Slug: "jane", FeedTokenHash: "hash", CreatedAt: createdAt},
+	}, int64(21), nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, "example", 20, 0)
+	suite.NoError(err)
+	suite.Equal(contract.UserList{Users: []contract.UserResponse{
+		{ID: 1, Name: "Jane", Email: "jane@example.xyz", TimeZone: "UTC", Slug: "jane", CreatedAt: createdAt},
+	}, Total: 21}, resp)
+}
+
+func (suite *UserTestSuite) TestUpdateOnlyChangesGivenFields() {
+	name := "Jane Doe"
+	timeZone := "Asia/Kolkata"
+	suite.mockUserRepository.On("GetByID", suite.ctx, 1).Return(model.User{ID: 1, Name: "Jane", Email: "jane@example.xyz", TimeZone: "UTC",
+		Slug: "jane"}, nil)
+	suite.mockUserRepository.On("Update", suite.ctx, model.User{ID: 1, Name: "Jane Doe", Email: "jane@example.xyz", TimeZone: "Asia/Kolkata",
+		Slug: "jane"}).Return(model.User{ID: 1, Name: "Jane Doe", Email: "jane@example.xyz", TimeZone: "Asia/Kolkata", Slug: "jane"}, nil)
+
+	resp, err := suite.service.Update(suite.ctx, 1, contract.UserUpdate{Name: &name, TimeZone: &timeZone})
+	suite.NoError(err)
+	suite.Equal(contract.UserResponse{ID: 1, Name: "Jane Doe", Email: "jane@example.xyz", TimeZone: "Asia/Kolkata", Slug: "jane"}, resp)
+	suite.mockUserRepository.AssertNotCalled(suite.T(), "GetBySlug", mock.Anything, mock.Anything)
+}
+
+func (suite *UserTestSuite) TestUpdateReturnsErrorIfSlugIsTaken() {
+	slug := "john"
+	suite.mockUserRepository.On("GetByID", suite.ctx, 1).Return(model.User{ID: 1, Name: "Jane", Slug: "jane"}, nil)
+	suite.mockUserRepository.On("GetBySlug", suite.ctx, "john").Return(model.User{ID: 2}, nil)
+
+	_, err := suite.service.Update(suite.ctx, 1, contract.UserUpdate{Slug: &slug})
+	suite.ErrorIs(err, model.ErrDuplicateSlug)
+	suite.mockUserRepository.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
+}
+
+func (suite *UserTestSuite) TestUpdateReturnsErrorIfEmailIsTaken() {
+	email := "john@example.xyz"
+	suite.mockUserRepository.On("GetByID", suite.ctx, 1).Return(model.User{ID: 1, Name: "Jane", Email: "jane@example.xyz"}, nil)
+	suite.mockUserRepository.On("Update", suite.ctx, model.User{ID: 1, Name: "Jane", Email: "john@example.xyz"}).Return(model.User{}, model.ErrDuplicateEmail)
+
+	_, err := suite.service.Update(suite.ctx, 1, contract.UserUpdate{Email: &email})
+	suite.ErrorIs(err, model.ErrDuplicateEmail)
+}
+
+func (suite *UserTestSuite) TestDeleteReturnsNotFoundIfUserDoesNotExist() {
+	suite.mockUserRepository.On("Delete", suite.ctx, 2).Return(sql.ErrNoRows)
+
+	err := suite.service.Delete(suite.ctx, 2)
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.EqualError(err, "user not found: sql: no rows in result set")
+}
+
+func (suite *UserTestSuite) TestSetSlugKeepsTheUsersOwnSlug() {
+	suite.mockUserRepository.On("GetBySlug", suite.ctx, "jane").Return(model.User{ID: 1}, nil)
+	suite.mockUserRepository.On("SetSlug", suite.ctx, 1, "jane").Return(nil)
+
+	resp, err := suite.service.SetSlug(suite.ctx, 1, contract.UserSlug{Slug: "jane"})
+	suite.NoError(err)
+	suite.Equal(contract.UserSlug{Slug: "jane"}, resp)
+}
+
+func (suite *UserTestSuite) TestSetSlugReturnsErrorIfAnotherUserHasIt() {
+	suite.mockUserRepository.On("GetBySlug", suite.ctx, "jane").Return(model.User{ID: 2}, nil)
+
+	_, err := suite.service.SetSlug(suite.ctx, 1, contract.UserSlug{Slug: "jane"})
+	suite.ErrorIs(err, model.ErrDuplicateSlug)
+	suite.mockUserRepository.AssertNotCalled(suite.T(), "SetSlug", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *UserTestSuite) TestSlugifyFallsBackToUserForNamesWithoutLatinCharacters() {
+	suite.Equal("mary-ann-o-brien", slugify("  Mary-Ann O'Brien "))
+	suite.Equal("user", slugify("李雷"))
 }
 
 func (suite *UserTestSuite) TestCreateShouldReturnErrorIfRepositoryFails() {
@@ -52,7 +189,9 @@ func (suite *UserTestSuite) TestCreateShouldReturnErrorIfRepositoryFails() {
 		Name:  "test",
 		Email: "test@example.xyz",
 	}
-	suite.mockUserRepository.On("Create", suite.ctx, model.User{Name: "test", Email: "test@example.xyz"}).Return(model.User{}, errors.New("some error"))
+	suite.mockUserRepository.On("GetBySlug", suite.ctx, "test").Return(model.User{}, sql.ErrNoRows)
+	suite.mockUserRepository.On("Create", suite.ctx, suite.newUser(model.User{Name: "test", Email: "test@example.xyz", TimeZone: "UTC", Slug: "test"})).
+		Return(model.User{}, errors.New("some error"))
 
 	resp, err := suite.service.Create(suite.ctx, input)
 	suite.Equal("some error", err.Error())
@@ -75,16 +214,18 @@ func (suite *UserTestSuite) TestSetAvailabilityHappyFlow() {
 	input := contract.UserAvailability{
 		Availability:        availability,
 		MeetingDurationMins: 30,
+		TimeZone:            "Europe/Berlin",
 	}
 	expectedResp := model.UserAvailability{
 		UserID:              1,
 		Availability:        availability,
 		MeetingDurationMins: 30,
+		TimeZone:            "Europe/Berlin",
 		CreatedAt:           time.Now(),
 		UpdatedAt:           time.Now(),
 	}
 	suite.mockUserAvailabilityRepository.On("Set", suite.ctx, model.UserAvailability{
-		UserID: 1, Availability: availability, MeetingDurationMins: 30,
+		UserID: 1, Availability: availability, MeetingDurationMins: 30, TimeZone: "Europe/Berlin",
 	}).Return(expectedResp, nil)
 
 	resp, err := suite.service.SetAvailability(suite.ctx, 1, input)
@@ -92,6 +233,50 @@ func (suite *UserTestSuite) TestSetAvailabilityHappyFlow() {
 	suite.Equal(expectedResp, resp)
 }
 
+func (suite *UserTestSuite) TestSetAvailabilityEnqueuesSlotSyncAndWebhook() {
+	saved := model.UserAvailability{UserID: 1, MeetingDurationMins: 30, TimeZone: "Europe/Berlin", Availability: []model.DayAvailability{
+		{Day: "monday", StartTime: datatypes.NewTime(10, 0, 0, 0), EndTime: datatypes.NewTime(17, 0, 0, 0)},
+	}}
+	suite.mockUserAvailabilityRepository.On("Set", suite.ctx, mock.Anything).Return(saved, nil)
+
+	_, err := suite.service.SetAvailability(suite.ctx, 1, contract.UserAvailability{Availability: saved.Availability, MeetingDurationMins: 30,
+		TimeZone: "Europe/Berlin"})
+	suite.NoError(err)
+	jobs := suite.mockUserAvailabilityRepository.Jobs
+	suite.Len(jobs, 2)
+	suite.Equal(jobSyncSlots, jobs[0].Kind)
+	suite.JSONEq(`{"user_id":1}`, string(jobs[0].Payload))
+	suite.Equal(jobPublishWebhook, jobs[1].Kind)
+	published := webhookJob{}
+	suite.NoError(json.Unmarshal(jobs[1].Payload, &published))
+	suite.Equal(model.WebhookAvailabilityUpdated, published.Event)
+	suite.Contains(string(published.Data), `"meeting_duration_mins":30`)
+}
+
+func (suite *UserTestSuite) TestSetAvailabilityDefaultsToUserTimeZone() {
+	availability := []model.DayAvailability{
+		{
+			Day:       "monday",
+			StartTime: datatypes.NewTime(10, 0, 0, 0),
+			EndTime:   datatypes.NewTime(17, 0, 0, 0),
+		},
+	}
+	input := contract.UserAvailability{
+		Availability:        availability,
+		MeetingDurationMins: 30,
+	}
+	expected := model.UserAvailability{
+		UserID: 1, Availability: availability, MeetingDurationMins: 30, TimeZone: "America/New_York",
+	}
+	suite.mockUserRepository.On("GetByID", suite.ctx, 1).Return(model.User{ID: 1, TimeZone: "America/New_York"}, nil)
+	suite.mockUserAvailabilityRepository.On("Set", suite.ctx, expected).Return(expected, nil)
+
+	resp, err := suite.service.SetAvailability(suite.ctx, 1, input)
+	suite.Nil(err)
+	suite.Equal("America/New_York", resp.TimeZone)
+	suite.mockUserAvailabilityRepository.AssertExpectations(suite.T())
+}
+
 func (suite *UserTestSuite) TestSetAvailabilityShouldReturnErrorIfRepositoryFails() {
 	availability := []model.DayAvailability{
 		{
@@ -108,10 +293,11 @@ func (suite *UserTestSuite) TestSetAvailabilityShouldReturnErrorIfRepositoryFail
 	input := contract.UserAvailability{
 		Availability:        availability,
 		MeetingDurationMins: 30,
+		TimeZone:            "Europe/Berlin",
 	}
 
 	suite.mockUserAvailabilityRepository.On("Set", suite.ctx, model.UserAvailability{
-		UserID: 1, Availability: availability, MeetingDurationMins: 30,
+		UserID: 1, Availability: availability, MeetingDurationMins: 30, TimeZone: "Europe/Berlin",
 	}).Return(model.UserAvailability{}, errors.New("some error"))
 
 	resp, err := suite.service.SetAvailability(suite.ctx, 1, input)
@@ -207,10 +393,66 @@ func (suite *UserTestSuite) TestGerAvailabilityOverlapReturnsOverlapIfItExists()
 			},
 		},
 	}
-	resp, err := suite.service.GetAvailabilityOverlap(suite.ctx, 1, 2)
+	resp, err := suite.service.GetAvailabilityOverlap(suite.ctx, 1, 2, time.Time{}, time.Time{})
 	suite.Nil(err)
 	suite.Equal(2, len(resp.Overlap))
-	suite.Equal(expectedResp, resp, "This is an undeterministic test, please rerun") // TODO: This is a bit undeterministic due to order of the slice.
+	suite.Equal(expectedResp, resp)
+}
+
+func (suite *UserTestSuite) TestGerAvailabilityOverlapHonoursEveryWindowOfADay() {
+	availability1 := []model.DayAvailability{
+		{
+			Day:       "monday",
+			StartTime: datatypes.NewTime(14, 0, 0, 0),
+			EndTime:   datatypes.NewTime(18, 0, 0, 0),
+		},
+		{
+			Day:       "monday",
+			StartTime: datatypes.NewTime(9, 0, 0, 0),
+			EndTime:   datatypes.NewTime(12, 0, 0, 0),
+		},
+	}
+	availability2 := []model.DayAvailability{
+		{
+			Day:       "monday",
+			StartTime: datatypes.NewTime(11, 0, 0, 0),
+			EndTime:   datatypes.NewTime(15, 0, 0, 0),
+		},
+		{
+			Day:       "monday",
+			StartTime: datatypes.NewTime(17, 0, 0, 0),
+			EndTime:   datatypes.NewTime(19, 0, 0, 0),
+		},
+	}
+
+	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1, Availability: availability1, MeetingDurationMins: 30}, nil)
+	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 2).Return(model.UserAvailability{UserID: 2, Availability: availability2, MeetingDurationMins: 30}, nil)
+
+	resp, err := suite.service.GetAvailabilityOverlap(suite.ctx, 1, 2, time.Time{}, time.Time{})
+	suite.Nil(err)
+	suite.Equal([]model.DayAvailability{
+		{Day: "monday", StartTime: datatypes.NewTime(11, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		{Day: "monday", StartTime: datatypes.NewTime(14, 0, 0, 0), EndTime: datatypes.NewTime(15, 0, 0, 0)},
+		{Day: "monday", StartTime: datatypes.NewTime(17, 0, 0, 0), EndTime: datatypes.NewTime(18, 0, 0, 0)},
+	}, resp.Overlap)
+}
+
+func (suite *UserTestSuite) TestGetAvailabilityOverlapComparesUsersInDifferentTimeZonesAtTheSameInstants() {
+	suite.service.now = func() time.Time { return time.Date(2023, 6, 7, 12, 0, 0, 0, time.UTC) }
+	workday := []model.DayAvailability{
+		{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(17, 0, 0, 0)},
+	}
+	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1, Availability: workday,
+		MeetingDurationMins: 30, TimeZone: "America/New_York"}, nil)
+	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 2).Return(model.UserAvailability{UserID: 2, Availability: workday,
+		MeetingDurationMins: 30, TimeZone: "Europe/Berlin"}, nil)
+
+	resp, err := suite.service.GetAvailabilityOverlap(suite.ctx, 1, 2, time.Time{}, time.Time{})
+	suite.NoError(err)
+	// 17:00 in Berlin is 11:00 in New York, 6 hours behind in summer
+	suite.Equal([]model.DayAvailability{
+		{Day: "monday", StartTime: datatypes.NewTime(9, 0, 0, 0), EndTime: datatypes.NewTime(11, 0, 0, 0)},
+	}, resp.Overlap)
 }
 
 func (suite *UserTestSuite) TestGerAvailabilityOverlapReturnsNoOverlapIfItDoesNotExist() {
@@ -252,7 +494,7 @@ func (suite *UserTestSuite) TestGerAvailabilityOverlapReturnsNoOverlapIfItDoesNo
 	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1, Availability: availability1, MeetingDurationMins: 30}, nil)
 	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 2).Return(model.UserAvailability{UserID: 2, Availability: availability2, MeetingDurationMins: 30}, nil)
 
-	resp, err := suite.service.GetAvailabilityOverlap(suite.ctx, 1, 2)
+	resp, err := suite.service.GetAvailabilityOverlap(suite.ctx, 1, 2, time.Time{}, time.Time{})
 	suite.Nil(err)
 	suite.Equal(0, len(resp.Overlap))
 }
@@ -279,11 +521,191 @@ func (suite *UserTestSuite) TestGerAvailabilityOverlapReturnsErrorIfRepositoryRe
 	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1, Availability: availability1, MeetingDurationMins: 30}, nil)
 	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 2).Return(model.UserAvailability{}, errors.New("some error"))
 
-	resp, err := suite.service.GetAvailabilityOverlap(suite.ctx, 1, 2)
+	resp, err := suite.service.GetAvailabilityOverlap(suite.ctx, 1, 2, time.Time{}, time.Time{})
 	suite.Equal("some error", err.Error())
 	suite.Equal(0, len(resp.Overlap))
 }
 
+func (suite *UserTestSuite) TestGetAvailabilityOverlapReturnsIntervalsRespectingOverrides() {
+	availability1 := []model.DayAvailability{
+		{
+			Day:       "monday",
+			StartTime: datatypes.NewTime(9, 0, 0, 0),
+			EndTime:   datatypes.NewTime(17, 0, 0, 0),
+		},
+		{
+			Day:       "tuesday",
+			StartTime: datatypes.NewTime(9, 0, 0, 0),
+			EndTime:   datatypes.NewTime(17, 0, 0, 0),
+		},
+	}
+	availability2 := []model.DayAvailability{
+		{
+			Day:       "monday",
+			StartTime: datatypes.NewTime(9, 0, 0, 0),
+			EndTime:   datatypes.NewTime(12, 0, 0, 0),
+		},
+		{
+			Day:       "tuesday",
+			StartTime: datatypes.NewTime(9, 0, 0, 0),
+			EndTime:   datatypes.NewTime(12, 0, 0, 0),
+		},
+	}
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC) // monday
+	to := time.Date(2023, 6, 7, 0, 0, 0, 0, time.UTC)
+
+	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1, Availability: availability1, MeetingDurationMins: 30}, nil)
+	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 2).Return(model.UserAvailability{UserID: 2, Availability: availability2, MeetingDurationMins: 30, TimeZone: "Europe/Berlin"}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{
+		{
+			UserID:      1,
+			StartDate:   datatypes.Date(time.Date(2023, 6, 6, 0, 0, 0, 0, time.UTC)),
+			EndDate:     datatypes.Date(time.Date(2023, 6, 6, 0, 0, 0, 0, time.UTC)),
+			Unavailable: true,
+		},
+	}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 2, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+
+	resp, err := suite.service.GetAvailabilityOverlap(suite.ctx, 1, 2, from, to)
+	suite.Nil(err)
+	// Berlin is 2 hours ahead of UTC in june and tuesday is blacked out for the first user
+	suite.Equal([]contract.Interval{
+		{StartTime: time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC), EndTime: time.Date(2023, 6, 5, 10, 0, 0, 0, time.UTC)},
+	}, toUTC(resp.Intervals))
+}
+
+func (suite *UserTestSuite) TestGetFreeOverlapReturnsTimeFreeForEveryUser() {
+	weekdays := func(start, end datatypes.Time) []model.DayAvailability {
+		availability := make([]model.DayAvailability, 0)
+		for _, day := range []model.Day{model.Monday, model.Tuesday} {
+			availability = append(availability, model.DayAvailability{Day: day, StartTime: start, EndTime: end})
+		}
+		return availability
+	}
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC) // monday
+	to := time.Date(2023, 6, 7, 0, 0, 0, 0, time.UTC)
+	monday := func(hour, min int) time.Time { return time.Date(2023, 6, 5, hour, min, 0, 0, time.UTC) }
+	tuesday := func(hour, min int) time.Time { return time.Date(2023, 6, 6, hour, min, 0, 0, time.UTC) }
+
+	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{UserID: 1, Availability: weekdays(datatypes.NewTime(9, 0, 0, 0), datatypes.NewTime(17, 0, 0, 0))}, nil)
+	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 2).Return(model.UserAvailability{UserID: 2, Availability: weekdays(datatypes.NewTime(10, 0, 0, 0), datatypes.NewTime(12, 0, 0, 0))}, nil)
+	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 3).Return(model.UserAvailability{UserID: 3, Availability: weekdays(datatypes.NewTime(8, 0, 0, 0), datatypes.NewTime(18, 0, 0, 0))}, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, mock.Anything, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 1, from, to).Return([]model.Event{
+		{UserID: 1, StartTime: monday(10, 30), EndTime: monday(11, 0)},
+	}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 2, from, to).Return([]model.Event{}, nil)
+	suite.mockEventRepository.On("GetInRange", suite.ctx, 3, from, to).Return([]model.Event{
+		{UserID: 3, StartTime: monday(11, 45), EndTime: monday(13, 0)},
+		{UserID: 3, StartTime: tuesday(9, 0), EndTime: tuesday(10, 15)},
+	}, nil)
+
+	resp, err := suite.service.GetFreeOverlap(suite.ctx, []int{1, 2, 3}, from, to, 40*time.Minute)
+	suite.Nil(err)
+	suite.Equal(40, resp.DurationMins)
+	// Bookings split monday's common window, leaving a 30 minute gap before the first one which is too short
+	suite.Equal([]contract.Interval{
+		{StartTime: monday(11, 0), EndTime: monday(11, 45)},
+		{StartTime: tuesday(10, 15), EndTime: tuesday(12, 0)},
+	}, toUTC(resp.Intervals))
+}
+
+func (suite *UserTestSuite) TestGetFreeOverlapReturnsNotFoundIfAUserHasNoAvailability() {
+	from := time.Date(2023, 6, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 6, 7, 0, 0, 0, 0, time.UTC)
+	suite.mockUserAvailabilityRepository.On("Get", suite.ctx, 1).Return(model.UserAvailability{}, sql.ErrNoRows)
+
+	resp, err := suite.service.GetFreeOverlap(suite.ctx, []int{1, 2}, from, to, 0)
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.Equal("availability not found: sql: no rows in result set", err.Error())
+	suite.Empty(resp)
+}
+
+func (suite *UserTestSuite) TestCreateOverrideHappyFlow() {
+	input := contract.AvailabilityOverride{
+		StartDate:   "2023-12-24",
+		EndDate:     "2023-12-26",
+		Unavailable: true,
+	}
+	override := model.AvailabilityOverride{
+		UserID:      1,
+		StartDate:   datatypes.Date(time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC)),
+		EndDate:     datatypes.Date(time.Date(2023, 12, 26, 0, 0, 0, 0, time.UTC)),
+		Unavailable: true,
+	}
+	created := override
+	created.ID = 1
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, time.Time(override.StartDate), time.Time(override.EndDate)).Return([]model.AvailabilityOverride{}, nil)
+	suite.mockOverrideRepository.On("Create", suite.ctx, override).Return(created, nil)
+
+	resp, err := suite.service.CreateOverride(suite.ctx, 1, input)
+	suite.Nil(err)
+	suite.Equal(contract.AvailabilityOverrideResponse{
+		ID:           1,
+		StartDate:    "2023-12-24",
+		EndDate:      "2023-12-26",
+		Unavailable:  true,
+		Availability: []model.Availability{},
+	}, resp)
+}
+
+func (suite *UserTestSuite) TestCreateOverrideReturnsErrorIfItOverlapsAnExistingOverride() {
+	input := contract.AvailabilityOverride{
+		StartDate:   "2023-12-24",
+		EndDate:     "2023-12-26",
+		Unavailable: true,
+	}
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{{ID: 3, UserID: 1}}, nil)
+
+	resp, err := suite.service.CreateOverride(suite.ctx, 1, input)
+	suite.Equal(model.ErrOverlappingOverride, err)
+	suite.Empty(resp)
+	suite.mockOverrideRepository.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *UserTestSuite) TestUpdateOverrideIgnoresTheOverrideBeingUpdatedWhenCheckingOverlaps() {
+	input := contract.AvailabilityOverride{
+		StartDate: "2023-12-24",
+		EndDate:   "2023-12-24",
+		Availability: []model.Availability{
+			{StartTime: datatypes.NewTime(10, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+	}
+	existing := model.AvailabilityOverride{
+		ID:          3,
+		UserID:      1,
+		StartDate:   datatypes.Date(time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC)),
+		EndDate:     datatypes.Date(time.Date(2023, 12, 26, 0, 0, 0, 0, time.UTC)),
+		Unavailable: true,
+	}
+	suite.mockOverrideRepository.On("GetByID", suite.ctx, 1, 3).Return(existing, nil)
+	suite.mockOverrideRepository.On("GetInRange", suite.ctx, 1, mock.Anything, mock.Anything).Return([]model.AvailabilityOverride{existing}, nil)
+	updated := model.AvailabilityOverride{
+		ID:        3,
+		UserID:    1,
+		StartDate: datatypes.Date(time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC)),
+		EndDate:   datatypes.Date(time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC)),
+		Availability: []model.Availability{
+			{StartTime: datatypes.NewTime(10, 0, 0, 0), EndTime: datatypes.NewTime(12, 0, 0, 0)},
+		},
+	}
+	suite.mockOverrideRepository.On("Update", suite.ctx, updated).Return(updated, nil)
+
+	resp, err := suite.service.UpdateOverride(suite.ctx, 1, 3, input)
+	suite.Nil(err)
+	suite.Equal(uint(3), resp.ID)
+	suite.Equal("2023-12-24", resp.EndDate)
+	suite.False(resp.Unavailable)
+}
+
+func toUTC(intervals []contract.Interval) []contract.Interval {
+	for i := range intervals {
+		intervals[i].StartTime = intervals[i].StartTime.UTC()
+		intervals[i].EndTime = intervals[i].EndTime.UTC()
+	}
+	return intervals
+}
+
 func TestUserTestSuite(t *testing.T) {
 	suite.Run(t, new(UserTestSuite))
 }
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	// maxDeliveryAttempts is how often a delivery is attempted before it is marked as failed. With the delay doubling
+	// after every attempt, the last one is made about 4 hours after the first.
+	maxDeliveryAttempts = 10
+	retryBaseDelay      = 30 * time.Second
+	deliveryTimeout     = 10 * time.Second
+	// deliveryBatchSize bounds the deliveries attempted on each run of the dispatcher
+	deliveryBatchSize = 100
+	// deliveryLogSize is how many of the latest deliveries of a webhook are returned
+	deliveryLogSize = 100
+)
+
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+type Webhook struct {
+	webhookRepository WebhookRepository
+	client            *http.Client
+	now               func() time.Time
+}
+
+// Create subscribes a URL to events of the user. A secret is generated unless one is given, and is only returned
+// here.
+func (webhook Webhook) Create(ctx context.Context, userID int, input contract.Webhook) (contract.WebhookResponse, error) {
+	obj := model.Webhook{
+		UserID: uint(userID),
+		URL:    input.URL,
+		Secret: input.Secret,
+		Events: datatypes.JSONSlice[string](input.Events),
+	}
+	if obj.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return contract.WebhookResponse{}, err
+		}
+		obj.Secret = hex.EncodeToString(secret)
+	}
+
+	obj, err := webhook.webhookRepository.Create(ctx, obj)
+	if err != nil {
+		return contract.WebhookResponse{}, err
+	}
+
+	resp := toWebhookContract(obj)
+	resp.Secret = obj.Secret
+	return resp, nil
+}
+
+func (webhook Webhook) GetAll(ctx context.Context, userID int) (contract.WebhookList, error) {
+	webhooks, err := webhook.webhookRepository.GetAll(ctx, userID)
+	if err != nil {
+		return contract.WebhookList{}, err
+	}
+
+	resp := make([]contract.WebhookResponse, 0)
+	for _, obj := range webhooks {
+		resp = append(resp, toWebhookContract(obj))
+	}
+
+	return contract.WebhookList{Webhooks: resp}, nil
+}
+
+func (webhook Webhook) Delete(ctx context.Context, userID, webhookID int) error {
+	err := webhook.webhookRepository.Delete(ctx, userID, webhookID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return model.NotFound("webhook", err)
+	}
+	return err
+}
+
+// GetDeliveries returns the latest deliveries of the webhook, newest first.
+func (webhook Webhook) GetDeliveries(ctx context.Context, userID, webhookID int) (contract.WebhookDeliveryList, error) {
+	_, err := webhook.webhookRepository.GetByID(ctx, userID, webhookID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return contract.WebhookDeliveryList{}, model.NotFound("webhook", err)
+	}
+	if err != nil {
+		return contract.WebhookDeliveryList{}, err
+	}
+
+	deliveries, err := webhook.webhookRepository.GetDeliveries(ctx, webhookID, deliveryLogSize)
+	if err != nil {
+		return contract.WebhookDeliveryList{}, err
+	}
+
+	resp := make([]contract.WebhookDelivery, 0)
+	for _, obj := range deliveries {
+		resp = append(resp, toWebhookDeliveryContract(obj))
+	}
+
+	return contract.WebhookDeliveryList{Deliveries: resp}, nil
+}
+
+// DeliverDue attempts the pending deliveries which are due and returns the number of them which succeeded. Failed
+// attempts are retried with exponential backoff until maxDeliveryAttempts is reached.
+func (webhook Webhook) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := webhook.webhookRepository.GetDueDeliveries(ctx, webhook.now(), deliveryBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, obj := range deliveries {
+		obj = webhook.attempt(ctx, obj)
+		if err := webhook.webhookRepository.UpdateDelivery(ctx, obj); err != nil {
+			// The delivery stays due, so it is attempted again on the next run
+			continue
+		}
+		if obj.Status == model.DeliverySucceeded {
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// attempt POSTs the payload of the delivery to its webhook and records the outcome on the delivery.
+func (webhook Webhook) attempt(ctx context.Context, obj model.WebhookDelivery) model.WebhookDelivery {
+	now := webhook.now()
+	obj.Attempts++
+	obj.ResponseStatus, obj.Error = 0, ""
+
+	statusCode, err := webhook.post(ctx, obj)
+	obj.ResponseStatus = statusCode
+	if err == nil {
+		obj.Status = model.DeliverySucceeded
+		obj.DeliveredAt = now
+		return obj
+	}
+
+	obj.Error = err.Error()
+	if obj.Attempts >= maxDeliveryAttempts {
+		obj.Status = model.DeliveryFailed
+		log.Printf("giving up on webhook delivery %d after %d attempts: %s", obj.ID, obj.Attempts, obj.Error)
+		return obj
+	}
+	obj.NextAttemptAt = now.Add(retryBaseDelay << (obj.Attempts - 1))
+	return obj
+}
+
+func (webhook Webhook) post(ctx context.Context, obj model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, obj.Webhook.URL, bytes.NewReader(obj.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookEventHeader, obj.Event)
+	req.Header.Set(WebhookDeliveryHeader, strconv.Itoa(int(obj.ID)))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(obj.Webhook.Secret, webhook.now(), obj.Payload))
+
+	resp, err := webhook.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain the body so that the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload returns the signature header of a payload delivered at timestamp. It carries the Unix timestamp
+// along with the hex encoded HMAC-SHA256 of "<timestamp>.<payload>" keyed with the webhook secret, so that
+// receivers can reject replayed deliveries.
+func SignWebhookPayload(secret string, timestamp time.Time, payload []byte) string {
+	unix := strconv.FormatInt(timestamp.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unix + "."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%s,v1=%s", unix, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// webhooks queues events of users for delivery to the webhooks subscribed to them.
+type webhooks struct {
+	webhookRepository WebhookRepository
+	now               func() time.Time
+}
+
+// enqueue queues the event, which occurred at occurredAt, for delivery to the user's webhooks subscribed to it.
+func (webhooks webhooks) enqueue(ctx context.Context, userID uint, event string, occurredAt time.Time, data interface{}) error {
+	subscribed, err := webhooks.webhookRepository.GetAll(ctx, int(userID))
+	if err != nil {
+		return err
+	}
+
+	now := webhooks.now()
+	var payload []byte
+	deliveries := make([]model.WebhookDelivery, 0)
+	for _, obj := range subscribed {
+		if !obj.Subscribes(event) {
+			continue
+		}
+		if payload == nil {
+			payload, err = json.Marshal(contract.WebhookPayload{Event: event, OccurredAt: occurredAt, Data: data})
+			if err != nil {
+				return err
+			}
+		}
+		deliveries = append(deliveries, model.WebhookDelivery{
+			WebhookID:     obj.ID,
+			Event:         event,
+			Payload:       datatypes.JSON(payload),
+			Status:        model.DeliveryPending,
+			NextAttemptAt: now,
+		})
+	}
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return webhooks.webhookRepository.CreateDeliveries(ctx, deliveries)
+}
+
+func newWebhooks(webhookRepository WebhookRepository) webhooks {
+	return webhooks{webhookRepository: webhookRepository, now: time.Now}
+}
+
+func toWebhookContract(obj model.Webhook) contract.WebhookResponse {
+	return contract.WebhookResponse{
+		ID:        obj.ID,
+		UserID:    obj.UserID,
+		URL:       obj.URL,
+		Events:    obj.Events,
+		CreatedAt: obj.CreatedAt,
+	}
+}
+
+func toWebhookDeliveryContract(obj model.WebhookDelivery) contract.WebhookDelivery {
+	resp := contract.WebhookDelivery{
+		ID:             obj.ID,
+		WebhookID:      obj.WebhookID,
+		Event:          obj.Event,
+		Status:         obj.Status.String(),
+		Attempts:       obj.Attempts,
+		ResponseStatus: obj.ResponseStatus,
+		Error:          obj.Error,
+		CreatedAt:      obj.CreatedAt,
+		Payload:        json.RawMessage(obj.Payload),
+	}
+	if obj.Status == model.DeliveryPending {
+		nextAttemptAt := obj.NextAttemptAt
+		resp.NextAttemptAt = &nextAttemptAt
+	}
+	if obj.Status == model.DeliverySucceeded {
+		deliveredAt := obj.DeliveredAt
+		resp.DeliveredAt = &deliveredAt
+	}
+	return resp
+}
+
+func NewWebhook(webhookRepository WebhookRepository) Webhook {
+	return Webhook{
+		webhookRepository: webhookRepository,
+		client:            &http.Client{Timeout: deliveryTimeout},
+		now:               time.Now,
+	}
+}
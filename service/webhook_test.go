@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/contract"
+	"github.com/harbor-xyz/coding-project/model"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/datatypes"
+)
+
+type WebhookTestSuite struct {
+	suite.Suite
+	service               Webhook
+	mockWebhookRepository *MockWebhookRepository
+	server                *httptest.Server
+	received              []*http.Request
+	receivedBodies        [][]byte
+	responseStatus        int
+	ctx                   context.Context
+	now                   time.Time
+}
+
+func (suite *WebhookTestSuite) SetupTest() {
+	suite.mockWebhookRepository = &MockWebhookRepository{}
+	suite.received, suite.receivedBodies = nil, nil
+	suite.responseStatus = http.StatusOK
+	suite.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		suite.received = append(suite.received, r)
+		suite.receivedBodies = append(suite.receivedBodies, body)
+		w.WriteHeader(suite.responseStatus)
+	}))
+	suite.now = time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	suite.service = NewWebhook(suite.mockWebhookRepository)
+	suite.service.now = func() time.Time { return suite.now }
+	suite.ctx = context.Background()
+}
+
+func (suite *WebhookTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+func (suite *WebhookTestSuite) delivery(attempts int) model.WebhookDelivery {
+	return model.WebhookDelivery{
+		ID: 3, WebhookID: 2, Event: model.WebhookBookingCreated, Payload: datatypes.JSON(`{"event":"booking.created"}`), Attempts: attempts,
+		NextAttemptAt: suite.now,
+		Webhook:       model.Webhook{ID: 2, UserID: 1, URL: suite.server.URL + "/hook", Secret: "0123456789abcdef"},
+	}
+}
+
+func (suite *WebhookTestSuite) TestCreateGeneratesSecretUnlessGiven() {
+	suite.mockWebhookRepository.On("Create", suite.ctx, mock.MatchedBy(func(obj model.Webhook) bool {
+		return len(obj.Secret) == 64 && obj.URL == "https://example.xyz/hook"
+	})).Return(model.Webhook{ID: 2, UserID: 1, URL: "https://example.xyz/hook", Secret: "generated", Events: []string{model.WebhookBookingCreated}}, nil)
+
+	resp, err := suite.service.Create(suite.ctx, 1, contract.Webhook{URL: "https://example.xyz/hook", Events: []string{model.WebhookBookingCreated}})
+	suite.NoError(err)
+	suite.Equal("generated", resp.Secret)
+	suite.Equal([]string{model.WebhookBookingCreated}, resp.Events)
+}
+
+func (suite *WebhookTestSuite) TestGetAllDoesNotReturnSecrets() {
+	suite.mockWebhookRepository.On("GetAll", suite.ctx, 1).Return([]model.Webhook{{ID: 2, UserID: 1, URL: "https://example.xyz/hook", Secret: "secret"}}, nil)
+
+	resp, err := suite.service.GetAll(suite.ctx, 1)
+	suite.NoError(err)
+	suite.Len(resp.Webhooks, 1)
+	suite.Empty(resp.Webhooks[0].Secret)
+}
+
+func (suite *WebhookTestSuite) TestGetDeliveriesReturnsNotFoundIfWebhookBelongsToAnotherUser() {
+	suite.mockWebhookRepository.On("GetByID", suite.ctx, 1, 2).Return(model.Webhook{}, sql.ErrNoRows)
+
+	_, err := suite.service.GetDeliveries(suite.ctx, 1, 2)
+	suite.ErrorIs(err, sql.ErrNoRows)
+	suite.mockWebhookRepository.AssertNotCalled(suite.T(), "GetDeliveries", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookTestSuite) TestDeliverDueSignsPayload() {
+	delivery := suite.delivery(0)
+	suite.mockWebhookRepository.On("GetDueDeliveries", suite.ctx, suite.now, deliveryBatchSize).Return([]model.WebhookDelivery{delivery}, nil)
+	delivered := delivery
+	delivered.Attempts, delivered.Status, delivered.ResponseStatus, delivered.DeliveredAt = 1, model.DeliverySucceeded, http.StatusOK, suite.now
+	suite.mockWebhookRepository.On("UpdateDelivery", suite.ctx, delivered).Return(nil)
+
+	count, err := suite.service.DeliverDue(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(1, count)
+	suite.Len(suite.received, 1)
+	req := suite.received[0]
+	suite.Equal("application/json", req.Header.Get("Content-Type"))
+	suite.Equal(model.WebhookBookingCreated, req.Header.Get(WebhookEventHeader))
+	suite.Equal("3", req.Header.Get(WebhookDeliveryHeader))
+	suite.Equal(`{"event":"booking.created"}`, string(suite.receivedBodies[0]))
+	// t=1685577600 and the HMAC-SHA256 of "1685577600.{payload}" keyed with the secret
+	suite.Equal(SignWebhookPayload("0123456789abcdef", suite.now, suite.receivedBodies[0]), req.Header.Get(WebhookSignatureHeader))
+	suite.Regexp(`^t=1685577600,v1=[0-9a-f]{64}$`, req.Header.Get(WebhookSignatureHeader))
+	suite.mockWebhookRepository.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestDeliverDueRetriesWithExponentialBackoff() {
+	suite.responseStatus = http.StatusInternalServerError
+	delivery := suite.delivery(2)
+	suite.mockWebhookRepository.On("GetDueDeliveries", suite.ctx, suite.now, deliveryBatchSize).Return([]model.WebhookDelivery{delivery}, nil)
+	retried := delivery
+	retried.Attempts, retried.ResponseStatus, retried.Error = 3, http.StatusInternalServerError, "webhook returned status 500"
+	retried.NextAttemptAt = suite.now.Add(2 * time.Minute)
+	suite.mockWebhookRepository.On("UpdateDelivery", suite.ctx, retried).Return(nil)
+
+	count, err := suite.service.DeliverDue(suite.ctx)
+	suite.NoError(err)
+	suite.Equal(0, count)
+	suite.mockWebhookRepository.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestDeliverDueGivesUpAfterTheLastAttempt() {
+	delivery := suite.delivery(maxDeliveryAttempts - 1)
+	delivery.Webhook.URL = "http://127.0.0.1:0/hook"
+	suite.mockWebhookRepository.On("GetDueDeliveries", suite.ctx, suite.now, deliveryBatchSize).Return([]model.WebhookDelivery{delivery}, nil)
+	suite.mockWebhookRepository.On("UpdateDelivery", suite.ctx, mock.MatchedBy(func(obj model.WebhookDelivery) bool {
+		return obj.Status == model.DeliveryFailed && obj.Attempts == maxDeliveryAttempts && obj.ResponseStatus == 0 && obj.Error != ""
+	})).Return(nil)
+
+	_, err := suite.service.DeliverDue(suite.ctx)
+	suite.NoError(err)
+	suite.mockWebhookRepository.AssertExpectations(suite.T())
+}
+
+func (suite *WebhookTestSuite) TestEnqueueOnlyQueuesDeliveriesForSubscribedWebhooks() {
+	publisher := newWebhooks(suite.mockWebhookRepository)
+	publisher.now = func() time.Time { return suite.now }
+	suite.mockWebhookRepository.On("GetAll", suite.ctx, 1).Return([]model.Webhook{
+		{ID: 1, UserID: 1, Events: []string{model.WebhookAvailabilityUpdated}},
+		{ID: 2, UserID: 1, Events: []string{model.WebhookBookingCreated, model.WebhookBookingCancelled}},
+	}, nil)
+	var queued []model.WebhookDelivery
+	suite.mockWebhookRepository.On("CreateDeliveries", suite.ctx, mock.Anything).Run(func(args mock.Arguments) {
+		queued = args.Get(1).([]model.WebhookDelivery)
+	}).Return(nil)
+
+	suite.NoError(publisher.enqueue(suite.ctx, 1, model.WebhookBookingCancelled, suite.now, contract.EventResponse{ID: 3}))
+
+	suite.Len(queued, 1)
+	suite.Equal(uint(2), queued[0].WebhookID)
+	suite.Equal(model.DeliveryPending, queued[0].Status)
+	suite.Equal(suite.now, queued[0].NextAttemptAt)
+	payload := map[string]interface{}{}
+	suite.NoError(json.Unmarshal(queued[0].Payload, &payload))
+	suite.Equal("booking.cancelled", payload["event"])
+	suite.Equal("2023-06-01T00:00:00Z", payload["occurred_at"])
+	suite.Equal(float64(3), payload["data"].(map[string]interface{})["id"])
+}
+
+func (suite *WebhookTestSuite) TestEnqueueDoesNotQueueAnythingWithoutSubscribers() {
+	publisher := newWebhooks(suite.mockWebhookRepository)
+	suite.mockWebhookRepository.On("GetAll", suite.ctx, 1).Return([]model.Webhook{{ID: 1, UserID: 1, Events: []string{model.WebhookAvailabilityUpdated}}}, nil)
+
+	suite.NoError(publisher.enqueue(suite.ctx, 1, model.WebhookBookingCreated, suite.now, contract.EventResponse{ID: 3}))
+
+	suite.mockWebhookRepository.AssertNotCalled(suite.T(), "CreateDeliveries", mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookTestSuite) TestEnqueueReturnsErrorIfWebhooksCannotBeFetched() {
+	publisher := newWebhooks(suite.mockWebhookRepository)
+	suite.mockWebhookRepository.On("GetAll", suite.ctx, 1).Return([]model.Webhook(nil), errors.New("some error"))
+
+	err := publisher.enqueue(suite.ctx, 1, model.WebhookBookingCreated, suite.now, contract.EventResponse{ID: 3})
+	suite.Error(err)
+	suite.mockWebhookRepository.AssertNotCalled(suite.T(), "CreateDeliveries", mock.Anything, mock.Anything)
+}
+
+func TestWebhookTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookTestSuite))
+}
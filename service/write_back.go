@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/harbor-xyz/coding-project/ical"
+	"github.com/harbor-xyz/coding-project/model"
+)
+
+// writeBackEvent puts the event into the user's external calendars which have write back enabled, or removes it from
+// them once it is cancelled. Every calendar is written to before the failures are returned, so that a single
+// unreachable calendar does not hold back the others. Writing again only replaces the event, so the whole write back
+// can be retried.
+func (effects Effects) writeBackEvent(ctx context.Context, eventObj model.Event) error {
+	calendars, err := effects.calendarRepository.GetAll(ctx, int(eventObj.UserID))
+	if err != nil {
+		return err
+	}
+	writeBack := make([]model.ExternalCalendar, 0)
+	for _, obj := range calendars {
+		if obj.WriteBack {
+			writeBack = append(writeBack, obj)
+		}
+	}
+	if len(writeBack) == 0 {
+		return nil
+	}
+
+	var icalEvent ical.Event
+	if eventObj.Status != model.EventCancelled {
+		icalEvent, err = effects.icalEvent(ctx, eventObj)
+		if err != nil {
+			return err
+		}
+	}
+
+	var errs []error
+	for _, obj := range writeBack {
+		provider, err := effects.providers(obj, time.UTC)
+		if err == nil {
+			if eventObj.Status == model.EventCancelled {
+				err = provider.DeleteEvent(ctx, icalUID(eventObj))
+			} else {
+				err = provider.PutEvent(ctx, icalEvent)
+			}
+		}
+		if err != nil {
+			log.Printf("error occurred while writing back event %d to external calendar %d: %s", eventObj.ID, obj.ID, err.Error())
+			errs = append(errs, fmt.Errorf("external calendar %d: %w", obj.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// icalEvent renders the event the way it is exported, with its host and event type.
+func (effects Effects) icalEvent(ctx context.Context, eventObj model.Event) (ical.Event, error) {
+	host, err := effects.userRepository.GetByID(ctx, int(eventObj.UserID))
+	if err != nil {
+		return ical.Event{}, err
+	}
+	var eventType model.EventType
+	if eventObj.EventTypeID != 0 {
+		eventType, err = effects.eventTypeRepository.GetByID(ctx, int(eventObj.UserID), int(eventObj.EventTypeID))
+		if err != nil {
+			return ical.Event{}, err
+		}
+	}
+	return toICalEvent(eventObj, host, eventType), nil
+}
+
+func icalUID(eventObj model.Event) string {
+	return fmt.Sprintf("event-%d%s", eventObj.ID, icalUIDSuffix)
+}